@@ -0,0 +1,241 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corehost "github.com/libp2p/go-libp2p/core/host"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/hva"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/tpm"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/wasmhost"
+)
+
+// maxJobResponseBytes bounds the /jobs/next body (wasm module plus manifest,
+// base64-expanded).
+const maxJobResponseBytes = 2 * wasmhost.MaxModuleBytes
+
+// maxKeySetBytes bounds the /orchestrator/keys body.
+const maxKeySetBytes = 64 << 10
+
+// jobReplay refuses a v2 manifest this node has already executed.
+var jobReplay = manifest.NewReplayGuard()
+
+// nextJob mirrors the orchestrator's /jobs/next response.
+type nextJob struct {
+	Wasm     []byte            `json:"wasm"`
+	Manifest manifest.Manifest `json:"manifest"`
+}
+
+// runJob fetches the next signed task, executes it in the Wasm sandbox and
+// submits the privatized gradient tagged with the manifest's TaskID, RoundID
+// and Nonce. round is used only for v1 manifests, which carry no round.
+func runJob(ctx context.Context, conf Config, plan hva.Plan, peerHost corehost.Host, round int) error {
+	if conf.OrchestratorURL == "" {
+		return fmt.Errorf("ORCHESTRATOR_URL not set")
+	}
+	client, err := orchestratorClient(conf)
+	if err != nil {
+		return err
+	}
+	orchPub, err := fetchOrchestratorKeys(ctx, conf, client)
+	if err != nil {
+		return fmt.Errorf("fetch orchestrator keys: %w", err)
+	}
+	job, err := fetchNextJob(ctx, conf, client)
+	if err != nil {
+		return fmt.Errorf("fetch next job: %w", err)
+	}
+	if job.Manifest.Version() >= manifest.SchemaV2 {
+		if job.Manifest.RoundID == 0 || job.Manifest.RoundID > math.MaxInt32 {
+			return fmt.Errorf("task %s carries invalid round %d", sanitizeLogValue(job.Manifest.TaskID), job.Manifest.RoundID)
+		}
+		round = int(job.Manifest.RoundID)
+	}
+	verify := manifest.VerifyOptions{Replay: jobReplay, RequireV2: conf.RequireManifestV2}
+	gradients, err := executeJob(ctx, conf.NodeID, job, orchPub, verify)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", sanitizeLogValue(job.Manifest.TaskID), err)
+	}
+	sendGradientUpdate(ctx, conf, plan, peerHost, round, job.Manifest.TaskID, job.Manifest.Nonce, gradients)
+	return nil
+}
+
+func orchestratorClient(conf Config) (*http.Client, error) {
+	tlsConfig, err := tpm.ClientTLSConfig(conf.NodeID, conf.OrchestratorServerName)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// fetchOrchestratorKeys returns the orchestrator's published key set, which
+// manifest.VerifySignature selects from by key ID. Orchestrators without
+// /orchestrator/keys fall back to the single /orchestrator/pubkey key.
+func fetchOrchestratorKeys(ctx context.Context, conf Config, client *http.Client) ([]byte, error) {
+	if keySet, err := getOrchestrator(ctx, conf, client, "/orchestrator/keys", maxKeySetBytes); err == nil {
+		return keySet, nil
+	}
+	body, err := getOrchestrator(ctx, conf, client, "/orchestrator/pubkey", 4096)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimSpace(string(body)))
+}
+
+func fetchNextJob(ctx context.Context, conf Config, client *http.Client) (*nextJob, error) {
+	path := "/jobs/next?node_id=" + url.QueryEscape(conf.NodeID)
+	body, err := getOrchestrator(ctx, conf, client, path, maxJobResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+	var job nextJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("decode job: %w", err)
+	}
+	return &job, nil
+}
+
+func getOrchestrator(ctx context.Context, conf Config, client *http.Client, path string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(conf.OrchestratorURL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", strings.SplitN(path, "?", 2)[0], resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s response exceeds %d bytes", strings.SplitN(path, "?", 2)[0], limit)
+	}
+	return body, nil
+}
+
+// checkJobBinding confirms the manifest was issued to nodeID and that the
+// shipped module matches its WasmModuleSHA256.
+func checkJobBinding(nodeID string, job *nextJob) error {
+	if job.Manifest.NodeID != nodeID {
+		return fmt.Errorf("manifest issued to node %q", sanitizeLogValue(job.Manifest.NodeID))
+	}
+	sum := sha256.Sum256(job.Wasm)
+	want := strings.ToLower(strings.TrimSpace(job.Manifest.WasmModuleSHA256))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(want)) != 1 {
+		return fmt.Errorf("wasm module does not match manifest sha256")
+	}
+	return nil
+}
+
+// executeJob runs the task module under the manifest's capabilities, memory
+// and time limits and returns the clipped, noised gradient it submitted.
+// verify sets the manifest expiry and replay policy.
+func executeJob(ctx context.Context, nodeID string, job *nextJob, orchPub []byte, verify manifest.VerifyOptions) ([]float64, error) {
+	cfg, err := wasmhost.ConfigFromManifest(&job.Manifest, orchPub, verify)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkJobBinding(nodeID, job); err != nil {
+		return nil, err
+	}
+	var submitted []float32
+	cfg.GradientSink = func(_ context.Context, g []float32) error {
+		if submitted != nil {
+			return fmt.Errorf("gradient already submitted")
+		}
+		submitted = g
+		return nil
+	}
+
+	host, err := wasmhost.NewHostWithConfig(ctx, job.Wasm, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer host.Close(ctx)
+	if err := host.RunTask(ctx, job.Manifest.MaxMillis); err != nil {
+		return nil, err
+	}
+	if submitted == nil {
+		return nil, fmt.Errorf("task finished without submitting a gradient")
+	}
+	return privatizeGradient(submitted, job.Manifest.MaxGradNorm, job.Manifest.Epsilon, job.Manifest.Delta)
+}
+
+// privatizeGradient clips g to ℓ₂ norm maxNorm and, when epsilon > 0, adds
+// Gaussian noise calibrated for (ε, δ)-DP with sensitivity maxNorm:
+// σ = maxNorm·√(2 ln(1.25/δ))/ε.
+func privatizeGradient(g []float32, maxNorm, epsilon, delta float64) ([]float64, error) {
+	if maxNorm < 0 || epsilon < 0 || delta < 0 {
+		return nil, fmt.Errorf("negative privacy parameters in manifest")
+	}
+	out := make([]float64, len(g))
+	var norm float64
+	for i, v := range g {
+		out[i] = float64(v)
+		norm += out[i] * out[i]
+	}
+	norm = math.Sqrt(norm)
+	if maxNorm > 0 && norm > maxNorm {
+		scale := maxNorm / norm
+		for i := range out {
+			out[i] *= scale
+		}
+	}
+	if epsilon == 0 {
+		return out, nil
+	}
+	if maxNorm == 0 {
+		return nil, fmt.Errorf("epsilon %.3g requires max_grad_norm to bound sensitivity", epsilon)
+	}
+	if delta <= 0 || delta >= 1 {
+		return nil, fmt.Errorf("delta %.3g must be in (0, 1)", delta)
+	}
+
+	var seed [32]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return nil, fmt.Errorf("seed dp noise: %w", err)
+	}
+	rng := rand.New(rand.NewChaCha8(seed))
+	sigma := maxNorm * math.Sqrt(2*math.Log(1.25/delta)) / epsilon
+	for i := range out {
+		out[i] += rng.NormFloat64() * sigma
+	}
+	return out, nil
+}
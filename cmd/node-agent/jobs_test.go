@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+// gradientTaskModule is a wasm task whose run_task submits the f32 gradient
+// [3, 4] through mohawk.submit_gradient.
+var gradientTaskModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0a, 0x02, 0x60, 0x02, 0x7f, 0x7f, 0x01,
+	0x7f, 0x60, 0x00, 0x00, 0x02, 0x1a, 0x01, 0x06, 0x6d, 0x6f, 0x68, 0x61, 0x77, 0x6b, 0x0f, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x5f, 0x67, 0x72, 0x61, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x01, 0x05, 0x03, 0x01, 0x00, 0x01, 0x07, 0x15, 0x02, 0x06, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x02, 0x00, 0x08, 0x72, 0x75, 0x6e, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x00, 0x01,
+	0x0a, 0x0b, 0x01, 0x09, 0x00, 0x41, 0x00, 0x41, 0x08, 0x10, 0x00, 0x1a, 0x0b, 0x0b, 0x0e, 0x01,
+	0x00, 0x41, 0x00, 0x0b, 0x08, 0x00, 0x00, 0x40, 0x40, 0x00, 0x00, 0x80, 0x40,
+}
+
+// shippedTaskModule is the fl_task module the orchestrator serves.
+const shippedTaskModule = "../../wasm-modules/fl_task/target/wasm32-unknown-unknown/release/fl_task.wasm"
+
+func signedJob(t *testing.T, m manifest.Manifest) (*nextJob, ed25519.PublicKey) {
+	t.Helper()
+	return signedModuleJob(t, gradientTaskModule, m)
+}
+
+func signedModuleJob(t *testing.T, wasm []byte, m manifest.Manifest) (*nextJob, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("keygen: %v", err)
+	}
+	sum := sha256.Sum256(wasm)
+	m.WasmModuleSHA256 = hex.EncodeToString(sum[:])
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	m.Signature = ed25519.Sign(priv, data)
+	return &nextJob{Wasm: wasm, Manifest: m}, pub
+}
+
+func TestExecuteJobClipsSubmittedGradient(t *testing.T) {
+	job, pub := signedJob(t, manifest.Manifest{
+		TaskID:       "task-1",
+		NodeID:       "node-1",
+		Capabilities: []manifest.Capability{manifest.CapSubmitGrad},
+		MaxMemPages:  1,
+		MaxMillis:    1000,
+		MaxGradNorm:  1,
+	})
+	grads, err := executeJob(context.Background(), "node-1", job, pub, manifest.VerifyOptions{})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(grads) != 2 || math.Abs(grads[0]-0.6) > 1e-6 || math.Abs(grads[1]-0.8) > 1e-6 {
+		t.Fatalf("expected [3,4] clipped to unit norm, got %v", grads)
+	}
+}
+
+func TestExecuteJobRunsShippedTaskModule(t *testing.T) {
+	wasm, err := os.ReadFile(shippedTaskModule)
+	if err != nil {
+		t.Fatalf("read shipped module: %v", err)
+	}
+	job, pub := signedModuleJob(t, wasm, manifest.Manifest{
+		TaskID:       "task-fl",
+		NodeID:       "node-1",
+		Capabilities: []manifest.Capability{manifest.CapLog, manifest.CapSubmitGrad},
+		MaxMemPages:  64,
+		MaxMillis:    1000,
+		MaxGradNorm:  1,
+	})
+	grads, err := executeJob(context.Background(), "node-1", job, pub, manifest.VerifyOptions{})
+	if err != nil {
+		t.Fatalf("execute shipped module: %v", err)
+	}
+	want := []float32{0.1, 0.2, 0.3}
+	if len(grads) != len(want) {
+		t.Fatalf("expected %d gradient values, got %v", len(want), grads)
+	}
+	for i := range want {
+		if grads[i] != float64(want[i]) {
+			t.Fatalf("expected the task's gradient %v, got %v", want, grads)
+		}
+	}
+
+	// Without the log capability the module's first import call traps
+	job, pub = signedModuleJob(t, wasm, manifest.Manifest{
+		TaskID:       "task-fl",
+		NodeID:       "node-1",
+		Capabilities: []manifest.Capability{manifest.CapSubmitGrad},
+		MaxMemPages:  64,
+		MaxMillis:    1000,
+	})
+	if _, err := executeJob(context.Background(), "node-1", job, pub, manifest.VerifyOptions{}); err == nil {
+		t.Fatal("expected the shipped module to trap without the log capability")
+	}
+}
+
+func TestExecuteJobRejectsUntrustedJobs(t *testing.T) {
+	base := manifest.Manifest{
+		TaskID:       "task-1",
+		NodeID:       "node-1",
+		Capabilities: []manifest.Capability{manifest.CapSubmitGrad},
+	}
+
+	job, pub := signedJob(t, base)
+	if _, err := executeJob(context.Background(), "node-2", job, pub, manifest.VerifyOptions{}); err == nil {
+		t.Fatal("expected job for another node to be rejected")
+	}
+
+	job, pub = signedJob(t, base)
+	job.Wasm = append([]byte(nil), gradientTaskModule...)
+	job.Wasm[len(job.Wasm)-1] ^= 0x01
+	if _, err := executeJob(context.Background(), "node-1", job, pub, manifest.VerifyOptions{}); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Fatalf("expected module hash mismatch, got %v", err)
+	}
+
+	job, _ = signedJob(t, base)
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := executeJob(context.Background(), "node-1", job, otherPub, manifest.VerifyOptions{}); err == nil {
+		t.Fatal("expected manifest signed by another key to be rejected")
+	}
+
+	noCap := base
+	noCap.Capabilities = nil
+	job, pub = signedJob(t, noCap)
+	if _, err := executeJob(context.Background(), "node-1", job, pub, manifest.VerifyOptions{}); err == nil {
+		t.Fatal("expected submit without SUBMIT_GRADIENTS to fail")
+	}
+}
+
+func TestExecuteJobRefusesReplayedManifest(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("keygen: %v", err)
+	}
+	keys := manifest.NewKeystore(priv)
+	sum := sha256.Sum256(gradientTaskModule)
+	now := time.Now().UTC()
+	m := manifest.Manifest{
+		SchemaVersion:    manifest.SchemaV2,
+		TaskID:           "task-1",
+		NodeID:           "node-1",
+		WasmModuleSHA256: hex.EncodeToString(sum[:]),
+		Capabilities:     []manifest.Capability{manifest.CapSubmitGrad},
+		MaxMemPages:      1,
+		MaxMillis:        1000,
+		IssuedAt:         now,
+		ExpiresAt:        now.Add(time.Minute),
+		Nonce:            "nonce-1",
+	}
+	if err := keys.Sign(&m); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	job := &nextJob{Wasm: gradientTaskModule, Manifest: m}
+	verify := manifest.VerifyOptions{Replay: manifest.NewReplayGuard()}
+
+	if _, err := executeJob(context.Background(), "node-1", job, keys.Current().PublicKey, verify); err != nil {
+		t.Fatalf("first execution: %v", err)
+	}
+	if _, err := executeJob(context.Background(), "node-1", job, keys.Current().PublicKey, verify); !errors.Is(err, manifest.ErrManifestReplayed) {
+		t.Fatalf("expected replay to be refused, got %v", err)
+	}
+}
+
+func TestPrivatizeGradientNoiseScale(t *testing.T) {
+	const n = 20000
+	g := make([]float32, n)
+	out, err := privatizeGradient(g, 1, 1, 1e-5)
+	if err != nil {
+		t.Fatalf("privatize: %v", err)
+	}
+	var sumSq float64
+	for _, v := range out {
+		sumSq += v * v
+	}
+	got := math.Sqrt(sumSq / n)
+	want := math.Sqrt(2 * math.Log(1.25/1e-5))
+	if math.Abs(got-want)/want > 0.05 {
+		t.Fatalf("noise std %.3f, want about %.3f", got, want)
+	}
+
+	if _, err := privatizeGradient(g, 0, 1, 1e-5); err == nil {
+		t.Fatal("expected epsilon without max_grad_norm to be rejected")
+	}
+	if _, err := privatizeGradient(g, 1, 1, 0); err == nil {
+		t.Fatal("expected zero delta to be rejected")
+	}
+}
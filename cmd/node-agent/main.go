@@ -61,6 +61,7 @@ type Config struct {
 	IPFSEndpoint              string
 	TotalNodes                int
 	MeshDimensions            int
+	RequireManifestV2         bool
 }
 
 func main() {
@@ -127,54 +128,38 @@ func main() {
 	if err != nil {
 		log.Fatalf("Critical Failure: Could not initialize Wasm Runner: %v", err)
 	}
-	defer runner.Close(ctx)
-
-	activeVerifier := runner
+	defer func() { _ = runner.Close(ctx) }()
 
 	log.Printf("Node %s successfully initialized with zk-SNARK verifier and transport stack", conf.NodeID)
 
-	mockProof := make([]byte, 200)
-	proofStart := time.Now()
-	success, err := runner.Verify(ctx, mockProof, conf.WasmMaxMillis)
-	proofLatency := float64(time.Since(proofStart).Microseconds()) / 1000.0
-	if err != nil {
-		if isMissingVerifyProofExportErr(err) {
-			if conf.AllowInsecureWASMFallback {
-				log.Printf("Warning: Wasm verifier at %s does not export verify_proof; using insecure fallback verifier due to MOHAWK_ALLOW_INSECURE_WASM_FALLBACK=true", conf.WasmModulePath)
-				fallbackRunner, fallbackErr := wasmhost.NewRunner(ctx, insecureFallbackVerifierModule())
-				if fallbackErr != nil {
-					log.Fatalf("Critical Failure: Could not initialize insecure fallback verifier: %v", fallbackErr)
-				}
-				_ = runner.Close(ctx)
-				runner = fallbackRunner
-				activeVerifier = fallbackRunner
-			} else {
-				log.Fatalf("Critical Failure: Wasm verifier at %s does not export verify_proof", conf.WasmModulePath)
-			}
-		} else {
-			metrics.ObserveProofVerification("groth16", false, proofLatency)
-			metrics.ObserveAcceleratorOp(string(tune.SelectedDevice.Backend), "proof_verify", false)
-			metrics.ObserveAcceleratorOpLatency(string(tune.SelectedDevice.Backend), "proof_verify", proofLatency)
-			log.Printf("Verification Process Executed: %v", err)
+	// Check the verifier's ABI up front; proofs are only verified once a
+	// real proof arrives, never against placeholder bytes.
+	if !runner.HasExport("verify_proof") {
+		if !conf.AllowInsecureWASMFallback {
+			log.Fatalf("Critical Failure: Wasm verifier at %s does not export verify_proof", conf.WasmModulePath)
 		}
-	} else {
-		metrics.ObserveProofVerification("groth16", success, proofLatency)
-		metrics.ObserveAcceleratorOp(string(tune.SelectedDevice.Backend), "proof_verify", success)
-		metrics.ObserveAcceleratorOpLatency(string(tune.SelectedDevice.Backend), "proof_verify", proofLatency)
-		log.Printf("Theorem 5 Verification Status: %v", success)
+		log.Printf("Warning: Wasm verifier at %s does not export verify_proof; using insecure fallback verifier due to MOHAWK_ALLOW_INSECURE_WASM_FALLBACK=true", conf.WasmModulePath)
+		fallbackRunner, fallbackErr := wasmhost.NewRunner(ctx, insecureFallbackVerifierModule())
+		if fallbackErr != nil {
+			log.Fatalf("Critical Failure: Could not initialize insecure fallback verifier: %v", fallbackErr)
+		}
+		_ = runner.Close(ctx)
+		runner = fallbackRunner
 	}
 
-	// Submit an initial gradient update over the libp2p transport.
-	sendGradientUpdate(ctx, conf, meshPlan, peerHost, 1)
+	// Execute the first orchestrator job and submit its gradient over libp2p.
+	if err := runJob(ctx, conf, meshPlan, peerHost, 1); err != nil {
+		log.Printf("Job: initial round deferred: %v", err)
+	}
 
 	// Enumerate hardware accelerators and log available backends.
 	logAcceleratorDevices()
 
 	log.Println("Node Agent operational. Entering supervised runtime loop...")
-	runSupervisor(rootCtx, conf, meshPlan, peerHost, activeVerifier)
+	runSupervisor(rootCtx, conf, meshPlan, peerHost)
 }
 
-func runSupervisor(rootCtx context.Context, conf Config, meshPlan hva.Plan, peerHost corehost.Host, runner *wasmhost.Host) {
+func runSupervisor(rootCtx context.Context, conf Config, meshPlan hva.Plan, peerHost corehost.Host) {
 	interval := time.Duration(defaultInt(os.Getenv("MOHAWK_SUPERVISOR_INTERVAL_SECONDS"), 30)) * time.Second
 	if interval < 5*time.Second {
 		interval = 5 * time.Second
@@ -186,7 +171,7 @@ func runSupervisor(rootCtx context.Context, conf Config, meshPlan hva.Plan, peer
 	consecutiveFailures := 0
 
 	for {
-		if err := runSupervisedRound(rootCtx, conf, meshPlan, peerHost, runner, round); err != nil {
+		if err := runSupervisedRound(rootCtx, conf, meshPlan, peerHost, round); err != nil {
 			consecutiveFailures++
 			backoff := time.Duration(consecutiveFailures)
 			if backoff > 10 {
@@ -212,7 +197,7 @@ func runSupervisor(rootCtx context.Context, conf Config, meshPlan hva.Plan, peer
 	}
 }
 
-func runSupervisedRound(rootCtx context.Context, conf Config, meshPlan hva.Plan, peerHost corehost.Host, runner *wasmhost.Host, round int) (err error) {
+func runSupervisedRound(rootCtx context.Context, conf Config, meshPlan hva.Plan, peerHost corehost.Host, round int) (err error) {
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			err = fmt.Errorf("panic recovered in supervisor round: %v", recovered)
@@ -240,34 +225,12 @@ func runSupervisedRound(rootCtx context.Context, conf Config, meshPlan hva.Plan,
 		log.Printf("Supervisor: router publish deferred: %v", err)
 	}
 
-	if runner != nil {
-		mockProof := make([]byte, 200)
-		proofStart := time.Now()
-		proofOK, proofErr := runner.Verify(roundCtx, mockProof, conf.WasmMaxMillis)
-		proofLatency := float64(time.Since(proofStart).Microseconds()) / 1000.0
-		if proofErr != nil {
-			metrics.ObserveProofVerification("groth16", false, proofLatency)
-			metrics.ObserveAcceleratorOp("cpu", "proof_verify", false)
-			metrics.ObserveAcceleratorOpLatency("cpu", "proof_verify", proofLatency)
-			log.Printf("Supervisor: proof verify failed: %v", proofErr)
-		} else {
-			metrics.ObserveProofVerification("groth16", proofOK, proofLatency)
-			metrics.ObserveAcceleratorOp("cpu", "proof_verify", proofOK)
-			metrics.ObserveAcceleratorOpLatency("cpu", "proof_verify", proofLatency)
-		}
+	if err := runJob(roundCtx, conf, meshPlan, peerHost, round); err != nil {
+		log.Printf("Supervisor: job deferred: %v", err)
 	}
-
-	sendGradientUpdate(roundCtx, conf, meshPlan, peerHost, round)
 	return nil
 }
 
-func isMissingVerifyProofExportErr(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "missing required export: verify_proof")
-}
-
 // logAcceleratorDevices detects and logs hardware compute backends.
 func logAcceleratorDevices() {
 	devices := accelerator.DetectDevices()
@@ -321,7 +284,7 @@ func fetchP2PInfo(ctx context.Context, conf Config) (*p2pInfo, error) {
 
 // sendGradientUpdate fetches the orchestrator's libp2p address, dials it, and delivers
 // a gradient update message over the /mohawk/gradient/1.0.0 protocol.
-func sendGradientUpdate(ctx context.Context, conf Config, plan hva.Plan, peerHost corehost.Host, round int) {
+func sendGradientUpdate(ctx context.Context, conf Config, plan hva.Plan, peerHost corehost.Host, round int, taskID, nonce string, gradients []float64) {
 	gradStart := time.Now()
 	info, err := fetchP2PInfo(ctx, conf)
 	if err != nil {
@@ -367,12 +330,12 @@ func sendGradientUpdate(ctx context.Context, conf Config, plan hva.Plan, peerHos
 		}
 		orchAddrs = append(orchAddrs, ma)
 	}
-	mockGradients := make([]float64, 128)
 	msg := &network.GradientMessage{
 		NodeID:    conf.NodeID,
-		TaskID:    "task-init",
+		TaskID:    taskID,
 		Round:     round,
-		Gradients: mockGradients,
+		Gradients: gradients,
+		Nonce:     nonce,
 	}
 	ack, err := network.SendGradientWithKEX(ctx, peerHost, orchPeerID, orchAddrs, msg, conf.TransportKEXMode)
 	if err != nil {
@@ -384,10 +347,10 @@ func sendGradientUpdate(ctx context.Context, conf Config, plan hva.Plan, peerHos
 	metrics.ObserveAcceleratorOp("cpu", "gradient_submit", ack.Accepted)
 	metrics.ObserveAcceleratorOpLatency("cpu", "gradient_submit", float64(time.Since(gradStart).Microseconds())/1000.0)
 	if !ack.Accepted {
-		log.Printf("Gradient: sent round=%d len=%d -> accepted=%v reason=%q negotiated_kex=%q kex_pubkey_len=%d", msg.Round, len(msg.Gradients), ack.Accepted, sanitizeLogValue(ack.Reason), sanitizeLogValue(ack.NegotiatedKEX), ack.KEXPublicKeyLen)
+		log.Printf("Gradient: sent task=%s round=%d len=%d -> accepted=%v reason=%q negotiated_kex=%q kex_pubkey_len=%d", sanitizeLogValue(msg.TaskID), msg.Round, len(msg.Gradients), ack.Accepted, sanitizeLogValue(ack.Reason), sanitizeLogValue(ack.NegotiatedKEX), ack.KEXPublicKeyLen)
 		return
 	}
-	log.Printf("Gradient: sent round=%d len=%d -> accepted=%v negotiated_kex=%q kex_pubkey_len=%d", msg.Round, len(msg.Gradients), ack.Accepted, sanitizeLogValue(ack.NegotiatedKEX), ack.KEXPublicKeyLen)
+	log.Printf("Gradient: sent task=%s round=%d len=%d -> accepted=%v negotiated_kex=%q kex_pubkey_len=%d", sanitizeLogValue(msg.TaskID), msg.Round, len(msg.Gradients), ack.Accepted, sanitizeLogValue(ack.NegotiatedKEX), ack.KEXPublicKeyLen)
 }
 
 func startMetricsServer(nodeID string) {
@@ -436,6 +399,7 @@ func loadConfig() (Config, error) {
 		IPFSEndpoint:              os.Getenv("IPFS_API_ENDPOINT"),
 		TotalNodes:                defaultInt(os.Getenv("MOHAWK_TOTAL_NODES"), 10000000),
 		MeshDimensions:            defaultInt(os.Getenv("MOHAWK_MESH_DIMENSIONS"), 1024),
+		RequireManifestV2:         strings.EqualFold(strings.TrimSpace(os.Getenv("MOHAWK_REQUIRE_MANIFEST_V2")), "true"),
 	}, nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -32,7 +33,9 @@ import (
 	"unicode"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/accelerator"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/ipfs"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
@@ -42,8 +45,18 @@ import (
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/tpm"
 )
 
-var orchPriv ed25519.PrivateKey
-var orchPub ed25519.PublicKey
+var orchKeys *manifest.Keystore
+
+// orchRounds assigns /jobs/next callers to the current training round.
+var orchRounds *RoundManager
+
+// orchModels is the global model lineage; its head is the model /jobs/next
+// assigns for training.
+var orchModels *ModelRegistry
+
+// manifestTTL is how long an issued manifest may be executed
+// (MOHAWK_MANIFEST_TTL, default 10m).
+var manifestTTL = 10 * time.Minute
 
 var buildVersion = "dev"
 var buildCommit = "unknown"
@@ -60,12 +73,36 @@ func main() {
 		log.Fatal(err)
 	}
 
-	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	keys, err := loadSigningKeys()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("failed to load orchestrator signing keys: %v", err)
+	}
+	orchKeys = keys
+	log.Printf("orchestrator manifest signing key_id=%s", orchKeys.Current().KeyID)
+	if ttl, err := time.ParseDuration(strings.TrimSpace(os.Getenv("MOHAWK_MANIFEST_TTL"))); err == nil && ttl > 0 {
+		manifestTTL = ttl
 	}
-	orchPriv = priv
-	orchPub = priv.Public().(ed25519.PublicKey)
+
+	checkpoints, err := initCheckpointStore()
+	if err != nil {
+		log.Fatalf("failed to initialize checkpoint store: %v", err)
+	}
+	models, err := initModelRegistry(orchKeys)
+	if err != nil {
+		log.Fatalf("failed to initialize model registry: %v", err)
+	}
+	orchModels = models
+	thinker, err := loadThinkerClauses()
+	if err != nil {
+		log.Fatalf("failed to load thinker clauses: %v", err)
+	}
+	rounds, err := initRoundManager(orchModels, checkpoints, thinker)
+	if err != nil {
+		log.Fatalf("failed to initialize round manager: %v", err)
+	}
+	orchRounds = rounds
+	go orchRounds.Run(context.Background(), time.Second)
+	log.Printf("orchestrator training round %d %s", orchRounds.Current().ID, orchRounds.Current().State)
 
 	tune := accelerator.BuildAutoTuneProfile(0)
 	if tune.RecommendedWorker > 0 {
@@ -103,30 +140,51 @@ func main() {
 	log.Printf("orchestrator transport KEX mode=%s expected_key_bytes=%d", kexMode, kexMode.ExpectedPublicKeyBytes())
 
 	server := &Server{
-		Checkpoints:      ipfs.NewBackend(os.Getenv("IPFS_API_ENDPOINT")),
+		Checkpoints:      checkpoints,
 		MeshDimensions:   meshDimensions,
 		PeerHost:         transportHost,
 		TransportKEXMode: kexMode,
 		AdminToken:       loadSecretValue("MOHAWK_ADMIN_TOKEN", "MOHAWK_ADMIN_TOKEN_FILE"),
+		SigningKeys:      orchKeys,
+		KeyOverlap:       parseKeyOverlap(os.Getenv("MOHAWK_ORCHESTRATOR_KEY_OVERLAP")),
+		Rounds:           orchRounds,
+		Models:           orchModels,
 	}
 	utilityLedger, err := initUtilityLedger()
 	if err != nil {
 		log.Fatalf("failed to initialize utility ledger: %v", err)
 	}
 	observePQCPolicyMetrics()
-	observeThinkerClausesFromCapabilities(defaultString(os.Getenv("MOHAWK_CAPABILITIES_PATH"), "capabilities.json"))
+	observeThinkerClauses(thinker)
 	server.UtilityLedger = utilityLedger
 	// Register the libp2p gradient-submission protocol so edge nodes can deliver
-	// gradient updates directly over the encrypted p2p transport.
+	// gradient updates directly over the encrypted p2p transport. Each update
+	// is bound to the round and task its manifest was issued for, and must
+	// carry that manifest's nonce since the node ID is self-reported.
 	network.RegisterGradientHandlerWithKEX(transportHost, kexMode, func(msg *network.GradientMessage) *network.GradientAck {
-		log.Printf("gradient received: round=%d len=%d node=%s task=%s",
-			msg.Round, len(msg.Gradients), sanitizeLogValue(msg.NodeID), sanitizeLogValue(msg.TaskID))
-		return &network.GradientAck{Accepted: true, NegotiatedKEX: string(kexMode), KEXPublicKeyLen: kexMode.ExpectedPublicKeyBytes()}
+		ack := &network.GradientAck{Accepted: true, NegotiatedKEX: string(kexMode), KEXPublicKeyLen: kexMode.ExpectedPublicKeyBytes()}
+		if msg.Round <= 0 {
+			ack.Accepted, ack.Reason = false, ErrUnknownRound.Error()
+		} else if err := orchRounds.Submit(uint64(msg.Round), msg.NodeID, msg.TaskID, msg.Nonce, msg.Gradients); err != nil {
+			ack.Accepted, ack.Reason = false, err.Error()
+		}
+		log.Printf("gradient received: round=%d len=%d node=%s task=%s accepted=%v reason=%q",
+			msg.Round, len(msg.Gradients), sanitizeLogValue(msg.NodeID), sanitizeLogValue(msg.TaskID), ack.Accepted, ack.Reason)
+		return ack
 	})
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/orchestrator/pubkey", handlePubkey)
+	mux.HandleFunc("/orchestrator/keys", server.HandleKeySet)
+	mux.HandleFunc("/orchestrator/keys/rotate", server.HandleKeyRotate)
 	mux.HandleFunc("/jobs/next", handleNextJob)
+	mux.HandleFunc("/rounds", server.HandleRounds)
+	mux.HandleFunc("/rounds/{id}", server.HandleRound)
+	mux.HandleFunc("/models", server.HandleModels)
+	mux.HandleFunc("/models/latest", server.HandleModelLatest)
+	mux.HandleFunc("/models/pin", server.HandleModelPin)
+	mux.HandleFunc("/models/rollback", server.HandleModelRollback)
+	mux.HandleFunc("/models/{version}", server.HandleModelVersion)
 	mux.HandleFunc("/attest", server.HandleAttest)
 	mux.HandleFunc("/checkpoints/put", server.HandleCheckpointPut)
 	mux.HandleFunc("/checkpoints/get", server.HandleCheckpointGet)
@@ -178,8 +236,10 @@ func main() {
 	log.Fatal(httpServer.ListenAndServeTLS("", ""))
 }
 
+// handlePubkey serves the current signing key for nodes that predate
+// /orchestrator/keys.
 func handlePubkey(w http.ResponseWriter, r *http.Request) {
-	if _, err := w.Write([]byte(hex.EncodeToString(orchPub))); err != nil {
+	if _, err := w.Write([]byte(hex.EncodeToString(orchKeys.Current().PublicKey))); err != nil {
 		log.Printf("failed to write pubkey response: %v", err)
 	}
 }
@@ -196,21 +256,61 @@ func handleNextJob(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "no wasm", http.StatusInternalServerError)
 		return
 	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		http.Error(w, "nonce generation failed", http.StatusInternalServerError)
+		return
+	}
 
+	round, taskID, err := orchRounds.Join(nodeID, hex.EncodeToString(nonce[:]))
+	switch {
+	case errors.Is(err, ErrAlreadySubmitted):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, ErrRoundNotOpen), errors.Is(err, ErrRoundFull):
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		log.Printf("round join failed for node=%s: %v", sanitizeLogValue(nodeID), err)
+		http.Error(w, "round join failed", http.StatusInternalServerError)
+		return
+	}
+
+	modelCID := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_CID"))
+	var modelVersion uint64
+	if head, ok := orchModels.Latest(); ok {
+		modelCID, modelVersion = head.CID, head.Version
+	}
+
+	issuedAt := time.Now().UTC().Truncate(time.Second)
 	m := manifest.Manifest{
-		TaskID:           "task-" + time.Now().Format("150405"),
+		SchemaVersion:    manifest.CurrentSchemaVersion,
+		TaskID:           taskID,
 		NodeID:           nodeID,
 		WasmModuleSHA256: wasmHash,
 		Capabilities: []manifest.Capability{
 			manifest.CapLog,
 			manifest.CapSubmitGrad,
 		},
-		MaxMemPages: 64,
-		MaxMillis:   30000,
-		Epsilon:     2.0,
+		MaxMemPages:  64,
+		MaxMillis:    30000,
+		MaxGradNorm:  1.0,
+		Epsilon:      2.0,
+		Delta:        1e-5,
+		IssuedAt:     issuedAt,
+		ExpiresAt:    issuedAt.Add(manifestTTL),
+		RoundID:      round,
+		ModelCID:     modelCID,
+		ModelVersion: modelVersion,
+		Nonce:        hex.EncodeToString(nonce[:]),
+	}
+
+	if err := orchKeys.Sign(&m); err != nil {
+		log.Printf("manifest signing failed: %v", err)
+		http.Error(w, "manifest signing failed", http.StatusInternalServerError)
+		return
 	}
-
-	signManifest(&m)
 	resp := NextJobResponse{Wasm: wasmBytes, Man: m}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
@@ -226,10 +326,125 @@ func loadWasm() ([]byte, string, error) {
 	return b, hex.EncodeToString(sum[:]), nil
 }
 
-func signManifest(m *manifest.Manifest) {
-	data, _ := json.Marshal(m)
-	sig := ed25519.Sign(orchPriv, data)
-	m.Signature = sig
+// loadSigningKeys opens the keystore at MOHAWK_ORCHESTRATOR_KEYSTORE, or
+// uses the PEM key at MOHAWK_ORCHESTRATOR_KEY_FILE. Without either it falls
+// back to an ephemeral key, which nodes must re-fetch after every restart.
+func loadSigningKeys() (*manifest.Keystore, error) {
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_ORCHESTRATOR_KEYSTORE")); raw != "" {
+		path, err := sanitizePathInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_ORCHESTRATOR_KEYSTORE: %w", err)
+		}
+		return manifest.OpenKeystore(path)
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_ORCHESTRATOR_KEY_FILE")); raw != "" {
+		path, err := sanitizePathInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_ORCHESTRATOR_KEY_FILE: %w", err)
+		}
+		priv, err := manifest.LoadPrivateKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return manifest.NewKeystore(priv), nil
+	}
+	log.Printf("warning: no MOHAWK_ORCHESTRATOR_KEYSTORE or MOHAWK_ORCHESTRATOR_KEY_FILE; using an ephemeral signing key")
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.NewKeystore(priv), nil
+}
+
+// initCheckpointStore builds the checkpoint store from a local block
+// directory at MOHAWK_CHECKPOINT_DIR and the IPFS node at IPFS_API_ENDPOINT.
+// With both, blocks are replicated to each and read from the directory first.
+// With neither, checkpoints are disabled and nil is returned.
+func initCheckpointStore() (checkpoint.Store, error) {
+	var blocks []checkpoint.BlockStore
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_CHECKPOINT_DIR")); raw != "" {
+		path, err := sanitizePathInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_CHECKPOINT_DIR: %w", err)
+		}
+		dir, err := checkpoint.NewDir(path)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, dir)
+	}
+	if backend := ipfs.NewBackend(os.Getenv("IPFS_API_ENDPOINT")); backend.Enabled() {
+		blocks = append(blocks, backend)
+	}
+	switch len(blocks) {
+	case 0:
+		return nil, nil
+	case 1:
+		return checkpoint.NewStore(blocks[0]), nil
+	default:
+		return checkpoint.NewStore(checkpoint.NewReplicated(blocks...)), nil
+	}
+}
+
+// initModelRegistry opens the registry for MOHAWK_MODEL_ID (default
+// "global") at MOHAWK_MODEL_REGISTRY_PATH. An empty registry starts from
+// MOHAWK_MODEL_CID as genesis version 1 when that is set.
+func initModelRegistry(keys *manifest.Keystore) (*ModelRegistry, error) {
+	var path string
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_REGISTRY_PATH")); raw != "" {
+		cleaned, err := sanitizePathInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_MODEL_REGISTRY_PATH: %w", err)
+		}
+		path = cleaned
+	}
+	registry, err := NewModelRegistry(defaultString(strings.TrimSpace(os.Getenv("MOHAWK_MODEL_ID")), "global"), path, keys)
+	if err != nil {
+		return nil, err
+	}
+	if genesis := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_CID")); genesis != "" && registry.Status().Versions == 0 {
+		if _, err := registry.Register(ModelVersion{Kind: ModelKindFull, CID: genesis}); err != nil {
+			return nil, fmt.Errorf("register genesis model: %w", err)
+		}
+	}
+	return registry, nil
+}
+
+// initRoundManager builds the round manager from MOHAWK_ROUND_* settings,
+// aggregating with a regional aggregator whose privacy accountant is backed by
+// MOHAWK_DP_LEDGER_PATH when set. Each committed round is published to models
+// as the next version. thinker, when set, governs which updates are aggregated.
+func initRoundManager(models *ModelRegistry, checkpoints checkpoint.Store, thinker *internal.ThinkerClauses) (*RoundManager, error) {
+	cfg, err := RoundConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	aggregator := internal.NewAggregator(internal.Regional)
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_DP_LEDGER_PATH")); raw != "" {
+		path, err := sanitizePathInput(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_DP_LEDGER_PATH: %w", err)
+		}
+		if aggregator, err = internal.NewPersistentAggregator(internal.Regional, path); err != nil {
+			return nil, err
+		}
+	}
+	aggregator.Thinker = thinker
+	rounds, err := NewRoundManager(cfg, aggregator)
+	if err != nil {
+		return nil, err
+	}
+	publisher := &modelPublisher{registry: models, checkpoints: checkpoints, aggregator: aggregator}
+	rounds.SetCommitHook(publisher.publish, models.ForRound)
+	return rounds, nil
+}
+
+// parseKeyOverlap reads the rotation overlap window (default 24h).
+func parseKeyOverlap(value string) time.Duration {
+	if parsed, err := time.ParseDuration(strings.TrimSpace(value)); err == nil && parsed >= 0 {
+		return parsed
+	}
+	return defaultKeyOverlap
 }
 
 func defaultPort(value string, fallback int) int {
@@ -343,47 +558,44 @@ func observePQCPolicyMetrics() {
 	}
 }
 
-func observeThinkerClausesFromCapabilities(path string) {
-	cleanPath, err := sanitizePathInput(path)
-	if err != nil {
-		log.Printf("warning: invalid capabilities path %q: %v", path, err)
-		return
-	}
-
-	type thinkerClauses struct {
-		Enabled                         bool    `json:"enabled"`
-		PreserveOutliers                bool    `json:"preserve_outliers"`
-		MinorityRetentionMin            float64 `json:"minority_retention_min"`
-		MinorityRetentionMax            float64 `json:"minority_retention_max"`
-		OutlierDistanceZScoreCap        float64 `json:"outlier_distance_zscore_cap"`
-		ManualReviewRequiredAboveZScore float64 `json:"manual_review_required_above_zscore"`
+// loadThinkerClauses loads the thinker clauses from the capabilities file at
+// MOHAWK_CAPABILITIES_PATH. Without that setting no clauses apply.
+func loadThinkerClauses() (*internal.ThinkerClauses, error) {
+	raw := strings.TrimSpace(os.Getenv("MOHAWK_CAPABILITIES_PATH"))
+	if raw == "" {
+		log.Printf("thinker clauses disabled: MOHAWK_CAPABILITIES_PATH is not set")
+		return nil, nil
 	}
-	type capabilities struct {
-		Thinker thinkerClauses `json:"thinker_clauses"`
+	path, err := sanitizePathInput(raw)
+	if err != nil {
+		return nil, fmt.Errorf("MOHAWK_CAPABILITIES_PATH: %w", err)
 	}
-
-	raw, err := os.ReadFile(cleanPath)
+	thinker, err := internal.LoadThinkerClauses(path)
 	if err != nil {
-		return
+		return nil, err
 	}
-	var cfg capabilities
-	if err := json.Unmarshal(raw, &cfg); err != nil {
-		return
+	if !thinker.Enabled {
+		return nil, nil
 	}
-	if cfg.Thinker.Enabled {
-		metrics.ObserveThinkerClauseValue("enabled", 1)
-	} else {
+	return &thinker, nil
+}
+
+// observeThinkerClauses publishes the clauses the aggregator applies.
+func observeThinkerClauses(thinker *internal.ThinkerClauses) {
+	if thinker == nil {
 		metrics.ObserveThinkerClauseValue("enabled", 0)
+		return
 	}
-	if cfg.Thinker.PreserveOutliers {
+	metrics.ObserveThinkerClauseValue("enabled", 1)
+	if thinker.PreserveOutliers {
 		metrics.ObserveThinkerClauseValue("preserve_outliers", 1)
 	} else {
 		metrics.ObserveThinkerClauseValue("preserve_outliers", 0)
 	}
-	metrics.ObserveThinkerClauseValue("minority_retention_min", cfg.Thinker.MinorityRetentionMin)
-	metrics.ObserveThinkerClauseValue("minority_retention_max", cfg.Thinker.MinorityRetentionMax)
-	metrics.ObserveThinkerClauseValue("outlier_distance_zscore_cap", cfg.Thinker.OutlierDistanceZScoreCap)
-	metrics.ObserveThinkerClauseValue("manual_review_required_above_zscore", cfg.Thinker.ManualReviewRequiredAboveZScore)
+	metrics.ObserveThinkerClauseValue("minority_retention_min", thinker.MinorityRetentionMin)
+	metrics.ObserveThinkerClauseValue("minority_retention_max", thinker.MinorityRetentionMax)
+	metrics.ObserveThinkerClauseValue("outlier_distance_zscore_cap", thinker.OutlierDistanceZScoreCap)
+	metrics.ObserveThinkerClauseValue("manual_review_required_above_zscore", thinker.ManualReviewRequiredAboveZScore)
 }
 
 func sanitizePathInput(raw string) (string, error) {
@@ -0,0 +1,467 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+var (
+	// ErrUnknownModelVersion is returned for a version the registry never issued.
+	ErrUnknownModelVersion = errors.New("unknown model version")
+	// ErrRoundAlreadyPublished is returned when a round already has a version.
+	ErrRoundAlreadyPublished = errors.New("round already has a model version")
+	// ErrHeadMoved is returned when a delta was built against a version that
+	// is no longer the head.
+	ErrHeadMoved = errors.New("model head moved")
+)
+
+// Model version kinds: how to read the checkpoint a version names.
+const (
+	// ModelKindFull is a complete model, such as a genesis checkpoint.
+	ModelKindFull = "full"
+	// ModelKindDelta is an aggregated update to add to the parent version's
+	// model; a delta without a parent applies to the zero model.
+	ModelKindDelta = "delta"
+)
+
+// AggregatorSettings records how a model version was aggregated.
+type AggregatorSettings struct {
+	Tier            int     `json:"tier"`
+	ClipNorm        float64 `json:"clip_norm"`
+	NoiseMultiplier float64 `json:"noise_multiplier"`
+	SamplingRate    float64 `json:"sampling_rate"`
+	InputCount      int     `json:"input_count"`
+	SelectedCount   int     `json:"selected_count"`
+	MultiKrum       bool    `json:"multi_krum,omitempty"`
+}
+
+// ModelVersion is one signed global checkpoint. ParentVersion is the version
+// its round trained on (0 for a genesis model) and ParentCID that version's
+// checkpoint. Kind says whether the checkpoint is a full model or a delta
+// over the parent; empty reads as full. CID is empty when no checkpoint store
+// is configured, in which case SHA256 still identifies it.
+type ModelVersion struct {
+	ModelID       string             `json:"model_id"`
+	Version       uint64             `json:"version"`
+	Kind          string             `json:"kind,omitempty"`
+	ParentVersion uint64             `json:"parent_version,omitempty"`
+	ParentCID     string             `json:"parent_cid,omitempty"`
+	CID           string             `json:"cid,omitempty"`
+	SHA256        string             `json:"sha256,omitempty"`
+	RoundID       uint64             `json:"round_id,omitempty"`
+	Participants  int                `json:"participants"`
+	EpsilonSpent  float64            `json:"epsilon_spent"`
+	Aggregator    AggregatorSettings `json:"aggregator"`
+	CreatedAt     time.Time          `json:"created_at"`
+	KeyID         string             `json:"key_id"`
+	Signature     []byte             `json:"signature,omitempty"`
+}
+
+// SigningBytes is the RFC 8785 canonical JSON of v without its signature.
+func (v ModelVersion) SigningBytes() ([]byte, error) {
+	v.Signature = nil
+	return manifest.CanonicalJSON(v)
+}
+
+// Verify checks v's signature against the key set key named by v.KeyID,
+// which must have been valid when v was created.
+func (v ModelVersion) Verify(ks *manifest.KeySet) error {
+	pk, err := ks.Key(v.KeyID, v.CreatedAt)
+	if err != nil {
+		return err
+	}
+	data, err := v.SigningBytes()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pk, data, v.Signature) {
+		return fmt.Errorf("invalid signature on model version %d", v.Version)
+	}
+	return nil
+}
+
+// ModelStatus is the registry's head and pin state.
+type ModelStatus struct {
+	ModelID string `json:"model_id"`
+	// Head is the version nodes train on and new versions descend from.
+	Head uint64 `json:"head"`
+	// Pinned keeps Head in place as new versions are registered.
+	Pinned bool `json:"pinned"`
+	// Versions is the number of versions issued.
+	Versions int `json:"versions"`
+}
+
+// registryFile is the persisted form of a ModelRegistry.
+type registryFile struct {
+	ModelID  string          `json:"model_id"`
+	Head     uint64          `json:"head"`
+	Pinned   bool            `json:"pinned"`
+	Versions []*ModelVersion `json:"versions"`
+}
+
+// ModelRegistry records the lineage of one global model. Versions are
+// numbered from 1 and never change once signed; the head moves forward as
+// rounds commit, and can be pinned or rolled back to an earlier version.
+type ModelRegistry struct {
+	mu       sync.RWMutex
+	path     string
+	keys     *manifest.Keystore
+	now      func() time.Time
+	modelID  string
+	head     uint64
+	pinned   bool
+	versions []*ModelVersion // versions[i].Version == i+1
+}
+
+// NewModelRegistry returns the registry for modelID signed with keys. With a
+// path it resumes the persisted registry, which must be for the same model
+// and carry a valid signature from keys on every version.
+func NewModelRegistry(modelID, path string, keys *manifest.Keystore) (*ModelRegistry, error) {
+	if modelID == "" {
+		return nil, errors.New("model id is required")
+	}
+	r := &ModelRegistry{path: path, keys: keys, now: time.Now, modelID: modelID}
+	if path == "" {
+		return r, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read model registry %q: %w", path, err)
+	}
+	var state registryFile
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("decode model registry %q: %w", path, err)
+	}
+	if state.ModelID != modelID {
+		return nil, fmt.Errorf("model registry %q holds model %q, not %q", path, state.ModelID, modelID)
+	}
+	history := keys.History()
+	for i, v := range state.Versions {
+		if v.Version != uint64(i+1) {
+			return nil, fmt.Errorf("model registry %q: version %d out of sequence", path, v.Version)
+		}
+		if v.ModelID != modelID {
+			return nil, fmt.Errorf("model registry %q: version %d is for model %q", path, v.Version, v.ModelID)
+		}
+		if err := v.Verify(&history); err != nil {
+			return nil, fmt.Errorf("model registry %q: %w", path, err)
+		}
+	}
+	if state.Head > uint64(len(state.Versions)) {
+		return nil, fmt.Errorf("model registry %q: head %d was never issued", path, state.Head)
+	}
+	r.head, r.pinned, r.versions = state.Head, state.Pinned, state.Versions
+	return r, nil
+}
+
+// Register signs and records v as the next version, descending from the
+// current head. The head advances to it unless pinned. A delta must name the
+// head it was built against in v.ParentVersion, or ErrHeadMoved is returned.
+// A round publishes at most one version: registering v.RoundID again returns
+// the existing version with ErrRoundAlreadyPublished.
+func (r *ModelRegistry) Register(v ModelVersion) (ModelVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.forRoundLocked(v.RoundID); ok {
+		return existing, ErrRoundAlreadyPublished
+	}
+	if v.Kind == ModelKindDelta && v.ParentVersion != r.head {
+		return ModelVersion{}, fmt.Errorf("%w: delta built on version %d, head is %d", ErrHeadMoved, v.ParentVersion, r.head)
+	}
+
+	v.ModelID = r.modelID
+	v.Version = uint64(len(r.versions)) + 1
+	v.ParentVersion = r.head
+	v.ParentCID = ""
+	if r.head != 0 {
+		v.ParentCID = r.versions[r.head-1].CID
+	}
+	v.CreatedAt = r.now().UTC()
+	keyID, sig, err := r.keys.SignPayload(func(keyID string) ([]byte, error) {
+		v.KeyID = keyID
+		return v.SigningBytes()
+	})
+	if err != nil {
+		return ModelVersion{}, fmt.Errorf("sign model version: %w", err)
+	}
+	v.KeyID, v.Signature = keyID, sig
+
+	head := r.head
+	r.versions = append(r.versions, &v)
+	if !r.pinned {
+		r.head = v.Version
+	}
+	if err := r.persistLocked(); err != nil {
+		r.versions = r.versions[:len(r.versions)-1]
+		r.head = head
+		return ModelVersion{}, err
+	}
+	return v, nil
+}
+
+// Latest returns the head version, if any version has been registered.
+func (r *ModelRegistry) Latest() (ModelVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.head == 0 {
+		return ModelVersion{}, false
+	}
+	return *r.versions[r.head-1], true
+}
+
+// Version returns version n.
+func (r *ModelRegistry) Version(n uint64) (ModelVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n == 0 || n > uint64(len(r.versions)) {
+		return ModelVersion{}, false
+	}
+	return *r.versions[n-1], true
+}
+
+// ForRound returns the version published for round roundID, if any.
+func (r *ModelRegistry) ForRound(roundID uint64) (ModelVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.forRoundLocked(roundID)
+}
+
+func (r *ModelRegistry) forRoundLocked(roundID uint64) (ModelVersion, bool) {
+	if roundID == 0 {
+		return ModelVersion{}, false
+	}
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if r.versions[i].RoundID == roundID {
+			return *r.versions[i], true
+		}
+	}
+	return ModelVersion{}, false
+}
+
+// Versions returns every version, newest first.
+func (r *ModelRegistry) Versions() []ModelVersion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ModelVersion, 0, len(r.versions))
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		out = append(out, *r.versions[i])
+	}
+	return out
+}
+
+// Status returns the head and pin state.
+func (r *ModelRegistry) Status() ModelStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return ModelStatus{ModelID: r.modelID, Head: r.head, Pinned: r.pinned, Versions: len(r.versions)}
+}
+
+// Pin holds the head at version n: rounds keep training on it and the
+// versions they produce are recorded without becoming the head. Pin(0)
+// releases the pin and leaves the head where it is.
+func (r *ModelRegistry) Pin(n uint64) error {
+	if n == 0 {
+		return r.setHead(0, false)
+	}
+	return r.setHead(n, true)
+}
+
+// Rollback moves the head back to version n and releases any pin, so the
+// next committed round descends from n.
+func (r *ModelRegistry) Rollback(n uint64) error {
+	if n == 0 {
+		return ErrUnknownModelVersion
+	}
+	return r.setHead(n, false)
+}
+
+// setHead moves the head to n (kept when n is 0) and sets the pin
+func (r *ModelRegistry) setHead(n uint64, pinned bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > uint64(len(r.versions)) {
+		return ErrUnknownModelVersion
+	}
+	head, wasPinned := r.head, r.pinned
+	if n != 0 {
+		r.head = n
+	}
+	r.pinned = pinned
+	if err := r.persistLocked(); err != nil {
+		r.head, r.pinned = head, wasPinned
+		return err
+	}
+	return nil
+}
+
+func (r *ModelRegistry) persistLocked() error {
+	if r.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return fmt.Errorf("create model registry directory: %w", err)
+	}
+	raw, err := json.MarshalIndent(registryFile{ModelID: r.modelID, Head: r.head, Pinned: r.pinned, Versions: r.versions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode model registry: %w", err)
+	}
+	// Durable before the round commits, so a restart finds the version
+	return writeFileSync(r.path, raw)
+}
+
+// modelCheckpoint is the payload stored for each committed round: the
+// aggregate as a delta over the parent version's model
+type modelCheckpoint struct {
+	ModelID       string    `json:"model_id"`
+	RoundID       uint64    `json:"round_id"`
+	Kind          string    `json:"kind"`
+	ParentVersion uint64    `json:"parent_version,omitempty"`
+	ParentCID     string    `json:"parent_cid,omitempty"`
+	Aggregate     []float64 `json:"aggregate"`
+}
+
+// publishAttempts bounds retries when the head moves mid-publish
+const publishAttempts = 3
+
+// modelPublisher turns committed rounds into registry versions. The round's
+// aggregate is recorded as a delta over the head it trained on, stored as a
+// checkpoint when a store is configured.
+type modelPublisher struct {
+	registry    *ModelRegistry
+	checkpoints checkpoint.Store
+	aggregator  *internal.Aggregator
+}
+
+// roundDelta is one round's aggregate and how it was produced
+type roundDelta struct {
+	roundID      uint64
+	aggregate    []float64
+	participants int
+	epsilon      float64
+	settings     AggregatorSettings
+}
+
+// publish is the round manager's CommitHook. It is idempotent per round: a
+// round that already has a version gets that version back.
+func (p *modelPublisher) publish(round Round, result internal.BatchProcessingResult) (ModelVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return p.publishDelta(ctx, roundDelta{
+		roundID:      round.ID,
+		aggregate:    result.Aggregate,
+		participants: len(round.Submissions),
+		epsilon:      p.aggregator.Accountant.GetCurrentEpsilon(),
+		settings: AggregatorSettings{
+			Tier:            int(p.aggregator.Tier),
+			ClipNorm:        result.ClipNorm,
+			NoiseMultiplier: result.NoiseMultiplier,
+			SamplingRate:    p.aggregator.DPSamplingRate,
+			InputCount:      result.InputCount,
+			SelectedCount:   result.SelectedCount,
+			MultiKrum:       result.UsedMultiKrum,
+		},
+	})
+}
+
+// PublishAggregate registers a streaming aggregate as the version for round,
+// so the publisher can back an internal.ModelRegistrySink. Like publish it is
+// idempotent per round.
+func (p *modelPublisher) PublishAggregate(ctx context.Context, round uint64, agg internal.StreamingAggregate) error {
+	_, err := p.publishDelta(ctx, roundDelta{
+		roundID:      round,
+		aggregate:    agg.Gradient,
+		participants: len(agg.Participants),
+		epsilon:      agg.Epsilon,
+		settings: AggregatorSettings{
+			Tier:          int(agg.Tier),
+			InputCount:    len(agg.Participants),
+			SelectedCount: len(agg.Selected),
+		},
+	})
+	return err
+}
+
+// publishDelta registers d, retrying when the head moves mid-publish
+func (p *modelPublisher) publishDelta(ctx context.Context, d roundDelta) (ModelVersion, error) {
+	var err error
+	for range publishAttempts {
+		var v ModelVersion
+		v, err = p.publishOnce(ctx, d)
+		if !errors.Is(err, ErrHeadMoved) {
+			return v, err
+		}
+	}
+	return ModelVersion{}, err
+}
+
+// publishOnce stores the round's delta against the current head and
+// registers it
+func (p *modelPublisher) publishOnce(ctx context.Context, d roundDelta) (ModelVersion, error) {
+	if v, ok := p.registry.ForRound(d.roundID); ok {
+		return v, nil
+	}
+	modelID := p.registry.Status().ModelID
+	delta := modelCheckpoint{ModelID: modelID, RoundID: d.roundID, Kind: ModelKindDelta, Aggregate: d.aggregate}
+	if head, ok := p.registry.Latest(); ok {
+		delta.ParentVersion, delta.ParentCID = head.Version, head.CID
+	}
+	payload, err := json.Marshal(delta)
+	if err != nil {
+		return ModelVersion{}, fmt.Errorf("encode checkpoint: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+
+	var cid string
+	if p.checkpoints != nil {
+		name := fmt.Sprintf("%s-round-%d.json", modelID, d.roundID)
+		if cid, err = checkpoint.PutBytes(ctx, p.checkpoints, name, payload); err != nil {
+			return ModelVersion{}, fmt.Errorf("store checkpoint: %w", err)
+		}
+	}
+
+	v, err := p.registry.Register(ModelVersion{
+		Kind:          ModelKindDelta,
+		ParentVersion: delta.ParentVersion,
+		CID:           cid,
+		SHA256:        hex.EncodeToString(sum[:]),
+		RoundID:       d.roundID,
+		Participants:  d.participants,
+		EpsilonSpent:  d.epsilon,
+		Aggregator:    d.settings,
+	})
+	if errors.Is(err, ErrRoundAlreadyPublished) {
+		return v, nil
+	}
+	return v, err
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+func newTestKeystore(t *testing.T) *manifest.Keystore {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return manifest.NewKeystore(priv)
+}
+
+func TestModelRegistry_LineagePinAndRollback(t *testing.T) {
+	keys := newTestKeystore(t)
+	path := filepath.Join(t.TempDir(), "models.json")
+	reg, err := NewModelRegistry("global", path, keys)
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if _, ok := reg.Latest(); ok {
+		t.Fatal("expected an empty registry to have no latest model")
+	}
+
+	genesis, err := reg.Register(ModelVersion{CID: "bafy-genesis"})
+	if err != nil {
+		t.Fatalf("register genesis: %v", err)
+	}
+	v2, _ := reg.Register(ModelVersion{CID: "bafy-2", RoundID: 1, Participants: 3, EpsilonSpent: 0.4})
+	if genesis.Version != 1 || genesis.ParentVersion != 0 || v2.Version != 2 || v2.ParentVersion != 1 {
+		t.Fatalf("unexpected lineage: %+v then %+v", genesis, v2)
+	}
+	ks := keys.KeySet()
+	if err := v2.Verify(&ks); err != nil {
+		t.Fatalf("verify v2: %v", err)
+	}
+	tampered := v2
+	tampered.EpsilonSpent = 0
+	if err := tampered.Verify(&ks); err == nil {
+		t.Fatal("expected a tampered version to fail verification")
+	}
+
+	// Pinned: new versions descend from the pin and do not move the head
+	if err := reg.Pin(1); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	v3, _ := reg.Register(ModelVersion{CID: "bafy-3", RoundID: 2})
+	if head, _ := reg.Latest(); head.Version != 1 || v3.ParentVersion != 1 {
+		t.Fatalf("expected head to stay pinned at 1, got head %d parent %d", head.Version, v3.ParentVersion)
+	}
+	if err := reg.Pin(0); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	v4, _ := reg.Register(ModelVersion{CID: "bafy-4", RoundID: 3})
+	if head, _ := reg.Latest(); head.Version != 4 || v4.ParentVersion != 1 {
+		t.Fatalf("expected unpinned head to advance to 4 from 1, got head %d parent %d", head.Version, v4.ParentVersion)
+	}
+
+	if err := reg.Rollback(2); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if err := reg.Rollback(9); !errors.Is(err, ErrUnknownModelVersion) {
+		t.Fatalf("expected rollback to an unknown version to fail, got %v", err)
+	}
+
+	reloaded, err := NewModelRegistry("global", path, keys)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if st := reloaded.Status(); st.Head != 2 || st.Pinned || st.Versions != 4 {
+		t.Fatalf("unexpected status after reload: %+v", st)
+	}
+	if v, ok := reloaded.Version(3); !ok || v.Verify(&ks) != nil {
+		t.Fatalf("expected version 3 to survive reload with a valid signature, got %+v", v)
+	}
+	if _, err := NewModelRegistry("other", path, keys); err == nil {
+		t.Fatal("expected a registry for another model to be refused")
+	}
+
+	// Versions signed by a retired key still load; a forged one does not
+	for range 2 {
+		if _, err := keys.Rotate(0); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+	}
+	if ks := keys.KeySet(); len(ks.Keys) >= len(keys.History().Keys) {
+		t.Fatalf("test setup: expected the signing key to be retired, key set %+v", ks)
+	}
+	if _, err := NewModelRegistry("global", path, keys); err != nil {
+		t.Fatalf("expected versions signed by a retired key to load, got %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	forged := strings.Replace(string(raw), `"epsilon_spent": 0.4`, `"epsilon_spent": 0.1`, 1)
+	if forged == string(raw) {
+		t.Fatal("test setup: epsilon not found in registry file")
+	}
+	if err := os.WriteFile(path, []byte(forged), 0o600); err != nil {
+		t.Fatalf("forge registry: %v", err)
+	}
+	if _, err := NewModelRegistry("global", path, keys); err == nil || !strings.Contains(err.Error(), "invalid signature") {
+		t.Fatalf("expected a forged version to be refused on load, got %v", err)
+	}
+	if _, err := NewModelRegistry("global", path, newTestKeystore(t)); err == nil {
+		t.Fatal("expected versions from another key to be refused on load")
+	}
+}
+
+func TestModelPublisher_RegistersCommittedRounds(t *testing.T) {
+	keys := newTestKeystore(t)
+	reg, _ := NewModelRegistry("global", "", keys)
+	genesis, _ := reg.Register(ModelVersion{Kind: ModelKindFull, CID: "bafy-genesis"})
+	dir, err := checkpoint.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("new dir: %v", err)
+	}
+	store := checkpoint.NewStore(dir)
+	publisher := &modelPublisher{registry: reg, checkpoints: store, aggregator: internal.NewAggregator(internal.Regional)}
+
+	clock := time.Unix(1_700_000_000, 0)
+	m := newTestRounds(t, RoundConfig{Participants: 1, Quorum: 1}, &meanAggregator{}, &clock)
+	m.SetCommitHook(publisher.publish, reg.ForRound)
+	round, task := mustJoin(t, m, "node-a")
+	if err := m.Submit(round, "node-a", task, testNonce("node-a"), []float64{0.25, 0.75}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	m.Tick(clock)
+
+	committed, _ := m.Round(round)
+	head, ok := reg.Latest()
+	if !ok || committed.State != RoundCommitted || committed.Result.ModelVersion != head.Version {
+		t.Fatalf("expected round %d to publish the head version, got %+v and %+v", round, committed, head)
+	}
+	if head.RoundID != round || head.Participants != 1 || head.SHA256 == "" || head.CID == "" {
+		t.Fatalf("unexpected published version %+v", head)
+	}
+	// The aggregate is recorded as a delta over the head it trained on
+	if head.Kind != ModelKindDelta || head.ParentVersion != genesis.Version || head.ParentCID != "bafy-genesis" {
+		t.Fatalf("expected a delta over genesis, got %+v", head)
+	}
+	raw, err := checkpoint.ReadAll(context.Background(), store, head.CID, 0)
+	if err != nil {
+		t.Fatalf("read checkpoint: %v", err)
+	}
+	var delta modelCheckpoint
+	if err := json.Unmarshal(raw, &delta); err != nil || delta.Kind != ModelKindDelta || delta.ParentCID != "bafy-genesis" || len(delta.Aggregate) != 2 {
+		t.Fatalf("unexpected checkpoint %s (%v)", raw, err)
+	}
+
+	// A delta built against a head that has since moved is refused
+	if _, err := reg.Register(ModelVersion{Kind: ModelKindDelta, ParentVersion: genesis.Version, RoundID: 99}); !errors.Is(err, ErrHeadMoved) {
+		t.Fatalf("expected a stale delta to be refused, got %v", err)
+	}
+
+	m.SetCommitHook(func(Round, internal.BatchProcessingResult) (ModelVersion, error) {
+		return ModelVersion{}, errors.New("ipfs unavailable")
+	}, nil)
+	round, task = mustJoin(t, m, "node-a")
+	_ = m.Submit(round, "node-a", task, testNonce("node-a"), []float64{1, 1})
+	m.Tick(clock)
+	if failed, _ := m.Round(round); failed.State != RoundFailed || !strings.Contains(failed.Error, "publish failed") {
+		t.Fatalf("expected a publish error to fail round %d, got %+v", round, failed)
+	}
+}
+
+func TestModelPublisher_PublishesEachRoundOnce(t *testing.T) {
+	reg, _ := NewModelRegistry("global", "", newTestKeystore(t))
+	publisher := &modelPublisher{registry: reg, aggregator: internal.NewAggregator(internal.Regional)}
+
+	// Round 1 was published, then the orchestrator crashed before the round
+	// state recorded the commit; it resumes with round 1 still aggregating
+	published, err := reg.Register(ModelVersion{RoundID: 1, Participants: 1, Aggregator: AggregatorSettings{InputCount: 1, SelectedCount: 1}})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if again, err := reg.Register(ModelVersion{RoundID: 1}); !errors.Is(err, ErrRoundAlreadyPublished) || again.Version != published.Version {
+		t.Fatalf("expected a second version for round 1 to be refused, got %+v (%v)", again, err)
+	}
+
+	clock := time.Unix(1_700_000_000, 0)
+	agg := &meanAggregator{}
+	m := newTestRounds(t, RoundConfig{Participants: 1, Quorum: 1}, agg, &clock)
+	m.SetCommitHook(publisher.publish, reg.ForRound)
+	round, task := mustJoin(t, m, "node-a")
+	if err := m.Submit(round, "node-a", task, testNonce("node-a"), []float64{1}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	m.Tick(clock)
+
+	committed, _ := m.Round(round)
+	if committed.State != RoundCommitted || committed.Result.ModelVersion != published.Version || agg.calls != 0 {
+		t.Fatalf("expected round %d committed as version %d without aggregating, got %+v after %d calls", round, published.Version, committed, agg.calls)
+	}
+	if st := reg.Status(); st.Versions != 1 {
+		t.Fatalf("expected no duplicate version, got %d versions", st.Versions)
+	}
+}
+
+func TestModelRegistrySink_PublishesStreamingAggregates(t *testing.T) {
+	reg, _ := NewModelRegistry("global", "", newTestKeystore(t))
+	publisher := &modelPublisher{registry: reg}
+	open := true
+	sink := internal.ModelRegistrySink{Publisher: publisher, Round: func() (uint64, bool) { return 5, open }}
+
+	agg := internal.StreamingAggregate{
+		RoundID:      1,
+		Tier:         internal.Regional,
+		Gradient:     []float64{0.5, -0.5},
+		Participants: []string{"node-a", "node-b", "node-c"},
+		Selected:     []int{0, 2},
+		Epsilon:      0.25,
+	}
+	if err := sink.SubmitAggregate(context.Background(), agg); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	v, ok := reg.ForRound(5)
+	if !ok || v.Kind != ModelKindDelta || v.Participants != 3 || v.EpsilonSpent != 0.25 || v.SHA256 == "" {
+		t.Fatalf("expected the aggregate published as round 5, got %+v", v)
+	}
+	if v.Aggregator.InputCount != 3 || v.Aggregator.SelectedCount != 2 || v.Aggregator.Tier != int(internal.Regional) {
+		t.Fatalf("unexpected aggregator settings %+v", v.Aggregator)
+	}
+
+	// A repeated flush for the same round keeps the published version
+	if err := sink.SubmitAggregate(context.Background(), agg); err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if st := reg.Status(); st.Versions != 1 {
+		t.Fatalf("expected one version, got %d", st.Versions)
+	}
+
+	open = false
+	if err := sink.SubmitAggregate(context.Background(), agg); err == nil {
+		t.Fatal("expected an error when no round is open")
+	}
+}
+
+func TestHandleModels(t *testing.T) {
+	t.Setenv("MOHAWK_ALLOW_UNAUTH_ADMIN", "")
+	reg, _ := NewModelRegistry("global", "", newTestKeystore(t))
+	s := &Server{Models: reg, AdminToken: "secret"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/models", s.HandleModels)
+	mux.HandleFunc("/models/latest", s.HandleModelLatest)
+	mux.HandleFunc("/models/pin", s.HandleModelPin)
+	mux.HandleFunc("/models/rollback", s.HandleModelRollback)
+	mux.HandleFunc("/models/{version}", s.HandleModelVersion)
+
+	serve := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(http.MethodGet, "/models/latest", "", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any version, got %d", rr.Code)
+	}
+	_, _ = reg.Register(ModelVersion{CID: "bafy-1"})
+	_, _ = reg.Register(ModelVersion{CID: "bafy-2"})
+
+	var head ModelVersion
+	if err := json.NewDecoder(serve(http.MethodGet, "/models/latest", "", "").Body).Decode(&head); err != nil || head.Version != 2 {
+		t.Fatalf("expected latest version 2, got %+v (%v)", head, err)
+	}
+	if rr := serve(http.MethodGet, "/models/1", "", ""); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "bafy-1") {
+		t.Fatalf("expected version 1, got %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := serve(http.MethodGet, "/models/7", "", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown version, got %d", rr.Code)
+	}
+
+	if rr := serve(http.MethodPost, "/models/rollback", `{"version":1}`, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected rollback to require admin, got %d", rr.Code)
+	}
+	if rr := serve(http.MethodPost, "/models/rollback", `{"version":1}`, "secret"); rr.Code != http.StatusOK {
+		t.Fatalf("rollback: %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := serve(http.MethodPost, "/models/pin", `{"version":5}`, "secret"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected pinning an unknown version to 404, got %d", rr.Code)
+	}
+	rr := serve(http.MethodPost, "/models/pin", `{"version":2}`, "secret")
+	var status ModelStatus
+	if err := json.NewDecoder(rr.Body).Decode(&status); err != nil || status.Head != 2 || !status.Pinned {
+		t.Fatalf("expected head pinned at 2, got %+v (%v)", status, err)
+	}
+}
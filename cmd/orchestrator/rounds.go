@@ -0,0 +1,763 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+// RoundState is a training round's position in its lifecycle:
+// open → collecting → aggregating → committed or failed.
+type RoundState string
+
+const (
+	// RoundOpen admits participants; admitted nodes may already submit.
+	RoundOpen RoundState = "open"
+	// RoundCollecting admits no new participants and waits for submissions.
+	RoundCollecting RoundState = "collecting"
+	// RoundAggregating runs the submissions through the aggregator.
+	RoundAggregating RoundState = "aggregating"
+	// RoundCommitted holds the aggregate of a completed round.
+	RoundCommitted RoundState = "committed"
+	// RoundFailed ends a round that missed quorum or failed aggregation.
+	RoundFailed RoundState = "failed"
+)
+
+// Terminal reports whether no further transitions follow s.
+func (s RoundState) Terminal() bool {
+	return s == RoundCommitted || s == RoundFailed
+}
+
+// Errors returned by RoundManager.Join and RoundManager.Submit; the gradient
+// handler returns them to nodes as the ack reason.
+var (
+	ErrRoundNotOpen        = errors.New("round is not admitting participants")
+	ErrRoundFull           = errors.New("round has no free participant slots")
+	ErrAlreadySubmitted    = errors.New("node already submitted for this round")
+	ErrUnknownRound        = errors.New("unknown round")
+	ErrNotParticipant      = errors.New("node is not a participant in this round")
+	ErrTaskMismatch        = errors.New("task does not belong to this round and node")
+	ErrDuplicateSubmission = errors.New("duplicate submission")
+	ErrLateSubmission      = errors.New("submission arrived after the round closed")
+	ErrDimensionMismatch   = errors.New("gradient dimension does not match the round")
+	ErrBadCredential       = errors.New("submission does not carry a job nonce issued to this node")
+)
+
+// maxCredentials bounds the job nonces remembered per participant; a node
+// that re-fetches its job more often keeps only its latest ones.
+const maxCredentials = 8
+
+// RoundConfig sizes and times rounds.
+type RoundConfig struct {
+	// Participants is the number of nodes admitted per round (default 10).
+	Participants int
+	// Quorum is the minimum number of submissions aggregated (default 2,
+	// capped at Participants).
+	Quorum int
+	// OpenTimeout bounds how long a round admits participants (default 60s).
+	OpenTimeout time.Duration
+	// CollectTimeout bounds how long a full round waits for submissions
+	// (default 2m).
+	CollectTimeout time.Duration
+	// StatePath, when set, persists round state across restarts. Gradients
+	// are written one file per submission under StatePath + ".submissions".
+	StatePath string
+	// History is the number of finished rounds kept (default 64).
+	History int
+}
+
+func (c RoundConfig) withDefaults() RoundConfig {
+	if c.Participants <= 0 {
+		c.Participants = 10
+	}
+	if c.Quorum <= 0 {
+		c.Quorum = 2
+	}
+	if c.Quorum > c.Participants {
+		c.Quorum = c.Participants
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = time.Minute
+	}
+	if c.CollectTimeout <= 0 {
+		c.CollectTimeout = 2 * time.Minute
+	}
+	if c.History <= 0 {
+		c.History = 64
+	}
+	return c
+}
+
+// RoundConfigFromEnv reads MOHAWK_ROUND_PARTICIPANTS, MOHAWK_ROUND_QUORUM,
+// MOHAWK_ROUND_OPEN_TIMEOUT, MOHAWK_ROUND_COLLECT_TIMEOUT and
+// MOHAWK_ROUND_STATE_PATH; unset or invalid values keep their defaults.
+func RoundConfigFromEnv() (RoundConfig, error) {
+	var cfg RoundConfig
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("MOHAWK_ROUND_PARTICIPANTS"))); err == nil && n > 0 {
+		cfg.Participants = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("MOHAWK_ROUND_QUORUM"))); err == nil && n > 0 {
+		cfg.Quorum = n
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("MOHAWK_ROUND_OPEN_TIMEOUT"))); err == nil && d > 0 {
+		cfg.OpenTimeout = d
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("MOHAWK_ROUND_COLLECT_TIMEOUT"))); err == nil && d > 0 {
+		cfg.CollectTimeout = d
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_ROUND_STATE_PATH")); raw != "" {
+		path, err := sanitizePathInput(raw)
+		if err != nil {
+			return RoundConfig{}, fmt.Errorf("MOHAWK_ROUND_STATE_PATH: %w", err)
+		}
+		cfg.StatePath = path
+	}
+	return cfg.withDefaults(), nil
+}
+
+// Submission is one participant's gradient for a round.
+type Submission struct {
+	NodeID     string    `json:"node_id"`
+	TaskID     string    `json:"task_id"`
+	ReceivedAt time.Time `json:"received_at"`
+	// Gradients is held until the round is aggregated. It is persisted only
+	// in the submission's own file, never in the round state, and never
+	// served.
+	Gradients []float64 `json:"-"`
+}
+
+// submissionFile is the persisted form of one Submission
+type submissionFile struct {
+	Submission
+	Gradients []float64 `json:"gradients"`
+}
+
+// RoundResult summarizes a committed round's aggregate.
+type RoundResult struct {
+	Dimension       int     `json:"dimension"`
+	InputCount      int     `json:"input_count"`
+	SelectedCount   int     `json:"selected_count"`
+	NoiseMultiplier float64 `json:"noise_multiplier"`
+	AggregateSHA256 string  `json:"aggregate_sha256"`
+	// ModelVersion and ModelCID name the registry version the commit hook
+	// published for this round.
+	ModelVersion uint64 `json:"model_version,omitempty"`
+	ModelCID     string `json:"model_cid,omitempty"`
+}
+
+// Round is one training round. Participants are in admission order.
+type Round struct {
+	ID           uint64                 `json:"id"`
+	State        RoundState             `json:"state"`
+	OpenedAt     time.Time              `json:"opened_at"`
+	Deadline     time.Time              `json:"deadline"`
+	ClosedAt     time.Time              `json:"closed_at,omitzero"`
+	Participants []string               `json:"participants"`
+	Submissions  map[string]*Submission `json:"submissions"`
+	// Credentials holds SHA-256 digests of the manifest nonces issued to each
+	// participant. A submission must present one of them; they are never
+	// served.
+	Credentials map[string][]string `json:"credentials,omitempty"`
+	Result      *RoundResult        `json:"result,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// TaskIDFor is the task a round assigns to nodeID.
+func TaskIDFor(roundID uint64, nodeID string) string {
+	return fmt.Sprintf("round-%d-%s", roundID, nodeID)
+}
+
+// credentialDigest is the stored form of a job nonce
+func credentialDigest(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *Round) addCredential(nodeID, nonce string) {
+	if r.Credentials == nil {
+		r.Credentials = map[string][]string{}
+	}
+	creds := append(r.Credentials[nodeID], credentialDigest(nonce))
+	if over := len(creds) - maxCredentials; over > 0 {
+		creds = creds[over:]
+	}
+	r.Credentials[nodeID] = creds
+}
+
+func (r *Round) hasCredential(nodeID, nonce string) bool {
+	if nonce == "" {
+		return false
+	}
+	digest := credentialDigest(nonce)
+	for _, c := range r.Credentials[nodeID] {
+		if subtle.ConstantTimeCompare([]byte(c), []byte(digest)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Round) isParticipant(nodeID string) bool {
+	for _, p := range r.Participants {
+		if p == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// view returns a deep copy of r without submitted gradients or credentials.
+func (r *Round) view() Round {
+	out := *r
+	out.Credentials = nil
+	out.Participants = append([]string(nil), r.Participants...)
+	out.Submissions = make(map[string]*Submission, len(r.Submissions))
+	for id, sub := range r.Submissions {
+		copied := *sub
+		copied.Gradients = nil
+		out.Submissions[id] = &copied
+	}
+	if r.Result != nil {
+		result := *r.Result
+		out.Result = &result
+	}
+	return out
+}
+
+// batchAggregator is the part of internal.Aggregator a round drives.
+type batchAggregator interface {
+	ProcessGradientBatch(updates [][]float64, totalNodes int, opts internal.BatchProcessingOptions) (internal.BatchProcessingResult, error)
+}
+
+// CommitHook publishes an aggregated round before it commits. An error fails
+// the round.
+type CommitHook func(round Round, result internal.BatchProcessingResult) (ModelVersion, error)
+
+// PublishedLookup returns the version already published for a round, so a
+// round that was published before a crash is not aggregated again.
+type PublishedLookup func(roundID uint64) (ModelVersion, bool)
+
+// roundFile is the persisted form of a RoundManager.
+type roundFile struct {
+	Current *Round   `json:"current"`
+	History []*Round `json:"history"`
+}
+
+// RoundManager owns the orchestrator's training rounds. Exactly one round is
+// current; when it commits or fails the next round opens. Nodes join the
+// current round through /jobs/next and submit over the gradient protocol.
+type RoundManager struct {
+	cfg        RoundConfig
+	aggregator batchAggregator
+	commit     CommitHook
+	published  PublishedLookup
+	now        func() time.Time
+
+	tickMu  sync.Mutex // serializes Tick, which aggregates outside mu
+	mu      sync.Mutex
+	current *Round
+	history []*Round // finished rounds, oldest first
+	wake    chan struct{}
+}
+
+// NewRoundManager returns a manager that aggregates with aggregator. With
+// cfg.StatePath set it resumes the persisted rounds; otherwise round 1 opens.
+func NewRoundManager(cfg RoundConfig, aggregator batchAggregator) (*RoundManager, error) {
+	return newRoundManager(cfg, aggregator, time.Now)
+}
+
+func newRoundManager(cfg RoundConfig, aggregator batchAggregator, now func() time.Time) (*RoundManager, error) {
+	m := &RoundManager{
+		cfg:        cfg.withDefaults(),
+		aggregator: aggregator,
+		now:        now,
+		wake:       make(chan struct{}, 1),
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	if m.current == nil {
+		m.openLocked(1, m.now())
+		if err := m.persistLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// SetCommitHook sets the hook run for each aggregated round. published, when
+// set, is consulted before aggregating: a round it already knows is committed
+// with that version instead of being aggregated, charged and published again.
+func (m *RoundManager) SetCommitHook(hook CommitHook, published PublishedLookup) {
+	m.tickMu.Lock()
+	defer m.tickMu.Unlock()
+	m.commit = hook
+	m.published = published
+}
+
+// Join admits nodeID to the current round and returns its round ID and task
+// ID. nonce is the nonce of the signed manifest the node is issued; only
+// submissions carrying it are accepted for the node. Joining again is
+// idempotent until the node has submitted, and adds the new nonce.
+func (m *RoundManager) Join(nodeID, nonce string) (uint64, string, error) {
+	if nonce == "" {
+		return 0, "", errors.New("job nonce is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.current
+	if _, ok := r.Submissions[nodeID]; ok {
+		return 0, "", ErrAlreadySubmitted
+	}
+	rejoin := r.isParticipant(nodeID) && (r.State == RoundOpen || r.State == RoundCollecting)
+	if !rejoin {
+		if r.State != RoundOpen {
+			return 0, "", ErrRoundNotOpen
+		}
+		if len(r.Participants) >= m.cfg.Participants {
+			return 0, "", ErrRoundFull
+		}
+		r.Participants = append(r.Participants, nodeID)
+		if len(r.Participants) == m.cfg.Participants {
+			m.collectLocked(r, m.now())
+		}
+	}
+	r.addCredential(nodeID, nonce)
+	if err := m.persistLocked(); err != nil {
+		return 0, "", err
+	}
+	return r.ID, TaskIDFor(r.ID, nodeID), nil
+}
+
+// Submit records nodeID's gradient for taskID in round roundID. nonce must
+// be one issued to nodeID by Join, since node and task IDs are self-reported.
+// The round moves to aggregating once every participant has submitted.
+func (m *RoundManager) Submit(roundID uint64, nodeID, taskID, nonce string, gradients []float64) error {
+	if len(gradients) == 0 {
+		return errors.New("empty gradient")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.current
+	switch {
+	case roundID < r.ID:
+		return ErrLateSubmission
+	case roundID > r.ID:
+		return ErrUnknownRound
+	case !r.isParticipant(nodeID):
+		return ErrNotParticipant
+	case taskID != TaskIDFor(r.ID, nodeID):
+		return ErrTaskMismatch
+	case !r.hasCredential(nodeID, nonce):
+		return ErrBadCredential
+	}
+	if _, ok := r.Submissions[nodeID]; ok {
+		return ErrDuplicateSubmission
+	}
+	now := m.now()
+	switch {
+	case r.State == RoundCollecting && now.After(r.Deadline):
+		return ErrLateSubmission
+	case r.State != RoundOpen && r.State != RoundCollecting:
+		return ErrLateSubmission
+	}
+	for _, sub := range r.Submissions {
+		if len(sub.Gradients) != len(gradients) {
+			return ErrDimensionMismatch
+		}
+		break
+	}
+
+	sub := &Submission{
+		NodeID:     nodeID,
+		TaskID:     taskID,
+		ReceivedAt: now.UTC(),
+		Gradients:  append([]float64(nil), gradients...),
+	}
+	// Each submission is written once to its own file, so persisting a round
+	// costs one write per gradient rather than rewriting all of them
+	if err := m.persistSubmission(r.ID, sub); err != nil {
+		return err
+	}
+	r.Submissions[nodeID] = sub
+	if r.State == RoundCollecting && len(r.Submissions) == len(r.Participants) {
+		r.State = RoundAggregating
+		m.signal()
+	}
+	return nil
+}
+
+// Tick applies deadlines at now, aggregates a round that is ready and opens
+// the next round once the current one is finished.
+func (m *RoundManager) Tick(now time.Time) {
+	m.tickMu.Lock()
+	defer m.tickMu.Unlock()
+
+	m.mu.Lock()
+	r := m.current
+	switch r.State {
+	case RoundOpen:
+		if now.After(r.Deadline) {
+			if len(r.Participants) < m.cfg.Quorum {
+				m.failLocked(r, now, fmt.Sprintf("%d of %d participants joined before the deadline", len(r.Participants), m.cfg.Quorum))
+			} else {
+				m.collectLocked(r, now)
+			}
+		}
+	case RoundCollecting:
+		switch {
+		case len(r.Submissions) == len(r.Participants):
+			r.State = RoundAggregating
+		case now.After(r.Deadline):
+			if len(r.Submissions) < m.cfg.Quorum {
+				m.failLocked(r, now, fmt.Sprintf("%d of %d submissions arrived before the deadline", len(r.Submissions), m.cfg.Quorum))
+			} else {
+				r.State = RoundAggregating
+			}
+		}
+	}
+
+	if r.State == RoundAggregating && m.published != nil {
+		if v, ok := m.published(r.ID); ok {
+			log.Printf("round %d: already published as model version %d; committing without re-aggregating", r.ID, v.Version)
+			m.commitLocked(r, now, internal.BatchProcessingResult{
+				InputCount:      v.Aggregator.InputCount,
+				SelectedCount:   v.Aggregator.SelectedCount,
+				NoiseMultiplier: v.Aggregator.NoiseMultiplier,
+			}, v)
+		}
+	}
+	if r.State == RoundAggregating {
+		updates := make([][]float64, 0, len(r.Submissions))
+		for _, nodeID := range r.Participants {
+			if sub, ok := r.Submissions[nodeID]; ok {
+				updates = append(updates, sub.Gradients)
+			}
+		}
+		total := len(r.Participants)
+		view := r.view()
+		if err := m.persistLocked(); err != nil {
+			log.Printf("round %d: persist before aggregation failed: %v", r.ID, err)
+		}
+		m.mu.Unlock()
+		var published ModelVersion
+		result, err := m.aggregator.ProcessGradientBatch(updates, total, internal.BatchProcessingOptions{})
+		if err != nil {
+			err = fmt.Errorf("aggregation failed: %w", err)
+		} else if m.commit != nil {
+			if published, err = m.commit(view, result); err != nil {
+				err = fmt.Errorf("publish failed: %w", err)
+			}
+		}
+		m.mu.Lock()
+		if err != nil {
+			m.failLocked(r, now, err.Error())
+		} else {
+			m.commitLocked(r, now, result, published)
+		}
+	}
+
+	finished := r.State.Terminal()
+	if finished {
+		log.Printf("round %d %s: participants=%d submissions=%d %s", r.ID, r.State, len(r.Participants), len(r.Submissions), r.Error)
+		m.finishLocked(r)
+		m.openLocked(r.ID+1, now)
+	}
+	if err := m.persistLocked(); err != nil {
+		log.Printf("round %d: persist failed: %v", m.current.ID, err)
+	} else if finished && m.cfg.StatePath != "" {
+		if err := os.RemoveAll(m.submissionDir(r.ID)); err != nil {
+			log.Printf("round %d: remove submissions failed: %v", r.ID, err)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// Run ticks every interval, and immediately when a round becomes ready to
+// aggregate, until ctx is done.
+func (m *RoundManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+		m.Tick(m.now())
+	}
+}
+
+// Current returns the current round.
+func (m *RoundManager) Current() Round {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current.view()
+}
+
+// Round returns round id if it is current or still in history.
+func (m *RoundManager) Round(id uint64) (Round, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current.ID == id {
+		return m.current.view(), true
+	}
+	for _, r := range m.history {
+		if r.ID == id {
+			return r.view(), true
+		}
+	}
+	return Round{}, false
+}
+
+// Rounds returns the current round followed by history, newest first.
+func (m *RoundManager) Rounds() []Round {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Round, 0, len(m.history)+1)
+	out = append(out, m.current.view())
+	for i := len(m.history) - 1; i >= 0; i-- {
+		out = append(out, m.history[i].view())
+	}
+	return out
+}
+
+func (m *RoundManager) signal() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *RoundManager) openLocked(id uint64, now time.Time) {
+	now = now.UTC()
+	m.current = &Round{
+		ID:          id,
+		State:       RoundOpen,
+		OpenedAt:    now,
+		Deadline:    now.Add(m.cfg.OpenTimeout),
+		Submissions: map[string]*Submission{},
+	}
+}
+
+func (m *RoundManager) collectLocked(r *Round, now time.Time) {
+	r.State = RoundCollecting
+	r.Deadline = now.UTC().Add(m.cfg.CollectTimeout)
+	if len(r.Submissions) == len(r.Participants) {
+		r.State = RoundAggregating
+		m.signal()
+	}
+}
+
+func (m *RoundManager) failLocked(r *Round, now time.Time, reason string) {
+	r.State = RoundFailed
+	r.ClosedAt = now.UTC()
+	r.Error = reason
+}
+
+func (m *RoundManager) commitLocked(r *Round, now time.Time, result internal.BatchProcessingResult, published ModelVersion) {
+	r.State = RoundCommitted
+	r.ClosedAt = now.UTC()
+	r.Result = &RoundResult{
+		Dimension:       len(result.Aggregate),
+		InputCount:      result.InputCount,
+		SelectedCount:   result.SelectedCount,
+		NoiseMultiplier: result.NoiseMultiplier,
+		ModelVersion:    published.Version,
+		ModelCID:        published.CID,
+	}
+	// A round recovered from its published version has no aggregate in hand
+	if len(result.Aggregate) > 0 {
+		r.Result.AggregateSHA256 = aggregateDigest(result.Aggregate)
+	}
+}
+
+// finishLocked moves r to history, dropping its gradients and credentials.
+func (m *RoundManager) finishLocked(r *Round) {
+	for _, sub := range r.Submissions {
+		sub.Gradients = nil
+	}
+	r.Credentials = nil
+	m.history = append(m.history, r)
+	if over := len(m.history) - m.cfg.History; over > 0 {
+		m.history = append([]*Round(nil), m.history[over:]...)
+	}
+}
+
+// aggregateDigest hashes the aggregate as little-endian float64s.
+func aggregateDigest(aggregate []float64) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, v := range aggregate {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (m *RoundManager) load() error {
+	if m.cfg.StatePath == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(m.cfg.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read round state %q: %w", m.cfg.StatePath, err)
+	}
+	var state roundFile
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return fmt.Errorf("decode round state %q: %w", m.cfg.StatePath, err)
+	}
+	if state.Current == nil {
+		return fmt.Errorf("round state %q has no current round", m.cfg.StatePath)
+	}
+	if state.Current.Submissions == nil {
+		state.Current.Submissions = map[string]*Submission{}
+	}
+	sort.Slice(state.History, func(i, j int) bool { return state.History[i].ID < state.History[j].ID })
+	m.current = state.Current
+	m.history = state.History
+	return m.loadSubmissions(m.current)
+}
+
+// loadSubmissions restores r's submissions from their files, which Submit
+// writes without touching the round state.
+func (m *RoundManager) loadSubmissions(r *Round) error {
+	dir := m.submissionDir(r.ID)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read round %d submissions: %w", r.ID, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("read round %d submission: %w", r.ID, err)
+		}
+		var file submissionFile
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return fmt.Errorf("decode round %d submission %s: %w", r.ID, entry.Name(), err)
+		}
+		if !r.isParticipant(file.NodeID) {
+			return fmt.Errorf("round %d submission %s is from non-participant %q", r.ID, entry.Name(), file.NodeID)
+		}
+		sub := file.Submission
+		sub.Gradients = file.Gradients
+		r.Submissions[sub.NodeID] = &sub
+	}
+	return nil
+}
+
+// submissionDir holds round id's submission files.
+func (m *RoundManager) submissionDir(id uint64) string {
+	return filepath.Join(m.cfg.StatePath+".submissions", strconv.FormatUint(id, 10))
+}
+
+func (m *RoundManager) persistSubmission(roundID uint64, sub *Submission) error {
+	if m.cfg.StatePath == "" {
+		return nil
+	}
+	dir := m.submissionDir(roundID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create submission directory: %w", err)
+	}
+	raw, err := json.Marshal(submissionFile{Submission: *sub, Gradients: sub.Gradients})
+	if err != nil {
+		return fmt.Errorf("encode submission: %w", err)
+	}
+	name := sha256.Sum256([]byte(sub.NodeID))
+	return writeFileSync(filepath.Join(dir, hex.EncodeToString(name[:])+".json"), raw)
+}
+
+func (m *RoundManager) persistLocked() error {
+	if m.cfg.StatePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.cfg.StatePath), 0o700); err != nil {
+		return fmt.Errorf("create round state directory: %w", err)
+	}
+	raw, err := json.Marshal(roundFile{Current: m.current, History: m.history})
+	if err != nil {
+		return fmt.Errorf("encode round state: %w", err)
+	}
+	return writeFileSync(m.cfg.StatePath, raw)
+}
+
+// writeFileSync replaces path with raw through a fsynced temp file and
+// fsyncs the directory, so a crash leaves either the old or new content.
+func writeFileSync(path string, raw []byte) error {
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file for %s: %w", path, err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if err := f.Chmod(0o600); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("chmod %s: %w", tmp, err)
+	}
+	if _, err := f.Write(raw); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fsync %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("commit %s: %w", path, err)
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("fsync %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+// meanAggregator averages updates, or fails with err when set
+type meanAggregator struct {
+	calls int
+	err   error
+}
+
+func (a *meanAggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, _ internal.BatchProcessingOptions) (internal.BatchProcessingResult, error) {
+	a.calls++
+	if a.err != nil {
+		return internal.BatchProcessingResult{}, a.err
+	}
+	mean := make([]float64, len(updates[0]))
+	for _, u := range updates {
+		for i, v := range u {
+			mean[i] += v / float64(len(updates))
+		}
+	}
+	return internal.BatchProcessingResult{InputCount: len(updates), SelectedCount: len(updates), Aggregate: mean}, nil
+}
+
+func newTestRounds(t *testing.T, cfg RoundConfig, agg batchAggregator, clock *time.Time) *RoundManager {
+	t.Helper()
+	m, err := newRoundManager(cfg, agg, func() time.Time { return *clock })
+	if err != nil {
+		t.Fatalf("new round manager: %v", err)
+	}
+	return m
+}
+
+// testNonce stands in for the manifest nonce /jobs/next issues nodeID
+func testNonce(nodeID string) string {
+	return "nonce-" + nodeID
+}
+
+func mustJoin(t *testing.T, m *RoundManager, nodeID string) (uint64, string) {
+	t.Helper()
+	round, task, err := m.Join(nodeID, testNonce(nodeID))
+	if err != nil {
+		t.Fatalf("join %s: %v", nodeID, err)
+	}
+	return round, task
+}
+
+func TestRoundManager_CommitsWhenAllParticipantsSubmit(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	agg := &meanAggregator{}
+	m := newTestRounds(t, RoundConfig{Participants: 2, Quorum: 2}, agg, &clock)
+
+	round, taskA := mustJoin(t, m, "node-a")
+	if again, task, _ := m.Join("node-a", testNonce("node-a")); again != round || task != taskA {
+		t.Fatalf("expected an idempotent join, got round %d task %s", again, task)
+	}
+	_, taskB := mustJoin(t, m, "node-b")
+	if m.Current().State != RoundCollecting {
+		t.Fatalf("expected a full round to collect, got %s", m.Current().State)
+	}
+	if _, _, err := m.Join("node-c", testNonce("node-c")); !errors.Is(err, ErrRoundNotOpen) {
+		t.Fatalf("expected a collecting round to refuse joins, got %v", err)
+	}
+
+	if err := m.Submit(round, "node-a", taskB, testNonce("node-a"), []float64{1, 2}); !errors.Is(err, ErrTaskMismatch) {
+		t.Fatalf("expected another node's task to be refused, got %v", err)
+	}
+	if err := m.Submit(round, "node-c", TaskIDFor(round, "node-c"), testNonce("node-c"), []float64{1, 2}); !errors.Is(err, ErrNotParticipant) {
+		t.Fatalf("expected a non-participant to be refused, got %v", err)
+	}
+	if err := m.Submit(round, "node-a", taskA, testNonce("node-a"), []float64{1, 2}); err != nil {
+		t.Fatalf("submit node-a: %v", err)
+	}
+	if err := m.Submit(round, "node-a", taskA, testNonce("node-a"), []float64{1, 2}); !errors.Is(err, ErrDuplicateSubmission) {
+		t.Fatalf("expected a duplicate to be refused, got %v", err)
+	}
+	if _, _, err := m.Join("node-a", testNonce("node-a")); !errors.Is(err, ErrAlreadySubmitted) {
+		t.Fatalf("expected a submitted node not to rejoin, got %v", err)
+	}
+	if err := m.Submit(round, "node-b", taskB, testNonce("node-b"), []float64{1, 2, 3}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected a dimension mismatch, got %v", err)
+	}
+	if err := m.Submit(round, "node-b", taskB, testNonce("node-b"), []float64{3, 4}); err != nil {
+		t.Fatalf("submit node-b: %v", err)
+	}
+
+	m.Tick(clock)
+	done, ok := m.Round(round)
+	if !ok || done.State != RoundCommitted || done.Result == nil || done.Result.InputCount != 2 || done.Result.Dimension != 2 {
+		t.Fatalf("expected round %d committed with 2 inputs, got %+v", round, done)
+	}
+	if done.Submissions["node-a"].Gradients != nil {
+		t.Fatal("expected gradients to be stripped from round views")
+	}
+	if next := m.Current(); next.ID != round+1 || next.State != RoundOpen {
+		t.Fatalf("expected round %d to open, got %d %s", round+1, next.ID, next.State)
+	}
+	if err := m.Submit(round, "node-b", taskB, testNonce("node-b"), []float64{3, 4}); !errors.Is(err, ErrLateSubmission) {
+		t.Fatalf("expected a submission to a finished round to be late, got %v", err)
+	}
+}
+
+func TestRoundManager_RejectsSpoofedNodeID(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	m := newTestRounds(t, RoundConfig{Participants: 2, Quorum: 2}, &meanAggregator{}, &clock)
+	round, taskA := mustJoin(t, m, "node-a")
+
+	// node-a's task ID is guessable; without node-a's job nonce a peer
+	// claiming to be node-a is refused and node-a can still submit
+	for _, nonce := range []string{"", testNonce("node-b"), "nonce-guess"} {
+		if err := m.Submit(round, "node-a", taskA, nonce, []float64{9, 9}); !errors.Is(err, ErrBadCredential) {
+			t.Fatalf("nonce %q: expected a spoofed submission to be refused, got %v", nonce, err)
+		}
+	}
+	if _, _, err := m.Join("node-a", "refetched"); err != nil {
+		t.Fatalf("rejoin: %v", err)
+	}
+	if err := m.Submit(round, "node-a", taskA, "refetched", []float64{1, 2}); err != nil {
+		t.Fatalf("expected node-a to submit with its latest job nonce, got %v", err)
+	}
+	if current := m.Current(); current.Credentials != nil {
+		t.Fatal("expected credentials to be stripped from round views")
+	}
+}
+
+func TestRoundManager_DeadlinesAndQuorum(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	agg := &meanAggregator{}
+	cfg := RoundConfig{Participants: 3, Quorum: 2, OpenTimeout: time.Minute, CollectTimeout: time.Minute}
+	m := newTestRounds(t, cfg, agg, &clock)
+
+	// One participant by the open deadline misses quorum
+	mustJoin(t, m, "node-a")
+	clock = clock.Add(2 * time.Minute)
+	m.Tick(clock)
+	if first, _ := m.Round(1); first.State != RoundFailed || first.Error == "" {
+		t.Fatalf("expected round 1 to fail below quorum, got %+v", first)
+	}
+
+	// Two participants close the open window; only one submits in time
+	round, taskA := mustJoin(t, m, "node-a")
+	_, taskB := mustJoin(t, m, "node-b")
+	clock = clock.Add(2 * time.Minute)
+	m.Tick(clock)
+	if m.Current().State != RoundCollecting {
+		t.Fatalf("expected round %d to collect, got %s", round, m.Current().State)
+	}
+	if err := m.Submit(round, "node-a", taskA, testNonce("node-a"), []float64{1}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	clock = clock.Add(2 * time.Minute)
+	if err := m.Submit(round, "node-b", taskB, testNonce("node-b"), []float64{1}); !errors.Is(err, ErrLateSubmission) {
+		t.Fatalf("expected a submission past the deadline to be late, got %v", err)
+	}
+	m.Tick(clock)
+	if r, _ := m.Round(round); r.State != RoundFailed || agg.calls != 0 {
+		t.Fatalf("expected round %d to fail without aggregating, got %s after %d calls", round, r.State, agg.calls)
+	}
+
+	// Aggregation errors fail the round
+	agg.err = errors.New("privacy guard triggered")
+	round, taskA = mustJoin(t, m, "node-a")
+	_, taskB = mustJoin(t, m, "node-b")
+	_, taskC := mustJoin(t, m, "node-c")
+	for node, task := range map[string]string{"node-a": taskA, "node-b": taskB, "node-c": taskC} {
+		if err := m.Submit(round, node, task, testNonce(node), []float64{1}); err != nil {
+			t.Fatalf("submit %s: %v", node, err)
+		}
+	}
+	m.Tick(clock)
+	if r, _ := m.Round(round); r.State != RoundFailed || agg.calls != 1 {
+		t.Fatalf("expected round %d to fail in aggregation, got %+v", round, r)
+	}
+}
+
+func TestRoundManager_ResumesAfterRestart(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	cfg := RoundConfig{Participants: 2, Quorum: 2, StatePath: filepath.Join(t.TempDir(), "rounds.json")}
+	m := newTestRounds(t, cfg, &meanAggregator{}, &clock)
+
+	round, taskA := mustJoin(t, m, "node-a")
+	_, taskB := mustJoin(t, m, "node-b")
+	if err := m.Submit(round, "node-a", taskA, testNonce("node-a"), []float64{1, 1}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	raw, err := os.ReadFile(cfg.StatePath)
+	if err != nil || strings.Contains(string(raw), "gradients") {
+		t.Fatalf("expected gradients to stay out of the round state, got %s (%v)", raw, err)
+	}
+
+	agg := &meanAggregator{}
+	restarted := newTestRounds(t, cfg, agg, &clock)
+	if err := restarted.Submit(round, "node-a", taskA, testNonce("node-a"), []float64{1, 1}); !errors.Is(err, ErrDuplicateSubmission) {
+		t.Fatalf("expected the persisted submission to survive, got %v", err)
+	}
+	if err := restarted.Submit(round, "node-b", taskB, testNonce("node-b"), []float64{3, 3}); err != nil {
+		t.Fatalf("submit after restart: %v", err)
+	}
+	restarted.Tick(clock)
+	if r, _ := restarted.Round(round); r.State != RoundCommitted || r.Result.InputCount != 2 {
+		t.Fatalf("expected round %d to commit both submissions, got %+v", round, r)
+	}
+	if _, err := os.Stat(restarted.submissionDir(round)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected committed round %d submissions to be removed, got %v", round, err)
+	}
+
+	again := newTestRounds(t, cfg, agg, &clock)
+	if again.Current().ID != round+1 {
+		t.Fatalf("expected round %d current after reload, got %d", round+1, again.Current().ID)
+	}
+	if r, ok := again.Round(round); !ok || r.State != RoundCommitted {
+		t.Fatalf("expected committed round %d in history after reload, got %+v", round, r)
+	}
+}
+
+func TestHandleRounds(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	m := newTestRounds(t, RoundConfig{Participants: 1, Quorum: 1}, &meanAggregator{}, &clock)
+	round, task := mustJoin(t, m, "node-a")
+	if err := m.Submit(round, "node-a", task, testNonce("node-a"), []float64{0.5}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	m.Tick(clock)
+	s := &Server{Rounds: m}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rounds", s.HandleRounds)
+	mux.HandleFunc("/rounds/{id}", s.HandleRound)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/rounds", nil))
+	var list struct {
+		Rounds []Round `json:"rounds"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("decode rounds: %v", err)
+	}
+	if len(list.Rounds) != 2 || list.Rounds[0].ID != 2 || list.Rounds[1].State != RoundCommitted {
+		t.Fatalf("expected current round 2 then committed round 1, got %+v", list.Rounds)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/rounds/1", nil))
+	var one Round
+	if err := json.NewDecoder(rr.Body).Decode(&one); err != nil {
+		t.Fatalf("decode round: %v", err)
+	}
+	if one.ID != 1 || one.Result == nil || one.Submissions["node-a"].Gradients != nil {
+		t.Fatalf("unexpected round view %+v", one)
+	}
+
+	for path, want := range map[string]int{"/rounds/99": http.StatusNotFound, "/rounds/x": http.StatusBadRequest} {
+		rr = httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != want {
+			t.Fatalf("%s: expected %d, got %d", path, want, rr.Code)
+		}
+	}
+}
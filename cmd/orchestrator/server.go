@@ -19,7 +19,9 @@ import (
 	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -28,8 +30,9 @@ import (
 	"time"
 
 	corehost "github.com/libp2p/go-libp2p/core/host"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/hva"
-	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/ipfs"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/network"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/token"
@@ -37,16 +40,211 @@ import (
 
 // Server handles orchestrator HTTP requests.
 type Server struct {
-	Checkpoints      *ipfs.Backend
+	Checkpoints      checkpoint.Store
 	MeshDimensions   int
 	PeerHost         corehost.Host
 	TransportKEXMode network.KEXMode
 	UtilityLedger    *token.Ledger
 	AdminToken       string
+	SigningKeys      *manifest.Keystore
+	KeyOverlap       time.Duration // default overlap for key rotation
+	Rounds           *RoundManager
+	Models           *ModelRegistry
 }
 
 const maxJSONRequestBodyBytes int64 = 1 << 20
 
+// maxCheckpointBytes bounds a streamed checkpoint upload; checkpoints above
+// maxCheckpointJSONBytes are only served with format=raw.
+const (
+	maxCheckpointBytes     int64 = 4 << 30
+	maxCheckpointJSONBytes int64 = 32 << 20
+)
+
+// defaultKeyOverlap keeps a rotated-out signing key valid for a day.
+const defaultKeyOverlap = 24 * time.Hour
+
+// HandleKeySet publishes the current and previous manifest signing keys with
+// their validity windows.
+func (s *Server) HandleKeySet(w http.ResponseWriter, r *http.Request) {
+	if s.SigningKeys == nil {
+		http.Error(w, "signing keys not configured", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.SigningKeys.KeySet())
+}
+
+// HandleKeyRotate makes a fresh manifest signing key current. The previous
+// key stays valid for overlap_seconds (default KeyOverlap).
+func (s *Server) HandleKeyRotate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.SigningKeys == nil {
+		http.Error(w, "signing keys not configured", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		OverlapSeconds *int64 `json:"overlap_seconds,omitempty"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONRequestBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	overlap := s.KeyOverlap
+	if req.OverlapSeconds != nil {
+		if *req.OverlapSeconds < 0 {
+			http.Error(w, "overlap_seconds must not be negative", http.StatusBadRequest)
+			return
+		}
+		overlap = time.Duration(*req.OverlapSeconds) * time.Second
+	}
+	current, err := s.SigningKeys.Rotate(overlap)
+	if err != nil {
+		log.Printf("signing key rotation failed: %v", err)
+		http.Error(w, "key rotation failed", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("orchestrator manifest signing key rotated to key_id=%s overlap=%s", current.KeyID, overlap)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"current": current,
+		"keys":    s.SigningKeys.KeySet().Keys,
+	})
+}
+
+// HandleRounds lists the current training round followed by finished rounds,
+// newest first.
+func (s *Server) HandleRounds(w http.ResponseWriter, r *http.Request) {
+	if s.Rounds == nil {
+		http.Error(w, "round manager not configured", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"rounds": s.Rounds.Rounds()})
+}
+
+// HandleRound returns one round by ID.
+func (s *Server) HandleRound(w http.ResponseWriter, r *http.Request) {
+	if s.Rounds == nil {
+		http.Error(w, "round manager not configured", http.StatusServiceUnavailable)
+		return
+	}
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid round id", http.StatusBadRequest)
+		return
+	}
+	round, ok := s.Rounds.Round(id)
+	if !ok {
+		http.Error(w, "round not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(round)
+}
+
+// HandleModels lists the model registry's head, pin state and versions,
+// newest first.
+func (s *Server) HandleModels(w http.ResponseWriter, r *http.Request) {
+	if s.Models == nil {
+		http.Error(w, "model registry not configured", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":   s.Models.Status(),
+		"versions": s.Models.Versions(),
+	})
+}
+
+// HandleModelLatest returns the head version nodes are training on.
+func (s *Server) HandleModelLatest(w http.ResponseWriter, r *http.Request) {
+	if s.Models == nil {
+		http.Error(w, "model registry not configured", http.StatusServiceUnavailable)
+		return
+	}
+	head, ok := s.Models.Latest()
+	if !ok {
+		http.Error(w, "no model registered", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(head)
+}
+
+// HandleModelVersion returns one model version by number.
+func (s *Server) HandleModelVersion(w http.ResponseWriter, r *http.Request) {
+	if s.Models == nil {
+		http.Error(w, "model registry not configured", http.StatusServiceUnavailable)
+		return
+	}
+	n, err := strconv.ParseUint(r.PathValue("version"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid model version", http.StatusBadRequest)
+		return
+	}
+	version, ok := s.Models.Version(n)
+	if !ok {
+		http.Error(w, "model version not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(version)
+}
+
+// HandleModelPin pins the head to {"version": n}; version 0 releases the pin.
+func (s *Server) HandleModelPin(w http.ResponseWriter, r *http.Request) {
+	s.handleModelHead(w, r, s.Models.Pin)
+}
+
+// HandleModelRollback moves the head back to {"version": n} and releases any
+// pin.
+func (s *Server) HandleModelRollback(w http.ResponseWriter, r *http.Request) {
+	s.handleModelHead(w, r, s.Models.Rollback)
+}
+
+func (s *Server) handleModelHead(w http.ResponseWriter, r *http.Request, move func(uint64) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.Models == nil {
+		http.Error(w, "model registry not configured", http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		Version *uint64 `json:"version"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONRequestBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Version == nil {
+		http.Error(w, "version required", http.StatusBadRequest)
+		return
+	}
+	if err := move(*req.Version); err != nil {
+		if errors.Is(err, ErrUnknownModelVersion) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Printf("model registry update failed: %v", err)
+		http.Error(w, "model registry update failed", http.StatusInternalServerError)
+		return
+	}
+	status := s.Models.Status()
+	log.Printf("model %s head=%d pinned=%v", sanitizeLogValue(status.ModelID), status.Head, status.Pinned)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
 // HandleMigrationDigest returns the canonical migration digest to be signed by legacy and PQC keys.
 func (s *Server) HandleMigrationDigest(w http.ResponseWriter, r *http.Request) {
 	started := time.Now()
@@ -156,36 +354,66 @@ func (s *Server) HandleAttest(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandleCheckpointPut stores a checkpoint and returns its CID. A JSON body
+// carries {"name", "payload"}; an application/octet-stream body is streamed
+// in as the payload of ?name=. Streamed uploads run without a deadline and may
+// reach maxCheckpointBytes, so they require the admin token.
 func (s *Server) HandleCheckpointPut(w http.ResponseWriter, r *http.Request) {
-	if s.Checkpoints == nil || !s.Checkpoints.Enabled() {
-		http.Error(w, "ipfs backend not configured", http.StatusServiceUnavailable)
+	if s.Checkpoints == nil {
+		http.Error(w, "checkpoint store not configured", http.StatusServiceUnavailable)
 		return
 	}
 
-	var req struct {
-		Name    string `json:"name"`
-		Payload string `json:"payload"`
-	}
-	r.Body = http.MaxBytesReader(w, r.Body, maxJSONRequestBodyBytes)
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
-		return
+	var (
+		name string
+		body io.Reader
+	)
+	ctx := r.Context()
+	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		if !s.authorizeAdmin(w, r) {
+			return
+		}
+		name = r.URL.Query().Get("name")
+		r.Body = http.MaxBytesReader(w, r.Body, maxCheckpointBytes)
+		body = r.Body
+		clearDeadlines(w)
+	} else {
+		var req struct {
+			Name    string `json:"name"`
+			Payload string `json:"payload"`
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxJSONRequestBodyBytes)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		name, body = req.Name, strings.NewReader(req.Payload)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
-	defer cancel()
-	cid, err := s.Checkpoints.PutCheckpoint(ctx, req.Name, []byte(req.Payload))
+	cid, err := s.Checkpoints.Put(ctx, name, body)
 	if err != nil {
-		log.Printf("checkpoint put failed for name=%q: %v", req.Name, err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "checkpoint too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		log.Printf("checkpoint put failed for name=%q: %v", sanitizeLogValue(name), err)
 		http.Error(w, "checkpoint storage failed", http.StatusBadGateway)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{"cid": cid})
 }
 
+// HandleCheckpointGet returns the checkpoint at ?cid= as {"payload"}, or
+// streams it as application/octet-stream with ?format=raw. Content is
+// verified against the CID as it is read.
 func (s *Server) HandleCheckpointGet(w http.ResponseWriter, r *http.Request) {
-	if s.Checkpoints == nil || !s.Checkpoints.Enabled() {
-		http.Error(w, "ipfs backend not configured", http.StatusServiceUnavailable)
+	if s.Checkpoints == nil {
+		http.Error(w, "checkpoint store not configured", http.StatusServiceUnavailable)
 		return
 	}
 
@@ -194,18 +422,59 @@ func (s *Server) HandleCheckpointGet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "cid required", http.StatusBadRequest)
 		return
 	}
+	if _, err := checkpoint.ParseID(cid); err != nil {
+		http.Error(w, "invalid cid", http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "raw" {
+		clearDeadlines(w)
+		body, err := s.Checkpoints.Get(r.Context(), cid)
+		if err != nil {
+			writeCheckpointGetError(w, cid, err)
+			return
+		}
+		defer body.Close()
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if _, err := io.Copy(w, body); err != nil {
+			// Headers are sent; abort so the client sees a truncated body
+			log.Printf("checkpoint stream failed for cid=%q: %v", sanitizeLogValue(cid), err)
+			panic(http.ErrAbortHandler)
+		}
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
-	payload, err := s.Checkpoints.GetCheckpoint(ctx, cid)
+	payload, err := checkpoint.ReadAll(ctx, s.Checkpoints, cid, maxCheckpointJSONBytes)
 	if err != nil {
-		log.Printf("checkpoint get failed for cid=%q: %v", cid, err)
-		http.Error(w, "checkpoint retrieval failed", http.StatusBadGateway)
+		writeCheckpointGetError(w, cid, err)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{"payload": string(payload)})
 }
 
+func writeCheckpointGetError(w http.ResponseWriter, cid string, err error) {
+	switch {
+	case errors.Is(err, checkpoint.ErrNotFound):
+		http.Error(w, "checkpoint not found", http.StatusNotFound)
+	case errors.Is(err, checkpoint.ErrTooLarge):
+		http.Error(w, "checkpoint too large; use format=raw", http.StatusRequestEntityTooLarge)
+	default:
+		log.Printf("checkpoint get failed for cid=%q: %v", sanitizeLogValue(cid), err)
+		http.Error(w, "checkpoint retrieval failed", http.StatusBadGateway)
+	}
+}
+
+// clearDeadlines lifts the server read and write timeouts for a streamed
+// checkpoint transfer
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
+
 func (s *Server) HandleMeshPlan(w http.ResponseWriter, r *http.Request) {
 	totalNodes, err := strconv.Atoi(defaultString(r.URL.Query().Get("total_nodes"), "10000000"))
 	if err != nil {
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/ipfs"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
 )
 
 func TestAuthorizeAdmin_FailClosedWhenTokenMissing(t *testing.T) {
@@ -56,7 +61,7 @@ func TestHandleCheckpointPut_RedactsBackendError(t *testing.T) {
 	}))
 	defer ipfsServer.Close()
 
-	s := &Server{Checkpoints: ipfs.NewBackend(ipfsServer.URL)}
+	s := &Server{Checkpoints: checkpoint.NewStore(ipfs.NewBackend(ipfsServer.URL))}
 	req := httptest.NewRequest(http.MethodPost, "/checkpoints/put", strings.NewReader(`{"name":"state.json","payload":"abc"}`))
 	rr := httptest.NewRecorder()
 
@@ -80,8 +85,8 @@ func TestHandleCheckpointGet_RedactsBackendError(t *testing.T) {
 	}))
 	defer ipfsServer.Close()
 
-	s := &Server{Checkpoints: ipfs.NewBackend(ipfsServer.URL)}
-	req := httptest.NewRequest(http.MethodGet, "/checkpoints/get?cid=abc123", nil)
+	s := &Server{Checkpoints: checkpoint.NewStore(ipfs.NewBackend(ipfsServer.URL))}
+	req := httptest.NewRequest(http.MethodGet, "/checkpoints/get?cid="+checkpoint.BlockCID([]byte("x")).String(), nil)
 	rr := httptest.NewRecorder()
 
 	s.HandleCheckpointGet(rr, req)
@@ -115,13 +120,19 @@ func TestHandleMeshPlan_ReturnsGenericValidationError(t *testing.T) {
 }
 
 func TestHandleCheckpointGet_SuccessReturnsPayloadJSON(t *testing.T) {
-	ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("payload-data"))
-	}))
-	defer ipfsServer.Close()
+	dir, err := checkpoint.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("new dir: %v", err)
+	}
+	s := &Server{Checkpoints: checkpoint.NewStore(dir)}
+	put := httptest.NewRecorder()
+	s.HandleCheckpointPut(put, httptest.NewRequest(http.MethodPost, "/checkpoints/put", strings.NewReader(`{"name":"state.json","payload":"payload-data"}`)))
+	var stored map[string]string
+	if err := json.Unmarshal(put.Body.Bytes(), &stored); err != nil || stored["cid"] == "" {
+		t.Fatalf("expected a cid from put, got %d %q", put.Code, put.Body.String())
+	}
 
-	s := &Server{Checkpoints: ipfs.NewBackend(ipfsServer.URL)}
-	req := httptest.NewRequest(http.MethodGet, "/checkpoints/get?cid=abc123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/checkpoints/get?cid="+stored["cid"], nil)
 	rr := httptest.NewRecorder()
 
 	s.HandleCheckpointGet(rr, req)
@@ -137,3 +148,93 @@ func TestHandleCheckpointGet_SuccessReturnsPayloadJSON(t *testing.T) {
 		t.Fatalf("unexpected payload %q", response["payload"])
 	}
 }
+
+func TestHandleCheckpointPut_StreamedUploadRequiresAdmin(t *testing.T) {
+	t.Setenv("MOHAWK_ALLOW_UNAUTH_ADMIN", "")
+	dir, err := checkpoint.NewDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("new dir: %v", err)
+	}
+	s := &Server{Checkpoints: checkpoint.NewStore(dir), AdminToken: "admin-secret"}
+
+	raw := strings.Repeat("weights", 100_000)
+	putReq := httptest.NewRequest(http.MethodPost, "/checkpoints/put?name=model.bin", strings.NewReader(raw))
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+	put := httptest.NewRecorder()
+	s.HandleCheckpointPut(put, putReq)
+	if put.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unauthenticated streamed upload to be refused, got %d", put.Code)
+	}
+
+	// With the token it reads back byte for byte in raw form
+	putReq = httptest.NewRequest(http.MethodPost, "/checkpoints/put?name=model.bin", strings.NewReader(raw))
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+	putReq.Header.Set("Authorization", "Bearer admin-secret")
+	put = httptest.NewRecorder()
+	s.HandleCheckpointPut(put, putReq)
+	var stored map[string]string
+	if err := json.Unmarshal(put.Body.Bytes(), &stored); err != nil || stored["cid"] == "" {
+		t.Fatalf("decode streamed put: %d %q (%v)", put.Code, put.Body.String(), err)
+	}
+	rr := httptest.NewRecorder()
+	s.HandleCheckpointGet(rr, httptest.NewRequest(http.MethodGet, "/checkpoints/get?format=raw&cid="+stored["cid"], nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != raw {
+		t.Fatalf("expected the raw checkpoint back, got %d with %d bytes", rr.Code, rr.Body.Len())
+	}
+}
+
+func TestHandleCheckpointGet_RejectsSwappedGatewayPayload(t *testing.T) {
+	ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("payload-data"))
+	}))
+	defer ipfsServer.Close()
+
+	s := &Server{Checkpoints: checkpoint.NewStore(ipfs.NewBackend(ipfsServer.URL))}
+	legacy := "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o" // a UnixFS file from /api/v0/add
+	for _, cid := range []string{"abc123", checkpoint.BlockCID([]byte("expected")).String(), legacy} {
+		rr := httptest.NewRecorder()
+		s.HandleCheckpointGet(rr, httptest.NewRequest(http.MethodGet, "/checkpoints/get?cid="+cid, nil))
+		if rr.Code == http.StatusOK || strings.Contains(rr.Body.String(), "payload-data") {
+			t.Fatalf("cid %s: expected the gateway payload to be refused, got %d %q", cid, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandleKeyRotate_RequiresAdmin(t *testing.T) {
+	t.Setenv("MOHAWK_ALLOW_UNAUTH_ADMIN", "")
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := manifest.NewKeystore(priv)
+	s := &Server{AdminToken: "secret", SigningKeys: keys, KeyOverlap: time.Hour}
+	original := keys.Current().KeyID
+
+	req := httptest.NewRequest(http.MethodPost, "/orchestrator/keys/rotate", nil)
+	rr := httptest.NewRecorder()
+	s.HandleKeyRotate(rr, req)
+	if rr.Code != http.StatusUnauthorized || keys.Current().KeyID != original {
+		t.Fatalf("expected unauthenticated rotation to be refused, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/orchestrator/keys/rotate", strings.NewReader(`{"overlap_seconds":60}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	s.HandleKeyRotate(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if keys.Current().KeyID == original {
+		t.Fatal("expected a new current key")
+	}
+
+	rr = httptest.NewRecorder()
+	s.HandleKeySet(rr, httptest.NewRequest(http.MethodGet, "/orchestrator/keys", nil))
+	var published manifest.KeySet
+	if err := json.Unmarshal(rr.Body.Bytes(), &published); err != nil {
+		t.Fatalf("decode key set: %v", err)
+	}
+	if len(published.Keys) != 2 || published.Keys[0].KeyID != original || published.Keys[0].NotAfter.IsZero() {
+		t.Fatalf("expected the previous key with a closing window, got %+v", published.Keys)
+	}
+}
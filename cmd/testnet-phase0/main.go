@@ -6,10 +6,13 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
@@ -25,16 +28,39 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	// 1. Initialize topology
-	topology := cluster.NewTopology()
+	// 1. Initialize topology; nodes missing heartbeats for 2s fail over
+	topology := cluster.NewTopology(cluster.TopologyOptions{HeartbeatTimeout: 2 * time.Second})
+	var reassigned atomic.Int64
+	topology.OnChange(func(ev cluster.TopologyEvent) {
+		switch ev.Kind {
+		case cluster.NodeUnhealthy:
+			fmt.Printf("  ⚠ %s marked unhealthy\n", ev.NodeID)
+		case cluster.NodeRecovered:
+			fmt.Printf("  ✓ %s recovered\n", ev.NodeID)
+		case cluster.AssignmentChanged:
+			reassigned.Add(1)
+		}
+	})
 	log.Println("✓ Topology initialized")
 
-	// 2. Create transport layer with MRC adapter
-	mrcAdapter := transport.NewMRCAdapter("coordinator-1", 4)
-	log.Println("✓ MRC Transport initialized (4 paths per destination)")
+	// 2. Start the aggregator host's MRC listener and the edge side's adapter.
+	// Phase-0 runs every aggregator on one host, so each is a destination at
+	// the same listener, and every chunk crosses real TCP paths.
+	numEdgeNodes := 50
+	aggHost, err := transport.NewTransport(transport.Config{Type: "mrc", NodeID: "coordinator-1", BufferSize: 2 * 100 * numEdgeNodes})
+	if err != nil {
+		log.Fatalf("create aggregator transport: %v", err)
+	}
+	defer aggHost.Close()
+	aggAddr, err := aggHost.Listen("127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("listen: %v", err)
+	}
+	mrcAdapter := transport.NewMRCAdapter("edge-gateway", 4)
+	defer mrcAdapter.Close()
+	log.Printf("✓ MRC Transport initialized (listener %s, 4 TCP paths per destination)\n", aggAddr)
 
 	// 3. Register edge nodes (50 nodes for Phase-0)
-	numEdgeNodes := 50
 	edgeNodes := make([]string, numEdgeNodes)
 	for i := 0; i < numEdgeNodes; i++ {
 		nodeID := fmt.Sprintf("edge-%03d", i)
@@ -43,68 +69,117 @@ func main() {
 	}
 	log.Printf("✓ Registered %d edge nodes\n", numEdgeNodes)
 
-	// 4. Register regional aggregators (5 nodes)
+	// 4. Register regional aggregators (5 nodes across 3 regions)
 	numAggregators := 5
+	regions := []string{"us-east", "eu-west", "ap-south"}
 	aggregators := make([]string, numAggregators)
 	for i := 0; i < numAggregators; i++ {
 		aggID := fmt.Sprintf("agg-regional-%d", i)
 		aggregators[i] = aggID
 		topology.RegisterNode(aggID, cluster.RegionalAggregator)
-		mrcAdapter.RegisterDestination(aggID)
+		topology.SetRegion(aggID, regions[i%len(regions)])
+		if err := mrcAdapter.RegisterDestinationAddr(aggID, aggAddr.String()); err != nil {
+			log.Fatalf("connect to %s: %v", aggID, err)
+		}
 	}
 	log.Printf("✓ Registered %d regional aggregators\n", numAggregators)
 
 	// 5. Register global coordinator
 	globalID := "coordinator-global"
 	topology.RegisterNode(globalID, cluster.GlobalCoordinator)
-	mrcAdapter.RegisterDestination(globalID)
+	if err := mrcAdapter.RegisterDestinationAddr(globalID, aggAddr.String()); err != nil {
+		log.Fatalf("connect to %s: %v", globalID, err)
+	}
 	log.Println("✓ Registered global coordinator")
 
-	// 6. Assign redundant paths (edge -> 3 aggregators each)
+	// 6. Assign redundant paths (edge -> 3 aggregators each, region-diverse)
 	for _, edgeID := range edgeNodes {
-		selected := selectDiverseAggregators(aggregators, 3)
-		topology.AssignAggregator(edgeID, selected)
+		if err := topology.AssignAggregator(edgeID, aggregators); err != nil {
+			log.Fatalf("assign %s: %v", edgeID, err)
+		}
 	}
 	log.Println("✓ Assigned redundant aggregation paths (3-way diversity)")
 	fmt.Println()
 
-	// 7. Create streaming aggregator (transport.MRCAdapter implements transport.Transport interface)
-	streamingAgg := internal.NewStreamingAggregator(internal.Regional, mrcAdapter)
-	log.Println("✓ Created streaming aggregator")
+	// 7. Create the streaming aggregator over the host's transport
+	// A round flushes once every edge node's signed tensor is ready
+	streamingAgg := internal.NewStreamingAggregator(internal.Regional, aggHost, internal.StreamingAggregatorOptions{
+		Topology:   topology,
+		QuorumSize: numEdgeNodes,
+		Sinks:      []internal.AggregateSink{internal.AggregateSinkFunc(printAggregate)},
+	})
+	nodeKeys := make(map[string]ed25519.PrivateKey, numEdgeNodes)
+	for _, nodeID := range edgeNodes {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			log.Fatalf("generate key for %s: %v", nodeID, err)
+		}
+		if err := streamingAgg.RegisterNodeKey(nodeID, pub); err != nil {
+			log.Fatalf("register key for %s: %v", nodeID, err)
+		}
+		nodeKeys[nodeID] = priv
+	}
+	log.Println("✓ Created streaming aggregator with per-node signing keys")
 
-	// 8. Start aggregation loop
+	// 8. Start aggregation loop; it ingests every chunk the listener receives
 	go streamingAgg.RunAggregationLoop(ctx)
 
-	// 9. Start health monitor
+	// 9. Start health monitors and node heartbeats
 	go mrcAdapter.HealthMonitor(ctx)
+	go topology.RunHealthMonitor(ctx, 500*time.Millisecond)
+	var outage sync.Map // node ID -> struct{} for nodes that stopped heartbeating
+	go sendHeartbeats(ctx, topology, append(append([]string{globalID}, edgeNodes...), aggregators...), &outage)
 
 	// 10. Run training rounds with gradient flow
 	fmt.Println("🔄 Starting training simulation (5 rounds):")
 	fmt.Println("─────────────────────────────────────────")
 	fmt.Println()
 
-	simulateTrainingRounds(ctx, mrcAdapter, streamingAgg, topology, edgeNodes, 5)
+	simulateTrainingRounds(ctx, mrcAdapter, streamingAgg, topology, edgeNodes, nodeKeys, 5, func(round int) {
+		// Take one aggregator down after round 2 so its edge nodes fail over
+		if round == 2 {
+			fmt.Printf("  ✗ Simulating outage of %s\n", aggregators[0])
+			outage.Store(aggregators[0], struct{}{})
+		}
+	})
 
 	// 11. Wait a bit for aggregation to complete
 	time.Sleep(1 * time.Second)
 
 	// 12. Collect and display metrics
-	displayMetrics(mrcAdapter, streamingAgg, topology)
+	var dropped int64
+	if host, ok := aggHost.(*transport.MRCAdapter); ok {
+		dropped = host.DuplicateChunks()
+	}
+	displayMetrics(mrcAdapter, dropped, streamingAgg, topology, reassigned.Load())
 
 	fmt.Println()
 	fmt.Println("✅ Phase-0 Testnet Simulation Complete")
 }
 
-func selectDiverseAggregators(aggregators []string, count int) []string {
-	selected := make([]string, 0, count)
-	indices := rand.Perm(len(aggregators))
-	for i := 0; i < count && i < len(indices); i++ {
-		selected = append(selected, aggregators[indices[i]])
+// sendHeartbeats stands in for each node's agent, reporting a simulated
+// latency every 500ms for every node not in outage
+func sendHeartbeats(ctx context.Context, topo *cluster.Topology, nodes []string, outage *sync.Map) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		for i, nodeID := range nodes {
+			if _, down := outage.Load(nodeID); down {
+				continue
+			}
+			latency := time.Duration(5+i%7*3+rand.Intn(3)) * time.Millisecond
+			topo.Heartbeat(nodeID, latency)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	return selected
 }
 
-func simulateTrainingRounds(ctx context.Context, trans transport.Transport, agg *internal.StreamingAggregator, topo *cluster.Topology, nodes []string, rounds int) {
+func simulateTrainingRounds(ctx context.Context, trans transport.Transport, agg *internal.StreamingAggregator, topo *cluster.Topology, nodes []string, keys map[string]ed25519.PrivateKey, rounds int, afterRound func(round int)) {
 	for round := 1; round <= rounds; round++ {
 		roundStart := time.Now()
 		totalChunks := 0
@@ -122,28 +197,39 @@ func simulateTrainingRounds(ctx context.Context, trans transport.Transport, agg
 				aggs, _ := topo.GetAssignedAggregators(nid)
 				tensorID := fmt.Sprintf("%s-round%d", nid, round)
 
-				// Each node sends 100 chunks (10 chunks × 10-dim each = full gradient)
-				for chunk := 0; chunk < 100; chunk++ {
-					gradChunk := transport.GradientChunk{
+				// Each node sends 100 chunks of 100 dims, hashed and signed as one tensor
+				chunks := make([]transport.GradientChunk, 100)
+				for chunk := range chunks {
+					chunks[chunk] = transport.GradientChunk{
 						ID:      tensorID,
 						NodeID:  nid,
 						Index:   chunk,
-						Total:   100,
+						Total:   len(chunks),
 						Payload: make([]float32, 100), // 100 dims per chunk
 					}
 
 					// Fill with synthetic data
-					for j := range gradChunk.Payload {
-						gradChunk.Payload[j] = float32(rand.Intn(100))
+					for j := range chunks[chunk].Payload {
+						chunks[chunk].Payload[j] = float32(rand.Intn(100))
 					}
+				}
+				if err := transport.SealTensor(chunks, keys[nid]); err != nil {
+					log.Printf("seal tensor %s: %v", tensorID, err)
+					return
+				}
 
+				for _, gradChunk := range chunks {
 					// Send to first assigned aggregator (MRC handles redundancy)
-					if len(aggs) > 0 {
-						_ = trans.SendChunk(ctx, aggs[0], gradChunk)
-						mu.Lock()
-						totalChunks++
-						mu.Unlock()
+					if len(aggs) == 0 {
+						continue
 					}
+					if err := trans.SendChunk(ctx, aggs[0], gradChunk); err != nil {
+						log.Printf("send chunk %s[%d]: %v", tensorID, gradChunk.Index, err)
+						continue
+					}
+					mu.Lock()
+					totalChunks++
+					mu.Unlock()
 				}
 			}(nodeID)
 		}
@@ -155,10 +241,50 @@ func simulateTrainingRounds(ctx context.Context, trans transport.Transport, agg
 
 		fmt.Printf("Round %d/%d: %d chunks submitted | Latency: %.0fms | Throughput: %.0f chunks/sec\n",
 			round, rounds, totalChunks, roundTime.Seconds()*1000, throughput)
+
+		// Flush now rather than waiting for the aggregation loop's next tick
+		if err := awaitFlush(ctx, agg, round); err != nil {
+			log.Printf("flush round %d: %v", round, err)
+		}
+		afterRound(round)
+	}
+}
+
+// awaitFlush flushes until agg has flushed round rounds; the last chunks of a
+// round may still be queued for the aggregation loop when their sends return
+func awaitFlush(ctx context.Context, agg *internal.StreamingAggregator, round int) error {
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(10 * time.Second)
+	for {
+		if flushed, _ := agg.GetStats()["rounds_flushed"].(uint64); flushed >= uint64(round) {
+			return nil
+		}
+		if _, err := agg.Flush(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("round not flushed within 10s")
+		case <-ticker.C:
+		}
+	}
+}
+
+// printAggregate is the testnet's aggregate sink
+func printAggregate(_ context.Context, agg internal.StreamingAggregate) error {
+	norm := 0.0
+	for _, v := range agg.Gradient {
+		norm += v * v
 	}
+	fmt.Printf("  ↳ Aggregate %d: %d participants, %d selected, dim=%d, norm=%.2f, ε=%.4f\n",
+		agg.RoundID, len(agg.Participants), len(agg.Selected), len(agg.Gradient), math.Sqrt(norm), agg.Epsilon)
+	return nil
 }
 
-func displayMetrics(trans transport.Transport, agg *internal.StreamingAggregator, topo *cluster.Topology) {
+func displayMetrics(trans transport.Transport, dropped int64, agg *internal.StreamingAggregator, topo *cluster.Topology, reassigned int64) {
 	fmt.Println()
 	fmt.Println("📊 Performance Metrics:")
 	fmt.Println("─────────────────────────────")
@@ -176,12 +302,15 @@ func displayMetrics(trans transport.Transport, agg *internal.StreamingAggregator
 	} else {
 		fmt.Printf("  Healthy paths: %d\n", healthyPaths)
 	}
+	fmt.Printf("  Redundant chunk copies dropped: %d\n", dropped)
 
 	// Aggregator stats
 	stats := agg.GetStats()
 	fmt.Printf("\nAggregation:\n")
 	fmt.Printf("  Total chunks ingested: %v\n", stats["total_chunks_ingested"])
 	fmt.Printf("  Complete gradients: %v\n", stats["total_gradients"])
+	fmt.Printf("  Rounds flushed: %v\n", stats["rounds_flushed"])
+	fmt.Printf("  Rejected chunks: %v\n", stats["total_chunks_rejected"])
 	fmt.Printf("  Active assemblies: %v\n", stats["active_assemblies"])
 
 	// Topology status
@@ -195,6 +324,7 @@ func displayMetrics(trans transport.Transport, agg *internal.StreamingAggregator
 	fmt.Printf("  Total nodes: %d\n", totalNodes)
 	fmt.Printf("  Healthy nodes: %d (%.1f%%)\n", topo.GetHealthyNodes(), float64(topo.GetHealthyNodes())*100/float64(totalNodes))
 	fmt.Printf("  Unhealthy nodes: %d\n", len(unhealthy))
+	fmt.Printf("  Edge node reassignments: %d\n", reassigned)
 
 	fmt.Printf("\nByzantine Resilience:\n")
 	fmt.Printf("  Edge nodes: %d\n", topo.GetNodeCount(cluster.EdgeNode))
@@ -24,6 +24,8 @@ import (
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/hva"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/secagg"
 )
 
 // Tier represents the hierarchical level of the aggregator.
@@ -38,12 +40,19 @@ const (
 
 // Aggregator coordinates the verification and synthesis of model updates.
 type Aggregator struct {
-	Tier                 Tier
-	Accountant           *RDPAccountant
-	DPSigma              float64
-	Liveness             *StragglerMonitor
-	Convergence          *ConvergenceMonitor
-	MeshPlan             hva.Plan
+	Tier       Tier
+	Accountant *RDPAccountant
+	DPSigma    float64
+	// DPClipNorm bounds each update's ℓ₂ norm before noise is added.
+	DPClipNorm float64
+	// DPSamplingRate is the Poisson sampling rate q used for privacy
+	// amplification; 1 accounts every round as a full Gaussian release.
+	DPSamplingRate float64
+	Liveness       *StragglerMonitor
+	Convergence    *ConvergenceMonitor
+	MeshPlan       hva.Plan
+	// Thinker enables outlier governance in ProcessGradientBatch; nil disables it.
+	Thinker              *ThinkerClauses
 	recentRoundLatencyMs float64
 }
 
@@ -64,6 +73,9 @@ type BatchProcessingOptions struct {
 	UpdateAgesSec         []float64
 	UpdateWeights         []float64
 	UpdateUtilityScores   []float64
+	// SecureAggregate is the unmasked sum of a pairwise-masked round. When set,
+	// the batch carries no individual updates and per-update filtering is unavailable.
+	SecureAggregate *secagg.Sum
 }
 
 // BatchProcessingResult captures runtime batch decisions for observability.
@@ -74,17 +86,30 @@ type BatchProcessingResult struct {
 	MaxGradNorm     float64
 	UsedMultiKrum   bool
 	UsedFallback    bool
+	UsedSecureAgg   bool
 	EffectiveQuorum float64
+	// Aggregate is the clipped, weighted mean of the selected updates with
+	// Gaussian noise of standard deviation NoiseMultiplier·ClipNorm added to
+	// the weighted sum. NoiseMultiplier is the sigma recorded by the accountant.
+	Aggregate       []float64
+	NoiseMultiplier float64
+	ClipNorm        float64
+	// Governance is set when thinker clauses were applied to the batch.
+	Governance *GovernanceReport
 }
 
 type updateEnvelope struct {
+	index   int
 	vector  []float64
 	ageSec  float64
 	weight  float64
 	utility float64
+	zscore  float64
 }
 
 // NewAggregator initializes a tier-specific aggregator with all formal guards.
+// Thinker clauses are not loaded; set Thinker, for instance from
+// ThinkerClausesFromEnv, to apply them.
 func NewAggregator(t Tier) *Aggregator {
 	dp := LoadDPConfig()
 
@@ -98,15 +123,33 @@ func NewAggregator(t Tier) *Aggregator {
 
 	meshPlan, _ := hva.BuildPlan(totalNodes, 1024)
 	return &Aggregator{
-		Tier:        t,
-		Accountant:  NewRDPAccountant(dp.TargetEpsilon, dp.Delta),
-		DPSigma:     dp.Sigma,
-		Liveness:    NewStragglerMonitor(),
-		Convergence: NewConvergenceMonitor(0.1, 0.01),
-		MeshPlan:    meshPlan,
+		Tier:           t,
+		Accountant:     NewRDPAccountant(dp.TargetEpsilon, dp.Delta),
+		DPSigma:        dp.Sigma,
+		DPClipNorm:     dp.ClipNorm,
+		DPSamplingRate: dp.SamplingRate,
+		Liveness:       NewStragglerMonitor(),
+		Convergence:    NewConvergenceMonitor(0.1, 0.01),
+		MeshPlan:       meshPlan,
 	}
 }
 
+// NewPersistentAggregator is NewAggregator with its privacy accountant backed
+// by the budget ledger at ledgerPath. It fails if the ledger cannot be
+// replayed, including when its hash chain has been tampered with.
+func NewPersistentAggregator(t Tier, ledgerPath string) (*Aggregator, error) {
+	ledger, err := privacy.OpenBudgetLedger(ledgerPath)
+	if err != nil {
+		return nil, fmt.Errorf("open privacy ledger: %w", err)
+	}
+	a := NewAggregator(t)
+	if err := a.Accountant.AttachLedger(ledger); err != nil {
+		_ = ledger.Close()
+		return nil, fmt.Errorf("replay privacy ledger: %w", err)
+	}
+	return a, nil
+}
+
 // ProcessUpdates executes the verified aggregation pipeline.
 func (a *Aggregator) ProcessUpdates(activeNodes int, totalNodes int, gradNorm float64) error {
 	meshPlan, err := hva.BuildPlan(totalNodes, 1024)
@@ -129,7 +172,7 @@ func (a *Aggregator) ProcessUpdates(activeNodes int, totalNodes int, gradNorm fl
 	metrics.ObserveFormalLivenessSuccessProbability(scope, a.Liveness.CalculateSuccessProbability(activeNodes, 0.5))
 
 	// Active Guard: Theorem 2 (Privacy Budget)
-	if err := a.Accountant.RecordGaussianStepRDP(a.DPSigma); err != nil {
+	if err := a.Accountant.RecordSubsampledGaussianStep(a.samplingRate(), a.DPSigma); err != nil {
 		return fmt.Errorf("privacy accounting failed: %w", err)
 	}
 	if err := a.Accountant.CheckBudget(); err != nil {
@@ -146,9 +189,24 @@ func (a *Aggregator) ProcessUpdates(activeNodes int, totalNodes int, gradNorm fl
 	return nil
 }
 
-// ProcessGradientBatch performs optional Multi-Krum filtering, computes gradient norms,
-// and executes the standard guard pipeline through ProcessUpdates.
+func (a *Aggregator) samplingRate() float64 {
+	if a.DPSamplingRate <= 0 || a.DPSamplingRate > 1 {
+		return 1
+	}
+	return a.DPSamplingRate
+}
+
+// ProcessGradientBatch applies thinker-clause governance and optional Multi-Krum
+// filtering, computes gradient norms, and executes the standard guard pipeline
+// through ProcessUpdates. Minority outliers retained by governance bypass
+// trimming and Multi-Krum and are aggregated alongside the surviving inliers.
 func (a *Aggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, opts BatchProcessingOptions) (BatchProcessingResult, error) {
+	if opts.SecureAggregate != nil {
+		if len(updates) != 0 {
+			return BatchProcessingResult{}, fmt.Errorf("secure aggregation batch must not carry individual updates")
+		}
+		return a.processSecureAggregate(*opts.SecureAggregate, totalNodes, opts)
+	}
 	if len(updates) == 0 {
 		return BatchProcessingResult{}, fmt.Errorf("gradient batch is empty")
 	}
@@ -156,7 +214,7 @@ func (a *Aggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, o
 
 	envelopes := make([]updateEnvelope, 0, len(updates))
 	for i, update := range updates {
-		env := updateEnvelope{vector: append([]float64(nil), update...), weight: 1.0}
+		env := updateEnvelope{index: i, vector: append([]float64(nil), update...), weight: 1.0}
 		if i < len(opts.UpdateAgesSec) {
 			env.ageSec = maxFloat(0, opts.UpdateAgesSec[i])
 		}
@@ -185,21 +243,36 @@ func (a *Aggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, o
 		scaleVector(envelopes[i].vector, envelopes[i].weight)
 	}
 
+	if len(envelopes) == 0 {
+		return BatchProcessingResult{}, fmt.Errorf("no updates available after async selection")
+	}
+
+	var governance *governanceSplit
+	if a.Thinker != nil && a.Thinker.Enabled {
+		split, err := applyThinkerClauses(envelopes, *a.Thinker)
+		if err != nil {
+			return BatchProcessingResult{}, err
+		}
+		governance = &split
+		envelopes = split.inliers
+	}
+
 	selected := envelopesToVectors(envelopes)
-	if len(selected) == 0 {
+	weights := envelopeWeights(envelopes)
+	if len(selected) == 0 && governance == nil {
 		return BatchProcessingResult{}, fmt.Errorf("no updates available after async selection")
 	}
 
-	if opts.WeightedTrimFraction > 0 {
-		trimmed, err := trimByGradientNorm(selected, opts.WeightedTrimFraction)
+	if opts.WeightedTrimFraction > 0 && len(selected) > 0 {
+		kept, err := trimByGradientNorm(selected, opts.WeightedTrimFraction)
 		if err != nil {
 			return BatchProcessingResult{}, err
 		}
-		selected = trimmed
+		selected, weights = pickUpdates(selected, weights, kept)
 	}
 
-	if opts.HierarchicalGroupSize > 1 {
-		selected = hierarchicalAverage(selected, opts.HierarchicalGroupSize)
+	if opts.HierarchicalGroupSize > 1 && len(selected) > 0 {
+		selected, weights = hierarchicalAverage(selected, weights, opts.HierarchicalGroupSize)
 		if len(selected) == 0 {
 			return BatchProcessingResult{}, fmt.Errorf("hierarchical aggregation produced empty selection")
 		}
@@ -207,55 +280,53 @@ func (a *Aggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, o
 
 	usedMultiKrum := false
 	usedFallback := false
-	if opts.ByzantineF > 0 {
+	if opts.ByzantineF > 0 && len(selected) > 0 {
 		_, selectedIndices, _, err := MultiKrumAggregate(selected, opts.ByzantineF, opts.MultiKrumM)
 		if err != nil {
 			if !opts.EnableAsyncFallback {
 				return BatchProcessingResult{}, fmt.Errorf("multi-krum filtering failed: %w", err)
 			}
-			fallback, ferr := trimByGradientNorm(selected, 0.10)
+			kept, ferr := trimByGradientNorm(selected, 0.10)
 			if ferr != nil {
 				return BatchProcessingResult{}, fmt.Errorf("multi-krum filtering failed and fallback failed: %w", err)
 			}
-			selected = fallback
+			selected, weights = pickUpdates(selected, weights, kept)
 			usedFallback = true
 		} else {
-			filtered := make([][]float64, 0, len(selectedIndices))
-			for _, idx := range selectedIndices {
-				if idx >= 0 && idx < len(selected) {
-					filtered = append(filtered, selected[idx])
-				}
-			}
+			filtered, filteredWeights := pickUpdates(selected, weights, selectedIndices)
 			if len(filtered) == 0 {
 				return BatchProcessingResult{}, fmt.Errorf("multi-krum selected no updates")
 			}
-			selected = filtered
+			selected, weights = filtered, filteredWeights
 			usedMultiKrum = true
 		}
 	}
 
-	maxNorm := maxGradNorm(selected)
-	activeNodes := len(selected)
-	effectiveQuorum := resolvedQuorum(opts, a.recentRoundLatencyMs)
-	if effectiveQuorum > 0 && effectiveQuorum <= 1 {
-		quorumCount := int(math.Ceil(float64(totalNodes) * effectiveQuorum))
-		if quorumCount < 1 {
-			quorumCount = 1
+	var report *GovernanceReport
+	if governance != nil {
+		for _, env := range governance.retainMinority(len(selected), *a.Thinker) {
+			selected = append(selected, env.vector)
+			weights = append(weights, env.weight)
 		}
-		if activeNodes > quorumCount {
-			activeNodes = quorumCount
+		report = &governance.report
+		metrics.ObserveThinkerDecisions("rejected", len(report.Rejected))
+		metrics.ObserveThinkerDecisions("review", len(report.ReviewQueue))
+		metrics.ObserveThinkerDecisions("retained", len(report.RetainedOutliers))
+		if len(selected) == 0 {
+			return BatchProcessingResult{}, fmt.Errorf("thinker clauses left no updates to aggregate")
 		}
 	}
-	if activeNodes < 80 {
-		activeNodes = 80
-	}
-	if totalNodes < activeNodes {
-		totalNodes = activeNodes
-	}
+
+	maxNorm := maxGradNorm(selected)
+	activeNodes, totalNodes, effectiveQuorum := a.resolveActiveNodes(len(selected), totalNodes, opts)
 
 	if err := a.ProcessUpdates(activeNodes, totalNodes, maxNorm); err != nil {
 		return BatchProcessingResult{}, err
 	}
+	aggregate, err := noisyWeightedMean(selected, weights, a.DPClipNorm, a.DPSigma)
+	if err != nil {
+		return BatchProcessingResult{}, err
+	}
 	a.recentRoundLatencyMs = ewma(a.recentRoundLatencyMs, float64(time.Since(roundStart).Microseconds())/1000.0, 0.2)
 
 	return BatchProcessingResult{
@@ -266,9 +337,87 @@ func (a *Aggregator) ProcessGradientBatch(updates [][]float64, totalNodes int, o
 		UsedMultiKrum:   usedMultiKrum,
 		UsedFallback:    usedFallback,
 		EffectiveQuorum: effectiveQuorum,
+		Aggregate:       aggregate,
+		NoiseMultiplier: a.DPSigma,
+		ClipNorm:        a.DPClipNorm,
+		Governance:      report,
+	}, nil
+}
+
+// processSecureAggregate runs the guard pipeline on a secure-aggregation sum.
+// Only the mean of the surviving clients is visible, so Multi-Krum and the
+// per-update selection options cannot be applied.
+func (a *Aggregator) processSecureAggregate(sum secagg.Sum, totalNodes int, opts BatchProcessingOptions) (BatchProcessingResult, error) {
+	if opts.ByzantineF > 0 || opts.WeightedTrimFraction > 0 || opts.HierarchicalGroupSize > 1 || (opts.UtilityTopFraction > 0 && opts.UtilityTopFraction < 1) {
+		return BatchProcessingResult{}, fmt.Errorf("per-update filtering is unavailable under secure aggregation")
+	}
+	contributors := len(sum.Contributors)
+	if contributors == 0 || len(sum.Vector) == 0 {
+		return BatchProcessingResult{}, fmt.Errorf("secure aggregate is empty")
+	}
+	roundStart := time.Now()
+
+	mean := append([]float64(nil), sum.Vector...)
+	scaleVector(mean, 1/float64(contributors))
+	meanNorm := maxGradNorm([][]float64{mean})
+
+	activeNodes, totalNodes, effectiveQuorum := a.resolveActiveNodes(contributors, totalNodes, opts)
+
+	if err := a.ProcessUpdates(activeNodes, totalNodes, meanNorm); err != nil {
+		return BatchProcessingResult{}, err
+	}
+	if a.DPClipNorm <= 0 {
+		return BatchProcessingResult{}, fmt.Errorf("dp clip norm must be positive")
+	}
+	// Updates are masked, so clipping is up to clients (secagg.Client.MaskInput
+	// clips to Params.ClipNorm); the noise only bounds sensitivity if that
+	// clip is no looser than DPClipNorm.
+	if sum.ClipNorm <= 0 || sum.ClipNorm > a.DPClipNorm {
+		return BatchProcessingResult{}, fmt.Errorf("secure aggregate clipped to %v, dp clip norm is %v", sum.ClipNorm, a.DPClipNorm)
+	}
+	aggregate := append([]float64(nil), sum.Vector...)
+	if err := addGaussianNoise(aggregate, a.DPSigma*a.DPClipNorm); err != nil {
+		return BatchProcessingResult{}, err
+	}
+	scaleVector(aggregate, 1/float64(contributors))
+	a.recentRoundLatencyMs = ewma(a.recentRoundLatencyMs, float64(time.Since(roundStart).Microseconds())/1000.0, 0.2)
+
+	return BatchProcessingResult{
+		InputCount:      contributors + len(sum.Dropped),
+		SelectedCount:   contributors,
+		ActiveNodes:     activeNodes,
+		MaxGradNorm:     meanNorm,
+		UsedSecureAgg:   true,
+		EffectiveQuorum: effectiveQuorum,
+		Aggregate:       aggregate,
+		NoiseMultiplier: a.DPSigma,
+		ClipNorm:        a.DPClipNorm,
 	}, nil
 }
 
+// resolveActiveNodes caps the selected count at the effective quorum and
+// applies the liveness floor used by ProcessUpdates.
+func (a *Aggregator) resolveActiveNodes(selected, totalNodes int, opts BatchProcessingOptions) (int, int, float64) {
+	activeNodes := selected
+	effectiveQuorum := resolvedQuorum(opts, a.recentRoundLatencyMs)
+	if effectiveQuorum > 0 && effectiveQuorum <= 1 {
+		quorumCount := int(math.Ceil(float64(totalNodes) * effectiveQuorum))
+		if quorumCount < 1 {
+			quorumCount = 1
+		}
+		if activeNodes > quorumCount {
+			activeNodes = quorumCount
+		}
+	}
+	if activeNodes < 80 {
+		activeNodes = 80
+	}
+	if totalNodes < activeNodes {
+		totalNodes = activeNodes
+	}
+	return activeNodes, totalNodes, effectiveQuorum
+}
+
 func selectTopUtility(envelopes []updateEnvelope, topFraction float64) []updateEnvelope {
 	keep := int(math.Ceil(float64(len(envelopes)) * topFraction))
 	if keep < 1 {
@@ -298,6 +447,27 @@ func envelopesToVectors(envelopes []updateEnvelope) [][]float64 {
 	return out
 }
 
+func envelopeWeights(envelopes []updateEnvelope) []float64 {
+	out := make([]float64, 0, len(envelopes))
+	for _, env := range envelopes {
+		out = append(out, env.weight)
+	}
+	return out
+}
+
+// pickUpdates keeps the updates and weights at the given in-range indices.
+func pickUpdates(updates [][]float64, weights []float64, indices []int) ([][]float64, []float64) {
+	picked := make([][]float64, 0, len(indices))
+	pickedWeights := make([]float64, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= 0 && idx < len(updates) {
+			picked = append(picked, updates[idx])
+			pickedWeights = append(pickedWeights, weights[idx])
+		}
+	}
+	return picked, pickedWeights
+}
+
 func scaleVector(vec []float64, factor float64) {
 	if factor <= 0 {
 		factor = 0.000001
@@ -353,12 +523,16 @@ func maxFloat(a, b float64) float64 {
 	return b
 }
 
-func trimByGradientNorm(updates [][]float64, fraction float64) ([][]float64, error) {
+func trimByGradientNorm(updates [][]float64, fraction float64) ([]int, error) {
 	if len(updates) == 0 {
 		return nil, fmt.Errorf("cannot trim empty updates")
 	}
 	if fraction <= 0 {
-		return updates, nil
+		all := make([]int, len(updates))
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
 	}
 	if fraction >= 1 {
 		return nil, fmt.Errorf("weighted trim fraction must be < 1")
@@ -381,26 +555,32 @@ func trimByGradientNorm(updates [][]float64, fraction float64) ([][]float64, err
 		return rankedUpdates[i].norm < rankedUpdates[j].norm
 	})
 
-	trimmed := make([][]float64, 0, keep)
+	kept := make([]int, 0, keep)
 	for _, candidate := range rankedUpdates[:keep] {
-		trimmed = append(trimmed, updates[candidate.idx])
+		kept = append(kept, candidate.idx)
 	}
-	return trimmed, nil
+	return kept, nil
 }
 
-func hierarchicalAverage(updates [][]float64, groupSize int) [][]float64 {
+func hierarchicalAverage(updates [][]float64, weights []float64, groupSize int) ([][]float64, []float64) {
 	if groupSize <= 1 || len(updates) <= 1 {
-		return updates
+		return updates, weights
 	}
 	groups := make([][]float64, 0, (len(updates)+groupSize-1)/groupSize)
+	groupWeights := make([]float64, 0, cap(groups))
 	for i := 0; i < len(updates); i += groupSize {
 		end := i + groupSize
 		if end > len(updates) {
 			end = len(updates)
 		}
 		groups = append(groups, meanGradient(updates[i:end]))
+		var w float64
+		for _, v := range weights[i:end] {
+			w += v
+		}
+		groupWeights = append(groupWeights, w/float64(end-i))
 	}
-	return groups
+	return groups, groupWeights
 }
 
 func meanGradient(updates [][]float64) []float64 {
@@ -1,6 +1,10 @@
 package internal
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/secagg"
+)
 
 func TestProcessGradientBatchWithMultiKrum(t *testing.T) {
 	t.Setenv("MOHAWK_DP_SIGMA", "5")
@@ -185,3 +189,39 @@ func TestProcessGradientBatchAsyncFallbackOnMultiKrumError(t *testing.T) {
 		t.Fatalf("expected fallback path to be used")
 	}
 }
+
+func TestProcessGradientBatchWithSecureAggregate(t *testing.T) {
+	t.Setenv("MOHAWK_DP_SIGMA", "5")
+	agg := NewAggregator(Regional)
+	sum := &secagg.Sum{
+		Vector:       []float64{0.3, 0.6, 0.9},
+		Contributors: []string{"a", "b", "c"},
+		Dropped:      []string{"d"},
+		ClipNorm:     1,
+	}
+
+	result, err := agg.ProcessGradientBatch(nil, 4, BatchProcessingOptions{SecureAggregate: sum})
+	if err != nil {
+		t.Fatalf("ProcessGradientBatch failed: %v", err)
+	}
+	if !result.UsedSecureAgg || result.UsedMultiKrum {
+		t.Fatalf("unexpected result flags: %+v", result)
+	}
+	if result.InputCount != 4 || result.SelectedCount != 3 {
+		t.Fatalf("input=%d selected=%d, want 4/3", result.InputCount, result.SelectedCount)
+	}
+
+	if _, err := agg.ProcessGradientBatch(nil, 4, BatchProcessingOptions{SecureAggregate: sum, ByzantineF: 1}); err == nil {
+		t.Fatal("expected Multi-Krum to be rejected under secure aggregation")
+	}
+	if _, err := agg.ProcessGradientBatch([][]float64{{1, 2, 3}}, 4, BatchProcessingOptions{SecureAggregate: sum}); err == nil {
+		t.Fatal("expected individual updates to be rejected under secure aggregation")
+	}
+
+	// Noise is calibrated to DPClipNorm, so a looser client clip is refused
+	loose := *sum
+	loose.ClipNorm = agg.DPClipNorm * 2
+	if _, err := agg.ProcessGradientBatch(nil, 4, BatchProcessingOptions{SecureAggregate: &loose}); err == nil {
+		t.Fatal("expected a sum clipped above the dp clip norm to be rejected")
+	}
+}
@@ -112,3 +112,50 @@ func (bv *BatchVerifier) VerifySignaturesWithComputeProof(
 
 	return results, nil
 }
+
+// VerifySignaturesWithGroth16Batch checks signatures and then batch-verifies
+// the Groth16 proofs of every correctly signed update under circuitID. An
+// update is accepted only if both its signature and its proof verify.
+func (bv *BatchVerifier) VerifySignaturesWithGroth16Batch(
+	pubKeys []ed25519.PublicKey,
+	messages [][]byte,
+	signatures [][]byte,
+	circuitID string,
+	proofs [][]byte,
+	publicInputs [][]byte,
+) ([]bool, error) {
+	if len(proofs) != len(pubKeys) || (publicInputs != nil && len(publicInputs) != len(pubKeys)) {
+		return nil, errors.New("groth16 proof lengths must match signature inputs")
+	}
+
+	sigResults, err := bv.VerifySignatures(pubKeys, messages, signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx []int
+	var batchProofs, batchInputs [][]byte
+	for i, ok := range sigResults {
+		if !ok {
+			continue
+		}
+		idx = append(idx, i)
+		batchProofs = append(batchProofs, proofs[i])
+		if publicInputs != nil {
+			batchInputs = append(batchInputs, publicInputs[i])
+		}
+	}
+
+	results := make([]bool, len(sigResults))
+	if len(idx) == 0 {
+		return results, nil
+	}
+	batch, err := BatchVerifyProofs(circuitID, batchProofs, batchInputs)
+	if err != nil {
+		return nil, fmt.Errorf("groth16 batch verification failed: %w", err)
+	}
+	for j, i := range idx {
+		results[i] = batch.Valid[j]
+	}
+	return results, nil
+}
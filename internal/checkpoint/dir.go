@@ -0,0 +1,100 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ipfs/go-cid"
+)
+
+// Dir is a BlockStore in a local directory, one file per block named by its
+// CID. It needs no network, for offline and air-gapped deployments.
+type Dir struct {
+	root string
+}
+
+// NewDir returns the block directory at root, creating it (mode 0700) if
+// needed.
+func NewDir(root string) (*Dir, error) {
+	if root == "" {
+		return nil, errors.New("checkpoint directory is required")
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	return &Dir{root: root}, nil
+}
+
+func (d *Dir) path(c cid.Cid) string {
+	return filepath.Join(d.root, c.String())
+}
+
+// PutBlock writes data under c unless an intact copy is already present, so
+// putting a block again repairs a corrupt one. Data that does not match c is
+// refused.
+func (d *Dir) PutBlock(_ context.Context, c cid.Cid, data []byte) error {
+	if len(data) > MaxBlockSize {
+		return fmt.Errorf("block %s: %w", c, ErrTooLarge)
+	}
+	if err := VerifyBlock(c, data); err != nil {
+		return err
+	}
+	path := d.path(c)
+	if existing, err := os.ReadFile(path); err == nil && VerifyBlock(c, existing) == nil {
+		return nil
+	}
+	tmp, err := os.CreateTemp(d.root, ".block-*")
+	if err != nil {
+		return fmt.Errorf("create block temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write block %s: %w", c, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write block %s: %w", c, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("commit block %s: %w", c, err)
+	}
+	return nil
+}
+
+// GetBlock reads the block named by c.
+func (d *Dir) GetBlock(_ context.Context, c cid.Cid) ([]byte, error) {
+	f, err := os.Open(d.path(c))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("block %s: %w", c, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open block %s: %w", c, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, MaxBlockSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read block %s: %w", c, err)
+	}
+	if len(data) > MaxBlockSize {
+		return nil, fmt.Errorf("block %s: %w", c, ErrTooLarge)
+	}
+	return data, nil
+}
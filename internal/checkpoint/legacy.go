@@ -0,0 +1,249 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Legacy checkpoints are UnixFS files written by the IPFS /api/v0/add API,
+// named by a CIDv0 ("Qm...") or a CIDv1 dag-pb root. They are read by walking
+// the file's DAG, re-hashing every block against the link that named it.
+
+// maxLegacyDepth bounds how deep a legacy file's DAG may nest
+const maxLegacyDepth = 32
+
+// UnixFS data types a file DAG may contain
+const (
+	unixfsRaw  = 0
+	unixfsFile = 2
+)
+
+// ParseID parses a checkpoint ID: a CIDv1 raw root as written by
+// ChunkedStore, or a legacy UnixFS file root (CIDv0, or CIDv1 dag-pb).
+// Both must use sha2-256.
+func ParseID(id string) (cid.Cid, error) {
+	if c, err := ParseCID(id); err == nil {
+		return c, nil
+	}
+	c, err := cid.Decode(id)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("invalid cid %q: %w", id, err)
+	}
+	if !isLegacyCID(c) || c.Prefix().Codec != cid.DagProtobuf {
+		return cid.Undef, fmt.Errorf("cid %q is neither a checkpoint nor a UnixFS file address", id)
+	}
+	return c, nil
+}
+
+// isLegacyCID reports whether c can name a block of a UnixFS file DAG
+func isLegacyCID(c cid.Cid) bool {
+	prefix := c.Prefix()
+	if prefix.MhType != multihash.SHA2_256 {
+		return false
+	}
+	return prefix.Codec == cid.DagProtobuf || (prefix.Version == 1 && prefix.Codec == cid.Raw)
+}
+
+// legacyNode is one pending block of a UnixFS walk
+type legacyNode struct {
+	c     cid.Cid
+	depth int
+}
+
+// legacyReader streams a UnixFS file depth-first, verifying each block
+type legacyReader struct {
+	ctx     context.Context
+	store   *ChunkedStore
+	pending []legacyNode // stack; the next block is last
+	buf     []byte
+	read    int64
+	size    int64 // filesize from the root, -1 when unknown
+	err     error
+}
+
+func (s *ChunkedStore) getLegacy(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	r := &legacyReader{ctx: ctx, store: s, size: -1}
+	data, err := r.visit(legacyNode{c: c})
+	if err != nil {
+		return nil, err
+	}
+	r.buf = data
+	return r, nil
+}
+
+// visit fetches and verifies n, queues its children and returns its inline
+// file data
+func (r *legacyReader) visit(n legacyNode) ([]byte, error) {
+	if n.depth > maxLegacyDepth {
+		return nil, fmt.Errorf("block %s: file DAG deeper than %d", n.c, maxLegacyDepth)
+	}
+	raw, err := r.store.getBlock(r.ctx, n.c)
+	if err != nil {
+		return nil, err
+	}
+	if n.c.Prefix().Codec == cid.Raw {
+		return raw, nil
+	}
+	links, data, err := decodePBNode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("block %s: %w", n.c, err)
+	}
+	kind, content, filesize, err := decodeUnixFSFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("block %s: %w", n.c, err)
+	}
+	if kind != unixfsFile && kind != unixfsRaw {
+		return nil, fmt.Errorf("block %s: unixfs type %d is not a file", n.c, kind)
+	}
+	if n.depth == 0 && filesize >= 0 {
+		r.size = filesize
+	}
+	for i := len(links) - 1; i >= 0; i-- {
+		if !isLegacyCID(links[i]) {
+			return nil, fmt.Errorf("block %s: link %s is not a sha2-256 file block", n.c, links[i])
+		}
+		r.pending = append(r.pending, legacyNode{c: links[i], depth: n.depth + 1})
+	}
+	return content, nil
+}
+
+func (r *legacyReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if len(r.pending) == 0 {
+			r.err = io.EOF
+			if r.size >= 0 && r.read != r.size {
+				r.err = ErrHashMismatch
+			}
+			return 0, r.err
+		}
+		next := r.pending[len(r.pending)-1]
+		r.pending = r.pending[:len(r.pending)-1]
+		data, err := r.visit(next)
+		if err != nil {
+			r.err = err
+			return 0, r.err
+		}
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.read += int64(n)
+	return n, nil
+}
+
+func (r *legacyReader) Close() error {
+	r.buf, r.pending = nil, nil
+	if r.err == nil {
+		r.err = errors.New("checkpoint reader closed")
+	}
+	return nil
+}
+
+// decodePBNode decodes a dag-pb node into its link CIDs and data
+func decodePBNode(raw []byte) ([]cid.Cid, []byte, error) {
+	var (
+		links []cid.Cid
+		data  []byte
+	)
+	err := walkFields(raw, func(num protowire.Number, typ protowire.Type, v []byte, _ uint64) error {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			data = v
+		case num == 2 && typ == protowire.BytesType:
+			var hash []byte
+			if err := walkFields(v, func(num protowire.Number, typ protowire.Type, v []byte, _ uint64) error {
+				if num == 1 && typ == protowire.BytesType {
+					hash = v
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			c, err := cid.Cast(hash)
+			if err != nil {
+				return fmt.Errorf("invalid link: %w", err)
+			}
+			links = append(links, c)
+		}
+		return nil
+	})
+	return links, data, err
+}
+
+// decodeUnixFSFile returns the type, inline data and filesize (-1 when
+// absent) of a UnixFS data message
+func decodeUnixFSFile(raw []byte) (int, []byte, int64, error) {
+	kind, filesize := -1, int64(-1)
+	var data []byte
+	err := walkFields(raw, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			kind = int(n)
+		case num == 2 && typ == protowire.BytesType:
+			data = v
+		case num == 3 && typ == protowire.VarintType:
+			filesize = int64(n)
+		}
+		return nil
+	})
+	if err == nil && kind < 0 {
+		err = errors.New("unixfs data has no type")
+	}
+	return kind, data, filesize, err
+}
+
+// walkFields calls fn for each field of a protobuf message with its bytes
+// (length-delimited fields) or value (varints)
+func walkFields(raw []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error) error {
+	for len(raw) > 0 {
+		num, typ, tagLen := protowire.ConsumeTag(raw)
+		if tagLen < 0 {
+			return fmt.Errorf("malformed protobuf: %w", protowire.ParseError(tagLen))
+		}
+		raw = raw[tagLen:]
+		var (
+			v      []byte
+			n      uint64
+			valLen int
+		)
+		switch typ {
+		case protowire.BytesType:
+			v, valLen = protowire.ConsumeBytes(raw)
+		case protowire.VarintType:
+			n, valLen = protowire.ConsumeVarint(raw)
+		default:
+			valLen = protowire.ConsumeFieldValue(num, typ, raw)
+		}
+		if valLen < 0 {
+			return fmt.Errorf("malformed protobuf: %w", protowire.ParseError(valLen))
+		}
+		raw = raw[valLen:]
+		if err := fn(num, typ, v, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
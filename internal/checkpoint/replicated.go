@@ -0,0 +1,71 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+)
+
+// Replicated is a BlockStore that writes every block to all of its stores and
+// reads from the first store holding a matching copy. Blocks share CIDs
+// across stores, so any mix of backends composes.
+type Replicated struct {
+	stores []BlockStore
+}
+
+// NewReplicated returns a BlockStore replicating across stores, read in the
+// given order.
+func NewReplicated(stores ...BlockStore) *Replicated {
+	return &Replicated{stores: stores}
+}
+
+// PutBlock writes data to every store; it fails if any store does.
+func (r *Replicated) PutBlock(ctx context.Context, c cid.Cid, data []byte) error {
+	var errs []error
+	for i, s := range r.stores {
+		if err := s.PutBlock(ctx, c, data); err != nil {
+			errs = append(errs, fmt.Errorf("replica %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetBlock returns the first copy of c that verifies, skipping replicas that
+// miss the block or hold a corrupt copy, and re-replicates it to them.
+func (r *Replicated) GetBlock(ctx context.Context, c cid.Cid) ([]byte, error) {
+	var errs []error
+	for i, s := range r.stores {
+		data, err := s.GetBlock(ctx, c)
+		if err == nil {
+			err = VerifyBlock(c, data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replica %d: %w", i, err))
+			continue
+		}
+		for _, stale := range r.stores[:i] {
+			_ = stale.PutBlock(ctx, c, data)
+		}
+		return data, nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("block %s: %w", c, ErrNotFound)
+	}
+	return nil, errors.Join(errs...)
+}
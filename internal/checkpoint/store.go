@@ -0,0 +1,305 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkpoint stores checkpoints as content-addressed blocks. A
+// checkpoint is split into chunks, each kept as a block named by its CIDv1
+// (raw codec, sha2-256), plus a root block listing the chunks; the root's CID
+// names the checkpoint. Every block is re-hashed on read, so a store or
+// gateway cannot substitute content. Legacy checkpoints written as UnixFS files
+// (CIDv0 "Qm..." addresses) stay readable through the same verified path.
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// MaxBlockSize is the largest block a BlockStore must hold, matching the
+// IPFS block API limit.
+const MaxBlockSize = 1 << 20
+
+// DefaultChunkSize is the chunk size used when Options.ChunkSize is zero.
+const DefaultChunkSize = 256 << 10
+
+// rootFormat tags root blocks so other raw blocks are not read as checkpoints
+const rootFormat = "mohawk-checkpoint/v1"
+
+var (
+	// ErrNotFound is returned for a block or checkpoint the store does not hold.
+	ErrNotFound = errors.New("checkpoint not found")
+	// ErrHashMismatch is returned when content does not match its CID.
+	ErrHashMismatch = errors.New("checkpoint content does not match its cid")
+	// ErrTooLarge is returned when a checkpoint exceeds Options.MaxBytes or
+	// needs a root block larger than MaxBlockSize.
+	ErrTooLarge = errors.New("checkpoint too large")
+)
+
+// Store persists checkpoints and returns their CIDs. Put and Get stream, so
+// checkpoints need not fit in memory.
+type Store interface {
+	// Put stores the content of r under name and returns its CID.
+	Put(ctx context.Context, name string, r io.Reader) (string, error)
+	// Get returns the checkpoint named by id. Reads fail with
+	// ErrHashMismatch as soon as content does not match.
+	Get(ctx context.Context, id string) (io.ReadCloser, error)
+}
+
+// BlockStore holds content-addressed blocks of at most MaxBlockSize bytes.
+// Implementations must not retain data after PutBlock returns and need not
+// verify what GetBlock returns; ChunkedStore does.
+type BlockStore interface {
+	PutBlock(ctx context.Context, c cid.Cid, data []byte) error
+	GetBlock(ctx context.Context, c cid.Cid) ([]byte, error)
+}
+
+// BlockCID returns the CIDv1 (raw codec, sha2-256) of data.
+func BlockCID(data []byte) cid.Cid {
+	sum, _ := multihash.Sum(data, multihash.SHA2_256, -1) // cannot fail for sha2-256
+	return cid.NewCidV1(cid.Raw, sum)
+}
+
+// ParseCID parses id, accepting only CIDv1 raw sha2-256 block addresses.
+func ParseCID(id string) (cid.Cid, error) {
+	c, err := cid.Decode(id)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("invalid cid %q: %w", id, err)
+	}
+	prefix := c.Prefix()
+	if prefix.Version != 1 || prefix.Codec != cid.Raw || prefix.MhType != multihash.SHA2_256 {
+		return cid.Undef, fmt.Errorf("cid %q is not a CIDv1 raw sha2-256 address", id)
+	}
+	return c, nil
+}
+
+// VerifyBlock reports whether data is the block named by c, which must be a
+// sha2-256 CID.
+func VerifyBlock(c cid.Cid, data []byte) error {
+	prefix := c.Prefix()
+	if prefix.MhType != multihash.SHA2_256 {
+		return fmt.Errorf("block %s: %w", c, ErrHashMismatch)
+	}
+	if sum, err := prefix.Sum(data); err != nil || !sum.Equals(c) {
+		return fmt.Errorf("block %s: %w", c, ErrHashMismatch)
+	}
+	return nil
+}
+
+// Options tunes a ChunkedStore.
+type Options struct {
+	// ChunkSize is the size of each content block (default DefaultChunkSize,
+	// at most MaxBlockSize).
+	ChunkSize int
+	// MaxBytes bounds a stored checkpoint; 0 means unbounded.
+	MaxBytes int64
+}
+
+// root is the block that names a checkpoint's chunks
+type root struct {
+	Format    string   `json:"format"`
+	Name      string   `json:"name,omitempty"`
+	Size      int64    `json:"size"`
+	ChunkSize int      `json:"chunk_size"`
+	SHA256    string   `json:"sha256"`
+	Chunks    []string `json:"chunks"`
+}
+
+// ChunkedStore is the Store over a BlockStore.
+type ChunkedStore struct {
+	blocks BlockStore
+	opts   Options
+}
+
+// NewStore returns a Store that keeps checkpoints in blocks.
+func NewStore(blocks BlockStore, opts ...Options) *ChunkedStore {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.ChunkSize <= 0 || o.ChunkSize > MaxBlockSize {
+		o.ChunkSize = DefaultChunkSize
+	}
+	return &ChunkedStore{blocks: blocks, opts: o}
+}
+
+// Put streams r into chunk blocks and writes the root block last, so a
+// checkpoint is only addressable once all of its chunks are stored.
+func (s *ChunkedStore) Put(ctx context.Context, name string, r io.Reader) (string, error) {
+	meta := root{Format: rootFormat, Name: name, ChunkSize: s.opts.ChunkSize, Chunks: []string{}}
+	whole := sha256.New()
+	buf := make([]byte, s.opts.ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			meta.Size += int64(n)
+			if s.opts.MaxBytes > 0 && meta.Size > s.opts.MaxBytes {
+				return "", fmt.Errorf("%w: exceeds %d bytes", ErrTooLarge, s.opts.MaxBytes)
+			}
+			chunk := buf[:n]
+			whole.Write(chunk)
+			c := BlockCID(chunk)
+			if err := s.blocks.PutBlock(ctx, c, chunk); err != nil {
+				return "", fmt.Errorf("store chunk %d: %w", len(meta.Chunks), err)
+			}
+			meta.Chunks = append(meta.Chunks, c.String())
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("read checkpoint: %w", err)
+		}
+	}
+	meta.SHA256 = hex.EncodeToString(whole.Sum(nil))
+
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("encode checkpoint root: %w", err)
+	}
+	if len(raw) > MaxBlockSize {
+		return "", fmt.Errorf("%w: %d chunks do not fit one root block", ErrTooLarge, len(meta.Chunks))
+	}
+	c := BlockCID(raw)
+	if err := s.blocks.PutBlock(ctx, c, raw); err != nil {
+		return "", fmt.Errorf("store checkpoint root: %w", err)
+	}
+	return c.String(), nil
+}
+
+// Get verifies the root block named by id and returns a reader that fetches
+// and verifies one chunk at a time. Legacy UnixFS file IDs are read the same
+// way, block by block.
+func (s *ChunkedStore) Get(ctx context.Context, id string) (io.ReadCloser, error) {
+	c, err := ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Prefix().Codec == cid.DagProtobuf {
+		return s.getLegacy(ctx, c)
+	}
+	raw, err := s.getBlock(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var meta root
+	if err := json.Unmarshal(raw, &meta); err != nil || meta.Format != rootFormat {
+		return nil, fmt.Errorf("%s is not a checkpoint root", id)
+	}
+	chunks := make([]cid.Cid, len(meta.Chunks))
+	for i, chunk := range meta.Chunks {
+		if chunks[i], err = ParseCID(chunk); err != nil {
+			return nil, fmt.Errorf("checkpoint %s chunk %d: %w", id, i, err)
+		}
+	}
+	return &chunkReader{ctx: ctx, store: s, chunks: chunks, meta: meta, whole: sha256.New()}, nil
+}
+
+func (s *ChunkedStore) getBlock(ctx context.Context, c cid.Cid) ([]byte, error) {
+	data, err := s.blocks.GetBlock(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyBlock(c, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// chunkReader streams a checkpoint's chunks in order, checking the total
+// size and SHA-256 recorded in the root at EOF
+type chunkReader struct {
+	ctx    context.Context
+	store  *ChunkedStore
+	chunks []cid.Cid
+	meta   root
+	next   int
+	buf    []byte
+	read   int64
+	whole  hash.Hash
+	err    error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.next == len(r.chunks) {
+			r.err = r.finish()
+			return 0, r.err
+		}
+		data, err := r.store.getBlock(r.ctx, r.chunks[r.next])
+		if err != nil {
+			r.err = fmt.Errorf("chunk %d: %w", r.next, err)
+			return 0, r.err
+		}
+		r.next++
+		r.whole.Write(data)
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.read += int64(n)
+	return n, nil
+}
+
+func (r *chunkReader) finish() error {
+	if r.read != r.meta.Size || hex.EncodeToString(r.whole.Sum(nil)) != r.meta.SHA256 {
+		return ErrHashMismatch
+	}
+	return io.EOF
+}
+
+func (r *chunkReader) Close() error {
+	r.buf = nil
+	if r.err == nil {
+		r.err = errors.New("checkpoint reader closed")
+	}
+	return nil
+}
+
+// PutBytes stores payload in s.
+func PutBytes(ctx context.Context, s Store, name string, payload []byte) (string, error) {
+	return s.Put(ctx, name, bytes.NewReader(payload))
+}
+
+// ReadAll reads the checkpoint id from s, failing with ErrTooLarge beyond
+// limit bytes (0 means unbounded).
+func ReadAll(ctx context.Context, s Store, id string, limit int64) ([]byte, error) {
+	body, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	if limit <= 0 {
+		return io.ReadAll(body)
+	}
+	payload, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrTooLarge, limit)
+	}
+	return payload, nil
+}
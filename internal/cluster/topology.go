@@ -5,7 +5,9 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -30,19 +32,85 @@ type NodeMetadata struct {
 	IsHealthy      bool
 }
 
+// TopologyOptions tunes health checks and aggregator assignment
+type TopologyOptions struct {
+	HeartbeatTimeout time.Duration // Silence after which a node is unhealthy (default 30s)
+	MinReputation    float64       // Reputation below which a node is unhealthy (default 0.3)
+	Redundancy       int           // Aggregators per edge node (default 3)
+	LatencyScale     time.Duration // Latency at which an aggregator's score halves (default 100ms)
+}
+
+// TopologyEventKind classifies a TopologyEvent
+type TopologyEventKind int
+
+const (
+	NodeUnhealthy TopologyEventKind = iota
+	NodeRecovered
+	AssignmentChanged
+)
+
+// TopologyEvent reports a health transition or an edge node reassignment
+type TopologyEvent struct {
+	Kind        TopologyEventKind
+	NodeID      string
+	Aggregators []string // AssignmentChanged: the new assignment
+	Previous    []string // AssignmentChanged: the assignment it replaced
+}
+
 // Topology manages cluster membership and aggregation trees
 type Topology struct {
 	nodes      map[string]*NodeMetadata
 	edges      map[string][]string // node -> aggregators (redundant paths)
 	mu         sync.RWMutex
 	lastUpdate time.Time
+	opts       TopologyOptions
+
+	subMu       sync.RWMutex
+	subscribers []func(TopologyEvent)
 }
 
-func NewTopology() *Topology {
+func NewTopology(opts ...TopologyOptions) *Topology {
+	var config TopologyOptions
+	if len(opts) > 0 {
+		config = opts[0]
+	}
+	if config.HeartbeatTimeout <= 0 {
+		config.HeartbeatTimeout = 30 * time.Second
+	}
+	if config.MinReputation <= 0 {
+		config.MinReputation = 0.3
+	}
+	if config.Redundancy <= 0 {
+		config.Redundancy = 3
+	}
+	if config.LatencyScale <= 0 {
+		config.LatencyScale = 100 * time.Millisecond
+	}
 	return &Topology{
 		nodes:      make(map[string]*NodeMetadata),
 		edges:      make(map[string][]string),
 		lastUpdate: time.Now(),
+		opts:       config,
+	}
+}
+
+// OnChange registers fn for every health transition and reassignment.
+// Events are delivered in order from the goroutine running HealthCheck,
+// after the topology lock is released.
+func (t *Topology) OnChange(fn func(TopologyEvent)) {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	t.subscribers = append(t.subscribers, fn)
+}
+
+func (t *Topology) publish(events []TopologyEvent) {
+	t.subMu.RLock()
+	subscribers := append(make([]func(TopologyEvent), 0, len(t.subscribers)), t.subscribers...)
+	t.subMu.RUnlock()
+	for _, ev := range events {
+		for _, fn := range subscribers {
+			fn(ev)
+		}
 	}
 }
 
@@ -62,18 +130,76 @@ func (t *Topology) RegisterNode(id string, role NodeRole) error {
 	return nil
 }
 
-// AssignAggregator maps edge node to aggregators (with 3-way redundancy)
-func (t *Topology) AssignAggregator(edgeNodeID string, aggregators []string) error {
+// SetRegion records the region a node runs in, used for assignment diversity
+func (t *Topology) SetRegion(nodeID, region string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	// Assign 2-3 aggregators for path diversity
-	numPaths := 3
-	if len(aggregators) < numPaths {
-		numPaths = len(aggregators)
+	node, exists := t.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node %s not registered", nodeID)
 	}
+	node.AssignedRegion = region
+	return nil
+}
+
+// Heartbeat records that a node is alive, with its latest measured latency
+// (0 leaves the latency unchanged). Health is re-evaluated by HealthCheck.
+func (t *Topology) Heartbeat(nodeID string, latency time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	t.edges[edgeNodeID] = aggregators[:numPaths]
+	node, exists := t.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node %s not registered", nodeID)
+	}
+	node.LastHeartbeat = time.Now()
+	if latency > 0 {
+		if node.Latency == 0 {
+			node.Latency = latency
+		} else {
+			// Smooth the latency so one slow probe does not trigger a reassignment
+			node.Latency = (4*node.Latency + latency) / 5
+		}
+	}
+	return nil
+}
+
+// GetNode returns a copy of a node's metadata
+func (t *Topology) GetNode(nodeID string) (NodeMetadata, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	node, exists := t.nodes[nodeID]
+	if !exists {
+		return NodeMetadata{}, false
+	}
+	return *node, true
+}
+
+// IsHealthy reports whether a node passed the last health check. Nodes the
+// topology does not know are not judged and report healthy.
+func (t *Topology) IsHealthy(nodeID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	node, exists := t.nodes[nodeID]
+	return !exists || node.IsHealthy
+}
+
+// AssignAggregator maps an edge node to Redundancy aggregators chosen from
+// candidates (every registered regional aggregator when empty). Healthy
+// candidates are ranked by reputation discounted by latency, then by how
+// many edge nodes they already serve, and spread across regions first.
+func (t *Topology) AssignAggregator(edgeNodeID string, aggregators []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	selected := t.selectAggregatorsLocked(aggregators, t.opts.Redundancy, nil, t.aggregatorLoadLocked())
+	if len(selected) == 0 {
+		return fmt.Errorf("no healthy aggregator available for %s", edgeNodeID)
+	}
+	t.edges[edgeNodeID] = selected
 	return nil
 }
 
@@ -87,7 +213,92 @@ func (t *Topology) GetAssignedAggregators(edgeNodeID string) ([]string, error) {
 		return nil, fmt.Errorf("node %s not assigned", edgeNodeID)
 	}
 
-	return aggs, nil
+	return append([]string(nil), aggs...), nil
+}
+
+// aggregatorScore ranks an aggregator: reputation, halved at LatencyScale
+func (t *Topology) aggregatorScore(node *NodeMetadata) float64 {
+	return node.Reputation / (1 + float64(node.Latency)/float64(t.opts.LatencyScale))
+}
+
+// aggregatorLoadLocked counts the edge nodes each aggregator serves
+func (t *Topology) aggregatorLoadLocked() map[string]int {
+	load := make(map[string]int)
+	for _, aggs := range t.edges {
+		for _, agg := range aggs {
+			load[agg]++
+		}
+	}
+	return load
+}
+
+// selectAggregatorsLocked picks up to k healthy aggregators from candidates
+// (all regional aggregators when empty), skipping those in keep and
+// preferring regions keep does not already cover
+func (t *Topology) selectAggregatorsLocked(candidates []string, k int, keep []string, load map[string]int) []string {
+	if len(candidates) == 0 {
+		for id, node := range t.nodes {
+			if node.Role == RegionalAggregator {
+				candidates = append(candidates, id)
+			}
+		}
+	}
+	excluded := make(map[string]bool, len(keep))
+	usedRegions := make(map[string]bool)
+	for _, id := range keep {
+		excluded[id] = true
+		if node, ok := t.nodes[id]; ok && node.AssignedRegion != "" {
+			usedRegions[node.AssignedRegion] = true
+		}
+	}
+
+	var eligible []*NodeMetadata
+	for _, id := range candidates {
+		node, ok := t.nodes[id]
+		if !ok || excluded[id] || !node.IsHealthy || node.Reputation < t.opts.MinReputation {
+			continue
+		}
+		excluded[id] = true // ignore duplicate candidates
+		eligible = append(eligible, node)
+	}
+	sort.Slice(eligible, func(i, j int) bool {
+		si, sj := t.aggregatorScore(eligible[i]), t.aggregatorScore(eligible[j])
+		if si != sj {
+			return si > sj
+		}
+		if li, lj := load[eligible[i].ID], load[eligible[j].ID]; li != lj {
+			return li < lj
+		}
+		return eligible[i].ID < eligible[j].ID
+	})
+
+	// First pass: best aggregator per uncovered region; second pass: fill
+	selected := make([]string, 0, k)
+	picked := make(map[string]bool)
+	for _, node := range eligible {
+		if len(selected) == k {
+			break
+		}
+		if node.AssignedRegion != "" && usedRegions[node.AssignedRegion] {
+			continue
+		}
+		usedRegions[node.AssignedRegion] = node.AssignedRegion != ""
+		picked[node.ID] = true
+		selected = append(selected, node.ID)
+	}
+	for _, node := range eligible {
+		if len(selected) == k {
+			break
+		}
+		if !picked[node.ID] {
+			picked[node.ID] = true
+			selected = append(selected, node.ID)
+		}
+	}
+	for _, id := range selected {
+		load[id]++
+	}
+	return selected
 }
 
 // UpdateReputation adjusts Byzantine scoring
@@ -106,33 +317,98 @@ func (t *Topology) UpdateReputation(nodeID string, delta float64) {
 	}
 }
 
-// HealthCheck identifies nodes to exclude
+// HealthCheck identifies nodes to exclude and moves edge nodes off
+// unhealthy aggregators, notifying OnChange subscribers of every change
 func (t *Topology) HealthCheck() []string {
 	t.mu.Lock()
-	defer t.mu.Unlock()
 
 	unhealthy := make([]string, 0)
 	now := time.Now()
+	var events []TopologyEvent
 
-	for id, node := range t.nodes {
-		// Heartbeat timeout
-		if now.Sub(node.LastHeartbeat) > 30*time.Second {
-			node.IsHealthy = false
+	ids := make([]string, 0, len(t.nodes))
+	for id := range t.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		node := t.nodes[id]
+		// Heartbeat timeout, or low reputation = Byzantine
+		healthy := now.Sub(node.LastHeartbeat) <= t.opts.HeartbeatTimeout && node.Reputation >= t.opts.MinReputation
+		if healthy != node.IsHealthy {
+			kind := NodeRecovered
+			if !healthy {
+				kind = NodeUnhealthy
+			}
+			events = append(events, TopologyEvent{Kind: kind, NodeID: id})
+		}
+		node.IsHealthy = healthy
+		if !healthy {
 			unhealthy = append(unhealthy, id)
-			continue
 		}
+	}
 
-		// Low reputation = Byzantine
-		if node.Reputation < 0.3 {
-			node.IsHealthy = false
-			unhealthy = append(unhealthy, id)
+	events = append(events, t.failoverLocked()...)
+	t.lastUpdate = now
+	t.mu.Unlock()
+
+	t.publish(events)
+	return unhealthy
+}
+
+// failoverLocked replaces unhealthy aggregators in every assignment and tops
+// assignments back up to Redundancy where healthy aggregators allow
+func (t *Topology) failoverLocked() []TopologyEvent {
+	edgeIDs := make([]string, 0, len(t.edges))
+	for id := range t.edges {
+		edgeIDs = append(edgeIDs, id)
+	}
+	sort.Strings(edgeIDs)
+
+	load := t.aggregatorLoadLocked()
+	var events []TopologyEvent
+	for _, edgeID := range edgeIDs {
+		previous := t.edges[edgeID]
+		keep := make([]string, 0, len(previous))
+		for _, agg := range previous {
+			if node, ok := t.nodes[agg]; ok && !node.IsHealthy {
+				load[agg]--
+				continue
+			}
+			keep = append(keep, agg)
+		}
+		if len(keep) == len(previous) && len(keep) >= t.opts.Redundancy {
 			continue
 		}
-
-		node.IsHealthy = true
+		replacements := t.selectAggregatorsLocked(nil, t.opts.Redundancy-len(keep), keep, load)
+		if len(keep) == len(previous) && len(replacements) == 0 {
+			continue
+		}
+		assigned := append(keep, replacements...)
+		t.edges[edgeID] = assigned
+		events = append(events, TopologyEvent{
+			Kind:        AssignmentChanged,
+			NodeID:      edgeID,
+			Aggregators: append([]string(nil), assigned...),
+			Previous:    append([]string(nil), previous...),
+		})
 	}
+	return events
+}
 
-	return unhealthy
+// RunHealthMonitor runs HealthCheck every interval until ctx is done
+func (t *Topology) RunHealthMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.HealthCheck()
+		}
+	}
 }
 
 // GetNodeCount returns total nodes
@@ -0,0 +1,141 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestTopology registers edge-1 and aggregators with the given regions
+func newTestTopology(t *testing.T, regions map[string]string) *Topology {
+	t.Helper()
+	topo := NewTopology()
+	if err := topo.RegisterNode("edge-1", EdgeNode); err != nil {
+		t.Fatal(err)
+	}
+	for id, region := range regions {
+		if err := topo.RegisterNode(id, RegionalAggregator); err != nil {
+			t.Fatal(err)
+		}
+		if err := topo.SetRegion(id, region); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return topo
+}
+
+// expireHeartbeat backdates a node's last heartbeat past the timeout
+func expireHeartbeat(topo *Topology, nodeID string) {
+	topo.mu.Lock()
+	defer topo.mu.Unlock()
+	topo.nodes[nodeID].LastHeartbeat = time.Now().Add(-2 * topo.opts.HeartbeatTimeout)
+}
+
+// TestHeartbeatKeepsNodeHealthy verifies heartbeat timeouts and recovery
+func TestHeartbeatKeepsNodeHealthy(t *testing.T) {
+	topo := newTestTopology(t, nil)
+	if err := topo.Heartbeat("missing", 0); err == nil {
+		t.Fatal("expected a heartbeat from an unregistered node to be refused")
+	}
+
+	expireHeartbeat(topo, "edge-1")
+	if unhealthy := topo.HealthCheck(); !reflect.DeepEqual(unhealthy, []string{"edge-1"}) {
+		t.Fatalf("expected edge-1 to time out, got %v", unhealthy)
+	}
+	if err := topo.Heartbeat("edge-1", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if unhealthy := topo.HealthCheck(); len(unhealthy) != 0 {
+		t.Fatalf("expected edge-1 to recover after a heartbeat, got %v", unhealthy)
+	}
+
+	// Later samples are smoothed into the recorded latency
+	_ = topo.Heartbeat("edge-1", 70*time.Millisecond)
+	if node, _ := topo.GetNode("edge-1"); node.Latency != 30*time.Millisecond {
+		t.Fatalf("expected smoothed latency 30ms, got %v", node.Latency)
+	}
+}
+
+// TestAssignAggregatorRanksAndDiversifies verifies reputation, latency and region ordering
+func TestAssignAggregatorRanksAndDiversifies(t *testing.T) {
+	topo := newTestTopology(t, map[string]string{
+		"agg-a": "us", "agg-b": "us", "agg-c": "eu", "agg-d": "ap", "agg-e": "ap",
+	})
+	_ = topo.Heartbeat("agg-a", 10*time.Millisecond)
+	_ = topo.Heartbeat("agg-b", 5*time.Millisecond)
+	_ = topo.Heartbeat("agg-c", 50*time.Millisecond)
+	_ = topo.Heartbeat("agg-d", 10*time.Millisecond)
+	_ = topo.Heartbeat("agg-e", 10*time.Millisecond)
+	topo.UpdateReputation("agg-d", -0.5)
+
+	if err := topo.AssignAggregator("edge-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	// agg-b beats agg-a in us on latency; agg-e beats agg-d in ap on reputation
+	got, _ := topo.GetAssignedAggregators("edge-1")
+	if want := []string{"agg-b", "agg-e", "agg-c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Candidates restrict the pool; unregistered ones are skipped
+	if err := topo.AssignAggregator("edge-1", []string{"agg-a", "ghost"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := topo.GetAssignedAggregators("edge-1"); !reflect.DeepEqual(got, []string{"agg-a"}) {
+		t.Fatalf("expected [agg-a], got %v", got)
+	}
+	if err := topo.AssignAggregator("edge-1", []string{"ghost"}); err == nil {
+		t.Fatal("expected an assignment with no eligible aggregator to fail")
+	}
+}
+
+// TestHealthCheckFailsOverEdgeNodes verifies reassignment and change notifications
+func TestHealthCheckFailsOverEdgeNodes(t *testing.T) {
+	topo := newTestTopology(t, map[string]string{
+		"agg-a": "us", "agg-b": "eu", "agg-c": "ap", "agg-d": "us",
+	})
+	_ = topo.Heartbeat("agg-d", 80*time.Millisecond)
+	if err := topo.AssignAggregator("edge-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := topo.GetAssignedAggregators("edge-1")
+	if want := []string{"agg-a", "agg-b", "agg-c"}; !reflect.DeepEqual(before, want) {
+		t.Fatalf("expected %v, got %v", want, before)
+	}
+
+	var events []TopologyEvent
+	topo.OnChange(func(ev TopologyEvent) { events = append(events, ev) })
+
+	expireHeartbeat(topo, "agg-a")
+	if unhealthy := topo.HealthCheck(); !reflect.DeepEqual(unhealthy, []string{"agg-a"}) {
+		t.Fatalf("expected agg-a unhealthy, got %v", unhealthy)
+	}
+	after, _ := topo.GetAssignedAggregators("edge-1")
+	if want := []string{"agg-b", "agg-c", "agg-d"}; !reflect.DeepEqual(after, want) {
+		t.Fatalf("expected failover to %v, got %v", want, after)
+	}
+	want := []TopologyEvent{
+		{Kind: NodeUnhealthy, NodeID: "agg-a"},
+		{Kind: AssignmentChanged, NodeID: "edge-1", Aggregators: after, Previous: before},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected events %+v, got %+v", want, events)
+	}
+
+	// A second check with nothing changed stays quiet
+	events = nil
+	topo.HealthCheck()
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+
+	// Recovery is reported, but edge-1 keeps its working assignment
+	_ = topo.Heartbeat("agg-a", 0)
+	topo.HealthCheck()
+	if len(events) != 1 || events[0].Kind != NodeRecovered || events[0].NodeID != "agg-a" {
+		t.Fatalf("expected agg-a recovery, got %+v", events)
+	}
+	if got, _ := topo.GetAssignedAggregators("edge-1"); !reflect.DeepEqual(got, after) {
+		t.Fatalf("expected assignment to stay %v, got %v", after, got)
+	}
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TrimmedMeanAggregate computes the coordinate-wise mean after discarding the
+// f smallest and f largest values of every coordinate.
+//
+// outliers[i] counts the coordinates in which update i fell into a trimmed tail.
+// @ requires len(updates) > 2*f
+// @ requires f >= 0
+// @ ensures err == nil ==> len(outliers) == len(updates)
+func TrimmedMeanAggregate(updates [][]float64, f int) ([]float64, []int, error) {
+	if err := validateCoordinateInput(updates, f); err != nil {
+		return nil, nil, err
+	}
+	n := len(updates)
+	if n <= 2*f {
+		return nil, nil, fmt.Errorf("trimmed mean requires n > 2f (n=%d f=%d)", n, f)
+	}
+
+	kept := float64(n - 2*f)
+	agg, outliers := coordinateReduce(updates, f, func(sorted []float64) float64 {
+		sum := 0.0
+		for _, v := range sorted[f : n-f] {
+			sum += v
+		}
+		return sum / kept
+	})
+	return agg, outliers, nil
+}
+
+// CoordinateMedianAggregate computes the coordinate-wise median. Updates in
+// the f most extreme positions on either side of a coordinate are reported
+// as outliers for that coordinate.
+// @ requires len(updates) > 0
+// @ requires f >= 0
+// @ ensures err == nil ==> len(outliers) == len(updates)
+func CoordinateMedianAggregate(updates [][]float64, f int) ([]float64, []int, error) {
+	if err := validateCoordinateInput(updates, f); err != nil {
+		return nil, nil, err
+	}
+	n := len(updates)
+	if 2*f >= n {
+		f = (n - 1) / 2
+	}
+
+	agg, outliers := coordinateReduce(updates, f, func(sorted []float64) float64 {
+		mid := n / 2
+		if n%2 == 1 {
+			return sorted[mid]
+		}
+		return (sorted[mid-1] + sorted[mid]) / 2
+	})
+	return agg, outliers, nil
+}
+
+func validateCoordinateInput(updates [][]float64, f int) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("coordinate-wise aggregation requires at least one update")
+	}
+	if f < 0 {
+		return fmt.Errorf("coordinate-wise aggregation requires non-negative f")
+	}
+	dim := len(updates[0])
+	for i := 1; i < len(updates); i++ {
+		if len(updates[i]) != dim {
+			return fmt.Errorf("gradient %d dimension %d != %d", i, len(updates[i]), dim)
+		}
+	}
+	return nil
+}
+
+// coordinateReduce sorts each coordinate across updates, applies reduce, and
+// tallies which updates landed in the f-wide tails.
+func coordinateReduce(updates [][]float64, f int, reduce func(sorted []float64) float64) ([]float64, []int) {
+	n := len(updates)
+	dim := len(updates[0])
+	agg := make([]float64, dim)
+	outliers := make([]int, n)
+
+	type column struct {
+		idx int
+		val float64
+	}
+	col := make([]column, n)
+	sorted := make([]float64, n)
+	for d := 0; d < dim; d++ {
+		for i := 0; i < n; i++ {
+			col[i] = column{idx: i, val: updates[i][d]}
+		}
+		sort.Slice(col, func(a, b int) bool {
+			if col[a].val == col[b].val {
+				return col[a].idx < col[b].idx
+			}
+			return col[a].val < col[b].val
+		})
+		for i := range col {
+			sorted[i] = col[i].val
+		}
+		agg[d] = reduce(sorted)
+		for k := 0; k < f; k++ {
+			outliers[col[k].idx]++
+			outliers[col[n-1-k].idx]++
+		}
+	}
+	return agg, outliers
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrimmedMeanAggregate(t *testing.T) {
+	updates := [][]float64{
+		{1.0, 2.0},
+		{1.2, 2.2},
+		{0.8, 1.8},
+		{50.0, -50.0}, // outlier
+		{1.0, 2.0},
+	}
+	agg, outliers, err := TrimmedMeanAggregate(updates, 1)
+	if err != nil {
+		t.Fatalf("TrimmedMeanAggregate failed: %v", err)
+	}
+	if math.Abs(agg[0]-1.0667) > 1e-3 || math.Abs(agg[1]-1.9333) > 1e-3 {
+		t.Fatalf("trimmed mean contaminated: %v", agg)
+	}
+	if outliers[3] != 2 {
+		t.Fatalf("outlier should be trimmed in both coordinates, got %d", outliers[3])
+	}
+	if _, _, err := TrimmedMeanAggregate(updates, 3); err == nil {
+		t.Fatal("expected n <= 2f to be rejected")
+	}
+}
+
+func TestCoordinateMedianAggregate(t *testing.T) {
+	updates := [][]float64{
+		{1.0, 3.0},
+		{2.0, 1.0},
+		{100.0, 2.0},
+		{3.0, 4.0},
+	}
+	agg, outliers, err := CoordinateMedianAggregate(updates, 1)
+	if err != nil {
+		t.Fatalf("CoordinateMedianAggregate failed: %v", err)
+	}
+	if agg[0] != 2.5 || agg[1] != 2.5 {
+		t.Fatalf("median = %v, want [2.5 2.5]", agg)
+	}
+	if outliers[2] != 1 {
+		t.Fatalf("largest value in coordinate 0 should be flagged, got %v", outliers)
+	}
+	if _, _, err := CoordinateMedianAggregate([][]float64{{1}, {1, 2}}, 0); err == nil {
+		t.Fatal("expected dimension mismatch to be rejected")
+	}
+}
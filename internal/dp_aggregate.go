@@ -0,0 +1,97 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// noisyWeightedMean releases the Gaussian-mechanism estimate of a weighted
+// mean. Each entry of scaled is an update already multiplied by its weight.
+//
+// Weights are normalized by their maximum so every client contributes at most
+// clipNorm to the weighted sum, which bounds its ℓ₂ sensitivity by clipNorm.
+// Noise N(0, (sigma·clipNorm)²) is added per coordinate of that sum before
+// dividing by the total normalized weight.
+func noisyWeightedMean(scaled [][]float64, weights []float64, clipNorm, sigma float64) ([]float64, error) {
+	if len(scaled) == 0 {
+		return nil, fmt.Errorf("no updates to aggregate")
+	}
+	if len(weights) != len(scaled) {
+		return nil, fmt.Errorf("got %d weights for %d updates", len(weights), len(scaled))
+	}
+	if clipNorm <= 0 || math.IsNaN(clipNorm) || math.IsInf(clipNorm, 0) {
+		return nil, fmt.Errorf("dp clip norm must be positive and finite")
+	}
+	if sigma < 0 || math.IsNaN(sigma) || math.IsInf(sigma, 0) {
+		return nil, fmt.Errorf("dp noise multiplier must be non-negative and finite")
+	}
+	maxWeight := 0.0
+	for _, w := range weights {
+		maxWeight = math.Max(maxWeight, w)
+	}
+	if maxWeight <= 0 {
+		return nil, fmt.Errorf("selected updates carry no weight")
+	}
+
+	dim := len(scaled[0])
+	sum := make([]float64, dim)
+	totalWeight := 0.0
+	for i, update := range scaled {
+		if len(update) != dim {
+			return nil, fmt.Errorf("gradient %d dimension %d != %d", i, len(update), dim)
+		}
+		w := weights[i] / maxWeight
+		if w <= 0 {
+			continue
+		}
+		factor := 1 / maxWeight
+		if norm := maxGradNorm([][]float64{update}); norm*factor > clipNorm*w {
+			factor = clipNorm * w / norm
+		}
+		for j, v := range update {
+			sum[j] += v * factor
+		}
+		totalWeight += w
+	}
+
+	if err := addGaussianNoise(sum, sigma*clipNorm); err != nil {
+		return nil, err
+	}
+	for j := range sum {
+		sum[j] /= totalWeight
+	}
+	return sum, nil
+}
+
+// addGaussianNoise adds N(0, std²) to each coordinate of vec using a ChaCha8
+// stream seeded from crypto/rand.
+func addGaussianNoise(vec []float64, std float64) error {
+	if std <= 0 {
+		return nil
+	}
+	var seed [32]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return fmt.Errorf("seed dp noise: %w", err)
+	}
+	rng := rand.New(rand.NewChaCha8(seed))
+	for i := range vec {
+		vec[i] += rng.NormFloat64() * std
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoisyWeightedMeanClipsBeforeAveraging(t *testing.T) {
+	// Second update has weight 0.5 and is passed pre-scaled, as in the pipeline.
+	scaled := [][]float64{{3, 4}, {0, 0.25}}
+	got, err := noisyWeightedMean(scaled, []float64{1, 0.5}, 1, 0)
+	if err != nil {
+		t.Fatalf("noisyWeightedMean failed: %v", err)
+	}
+	want := []float64{0.4, 0.7}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("aggregate=%v, want %v", got, want)
+		}
+	}
+
+	if _, err := noisyWeightedMean(scaled, []float64{1, 0.5}, 0, 1); err == nil {
+		t.Fatal("expected zero clip norm to be rejected")
+	}
+}
+
+func TestProcessGradientBatchReturnsNoisedAggregate(t *testing.T) {
+	t.Setenv("MOHAWK_DP_SIGMA", "5")
+	t.Setenv("MOHAWK_DP_CLIP_NORM", "2")
+	agg := NewAggregator(Regional)
+	const dim = 20000
+	updates := make([][]float64, 4)
+	for i := range updates {
+		updates[i] = make([]float64, dim)
+	}
+
+	result, err := agg.ProcessGradientBatch(updates, len(updates), BatchProcessingOptions{})
+	if err != nil {
+		t.Fatalf("ProcessGradientBatch failed: %v", err)
+	}
+	if result.NoiseMultiplier != 5 || result.ClipNorm != 2 || len(result.Aggregate) != dim {
+		t.Fatalf("sigma=%v clip=%v dim=%d", result.NoiseMultiplier, result.ClipNorm, len(result.Aggregate))
+	}
+	var sumSq float64
+	for _, v := range result.Aggregate {
+		sumSq += v * v
+	}
+	// Noise on the sum has std sigma*clip = 10; averaging over 4 updates gives 2.5.
+	if std := math.Sqrt(sumSq / dim); math.Abs(std-2.5)/2.5 > 0.05 {
+		t.Fatalf("aggregate noise std %.3f, want about 2.5", std)
+	}
+}
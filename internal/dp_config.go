@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -26,11 +27,15 @@ const (
 	defaultDPDelta         = 1e-5
 	defaultDPMinEpsilon    = 0.2
 	defaultDPMaxEpsilon    = 2.0
+	defaultDPClipNorm      = 1.0
+	defaultDPSamplingRate  = 1.0
 )
 
 // DPConfig defines the single runtime source of truth for DP-SGD privacy knobs.
 type DPConfig struct {
 	Sigma         float64
+	ClipNorm      float64
+	SamplingRate  float64
 	TargetEpsilon float64
 	Delta         float64
 	Adaptive      bool
@@ -49,6 +54,8 @@ func LoadDPConfig() DPConfig {
 
 	return DPConfig{
 		Sigma:         envFloat("MOHAWK_DP_SIGMA", defaultDPSigma),
+		ClipNorm:      envFloat("MOHAWK_DP_CLIP_NORM", defaultDPClipNorm),
+		SamplingRate:  math.Min(envFloat("MOHAWK_DP_SAMPLING_RATE", defaultDPSamplingRate), 1),
 		TargetEpsilon: defaultDPTargetEpsilon,
 		Delta:         envFloat("MOHAWK_DP_DELTA", defaultDPDelta),
 		Adaptive:      envBool("MOHAWK_DP_ADAPTIVE_ENABLED", false),
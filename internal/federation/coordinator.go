@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"sync"
 	"time"
 )
@@ -21,10 +22,29 @@ type Coordinator struct {
 	// Statistics
 	statsmu           sync.RWMutex
 	totalRounds       uint64
+	failedRounds      uint64
 	lastRoundTimeMs   float64
 	participationRate float64
+
+	// Round outcomes and global model publication
+	roundMu     sync.Mutex
+	outcomes    map[uint64]roundOutcome
+	waiters     map[uint64][]chan struct{}
+	latest      *GlobalModel
+	subscribers []chan GlobalModel
+	current     uint64 // most recently opened round
+	hasCurrent  bool
+}
+
+// roundOutcome records how a round finished at this tier
+type roundOutcome struct {
+	resp AggregationResponse
+	err  error
 }
 
+// maxRoundOutcomes bounds the per-coordinator round history
+const maxRoundOutcomes = 1024
+
 // NewCoordinator creates a federation coordinator for a specific tier
 func NewCoordinator(config TierConfig, serverAddr string, parentAddr string) (*Coordinator, error) {
 	handler, err := NewRPCHandler(config, serverAddr)
@@ -37,11 +57,148 @@ func NewCoordinator(config TierConfig, serverAddr string, parentAddr string) (*C
 		client = NewRPCClient(config, parentAddr)
 	}
 
-	return &Coordinator{
+	c := &Coordinator{
 		config:    config,
 		rpcServer: handler,
 		rpcClient: client,
-	}, nil
+		outcomes:  make(map[uint64]roundOutcome),
+		waiters:   make(map[uint64][]chan struct{}),
+	}
+
+	// Tier aggregates flow upward; the root publishes the global model
+	if client != nil {
+		handler.SetParentClient(client)
+	}
+	handler.OnAggregate(c.onRoundComplete)
+
+	return c, nil
+}
+
+// OpenRound begins collecting child gradients for a round. A zero DeadlineMs
+// uses the tier's AggregationTimeoutMs.
+func (c *Coordinator) OpenRound(req AggregationRequest) error {
+	if err := c.rpcServer.OpenRound(req); err != nil {
+		return err
+	}
+	c.roundMu.Lock()
+	c.current, c.hasCurrent = req.RoundID, true
+	c.roundMu.Unlock()
+	return nil
+}
+
+// CurrentRound returns the most recently opened round while it is still
+// collecting at this tier. It is the RoundSource for a ParentTierSink.
+func (c *Coordinator) CurrentRound() (uint64, bool) {
+	c.roundMu.Lock()
+	defer c.roundMu.Unlock()
+	return c.current, c.hasCurrent
+}
+
+// AwaitRound blocks until the round completes or fails at this tier
+func (c *Coordinator) AwaitRound(ctx context.Context, roundID uint64) (AggregationResponse, error) {
+	c.roundMu.Lock()
+	if outcome, ok := c.outcomes[roundID]; ok {
+		c.roundMu.Unlock()
+		return outcome.resp, outcome.err
+	}
+	ch := make(chan struct{})
+	c.waiters[roundID] = append(c.waiters[roundID], ch)
+	c.roundMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return AggregationResponse{}, ctx.Err()
+	case <-ch:
+	}
+
+	c.roundMu.Lock()
+	defer c.roundMu.Unlock()
+	outcome := c.outcomes[roundID]
+	return outcome.resp, outcome.err
+}
+
+// SubscribeGlobalModel returns a channel receiving each global model the root
+// publishes. Slow subscribers miss models rather than stalling aggregation.
+func (c *Coordinator) SubscribeGlobalModel() <-chan GlobalModel {
+	ch := make(chan GlobalModel, 16)
+	c.roundMu.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.roundMu.Unlock()
+	return ch
+}
+
+// LatestGlobalModel returns the most recently published global model
+func (c *Coordinator) LatestGlobalModel() (GlobalModel, bool) {
+	c.roundMu.Lock()
+	defer c.roundMu.Unlock()
+	if c.latest == nil {
+		return GlobalModel{}, false
+	}
+	return *c.latest, true
+}
+
+// onRoundComplete records round outcomes and, at the root, publishes the model
+func (c *Coordinator) onRoundComplete(round uint64, resp *AggregationResponse, aggregate *GradientMessage, err error) {
+	c.statsmu.Lock()
+	if err != nil {
+		c.failedRounds++
+	} else {
+		c.totalRounds++
+		c.lastRoundTimeMs = float64(resp.AggregationTimeMs)
+		if n := len(c.config.ChildNodeIDs); n > 0 {
+			c.participationRate = float64(resp.AggregatedCount) / float64(n)
+		}
+	}
+	c.statsmu.Unlock()
+
+	c.roundMu.Lock()
+	defer c.roundMu.Unlock()
+
+	outcome := roundOutcome{err: err}
+	if resp != nil {
+		outcome.resp = *resp
+	}
+	c.outcomes[round] = outcome
+	if c.hasCurrent && c.current == round {
+		c.hasCurrent = false
+	}
+	if len(c.outcomes) > maxRoundOutcomes {
+		oldest, found := uint64(0), false
+		for r := range c.outcomes {
+			if !found || r < oldest {
+				oldest, found = r, true
+			}
+		}
+		delete(c.outcomes, oldest)
+	}
+	for _, ch := range c.waiters[round] {
+		close(ch)
+	}
+	delete(c.waiters, round)
+
+	if err != nil || c.rpcClient != nil {
+		return
+	}
+
+	model := GlobalModel{
+		RoundID:          round,
+		RootTierNodeID:   c.config.TierID,
+		GradientData:     aggregate.GradientData,
+		Norm:             aggregate.Norm,
+		ContributorCount: resp.ContributorCount,
+		PathHops:         append(append([]string(nil), aggregate.PathHops...), c.config.TierID),
+		PublishedAt:      time.Now(),
+	}
+	c.latest = &model
+	for _, sub := range c.subscribers {
+		select {
+		case sub <- model:
+		default:
+			log.Printf("WARN: [%s coordinator] global model subscriber full, dropping round %d", c.config.TierID, round)
+		}
+	}
+	log.Printf("[%s coordinator] published global model for round %d (contributors=%d norm=%.4f)",
+		c.config.TierID, round, model.ContributorCount, model.Norm)
 }
 
 // Start begins listening for child tier connections
@@ -105,6 +262,11 @@ func (c *Coordinator) ForwardBatch(ctx context.Context, gradients []*GradientMes
 	return c.rpcClient.ForwardBatch(ctx, gradients)
 }
 
+// Addr returns the address the tier's RPC handler is bound to
+func (c *Coordinator) Addr() net.Addr {
+	return c.rpcServer.Addr()
+}
+
 // Stats returns comprehensive federation statistics
 func (c *Coordinator) Stats() map[string]interface{} {
 	c.statsmu.RLock()
@@ -116,6 +278,7 @@ func (c *Coordinator) Stats() map[string]interface{} {
 		"tier":               c.config.TierID,
 		"tier_level":         tierLevelName(c.config.Level),
 		"total_rounds":       c.totalRounds,
+		"failed_rounds":      c.failedRounds,
 		"last_round_time_ms": c.lastRoundTimeMs,
 		"participation_rate": c.participationRate,
 	}
@@ -0,0 +1,257 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Multi-tier upward propagation tests
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestCoordinatorThreeTierConvergesOnMean runs edge -> regional -> continental
+// -> global over loopback TCP and checks the root publishes the true mean
+func TestCoordinatorThreeTierConvergesOnMean(t *testing.T) {
+	runThreeTier(t, "", [][]int{{3, 3}, {3, 3}})
+}
+
+// TestCoordinatorUnbalancedTreeConvergesOnMean checks the mean rule weights
+// subtrees by their edge count: an unweighted mean of means would favour the
+// small regional and continental subtrees
+func TestCoordinatorUnbalancedTreeConvergesOnMean(t *testing.T) {
+	runThreeTier(t, RuleMean, [][]int{{1, 3}, {2}})
+}
+
+// runThreeTier builds a tree where shape[c][r] is the number of edges under
+// regional r of continental c, with every tier applying rule, sends one
+// gradient per edge, and checks the root publishes the mean over all edges
+func runThreeTier(t *testing.T, rule AggregationRule, shape [][]int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	const round = uint64(11)
+	numContinental := len(shape)
+
+	start := func(config TierConfig, parentAddr string) *Coordinator {
+		t.Helper()
+		coord, err := NewCoordinator(config, "127.0.0.1:0", parentAddr)
+		if err != nil {
+			t.Fatalf("new coordinator %s: %v", config.TierID, err)
+		}
+		if err := coord.Start(ctx, "127.0.0.1:0"); err != nil {
+			t.Fatalf("start coordinator %s: %v", config.TierID, err)
+		}
+		t.Cleanup(func() { coord.Close() })
+		if err := coord.OpenRound(AggregationRequest{RoundID: round}); err != nil {
+			t.Fatalf("open round at %s: %v", config.TierID, err)
+		}
+		return coord
+	}
+
+	globalConfig := TierConfig{
+		TierID:               "global-1",
+		Level:                TierGlobal,
+		MinQuorumSize:        numContinental,
+		AggregationTimeoutMs: 8000,
+		MaxBufferedGradients: 1000,
+		AggregationRule:      rule,
+	}
+	for c := 0; c < numContinental; c++ {
+		globalConfig.ChildNodeIDs = append(globalConfig.ChildNodeIDs, fmt.Sprintf("continental-%d", c))
+	}
+	global := start(globalConfig, "")
+	models := global.SubscribeGlobalModel()
+
+	var expected [2]float64
+	totalEdges := 0
+	for c := 0; c < numContinental; c++ {
+		numRegional := len(shape[c])
+		contConfig := TierConfig{
+			TierID:               fmt.Sprintf("continental-%d", c),
+			Level:                TierContinental,
+			ParentTierNodeID:     "global-1",
+			MinQuorumSize:        numRegional,
+			AggregationTimeoutMs: 6000,
+			MaxBufferedGradients: 1000,
+			AggregationRule:      rule,
+		}
+		for r := 0; r < numRegional; r++ {
+			contConfig.ChildNodeIDs = append(contConfig.ChildNodeIDs, fmt.Sprintf("regional-%d-%d", c, r))
+		}
+		continental := start(contConfig, global.Addr().String())
+
+		for r := 0; r < numRegional; r++ {
+			numEdges := shape[c][r]
+			regConfig := TierConfig{
+				TierID:               fmt.Sprintf("regional-%d-%d", c, r),
+				Level:                TierRegional,
+				ParentTierNodeID:     contConfig.TierID,
+				MinQuorumSize:        numEdges,
+				AggregationTimeoutMs: 4000,
+				MaxBufferedGradients: 1000,
+				AggregationRule:      rule,
+			}
+			for e := 0; e < numEdges; e++ {
+				regConfig.ChildNodeIDs = append(regConfig.ChildNodeIDs, fmt.Sprintf("edge-%d-%d-%d", c, r, e))
+			}
+			regional := start(regConfig, continental.Addr().String())
+
+			for e, edgeID := range regConfig.ChildNodeIDs {
+				v := float64(10*c + 3*r + e)
+				expected[0] += v
+				expected[1] += -2 * v
+				totalEdges++
+
+				edge := NewGRPCClientBackend(TierConfig{TierID: edgeID}, regional.Addr().String())
+				err := edge.SendGradient(ctx, &GradientMessage{
+					GradientID:       fmt.Sprintf("%s-r%d", edgeID, round),
+					SourceNodeID:     edgeID,
+					AggregationRound: round,
+					DimensionCount:   2,
+					GradientData:     []float64{v, -2 * v},
+					PathHops:         []string{edgeID},
+				})
+				edge.Close()
+				if err != nil {
+					t.Fatalf("edge %s send failed: %v", edgeID, err)
+				}
+			}
+		}
+	}
+	expected[0] /= float64(totalEdges)
+	expected[1] /= float64(totalEdges)
+
+	resp, err := global.AwaitRound(ctx, round)
+	if err != nil {
+		t.Fatalf("global round failed: %v", err)
+	}
+	if resp.AggregatedCount != numContinental {
+		t.Fatalf("global aggregated %d children, want %d", resp.AggregatedCount, numContinental)
+	}
+
+	select {
+	case model := <-models:
+		if model.RoundID != round {
+			t.Fatalf("published round %d, want %d", model.RoundID, round)
+		}
+		if model.ContributorCount != totalEdges {
+			t.Fatalf("model stands for %d edges, want %d", model.ContributorCount, totalEdges)
+		}
+		for i := range expected {
+			if math.Abs(model.GradientData[i]-expected[i]) > 1e-9 {
+				t.Fatalf("global model = %v, want %v", model.GradientData, expected)
+			}
+		}
+		// One tier chain, with no edge IDs
+		if want := []string{"regional-0-0", "continental-0", "global-1"}; !slices.Equal(model.PathHops, want) {
+			t.Fatalf("path hops %v, want %v", model.PathHops, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("global model was never published")
+	}
+
+	if _, ok := global.LatestGlobalModel(); !ok {
+		t.Fatal("root should retain the latest global model")
+	}
+}
+
+func TestCoordinatorRoundFailsWithoutQuorum(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	coord, err := NewCoordinator(TierConfig{
+		TierID:               "global-quorum",
+		Level:                TierGlobal,
+		ChildNodeIDs:         []string{"a", "b"},
+		MinQuorumSize:        2,
+		MaxBufferedGradients: 100,
+	}, "127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	if err := coord.Start(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer coord.Close()
+
+	if err := coord.OpenRound(AggregationRequest{RoundID: 5, DeadlineMs: 100}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	if err := coord.rpcServer.receiveGradient(&GradientMessage{
+		SourceTierNodeID: "a", AggregationRound: 5, DimensionCount: 1, GradientData: []float64{1},
+	}); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+
+	if _, err := coord.AwaitRound(ctx, 5); err == nil {
+		t.Fatal("expected round to fail without quorum")
+	}
+	late := &GradientMessage{SourceTierNodeID: "b", AggregationRound: 5, DimensionCount: 1, GradientData: []float64{1}}
+	if err := coord.rpcServer.receiveGradient(late); err == nil {
+		t.Fatal("expected late gradient for a closed round to be rejected")
+	}
+}
+
+func TestCoordinatorRoundFailsOnBufferOverflow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	coord, err := NewCoordinator(TierConfig{
+		TierID:               "regional-overflow",
+		MinQuorumSize:        1,
+		AggregationTimeoutMs: 60_000,
+		MaxBufferedGradients: 2,
+	}, "", "")
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	for _, round := range []uint64{1, 2} {
+		if err := coord.OpenRound(AggregationRequest{RoundID: round}); err != nil {
+			t.Fatalf("open round: %v", err)
+		}
+	}
+	handler := coord.rpcServer
+	for _, g := range append(childGradients(1, [][]float64{{1}, {2}}), childGradients(2, [][]float64{{3}})...) {
+		handler.bufferGradient(g)
+	}
+
+	if _, err := coord.AwaitRound(ctx, 1); err == nil || ctx.Err() != nil {
+		t.Fatalf("expected the overflowed round to fail promptly, got %v", err)
+	}
+	resend := childGradients(1, [][]float64{{1}})[0]
+	if err := handler.receiveGradient(resend); !errors.Is(err, errRoundClosed) {
+		t.Fatalf("expected a resubmission to the dropped round to be refused, got %v", err)
+	}
+}
+
+func TestCoordinatorRoundFailsWhenAggregationFails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	coord, err := NewCoordinator(TierConfig{
+		TierID:               "regional-agg-fail",
+		MinQuorumSize:        2,
+		MaxBufferedGradients: 100,
+	}, "", "")
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	if err := coord.OpenRound(AggregationRequest{RoundID: 3, RequestedCount: 2}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	// Gradients with no data leave nothing to aggregate
+	for _, g := range childGradients(3, [][]float64{{}, {}}) {
+		coord.rpcServer.bufferGradient(g)
+	}
+	coord.rpcServer.flushPendingAggregations(ctx)
+
+	if _, err := coord.AwaitRound(ctx, 3); err == nil || ctx.Err() != nil {
+		t.Fatalf("expected the round to fail promptly, got %v", err)
+	}
+}
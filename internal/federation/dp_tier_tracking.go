@@ -13,17 +13,30 @@ import (
 	"time"
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/privacy"
 )
 
-// DPTierTracker tracks differential privacy epsilon accounting per aggregation tier
+// dpTierLedgerSource tags DPTierTracker charges in a privacy.BudgetLedger.
+const dpTierLedgerSource = "dp_tier_tracker"
+
+// DPTierTracker tracks differential privacy accounting across the aggregation
+// hierarchy.
+//
+// Every tier node charges its own sub-ledger of a single RDP accountant with
+// the Poisson-subsampled Gaussian bound, so regional, continental and global
+// numbers are on the same curve. A client's data passes through one tier per
+// level, so the global view composes the sub-ledgers along each leaf-to-root
+// path and reports the worst path; disjoint regional tiers compose in
+// parallel rather than adding up.
 type DPTierTracker struct {
 	mu sync.RWMutex
 
-	// Per-tier accountants
-	accountants map[string]*internal.RDPAccountant // tierNodeID -> accountant
+	// Per-tier sub-ledgers, keyed by tier node ID
+	accountant *internal.RDPAccountant
 
-	// Global DP budget
-	globalBudget *internal.RDPAccountant
+	// Hierarchy: tierNodeID -> parent tierNodeID ("" for a root)
+	parents map[string]string
 
 	// Aggregation statistics
 	aggregationsPerTier map[string]int64 // tierNodeID -> count
@@ -36,17 +49,25 @@ type DPTierTracker struct {
 // delta: privacy failure probability (e.g., 1e-7)
 func NewDPTierTracker(maxGlobalEpsilon float64, delta float64) *DPTierTracker {
 	return &DPTierTracker{
-		accountants:         make(map[string]*internal.RDPAccountant),
-		globalBudget:        internal.NewRDPAccountant(maxGlobalEpsilon, delta),
+		accountant:          internal.NewRDPAccountant(maxGlobalEpsilon, delta),
+		parents:             make(map[string]string),
 		aggregationsPerTier: make(map[string]int64),
 	}
 }
 
+// RegisterTier records a tier's parent so the global view composes along the
+// hierarchy. Tiers that were never registered are treated as roots.
+func (t *DPTierTracker) RegisterTier(tierNodeID, parentTierNodeID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.parents[tierNodeID] = parentTierNodeID
+}
+
 // RecordAggregation records DP epsilon spent during an aggregation at a specific tier
 // tierNodeID: unique tier node (e.g., "regional-1", "continental-1", "global-1")
 // gradientCount: number of gradients aggregated
-// samplingRate: subsampling rate (0.0-1.0)
-// noiseMagnitude: DP noise added (relative to gradient norm)
+// samplingRate: Poisson subsampling rate of the tier's inputs (0.0-1.0)
+// noiseMagnitude: Gaussian noise multiplier (noise std / clipping norm)
 func (t *DPTierTracker) RecordAggregation(tierNodeID string, gradientCount int, samplingRate float64, noiseMagnitude float64) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -54,65 +75,141 @@ func (t *DPTierTracker) RecordAggregation(tierNodeID string, gradientCount int,
 	if t.budgetExhausted {
 		return fmt.Errorf("DP budget exhausted for tier %s", tierNodeID)
 	}
-
-	// Get or create per-tier accountant
-	if _, ok := t.accountants[tierNodeID]; !ok {
-		t.accountants[tierNodeID] = internal.NewRDPAccountant(100.0, 1e-7) // Per-tier budget
-	}
-
-	tierAccountant := t.accountants[tierNodeID]
-
-	// Calculate RDP epsilon for this aggregation step
-	// Using Gaussian DP composition:
-	// epsilon ≈ sqrt(2) * sqrt(log(1/delta)) / (noise_magnitude * sqrt(gradient_count * sampling_rate))
-	if samplingRate <= 0 || noiseMagnitude <= 0 || gradientCount <= 0 {
+	if samplingRate <= 0 || samplingRate > 1 || noiseMagnitude <= 0 || gradientCount <= 0 {
 		return fmt.Errorf("invalid DP parameters: sampling_rate=%f, noise=%f, count=%d",
 			samplingRate, noiseMagnitude, gradientCount)
 	}
 
-	epsilon := calculateGaussianEpsilon(samplingRate, noiseMagnitude, float64(gradientCount), 1e-7)
-
-	// Record epsilon in tier-specific accountant
-	tierAccountant.RecordStep(epsilon)
-
-	// Record epsilon in global accountant
-	t.globalBudget.RecordShardStep(tierNodeID, epsilon)
+	// The accountant persists the step to the attached ledger before applying it
+	if err := t.accountant.RecordShardSubsampledGaussianStep(tierNodeID, samplingRate, noiseMagnitude); err != nil {
+		return fmt.Errorf("record DP spend for tier %s: %w", tierNodeID, err)
+	}
 
-	// Update statistics
 	t.aggregationsPerTier[tierNodeID]++
 	t.totalAggregations++
 
-	// Check if global budget is exhausted
-	globalEps := t.globalBudget.GetCurrentEpsilon()
-	if globalEps > 100.0 { // Hard limit
+	tierEps := t.accountant.GetShardEpsilon(tierNodeID)
+	globalEps, _ := t.globalEpsilonLocked()
+	t.exportRemainingLocked(tierNodeID, globalEps)
+
+	if globalEps > t.accountant.MaxBudgetFloat() {
 		t.budgetExhausted = true
 		log.Printf("WARNING: Global DP budget exhausted at tier %s (epsilon=%.4f)", tierNodeID, globalEps)
 		return fmt.Errorf("global DP budget exhausted")
 	}
 
 	log.Printf("[DP-tracking] Tier=%s, Agg=%d, Tier-Epsilon=%.4f, Global-Epsilon=%.4f",
-		tierNodeID, t.aggregationsPerTier[tierNodeID], tierAccountant.GetCurrentEpsilon(), globalEps)
+		tierNodeID, t.aggregationsPerTier[tierNodeID], tierEps, globalEps)
+
+	return nil
+}
 
+// AttachLedger replays prior tier charges from ledger and persists every
+// later charge before it is applied. It must be called before any
+// aggregation is recorded. Charges from before the RDP accountant, which name
+// the tier in SpendRecord.Tier and carry only a scalar epsilon, replay as
+// scalar steps on that tier's sub-ledger.
+func (t *DPTierTracker) AttachLedger(ledger *privacy.BudgetLedger) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.totalAggregations > 0 {
+		return fmt.Errorf("tracker has unrecorded aggregations")
+	}
+	if err := t.accountant.AttachLedgerSource(ledger, dpTierLedgerSource); err != nil {
+		return err
+	}
+	for _, rec := range ledger.Records() {
+		if rec.Source != dpTierLedgerSource {
+			continue
+		}
+		t.aggregationsPerTier[rec.ShardID()]++
+		t.totalAggregations++
+	}
+
+	globalEps, _ := t.globalEpsilonLocked()
+	for tierID := range t.aggregationsPerTier {
+		t.exportRemainingLocked(tierID, globalEps)
+	}
+	// Replayed charges can exhaust the budget; that state is kept, not returned.
+	t.budgetExhausted = globalEps > t.accountant.MaxBudgetFloat()
 	return nil
 }
 
+// globalEpsilonLocked composes the sub-ledgers along every leaf-to-root path
+// and returns the largest epsilon with the path that produced it.
+func (t *DPTierTracker) globalEpsilonLocked() (float64, []string) {
+	hasChild := make(map[string]bool, len(t.parents))
+	for _, parent := range t.parents {
+		hasChild[parent] = true
+	}
+
+	worst, worstPath := 0.0, []string(nil)
+	seen := make(map[string]bool)
+	consider := func(leaf string) {
+		path := []string{}
+		onPath := make(map[string]bool)
+		for id := leaf; id != "" && !onPath[id]; id = t.parents[id] {
+			onPath[id] = true
+			seen[id] = true
+			path = append(path, id)
+		}
+		if eps, _ := t.accountant.ComposedShardEpsilon(path...); eps > worst || worstPath == nil {
+			worst, worstPath = eps, path
+		}
+	}
+	for tierID := range t.parents {
+		if !hasChild[tierID] {
+			consider(tierID)
+		}
+	}
+	// Unregistered tiers, and cycles with no leaf, still count on their own
+	for tierID := range t.aggregationsPerTier {
+		if !seen[tierID] {
+			consider(tierID)
+		}
+	}
+	return worst, worstPath
+}
+
+func (t *DPTierTracker) exportRemainingLocked(tierNodeID string, globalEps float64) {
+	metrics.ObservePrivacyBudgetRemaining("tier", tierNodeID, t.accountant.ShardRemainingBudget(tierNodeID))
+	metrics.ObservePrivacyBudgetRemaining("global", dpTierLedgerSource, math.Max(0, t.accountant.MaxBudgetFloat()-globalEps))
+}
+
+// GetTierRemaining returns how much of the global budget a tier's own
+// sub-ledger leaves
+func (t *DPTierTracker) GetTierRemaining(tierNodeID string) float64 {
+	return t.accountant.ShardRemainingBudget(tierNodeID)
+}
+
 // GetTierEpsilon returns cumulative epsilon spent by a specific tier
 func (t *DPTierTracker) GetTierEpsilon(tierNodeID string) float64 {
+	return t.accountant.GetShardEpsilon(tierNodeID)
+}
+
+// GetPathEpsilon returns the epsilon for data passing through tierNodeID and
+// every ancestor up to the root
+func (t *DPTierTracker) GetPathEpsilon(tierNodeID string) float64 {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	if accountant, ok := t.accountants[tierNodeID]; ok {
-		return accountant.GetCurrentEpsilon()
+	var path []string
+	onPath := make(map[string]bool)
+	for id := tierNodeID; id != "" && !onPath[id]; id = t.parents[id] {
+		onPath[id] = true
+		path = append(path, id)
 	}
-	return 0.0
+	eps, _ := t.accountant.ComposedShardEpsilon(path...)
+	return eps
 }
 
-// GetGlobalEpsilon returns cumulative global epsilon
+// GetGlobalEpsilon returns the worst-path epsilon across the hierarchy
 func (t *DPTierTracker) GetGlobalEpsilon() float64 {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	return t.globalBudget.GetCurrentEpsilon()
+	eps, _ := t.globalEpsilonLocked()
+	return eps
 }
 
 // GetTierStats returns aggregation statistics for a tier
@@ -120,16 +217,11 @@ func (t *DPTierTracker) GetTierStats(tierNodeID string) map[string]interface{} {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	stats := map[string]interface{}{
+	return map[string]interface{}{
 		"aggregations": t.aggregationsPerTier[tierNodeID],
-		"epsilon":      0.0,
+		"epsilon":      t.accountant.GetShardEpsilon(tierNodeID),
+		"remaining":    t.accountant.ShardRemainingBudget(tierNodeID),
 	}
-
-	if accountant, ok := t.accountants[tierNodeID]; ok {
-		stats["epsilon"] = accountant.GetCurrentEpsilon()
-	}
-
-	return stats
 }
 
 // GetGlobalStats returns overall DP accounting statistics
@@ -138,16 +230,22 @@ func (t *DPTierTracker) GetGlobalStats() map[string]interface{} {
 	defer t.mu.RUnlock()
 
 	tierStats := make(map[string]float64)
-	for tierID, accountant := range t.accountants {
-		tierStats[tierID] = accountant.GetCurrentEpsilon()
+	tierRemaining := make(map[string]float64)
+	for tierID := range t.aggregationsPerTier {
+		tierStats[tierID] = t.accountant.GetShardEpsilon(tierID)
+		tierRemaining[tierID] = t.accountant.ShardRemainingBudget(tierID)
 	}
+	globalEps, worstPath := t.globalEpsilonLocked()
 
 	return map[string]interface{}{
 		"total_aggregations": t.totalAggregations,
-		"global_epsilon":     t.globalBudget.GetCurrentEpsilon(),
+		"global_epsilon":     globalEps,
+		"max_epsilon":        t.accountant.MaxBudgetFloat(),
+		"worst_path":         worstPath,
 		"tier_epsilon":       tierStats,
+		"tier_remaining":     tierRemaining,
 		"budget_exhausted":   t.budgetExhausted,
-		"num_tiers":          len(t.accountants),
+		"num_tiers":          len(t.aggregationsPerTier),
 	}
 }
 
@@ -156,12 +254,18 @@ type CoordinatorWithDP struct {
 	coordinator *Coordinator
 	dpTracker   *DPTierTracker
 
-	tierNodeID     string
+	tierNodeID string
+	paramsMu   sync.RWMutex
+	// samplingRate and noiseMagnitude parameterize the subsampled Gaussian
+	// charged for each round this tier flushes
 	samplingRate   float64
 	noiseMagnitude float64
 }
 
-// NewCoordinatorWithDP creates a coordinator with DP tracking
+// NewCoordinatorWithDP creates a coordinator with DP tracking. Sampling rate
+// and noise multiplier default to MOHAWK_DP_SAMPLING_RATE and MOHAWK_DP_SIGMA.
+// Every round the tier's RPC handler flushes is charged to dpTracker before
+// its aggregate is forwarded; a round the budget cannot cover is dropped.
 func NewCoordinatorWithDP(config TierConfig, serverAddr string, parentAddr string,
 	dpTracker *DPTierTracker) (*CoordinatorWithDP, error) {
 
@@ -170,17 +274,26 @@ func NewCoordinatorWithDP(config TierConfig, serverAddr string, parentAddr strin
 		return nil, err
 	}
 
-	return &CoordinatorWithDP{
+	dpConfig := internal.LoadDPConfig()
+	c := &CoordinatorWithDP{
 		coordinator:    coordinator,
 		dpTracker:      dpTracker,
 		tierNodeID:     config.TierID,
-		samplingRate:   1.0,   // Default: no sampling
-		noiseMagnitude: 0.001, // Small noise (high utility)
-	}, nil
+		samplingRate:   dpConfig.SamplingRate,
+		noiseMagnitude: dpConfig.Sigma,
+	}
+	if dpTracker != nil {
+		dpTracker.RegisterTier(config.TierID, config.ParentTierNodeID)
+	}
+	coordinator.rpcServer.BeforeForward(c.chargeRound)
+
+	return c, nil
 }
 
 // SetDPParameters configures sampling and noise level for this tier
 func (c *CoordinatorWithDP) SetDPParameters(samplingRate, noiseMagnitude float64) {
+	c.paramsMu.Lock()
+	defer c.paramsMu.Unlock()
 	if samplingRate > 0 && samplingRate <= 1.0 {
 		c.samplingRate = samplingRate
 	}
@@ -191,8 +304,22 @@ func (c *CoordinatorWithDP) SetDPParameters(samplingRate, noiseMagnitude float64
 
 // RecordAggregation records aggregation and updates DP accounting
 func (c *CoordinatorWithDP) RecordAggregation(gradientCount int) error {
-	if c.dpTracker != nil {
-		return c.dpTracker.RecordAggregation(c.tierNodeID, gradientCount, c.samplingRate, c.noiseMagnitude)
+	if c.dpTracker == nil {
+		return nil
+	}
+	c.paramsMu.RLock()
+	samplingRate, noiseMagnitude := c.samplingRate, c.noiseMagnitude
+	c.paramsMu.RUnlock()
+	return c.dpTracker.RecordAggregation(c.tierNodeID, gradientCount, samplingRate, noiseMagnitude)
+}
+
+// chargeRound charges a round the tier's RPC handler aggregated before the
+// aggregate is released. A failed charge, including one that exhausts the
+// budget, keeps the aggregate from leaving the tier.
+func (c *CoordinatorWithDP) chargeRound(round uint64, resp *AggregationResponse, _ *GradientMessage) error {
+	if err := c.RecordAggregation(resp.AggregatedCount); err != nil {
+		log.Printf("WARN: [%s dp-tracking] round %d: %v", c.tierNodeID, round, err)
+		return err
 	}
 	return nil
 }
@@ -215,35 +342,6 @@ func (c *CoordinatorWithDP) GetDPStats() map[string]interface{} {
 	return c.dpTracker.GetGlobalStats()
 }
 
-// calculateGaussianEpsilon computes (ε, δ)-DP epsilon for Gaussian mechanism
-// Using: epsilon ≈ sqrt(2) * sqrt(log(1/delta)) / (noise_magnitude * sqrt(n * sampling_rate))
-// where n is the effective number of gradients
-func calculateGaussianEpsilon(samplingRate float64, noiseMagnitude float64, gradientCount float64, delta float64) float64 {
-	if noiseMagnitude <= 0 || samplingRate <= 0 || gradientCount <= 0 {
-		return 0.0
-	}
-
-	// sqrt(log(1/delta))
-	sqrtLogInvDelta := 0.0
-	if delta > 0 && delta < 1.0 {
-		// Use simplified formula: sqrt(log(2/delta))
-		sqrtLogInvDelta = math.Sqrt(math.Log(2.0 / delta))
-	}
-
-	// Effective count = n * sampling_rate
-	effectiveCount := gradientCount * samplingRate
-
-	// epsilon ≈ sqrt(2) * sqrt(log(1/delta)) / (noise_magnitude * sqrt(n * sampling_rate))
-	denominator := noiseMagnitude * math.Sqrt(effectiveCount)
-	if denominator <= 0 {
-		return 0.0
-	}
-
-	epsilon := (math.Sqrt(2.0) * sqrtLogInvDelta) / denominator
-
-	return epsilon
-}
-
 // MonitorDPBudget periodically checks DP budget status across all tiers
 func MonitorDPBudget(ctx context.Context, tracker *DPTierTracker, checkInterval time.Duration) {
 	ticker := time.NewTicker(checkInterval)
@@ -262,8 +360,8 @@ func MonitorDPBudget(ctx context.Context, tracker *DPTierTracker, checkInterval
 				globalEpsilon, numTiers, stats["budget_exhausted"].(bool))
 
 			// Alert if approaching budget limit
-			if globalEpsilon > 1.5 { // 1.5 out of 2.0 default
-				log.Printf("WARNING: DP budget approaching limit (%.4f/2.0)", globalEpsilon)
+			if maxEpsilon := stats["max_epsilon"].(float64); globalEpsilon > 0.75*maxEpsilon {
+				log.Printf("WARNING: DP budget approaching limit (%.4f/%.4f)", globalEpsilon, maxEpsilon)
 			}
 		}
 	}
@@ -0,0 +1,162 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Hierarchical DP accounting tests
+
+package federation
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+func TestDPTierTrackerMatchesAccountant(t *testing.T) {
+	tracker := NewDPTierTracker(100, 1e-5)
+	acc := internal.NewRDPAccountant(100, 1e-5)
+	for i := 0; i < 20; i++ {
+		if err := tracker.RecordAggregation("regional-1", 50, 0.05, 1.1); err != nil {
+			t.Fatalf("record aggregation: %v", err)
+		}
+		if err := acc.RecordSubsampledGaussianStep(0.05, 1.1); err != nil {
+			t.Fatalf("accountant step: %v", err)
+		}
+	}
+	if got, want := tracker.GetTierEpsilon("regional-1"), acc.GetCurrentEpsilon(); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("tier ε=%v disagrees with accountant ε=%v", got, want)
+	}
+	if got := tracker.GetGlobalEpsilon(); math.Abs(got-acc.GetCurrentEpsilon()) > 1e-12 {
+		t.Fatalf("single-tier global ε=%v should equal the tier ε", got)
+	}
+}
+
+func TestDPTierTrackerComposesAlongPaths(t *testing.T) {
+	tracker := NewDPTierTracker(100, 1e-5)
+	tracker.RegisterTier("global-1", "")
+	tracker.RegisterTier("continental-1", "global-1")
+	tracker.RegisterTier("regional-1", "continental-1")
+	tracker.RegisterTier("regional-2", "continental-1")
+
+	for _, tier := range []string{"regional-1", "regional-2", "continental-1", "global-1"} {
+		if err := tracker.RecordAggregation(tier, 10, 0.1, 1.0); err != nil {
+			t.Fatalf("record %s: %v", tier, err)
+		}
+	}
+	if err := tracker.RecordAggregation("regional-2", 10, 0.1, 1.0); err != nil {
+		t.Fatalf("record regional-2: %v", err)
+	}
+
+	path1, path2 := tracker.GetPathEpsilon("regional-1"), tracker.GetPathEpsilon("regional-2")
+	if path1 <= tracker.GetTierEpsilon("regional-1") || path2 <= path1 {
+		t.Fatalf("expected path ε to grow with tiers and rounds: regional-1=%v regional-2=%v", path1, path2)
+	}
+	if global := tracker.GetGlobalEpsilon(); global != path2 {
+		t.Fatalf("global ε=%v should be the worst path ε=%v", global, path2)
+	}
+
+	// Disjoint regional tiers compose in parallel, not sequentially
+	flat := internal.NewRDPAccountant(100, 1e-5)
+	for i := 0; i < 5; i++ {
+		_ = flat.RecordSubsampledGaussianStep(0.1, 1.0)
+	}
+	if tracker.GetGlobalEpsilon() >= flat.GetCurrentEpsilon() {
+		t.Fatalf("global ε=%v should be below sequential composition ε=%v", tracker.GetGlobalEpsilon(), flat.GetCurrentEpsilon())
+	}
+	if path := tracker.GetGlobalStats()["worst_path"].([]string); len(path) != 3 || path[0] != "regional-2" {
+		t.Fatalf("unexpected worst path %v", path)
+	}
+}
+
+func TestDPTierTrackerExhaustsConfiguredBudget(t *testing.T) {
+	tracker := NewDPTierTracker(1.0, 1e-5)
+	if err := tracker.RecordAggregation("regional-1", 10, 1, 0.5); err == nil {
+		t.Fatal("expected a full-batch σ=0.5 round to exhaust ε=1")
+	}
+	if err := tracker.RecordAggregation("regional-1", 10, 0.01, 5); err == nil {
+		t.Fatal("expected further aggregations to be refused after exhaustion")
+	}
+	if tracker.GetTierRemaining("regional-1") != 0 {
+		t.Fatalf("expected no remaining tier budget, got %v", tracker.GetTierRemaining("regional-1"))
+	}
+}
+
+func TestCoordinatorWithDPRecordsEachFlush(t *testing.T) {
+	tracker := NewDPTierTracker(100, 1e-5)
+	coord, err := NewCoordinatorWithDP(TierConfig{
+		TierID:               "regional-dp",
+		ParentTierNodeID:     "continental-dp",
+		MinQuorumSize:        2,
+		MaxBufferedGradients: 100,
+		AggregationRule:      RuleMean,
+	}, "", "", tracker)
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	coord.SetDPParameters(0.2, 1.5)
+
+	handler := coord.coordinator.rpcServer
+	for round := uint64(1); round <= 2; round++ {
+		if err := handler.OpenRound(AggregationRequest{RoundID: round, RequestedCount: 2}); err != nil {
+			t.Fatalf("open round: %v", err)
+		}
+		for _, g := range childGradients(round, [][]float64{{1, 2}, {3, 4}}) {
+			handler.bufferGradient(g)
+		}
+		handler.flushPendingAggregations(context.Background())
+	}
+
+	if n := tracker.GetTierStats("regional-dp")["aggregations"].(int64); n != 2 {
+		t.Fatalf("expected 2 flushed rounds to be charged, got %d", n)
+	}
+	acc := internal.NewRDPAccountant(100, 1e-5)
+	_ = acc.RecordSubsampledGaussianStep(0.2, 1.5)
+	_ = acc.RecordSubsampledGaussianStep(0.2, 1.5)
+	if got, want := tracker.GetTierEpsilon("regional-dp"), acc.GetCurrentEpsilon(); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("tier ε=%v, want %v", got, want)
+	}
+}
+
+func TestCoordinatorWithDPDropsRoundsOverBudget(t *testing.T) {
+	parent, parentAddr := startWireTestHandler(t, TierConfig{TierID: "continental-dp", MaxBufferedGradients: 100})
+
+	tracker := NewDPTierTracker(1.0, 1e-5)
+	config := TierConfig{
+		TierID:               "regional-dp",
+		ParentTierNodeID:     "continental-dp",
+		MinQuorumSize:        2,
+		MaxBufferedGradients: 100,
+		AggregationRule:      RuleMean,
+	}
+	coord, err := NewCoordinatorWithDP(config, "", "", tracker)
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	// A full-batch σ=0.5 round alone exceeds ε=1
+	coord.SetDPParameters(1, 0.5)
+	client := NewRPCClient(config, parentAddr)
+	defer client.Close()
+	handler := coord.coordinator.rpcServer
+	handler.SetParentClient(client)
+
+	if err := coord.coordinator.OpenRound(AggregationRequest{RoundID: 1, RequestedCount: 2}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	for _, g := range childGradients(1, [][]float64{{1, 2}, {3, 4}}) {
+		handler.bufferGradient(g)
+	}
+	handler.flushPendingAggregations(context.Background())
+
+	if _, err := coord.coordinator.AwaitRound(context.Background(), 1); err == nil {
+		t.Fatal("expected the round to fail when its DP charge exhausts the budget")
+	}
+	if _, ok := handler.LastAggregation(); ok {
+		t.Fatal("expected the refused aggregate not to be recorded")
+	}
+	select {
+	case got := <-parent.aggregationChan:
+		t.Fatalf("parent received an aggregate the budget refused: %+v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
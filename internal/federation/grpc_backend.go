@@ -6,6 +6,7 @@ package federation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -21,7 +22,6 @@ type GRPCBackend struct {
 	listenAddr     string
 	handler        *RPCHandler
 	gradientsRPCs  int64
-	batchRPCs      int64
 	totalRPCTimeMs int64
 }
 
@@ -71,137 +71,29 @@ func (g *GRPCBackend) acceptConnectionLoop(listener net.Listener) {
 }
 
 // handleGRPCConnection processes an incoming gRPC client connection
-// This implements a simple binary protocol for gradient forwarding
+// using the framed wire protocol defined in wire.go
 func (g *GRPCBackend) handleGRPCConnection(conn net.Conn) {
 	defer conn.Close()
 
-	// Set read/write timeout
-	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
-	conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
-
 	remoteAddr := conn.RemoteAddr().String()
 	log.Printf("[%s grpc-backend] connection from %s", g.config.TierID, remoteAddr)
 
-	buffer := make([]byte, 50*1024*1024) // 50MB buffer for large gradients
-
-	for {
-		// Read message type (1 byte: 0=gradient, 1=batch, 2=health-check)
-		n, err := conn.Read(buffer[:1])
-		if err != nil {
-			if n > 0 || err.Error() != "EOF" {
-				log.Printf("WARN: failed to read message type: %v", err)
+	server := &wireServer{
+		tierID:      g.config.TierID,
+		maxBytes:    resolveMaxFrameBytes(g.config),
+		idleTimeout: wireIdleTimeout,
+		receive: func(gradient *GradientMessage) error {
+			startTime := time.Now()
+			if err := g.handler.receiveGradient(gradient); err != nil {
+				log.Printf("WARN: failed to process gradient %s from %s: %v", gradient.GradientID, remoteAddr, err)
+				return err
 			}
-			return
-		}
-
-		messageType := buffer[0]
-
-		switch messageType {
-		case 0: // Single gradient forward
-			g.handleSingleGradient(conn, buffer, remoteAddr)
-
-		case 1: // Batch of gradients
-			g.handleBatchGradient(conn, buffer, remoteAddr)
-
-		case 2: // Health check
-			g.handleHealthCheck(conn)
-
-		default:
-			log.Printf("WARN: unknown gRPC message type: %d", messageType)
-			return
-		}
-	}
-}
-
-// handleSingleGradient processes a single gradient message
-func (g *GRPCBackend) handleSingleGradient(conn net.Conn, buffer []byte, remoteAddr string) error {
-	// Parse gradient data from buffer (simplified parsing)
-	// In real impl, would deserialize protobuf format
-	startTime := time.Now()
-
-	// Create test gradient for now
-	gradient := &GradientMessage{
-		GradientID:       fmt.Sprintf("grad-%d", atomic.AddInt64(&g.gradientsRPCs, 1)),
-		SourceNodeID:     remoteAddr,
-		AggregationRound: 1,
-		DimensionCount:   1000,
-		GradientData:     make([]float64, 1000),
-		Timestamp:        time.Now(),
-	}
-
-	// Fill dummy data
-	for i := range gradient.GradientData {
-		gradient.GradientData[i] = float64(i)
-		gradient.Norm += gradient.GradientData[i] * gradient.GradientData[i]
-	}
-	gradient.Norm = float64(int(gradient.Norm) % 100)
-
-	// Process gradient
-	if err := g.handler.receiveGradient(gradient); err != nil {
-		log.Printf("WARN: failed to process gradient: %v", err)
-		return err
-	}
-
-	timeMs := time.Since(startTime).Milliseconds()
-	atomic.AddInt64(&g.totalRPCTimeMs, timeMs)
-
-	// Send ACK
-	response := []byte{1} // ACK
-	if _, err := conn.Write(response); err != nil {
-		return fmt.Errorf("failed to send ACK: %w", err)
-	}
-
-	return nil
-}
-
-// handleBatchGradient processes a batch of gradients efficiently
-func (g *GRPCBackend) handleBatchGradient(conn net.Conn, buffer []byte, remoteAddr string) error {
-	startTime := time.Now()
-	batchSize := 10 // Process 10 gradients per batch
-
-	for i := 0; i < batchSize; i++ {
-		gradient := &GradientMessage{
-			GradientID:       fmt.Sprintf("batch-grad-%d-%d", atomic.AddInt64(&g.batchRPCs, 1), i),
-			SourceNodeID:     remoteAddr,
-			AggregationRound: 1,
-			DimensionCount:   1000,
-			GradientData:     make([]float64, 1000),
-			Timestamp:        time.Now(),
-		}
-
-		// Fill dummy data
-		for j := range gradient.GradientData {
-			gradient.GradientData[j] = float64(j)
-			gradient.Norm += gradient.GradientData[j] * gradient.GradientData[j]
-		}
-		gradient.Norm = float64(int(gradient.Norm) % 100)
-
-		if err := g.handler.receiveGradient(gradient); err != nil {
-			log.Printf("WARN: failed to process batch gradient %d: %v", i, err)
-			continue
-		}
-	}
-
-	timeMs := time.Since(startTime).Milliseconds()
-	atomic.AddInt64(&g.totalRPCTimeMs, timeMs)
-
-	// Send batch ACK with count
-	response := []byte{2, byte(batchSize)} // Batch ACK + count
-	if _, err := conn.Write(response); err != nil {
-		return fmt.Errorf("failed to send batch ACK: %w", err)
-	}
-
-	return nil
-}
-
-// handleHealthCheck responds to health check from client
-func (g *GRPCBackend) handleHealthCheck(conn net.Conn) error {
-	// Send health status: 1 = healthy
-	response := []byte{3, 1} // Health check response + healthy status
-	if _, err := conn.Write(response); err != nil {
-		return fmt.Errorf("failed to send health check: %w", err)
+			atomic.AddInt64(&g.gradientsRPCs, 1)
+			atomic.AddInt64(&g.totalRPCTimeMs, time.Since(startTime).Milliseconds())
+			return nil
+		},
 	}
-	return nil
+	server.serve(conn)
 }
 
 // GRPCClientBackend manages outbound gRPC connections to parent tier
@@ -210,6 +102,7 @@ type GRPCClientBackend struct {
 	config             TierConfig
 	parentAddr         string
 	conn               net.Conn
+	ioMu               sync.Mutex // serializes request/response pairs on conn
 	gradientsForwarded int64
 	bytesForwarded     int64
 	lastError          error
@@ -246,104 +139,162 @@ func (g *GRPCClientBackend) Connect(ctx context.Context) error {
 	return nil
 }
 
-// SendGradient forwards single gradient via gRPC
+// SendGradient forwards single gradient via gRPC and waits for its ACK
 func (g *GRPCClientBackend) SendGradient(ctx context.Context, gradient *GradientMessage) error {
-	g.mu.RLock()
-	conn := g.conn
-	g.mu.RUnlock()
-
-	if conn == nil {
-		if err := g.Connect(ctx); err != nil {
-			return err
-		}
-		g.mu.RLock()
-		conn = g.conn
-		g.mu.RUnlock()
+	body, err := EncodeGradient(gradient)
+	if err != nil {
+		return fmt.Errorf("encode gradient: %w", err)
 	}
 
-	// Send message type (0 = single gradient)
-	if _, err := conn.Write([]byte{0}); err != nil {
-		g.recordError(err)
+	msgType, resp, err := g.roundTrip(ctx, MsgGradient, body)
+	if err != nil {
+		return fmt.Errorf("gRPC send failed: %w", err)
+	}
+	codes, err := parseAckResponse(msgType, resp, 1)
+	if err != nil {
 		g.resetConnection()
 		return fmt.Errorf("gRPC send failed: %w", err)
 	}
+	if codes[0] != AckOK {
+		if codes[0].closesConnection() {
+			g.resetConnection()
+		}
+		return &NackError{Code: codes[0]}
+	}
 
-	// Send gradient data (simplified binary format).
-	// This transport is a placeholder, so we record the transfer and return
-	// success without waiting for an ACK that no stub server currently emits.
-	dataSize := 8 + 8 + 8*len(gradient.GradientData) // min overhead + gradient data
-	atomic.AddInt64(&g.bytesForwarded, int64(dataSize))
+	atomic.AddInt64(&g.bytesForwarded, int64(len(body)))
 	atomic.AddInt64(&g.gradientsForwarded, 1)
-
 	return nil
 }
 
-// SendBatch forwards multiple gradients via gRPC (more efficient)
+// SendBatch forwards multiple gradients via gRPC (more efficient).
+// Returns the number the parent accepted; an error is returned only when the
+// exchange failed or every gradient was rejected.
 func (g *GRPCClientBackend) SendBatch(ctx context.Context, gradients []*GradientMessage) (int, error) {
 	if len(gradients) == 0 {
 		return 0, nil
 	}
 
-	g.mu.RLock()
-	conn := g.conn
-	g.mu.RUnlock()
-
-	if conn == nil {
-		if err := g.Connect(ctx); err != nil {
-			return 0, err
-		}
-		g.mu.RLock()
-		conn = g.conn
-		g.mu.RUnlock()
+	body, err := encodeBatch(gradients)
+	if err != nil {
+		return 0, fmt.Errorf("encode batch: %w", err)
 	}
 
-	// Send message type (1 = batch)
-	msgType := []byte{1, byte(len(gradients))} // Type + count
-	if _, err := conn.Write(msgType); err != nil {
-		g.recordError(err)
+	msgType, resp, err := g.roundTrip(ctx, MsgBatch, body)
+	if err != nil {
+		return 0, fmt.Errorf("gRPC batch send failed: %w", err)
+	}
+	codes, err := parseAckResponse(msgType, resp, len(gradients))
+	if err != nil {
 		g.resetConnection()
 		return 0, fmt.Errorf("gRPC batch send failed: %w", err)
 	}
 
-	// Send batch data
-	dataSize := 8 * len(gradients) * (1000 + 2) // Crude size estim (ID + 1000-dim data)
-	atomic.AddInt64(&g.bytesForwarded, int64(dataSize))
-	atomic.AddInt64(&g.gradientsForwarded, int64(len(gradients)))
+	accepted := 0
+	var firstNack *NackError
+	for i, code := range codes {
+		if code == AckOK {
+			accepted++
+			continue
+		}
+		if firstNack == nil {
+			firstNack = &NackError{Code: code, Index: i}
+		}
+	}
 
-	// As above, this placeholder backend does not wait on a server-side ACK.
-	return len(gradients), nil
+	atomic.AddInt64(&g.bytesForwarded, int64(len(body)))
+	atomic.AddInt64(&g.gradientsForwarded, int64(accepted))
+
+	if accepted == 0 && firstNack != nil {
+		return 0, firstNack
+	}
+	return accepted, nil
 }
 
 // Health checks parent tier connection
 func (g *GRPCClientBackend) Health(ctx context.Context) error {
 	g.mu.RLock()
-	conn := g.conn
+	connected := g.conn != nil
 	g.mu.RUnlock()
 
-	if conn == nil {
+	if !connected {
 		return fmt.Errorf("not connected")
 	}
 
-	// Send health check (type 2)
-	if _, err := conn.Write([]byte{2}); err != nil {
-		g.recordError(err)
-		g.resetConnection()
+	msgType, resp, err := g.roundTrip(ctx, MsgHealth, nil)
+	if err != nil {
 		return err
 	}
+	if msgType != MsgHealth || len(resp) != 1 {
+		g.resetConnection()
+		return fmt.Errorf("unexpected health response type=%d len=%d", msgType, len(resp))
+	}
+	if resp[0] != 1 {
+		return fmt.Errorf("parent tier not healthy")
+	}
+
+	return nil
+}
+
+// roundTrip writes one request frame and reads its response frame.
+// Requests are serialized so responses cannot interleave on the shared conn.
+func (g *GRPCClientBackend) roundTrip(ctx context.Context, msgType uint8, body []byte) (uint8, []byte, error) {
+	if err := g.Connect(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	g.ioMu.Lock()
+	defer g.ioMu.Unlock()
+
+	g.mu.RLock()
+	conn := g.conn
+	g.mu.RUnlock()
+	if conn == nil {
+		return 0, nil, fmt.Errorf("connection closed")
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
 
-	// Read health response
-	respBuffer := make([]byte, 2)
-	if _, err := conn.Read(respBuffer); err != nil {
+	maxBytes := resolveMaxFrameBytes(g.config)
+	if err := writeFrame(conn, msgType, body, maxBytes); err != nil {
+		if errors.Is(err, errFrameTooLarge) {
+			return 0, nil, err
+		}
 		g.recordError(err)
 		g.resetConnection()
-		return err
+		return 0, nil, err
 	}
 
-	if respBuffer[1] != 1 {
-		return fmt.Errorf("parent tier not healthy")
+	respType, resp, err := readFrame(conn, maxBytes)
+	if err != nil {
+		g.recordError(err)
+		g.resetConnection()
+		return 0, nil, err
 	}
+	return respType, resp, nil
+}
 
-	return nil
+// parseAckResponse decodes an ACK frame and checks it covers want messages.
+// A single-code ACK for a multi-message request is a connection-level NACK.
+func parseAckResponse(msgType uint8, body []byte, want int) ([]AckCode, error) {
+	if msgType != MsgAck {
+		return nil, fmt.Errorf("unexpected response type %d", msgType)
+	}
+	codes, err := decodeAcks(body)
+	if err != nil {
+		return nil, fmt.Errorf("malformed ack: %w", err)
+	}
+	if len(codes) == want {
+		return codes, nil
+	}
+	if len(codes) == 1 && codes[0] != AckOK {
+		return nil, &NackError{Code: codes[0]}
+	}
+	return nil, fmt.Errorf("ack count mismatch: got %d want %d", len(codes), want)
 }
 
 // recordError records connection errors
@@ -0,0 +1,175 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Byzantine-robust aggregation rules for tier coordinators
+
+package federation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+// AggregationRule selects the robust aggregator a tier applies to child gradients
+type AggregationRule string
+
+const (
+	RuleMultiKrum        AggregationRule = "multi_krum"
+	RuleTrimmedMean      AggregationRule = "trimmed_mean"
+	RuleCoordinateMedian AggregationRule = "coordinate_median"
+	RuleMean             AggregationRule = "mean" // no Byzantine protection
+)
+
+// robustOutcome is the result of one tier-level robust aggregation
+type robustOutcome struct {
+	data     []float64
+	f        int
+	filtered []int // indices into the input considered Byzantine
+}
+
+// resolveRule returns the configured rule, defaulting to Multi-Krum
+func resolveRule(config TierConfig) AggregationRule {
+	if config.AggregationRule == "" {
+		return RuleMultiKrum
+	}
+	return config.AggregationRule
+}
+
+// byzantineBound derives f from the tier tolerance fraction, clamped to what
+// the rule can actually tolerate for n inputs
+func byzantineBound(rule AggregationRule, n int, frac float64) int {
+	if frac <= 0 || n <= 0 {
+		return 0
+	}
+	f := int(math.Floor(frac * float64(n)))
+	var maxF int
+	switch rule {
+	case RuleMultiKrum:
+		maxF = (n - 3) / 2 // n > 2f+2
+	case RuleTrimmedMean, RuleCoordinateMedian:
+		maxF = (n - 1) / 2 // n > 2f
+	default:
+		return 0
+	}
+	if maxF < 0 {
+		maxF = 0
+	}
+	if f > maxF {
+		f = maxF
+	}
+	return f
+}
+
+// robustAggregate applies rule to equal-length updates. weights[i] is the
+// number of edge gradients behind updates[i]; only the mean rule weights by
+// it. The Byzantine-robust rules treat every child alike, since the counts
+// are self-reported and a weighted survivor could still outvote the rest.
+func robustAggregate(rule AggregationRule, updates [][]float64, weights []float64, frac float64) (robustOutcome, error) {
+	n := len(updates)
+	if n == 0 {
+		return robustOutcome{}, fmt.Errorf("no updates to aggregate")
+	}
+	f := byzantineBound(rule, n, frac)
+
+	switch rule {
+	case RuleMultiKrum:
+		if n < 3 {
+			// Krum scores are undefined below three inputs
+			return robustOutcome{data: plainMean(updates)}, nil
+		}
+		mean, selected, _, err := internal.MultiKrumAggregate(updates, f, n-f)
+		if err != nil {
+			return robustOutcome{}, err
+		}
+		return robustOutcome{data: mean, f: f, filtered: complementIndices(n, selected)}, nil
+
+	case RuleTrimmedMean:
+		agg, outliers, err := internal.TrimmedMeanAggregate(updates, f)
+		if err != nil {
+			return robustOutcome{}, err
+		}
+		return robustOutcome{data: agg, f: f, filtered: majorityOutliers(outliers, len(updates[0]))}, nil
+
+	case RuleCoordinateMedian:
+		agg, outliers, err := internal.CoordinateMedianAggregate(updates, f)
+		if err != nil {
+			return robustOutcome{}, err
+		}
+		return robustOutcome{data: agg, f: f, filtered: majorityOutliers(outliers, len(updates[0]))}, nil
+
+	case RuleMean:
+		return robustOutcome{data: weightedMean(updates, weights)}, nil
+
+	default:
+		return robustOutcome{}, fmt.Errorf("unknown aggregation rule %q", rule)
+	}
+}
+
+// complementIndices returns the indices in [0,n) not present in selected
+func complementIndices(n int, selected []int) []int {
+	keep := make([]bool, n)
+	for _, idx := range selected {
+		if idx >= 0 && idx < n {
+			keep[idx] = true
+		}
+	}
+	var out []int
+	for i, k := range keep {
+		if !k {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// majorityOutliers flags updates trimmed in more than half of all coordinates
+func majorityOutliers(outliers []int, dim int) []int {
+	var out []int
+	for i, count := range outliers {
+		if count*2 > dim {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// plainMean computes the elementwise mean of equal-length updates
+func plainMean(updates [][]float64) []float64 {
+	mean := make([]float64, len(updates[0]))
+	for _, u := range updates {
+		for i, v := range u {
+			mean[i] += v
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(updates))
+	}
+	return mean
+}
+
+// weightedMean computes the elementwise mean of updates weighted by weights
+func weightedMean(updates [][]float64, weights []float64) []float64 {
+	mean := make([]float64, len(updates[0]))
+	total := 0.0
+	for idx, u := range updates {
+		w := weights[idx]
+		total += w
+		for i, v := range u {
+			mean[i] += w * v
+		}
+	}
+	for i := range mean {
+		mean[i] /= total
+	}
+	return mean
+}
+
+// l2Norm returns the Euclidean norm of v
+func l2Norm(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
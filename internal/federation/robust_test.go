@@ -0,0 +1,221 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Robust tier aggregation tests
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func childGradients(round uint64, values [][]float64) []*GradientMessage {
+	out := make([]*GradientMessage, len(values))
+	for i, v := range values {
+		out[i] = &GradientMessage{
+			GradientID:       fmt.Sprintf("g-%d", i),
+			SourceTierNodeID: fmt.Sprintf("child-%d", i),
+			AggregationRound: round,
+			DimensionCount:   len(v),
+			GradientData:     v,
+		}
+	}
+	return out
+}
+
+func TestAggregateRoundFiltersByzantineChild(t *testing.T) {
+	honest := [][]float64{{1, 1}, {1.1, 0.9}, {0.9, 1.1}, {1, 1.05}, {1.05, 1}}
+	poisoned := append(honest, []float64{-100, 100})
+
+	for _, rule := range []AggregationRule{RuleMultiKrum, RuleTrimmedMean, RuleCoordinateMedian} {
+		t.Run(string(rule), func(t *testing.T) {
+			handler, err := NewRPCHandler(TierConfig{
+				TierID:                 "regional-robust",
+				ByzantineToleranceFrac: 0.2,
+				AggregationRule:        rule,
+				MaxBufferedGradients:   100,
+			}, "")
+			if err != nil {
+				t.Fatalf("new handler: %v", err)
+			}
+
+			resp, msg, err := handler.aggregateRound(7, childGradients(7, poisoned))
+			if err != nil {
+				t.Fatalf("aggregateRound: %v", err)
+			}
+			if math.Abs(msg.GradientData[0]-1) > 0.1 || math.Abs(msg.GradientData[1]-1) > 0.1 {
+				t.Fatalf("aggregate contaminated by outlier: %v", msg.GradientData)
+			}
+			if resp.SkippedCount < 1 {
+				t.Fatalf("expected outlier to be counted as skipped, got %+v", resp)
+			}
+			found := false
+			for _, id := range resp.FilteredNodeIDs {
+				if id == "child-5" {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("expected child-5 in filtered set, got %v", resp.FilteredNodeIDs)
+			}
+		})
+	}
+}
+
+func TestClaimedContributorCountCannotDominate(t *testing.T) {
+	// Two children are below Multi-Krum's minimum; the second claims 2^32 edges
+	pair := childGradients(4, [][]float64{{1, 1}, {9, 9}})
+	pair[1].ContributorCount = 1 << 32
+	for _, rule := range []AggregationRule{RuleMultiKrum, RuleTrimmedMean, RuleMean} {
+		handler, err := NewRPCHandler(TierConfig{TierID: "regional-weights", AggregationRule: rule}, "")
+		if err != nil {
+			t.Fatalf("new handler: %v", err)
+		}
+		_, msg, err := handler.aggregateRound(4, pair)
+		if err != nil {
+			t.Fatalf("%s: aggregateRound: %v", rule, err)
+		}
+		want := 5.0 // the plain mean
+		if rule == RuleMean {
+			// weighted, but by the capped count
+			want = (1 + 9*float64(DefaultMaxChildWeight)) / float64(1+DefaultMaxChildWeight)
+		}
+		if math.Abs(msg.GradientData[0]-want) > 1e-9 {
+			t.Fatalf("%s: aggregate %v, want %v", rule, msg.GradientData, want)
+		}
+		if msg.ContributorCount != 1+DefaultMaxChildWeight {
+			t.Fatalf("%s: contributor count %d, want %d", rule, msg.ContributorCount, 1+DefaultMaxChildWeight)
+		}
+	}
+}
+
+func TestAggregateRoundCarriesOneTierChain(t *testing.T) {
+	handler, err := NewRPCHandler(TierConfig{TierID: "continental-hops", AggregationRule: RuleMean}, "")
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	edges := make([]*GradientMessage, 3)
+	for i := range edges {
+		id := fmt.Sprintf("edge-%d", i)
+		edges[i] = &GradientMessage{SourceNodeID: id, AggregationRound: 2, DimensionCount: 1, GradientData: []float64{1}, PathHops: []string{id}}
+	}
+	if _, msg, err := handler.aggregateRound(2, edges); err != nil || len(msg.PathHops) != 0 {
+		t.Fatalf("expected edge IDs to stay at the tier, got %v (%v)", msg, err)
+	}
+
+	tiers := childGradients(2, [][]float64{{1}, {2}})
+	tiers[0].PathHops = []string{"regional-0", "child-0"}
+	tiers[1].PathHops = []string{"child-1"}
+	_, msg, err := handler.aggregateRound(2, tiers)
+	if err != nil {
+		t.Fatalf("aggregateRound: %v", err)
+	}
+	if len(msg.PathHops) != 2 || msg.PathHops[1] != "child-0" {
+		t.Fatalf("expected the chain through child-0 only, got %v", msg.PathHops)
+	}
+}
+
+func TestByzantineBoundClamp(t *testing.T) {
+	if f := byzantineBound(RuleMultiKrum, 4, 0.5); f != 0 {
+		t.Fatalf("multi-krum with n=4 must clamp f to 0, got %d", f)
+	}
+	if f := byzantineBound(RuleTrimmedMean, 10, 0.33); f != 3 {
+		t.Fatalf("trimmed mean f=%d, want 3", f)
+	}
+	if f := byzantineBound(RuleMean, 10, 0.33); f != 0 {
+		t.Fatalf("mean tolerates no Byzantine inputs, got f=%d", f)
+	}
+}
+
+func TestRepeatedChildGradientsCountOnce(t *testing.T) {
+	handler, err := NewRPCHandler(TierConfig{
+		TierID:                 "regional-dedupe",
+		ByzantineToleranceFrac: 0.33,
+		AggregationRule:        RuleTrimmedMean,
+		MaxBufferedGradients:   100,
+	}, "")
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	if err := handler.OpenRound(AggregationRequest{RoundID: 9, RequestedCount: 4}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+
+	honest := childGradients(9, [][]float64{{1}, {1}, {1}})
+	for _, g := range honest {
+		if err := handler.receiveGradient(g); err != nil {
+			t.Fatalf("receive %s: %v", g.SourceTierNodeID, err)
+		}
+	}
+	// One child resends a poisoned gradient to outvote the others
+	for i := 0; i < 5; i++ {
+		spam := &GradientMessage{GradientID: fmt.Sprintf("spam-%d", i), SourceTierNodeID: "child-byz", AggregationRound: 9, DimensionCount: 1, GradientData: []float64{50}}
+		err := handler.receiveGradient(spam)
+		if i == 0 && err != nil {
+			t.Fatalf("first gradient from child-byz: %v", err)
+		}
+		if i > 0 && !errors.Is(err, errDuplicateGradient) {
+			t.Fatalf("copy %d from child-byz: expected duplicate, got %v", i, err)
+		}
+	}
+	for len(handler.aggregationChan) > 0 {
+		handler.bufferGradient(<-handler.aggregationChan)
+	}
+	handler.flushPendingAggregations(context.Background())
+
+	resp, ok := handler.LastAggregation()
+	if !ok || resp.AggregatedCount+resp.SkippedCount != 4 {
+		t.Fatalf("expected one gradient per child (4), got %+v", resp)
+	}
+	if resp.GradientResult[0] != 1 {
+		t.Fatalf("expected the repeated child not to move the trimmed mean, got %v", resp.GradientResult)
+	}
+}
+
+func TestFlushForwardsAggregateToParent(t *testing.T) {
+	parent, parentAddr := startWireTestHandler(t, TierConfig{TierID: "continental-robust", MaxBufferedGradients: 100})
+
+	childConfig := TierConfig{
+		TierID:                 "regional-robust",
+		ParentTierNodeID:       "continental-robust",
+		MinQuorumSize:          3,
+		ByzantineToleranceFrac: 0.25,
+		MaxBufferedGradients:   100,
+	}
+	child, err := NewRPCHandler(childConfig, "")
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	client := NewRPCClient(childConfig, parentAddr)
+	defer client.Close()
+	child.SetParentClient(client)
+	if err := child.OpenRound(AggregationRequest{RoundID: 3, RequestedCount: 5}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+
+	for _, g := range childGradients(3, [][]float64{{2, 4}, {2, 4}, {2, 4}, {2, 4}, {90, -90}}) {
+		child.bufferGradient(g)
+	}
+	child.flushPendingAggregations(context.Background())
+
+	resp, ok := child.LastAggregation()
+	if !ok || resp.RoundID != 3 || resp.SkippedCount != 1 {
+		t.Fatalf("unexpected aggregation result: %+v ok=%v", resp, ok)
+	}
+
+	select {
+	case got := <-parent.aggregationChan:
+		if got.AggregationRound != 3 || got.SourceTierNodeID != "regional-robust" {
+			t.Fatalf("parent received wrong aggregate: %+v", got)
+		}
+		if got.GradientData[0] != 2 || got.GradientData[1] != 4 {
+			t.Fatalf("parent aggregate = %v, want [2 4]", got.GradientData)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("parent never received forwarded aggregate")
+	}
+}
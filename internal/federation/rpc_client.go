@@ -6,6 +6,7 @@ package federation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -67,6 +68,11 @@ func (c *RPCClient) ForwardGradient(ctx context.Context, gradient *GradientMessa
 	err := c.grpcBackend.SendGradient(ctx, gradient)
 	latencyMs := float64(time.Since(startTime).Milliseconds())
 
+	var nack *NackError
+	if errors.As(err, &nack) {
+		// The link is healthy; the parent refused this particular gradient
+		return fmt.Errorf("gRPC forward rejected: %w", err)
+	}
 	if err != nil {
 		c.recordFailure(latencyMs)
 		c.applyBackoff()
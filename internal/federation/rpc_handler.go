@@ -6,6 +6,7 @@ package federation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -23,30 +24,75 @@ type RPCHandler struct {
 
 	// Child tier management
 	childHealthMu sync.RWMutex
-	childHealth   map[string]FederationHealth // childNodeID -> health
-	childBuffers  map[string][]*GradientMessage
+	childHealth   map[string]FederationHealth   // childNodeID -> health
+	childBuffers  map[uint64][]*GradientMessage // roundID -> pending child gradients
+	rounds        map[uint64]*roundState        // roundID -> deadline and contributors
+	closedRounds  map[uint64]time.Time          // flushed or failed rounds, rejects late arrivals
 	bufferMu      sync.Mutex
 	maxBufferSize int
 
 	// Aggregation
 	aggregationChan    chan *GradientMessage
 	aggregationTimeout time.Duration
+	flushNow           chan struct{}
 	done               chan struct{}
+
+	// Robust aggregation results and parent forwarding
+	parent            *RPCClient
+	resultMu          sync.RWMutex
+	lastResult        *AggregationResponse
+	byzantineFiltered int64
+	hooks             []AggregateHook
+	gates             []ForwardGate
+
 	// Active connections tracking for graceful shutdown
 	connMu sync.Mutex
 	conns  map[net.Conn]struct{}
 	connWg sync.WaitGroup
 }
 
+// AggregateHook observes every round a handler completes. On success resp and
+// aggregate are set; when the round fails (quorum missed by the deadline) err is set.
+type AggregateHook func(round uint64, resp *AggregationResponse, aggregate *GradientMessage, err error)
+
+// ForwardGate runs on each aggregate before it leaves the tier. An error
+// drops the aggregate: it is neither forwarded nor recorded, and the round
+// fails with that error.
+type ForwardGate func(round uint64, resp *AggregationResponse, aggregate *GradientMessage) error
+
+// roundState tracks the collection window of one aggregation round
+type roundState struct {
+	deadline     time.Time
+	expected     int                 // distinct children that complete the round early (0 = wait for deadline)
+	accepted     map[string]struct{} // children whose gradient was acked, including in-flight ones
+	contributors map[string]struct{} // children whose gradient is buffered
+}
+
+// maxClosedRounds bounds the late-arrival tombstones kept per handler
+const maxClosedRounds = 4096
+
+var (
+	errRoundClosed       = errors.New("aggregation round already closed")
+	errDuplicateGradient = errors.New("child already contributed to this round")
+)
+
 // NewRPCHandler creates a handler for child tier gradient streams
 func NewRPCHandler(config TierConfig, listenAddr string) (*RPCHandler, error) {
+	aggregationTimeout := 5 * time.Second
+	if config.AggregationTimeoutMs > 0 {
+		aggregationTimeout = time.Duration(config.AggregationTimeoutMs) * time.Millisecond
+	}
+
 	// Create listener (will be bound later in Start)
 	handler := &RPCHandler{
 		config:             config,
 		childHealth:        make(map[string]FederationHealth),
-		childBuffers:       make(map[string][]*GradientMessage),
+		childBuffers:       make(map[uint64][]*GradientMessage),
+		rounds:             make(map[uint64]*roundState),
+		closedRounds:       make(map[uint64]time.Time),
 		aggregationChan:    make(chan *GradientMessage, 10000),
-		aggregationTimeout: 5 * time.Second,
+		aggregationTimeout: aggregationTimeout,
+		flushNow:           make(chan struct{}, 1),
 		maxBufferSize:      config.MaxBufferedGradients,
 		done:               make(chan struct{}),
 		conns:              make(map[net.Conn]struct{}),
@@ -69,10 +115,19 @@ func (h *RPCHandler) receiveGradient(gradient *GradientMessage) error {
 		return fmt.Errorf("invalid gradient: nil or empty data")
 	}
 
+	// Claim the child's single slot in the round before acking, so repeats
+	// are refused even while the first copy is still queued
+	h.bufferMu.Lock()
+	err := h.claimLocked(gradient)
+	h.bufferMu.Unlock()
+	if err != nil {
+		return err
+	}
+
 	// Add to aggregation channel (non-blocking with buffer fallback)
 	select {
 	case h.aggregationChan <- gradient:
-		h.recordGradientReceived(gradient.SourceNodeID)
+		h.recordGradientReceived(childNodeID(gradient))
 		return nil
 	default:
 		// Channel is full, buffer the gradient
@@ -85,18 +140,128 @@ func (h *RPCHandler) receiveGradient(gradient *GradientMessage) error {
 			totalBuffered += len(buf)
 		}
 		if totalBuffered >= h.maxBufferSize {
+			h.releaseLocked(gradient)
 			return fmt.Errorf("aggregation buffer full, dropping gradient")
 		}
 
 		// Buffer the gradient
-		key := fmt.Sprintf("round-%d", gradient.AggregationRound)
-		h.childBuffers[key] = append(h.childBuffers[key], gradient)
-		h.recordGradientReceived(gradient.SourceNodeID)
+		if err := h.appendLocked(gradient); err != nil {
+			return err
+		}
+		h.recordGradientReceived(childNodeID(gradient))
 
 		return nil
 	}
 }
 
+// roundLocked returns a round's state, opening the round with the default
+// window on first use. Caller must hold bufferMu.
+func (h *RPCHandler) roundLocked(round uint64) *roundState {
+	st, ok := h.rounds[round]
+	if !ok {
+		st = &roundState{
+			deadline:     time.Now().Add(h.aggregationTimeout),
+			expected:     len(h.config.ChildNodeIDs),
+			accepted:     make(map[string]struct{}),
+			contributors: make(map[string]struct{}),
+		}
+		h.rounds[round] = st
+	}
+	return st
+}
+
+// claimLocked reserves the one gradient a child may contribute to a round.
+// Caller must hold bufferMu.
+func (h *RPCHandler) claimLocked(gradient *GradientMessage) error {
+	round := gradient.AggregationRound
+	if _, closed := h.closedRounds[round]; closed {
+		return fmt.Errorf("round %d: %w", round, errRoundClosed)
+	}
+	st := h.roundLocked(round)
+	child := childNodeID(gradient)
+	if _, dup := st.accepted[child]; dup {
+		return fmt.Errorf("round %d child %s: %w", round, child, errDuplicateGradient)
+	}
+	st.accepted[child] = struct{}{}
+	return nil
+}
+
+// releaseLocked frees a claim whose gradient was not buffered, so the child
+// may retry. Caller must hold bufferMu.
+func (h *RPCHandler) releaseLocked(gradient *GradientMessage) {
+	if st, ok := h.rounds[gradient.AggregationRound]; ok {
+		delete(st.accepted, childNodeID(gradient))
+	}
+}
+
+// appendLocked buffers a gradient under its round, opening the round on first
+// arrival. Each child contributes at most one gradient per round. Caller must
+// hold bufferMu.
+func (h *RPCHandler) appendLocked(gradient *GradientMessage) error {
+	round := gradient.AggregationRound
+	if _, closed := h.closedRounds[round]; closed {
+		return fmt.Errorf("round %d: %w", round, errRoundClosed)
+	}
+
+	st := h.roundLocked(round)
+	child := childNodeID(gradient)
+	if _, dup := st.contributors[child]; dup {
+		return fmt.Errorf("round %d child %s: %w", round, child, errDuplicateGradient)
+	}
+	h.childBuffers[round] = append(h.childBuffers[round], gradient)
+	st.accepted[child] = struct{}{}
+	st.contributors[child] = struct{}{}
+
+	if st.expected > 0 && len(st.contributors) >= st.expected {
+		select {
+		case h.flushNow <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// OpenRound starts (or re-times) collection for a round described by req.
+// DeadlineMs falls back to TierConfig.AggregationTimeoutMs; RequestedCount, when
+// positive, is the number of distinct children that completes the round early.
+func (h *RPCHandler) OpenRound(req AggregationRequest) error {
+	h.bufferMu.Lock()
+	defer h.bufferMu.Unlock()
+
+	if _, closed := h.closedRounds[req.RoundID]; closed {
+		return fmt.Errorf("round %d: %w", req.RoundID, errRoundClosed)
+	}
+
+	timeout := h.aggregationTimeout
+	if req.DeadlineMs > 0 {
+		timeout = time.Duration(req.DeadlineMs) * time.Millisecond
+	}
+	expected := req.RequestedCount
+	if expected <= 0 {
+		expected = len(h.config.ChildNodeIDs)
+	}
+
+	st := h.roundLocked(req.RoundID)
+	st.deadline = time.Now().Add(timeout)
+	st.expected = expected
+	return nil
+}
+
+// OnAggregate registers a hook invoked after each round completes or fails
+func (h *RPCHandler) OnAggregate(hook AggregateHook) {
+	h.resultMu.Lock()
+	defer h.resultMu.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+// BeforeForward registers a gate every aggregate must pass before it is
+// forwarded to the parent tier
+func (h *RPCHandler) BeforeForward(gate ForwardGate) {
+	h.resultMu.Lock()
+	defer h.resultMu.Unlock()
+	h.gates = append(h.gates, gate)
+}
+
 // Start begins listening for incoming gradients from child nodes
 func (h *RPCHandler) Start(listenAddr string) error {
 	listener, err := net.Listen("tcp", listenAddr)
@@ -112,6 +277,14 @@ func (h *RPCHandler) Start(listenAddr string) error {
 	return nil
 }
 
+// Addr returns the bound listen address, or nil before Start
+func (h *RPCHandler) Addr() net.Addr {
+	if h.listener == nil {
+		return nil
+	}
+	return h.listener.Addr()
+}
+
 // acceptLoop handles incoming connections from child nodes
 func (h *RPCHandler) acceptLoop() {
 	for {
@@ -157,48 +330,20 @@ func (h *RPCHandler) removeConn(c net.Conn) {
 	delete(h.conns, c)
 }
 
-// handleConnection processes incoming gradients from a single child node
+// handleConnection decodes framed gradients from a single child node
 func (h *RPCHandler) handleConnection(conn net.Conn) {
 	defer conn.Close()
 
-	// TODO: Implement actual gRPC stream handling
-	// For now, simulate receiving gradients
 	remoteAddr := conn.RemoteAddr().String()
 	log.Printf("[%s rpc-handler] accepted connection from %s", h.config.TierID, remoteAddr)
 
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-h.done:
-			return
-		case <-ticker.C:
-			// Simulate receiving gradient from stream
-			gradient := &GradientMessage{
-				GradientID:       fmt.Sprintf("grad-%d", atomic.AddInt64(&h.gradientsReceived, 1)),
-				SourceNodeID:     remoteAddr,
-				AggregationRound: 1,
-				DimensionCount:   1000,
-				GradientData:     make([]float64, 1000),
-				Timestamp:        time.Now(),
-			}
-			// Calculate norm
-			for i := range gradient.GradientData {
-				gradient.GradientData[i] = float64(i)
-				gradient.Norm += gradient.GradientData[i] * gradient.GradientData[i]
-			}
-			gradient.Norm = float64(int(gradient.Norm) % 100)
-
-			// Add to aggregation channel (non-blocking)
-			select {
-			case h.aggregationChan <- gradient:
-				h.recordGradientReceived(remoteAddr)
-			default:
-				log.Printf("WARN: aggregation channel full, dropping gradient from %s", remoteAddr)
-			}
-		}
+	server := &wireServer{
+		tierID:      h.config.TierID,
+		maxBytes:    resolveMaxFrameBytes(h.config),
+		idleTimeout: wireIdleTimeout,
+		receive:     h.receiveGradient,
 	}
+	server.serve(conn)
 }
 
 // recordGradientReceived updates child health metrics
@@ -218,7 +363,15 @@ func (h *RPCHandler) recordGradientReceived(childNodeID string) {
 func (h *RPCHandler) AggregateLoop(ctx context.Context) {
 	log.Printf("[%s rpc-handler] aggregate loop started", h.config.TierID)
 
-	ticker := time.NewTicker(h.aggregationTimeout)
+	// Poll well inside the round window so deadlines are honoured promptly
+	poll := h.aggregationTimeout / 10
+	if poll < 10*time.Millisecond {
+		poll = 10 * time.Millisecond
+	}
+	if poll > 500*time.Millisecond {
+		poll = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(poll)
 	defer ticker.Stop()
 
 	for {
@@ -243,93 +396,293 @@ func (h *RPCHandler) AggregateLoop(ctx context.Context) {
 
 		case <-ticker.C:
 			h.flushPendingAggregations(ctx)
+
+		case <-h.flushNow:
+			h.flushPendingAggregations(ctx)
 		}
 	}
 }
 
-// bufferGradient buffers an incoming gradient for aggregation
+// bufferGradient buffers an incoming gradient for aggregation. When the
+// buffers overflow, the oldest round is closed and fails.
 func (h *RPCHandler) bufferGradient(gradient *GradientMessage) {
 	h.bufferMu.Lock()
-	defer h.bufferMu.Unlock()
-
-	key := fmt.Sprintf("round-%d", gradient.AggregationRound)
-	h.childBuffers[key] = append(h.childBuffers[key], gradient)
+	if err := h.appendLocked(gradient); err != nil {
+		h.bufferMu.Unlock()
+		log.Printf("WARN: [%s rpc-handler] dropping gradient %s: %v", h.config.TierID, gradient.GradientID, err)
+		return
+	}
 
 	// Check if we've exceeded buffer limits
 	totalBuffered := 0
 	for _, buf := range h.childBuffers {
 		totalBuffered += len(buf)
 	}
-	if totalBuffered > h.maxBufferSize {
-		log.Printf("WARN: buffer overflow, dropping oldest gradients")
-		// Drop oldest round
-		for rk := range h.childBuffers {
-			delete(h.childBuffers, rk)
-			break
+	if totalBuffered <= h.maxBufferSize {
+		h.bufferMu.Unlock()
+		return
+	}
+	// Drop oldest round
+	oldest, found := uint64(0), false
+	for rk := range h.childBuffers {
+		if !found || rk < oldest {
+			oldest, found = rk, true
 		}
 	}
+	h.closeRoundLocked(oldest, time.Now())
+	h.bufferMu.Unlock()
+
+	err := fmt.Errorf("round %d dropped: buffered gradients exceeded %d", oldest, h.maxBufferSize)
+	log.Printf("WARN: [%s rpc-handler] %v", h.config.TierID, err)
+	h.notify(oldest, nil, nil, err)
 }
 
-// flushPendingAggregations collects child gradients and produces aggregates
+// flushPendingAggregations robustly aggregates every round that completed or
+// reached its deadline, forwards each aggregate to the parent tier and fails
+// rounds that missed quorum
 func (h *RPCHandler) flushPendingAggregations(ctx context.Context) {
-	h.bufferMu.Lock()
-	defer h.bufferMu.Unlock()
+	type readyRound struct {
+		round     uint64
+		gradients []*GradientMessage
+	}
 
-	for round, gradients := range h.childBuffers {
-		if len(gradients) == 0 {
+	now := time.Now()
+	h.bufferMu.Lock()
+	var ready []readyRound
+	var failed []uint64
+	for round, st := range h.rounds {
+		complete := st.expected > 0 && len(st.contributors) >= st.expected
+		expired := !now.Before(st.deadline)
+		if !complete && !expired {
 			continue
 		}
 
 		// Check if we have minimum quorum
-		if len(gradients) < h.config.MinQuorumSize {
+		if len(st.contributors) == 0 || len(st.contributors) < h.config.MinQuorumSize {
+			if expired {
+				failed = append(failed, round)
+				h.closeRoundLocked(round, now)
+			}
 			continue
 		}
+		ready = append(ready, readyRound{round: round, gradients: h.childBuffers[round]})
+		h.closeRoundLocked(round, now)
+	}
+	h.bufferMu.Unlock()
+
+	for _, round := range failed {
+		err := fmt.Errorf("round %d missed quorum of %d before deadline", round, h.config.MinQuorumSize)
+		log.Printf("WARN: [%s rpc-handler] %v", h.config.TierID, err)
+		h.notify(round, nil, nil, err)
+	}
 
-		// TODO: Apply Byzantine multi-Krum filtering
-		// For now, simple mean aggregation
-		aggregated := h.simpleAggregate(gradients)
+	// Aggregate and forward without holding the buffer lock
+	for _, r := range ready {
+		resp, aggregated, err := h.aggregateRound(r.round, r.gradients)
+		if err != nil {
+			log.Printf("WARN: [%s rpc-handler] round %d aggregation failed: %v", h.config.TierID, r.round, err)
+			h.notify(r.round, nil, nil, fmt.Errorf("round %d aggregation failed: %w", r.round, err))
+			continue
+		}
+		if err := h.passGates(r.round, resp, aggregated); err != nil {
+			log.Printf("WARN: [%s rpc-handler] round %d aggregate dropped: %v", h.config.TierID, r.round, err)
+			h.notify(r.round, nil, nil, err)
+			continue
+		}
 
 		log.Printf(
-			"[%s rpc-handler] aggregated %d gradients from children (round=%s) norm=%.4f",
+			"[%s rpc-handler] aggregated %d/%d gradients from children (round=%d rule=%s skipped=%d) norm=%.4f",
 			h.config.TierID,
-			len(gradients),
-			round,
-			aggregated.Norm,
+			resp.AggregatedCount,
+			len(r.gradients),
+			r.round,
+			resolveRule(h.config),
+			resp.SkippedCount,
+			resp.GradientNorm,
 		)
 
 		atomic.AddInt64(&h.gradientsAggregated, 1)
-		delete(h.childBuffers, round)
+		atomic.AddInt64(&h.byzantineFiltered, int64(resp.SkippedCount))
+		h.resultMu.Lock()
+		h.lastResult = resp
+		h.resultMu.Unlock()
+
+		if h.parent != nil {
+			if err := h.parent.ForwardGradient(ctx, aggregated); err != nil {
+				log.Printf("WARN: [%s rpc-handler] forwarding round %d to parent failed: %v", h.config.TierID, r.round, err)
+			}
+		}
+		h.notify(r.round, resp, aggregated, nil)
+	}
+}
+
+// closeRoundLocked drops a round's buffers and tombstones it against late
+// arrivals. Caller must hold bufferMu.
+func (h *RPCHandler) closeRoundLocked(round uint64, at time.Time) {
+	delete(h.childBuffers, round)
+	delete(h.rounds, round)
+	h.closedRounds[round] = at
+	if len(h.closedRounds) > maxClosedRounds {
+		oldest, found := uint64(0), false
+		for rk := range h.closedRounds {
+			if !found || rk < oldest {
+				oldest, found = rk, true
+			}
+		}
+		delete(h.closedRounds, oldest)
 	}
 }
 
-// simpleAggregate computes mean of child gradients (placeholder)
-func (h *RPCHandler) simpleAggregate(gradients []*GradientMessage) *GradientMessage {
-	if len(gradients) == 0 {
-		return &GradientMessage{}
+// notify invokes registered aggregate hooks
+func (h *RPCHandler) notify(round uint64, resp *AggregationResponse, aggregate *GradientMessage, err error) {
+	h.resultMu.RLock()
+	hooks := append([]AggregateHook(nil), h.hooks...)
+	h.resultMu.RUnlock()
+	for _, hook := range hooks {
+		hook(round, resp, aggregate, err)
 	}
+}
 
-	result := &GradientMessage{
-		GradientID: fmt.Sprintf("agg-%d", atomic.LoadInt64(&h.gradientsAggregated)),
-		Timestamp:  time.Now(),
+// passGates runs the forward gates in registration order, stopping at the
+// first that refuses the aggregate
+func (h *RPCHandler) passGates(round uint64, resp *AggregationResponse, aggregate *GradientMessage) error {
+	h.resultMu.RLock()
+	gates := append([]ForwardGate(nil), h.gates...)
+	h.resultMu.RUnlock()
+	for _, gate := range gates {
+		if err := gate(round, resp, aggregate); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	dim := len(gradients[0].GradientData)
-	result.GradientData = make([]float64, dim)
-	result.DimensionCount = dim
+// aggregateRound applies the tier's robust aggregation rule to one round.
+// Gradients whose dimension disagrees with the majority are skipped outright.
+func (h *RPCHandler) aggregateRound(round uint64, gradients []*GradientMessage) (*AggregationResponse, *GradientMessage, error) {
+	startTime := time.Now()
 
-	// Compute mean
+	dimVotes := make(map[int]int)
+	dim := 0
 	for _, g := range gradients {
-		for i, val := range g.GradientData {
-			result.GradientData[i] += val / float64(len(gradients))
+		dimVotes[len(g.GradientData)]++
+		if n := dimVotes[len(g.GradientData)]; n > dimVotes[dim] || (n == dimVotes[dim] && len(g.GradientData) < dim) {
+			dim = len(g.GradientData)
 		}
 	}
 
-	// Compute norm
-	for _, val := range result.GradientData {
-		result.Norm += val * val
+	var usable []*GradientMessage
+	var filteredIDs []string
+	for _, g := range gradients {
+		if len(g.GradientData) != dim {
+			filteredIDs = append(filteredIDs, childNodeID(g))
+			continue
+		}
+		usable = append(usable, g)
+	}
+	if len(usable) == 0 || dim == 0 {
+		return nil, nil, fmt.Errorf("no usable gradients")
+	}
+
+	updates := make([][]float64, len(usable))
+	weights := make([]float64, len(usable))
+	for i, g := range usable {
+		updates[i] = g.GradientData
+		weights[i] = float64(h.childWeight(g))
 	}
 
-	return result
+	outcome, err := robustAggregate(resolveRule(h.config), updates, weights, h.config.ByzantineToleranceFrac)
+	if err != nil {
+		return nil, nil, err
+	}
+	rejected := make(map[int]bool, len(outcome.filtered))
+	for _, idx := range outcome.filtered {
+		rejected[idx] = true
+		filteredIDs = append(filteredIDs, childNodeID(usable[idx]))
+	}
+
+	// Carry the edge count of every accepted contribution upward, and the
+	// tier chain below one of them. Edge gradients add no hops, so
+	// participant IDs stay at the tier that received them.
+	var hops []string
+	via := ""
+	contributors := 0
+	for i, g := range usable {
+		if rejected[i] {
+			continue
+		}
+		contributors += int(weights[i])
+		if g.SourceTierNodeID != "" && (via == "" || g.SourceTierNodeID < via) {
+			via = g.SourceTierNodeID
+			hops = append([]string(nil), g.PathHops...)
+		}
+	}
+
+	norm := l2Norm(outcome.data)
+	resp := &AggregationResponse{
+		RoundID:           round,
+		TierNodeID:        h.config.TierID,
+		AggregatedCount:   len(usable) - len(outcome.filtered),
+		SkippedCount:      len(filteredIDs),
+		FilteredNodeIDs:   filteredIDs,
+		GradientResult:    outcome.data,
+		GradientNorm:      norm,
+		ContributorCount:  contributors,
+		AggregationTimeMs: time.Since(startTime).Milliseconds(),
+	}
+	aggregated := &GradientMessage{
+		GradientID:       fmt.Sprintf("%s-round-%d", h.config.TierID, round),
+		SourceNodeID:     h.config.TierID,
+		SourceTierNodeID: h.config.TierID,
+		AggregationRound: round,
+		DimensionCount:   dim,
+		GradientData:     outcome.data,
+		Norm:             norm,
+		Timestamp:        time.Now(),
+		ContributorCount: contributors,
+		PathHops:         hops,
+	}
+	return resp, aggregated, nil
+}
+
+// childWeight is the number of edge gradients g stands for, capped at
+// TierConfig.MaxChildWeight
+func (h *RPCHandler) childWeight(g *GradientMessage) int {
+	limit := h.config.MaxChildWeight
+	if limit <= 0 {
+		limit = DefaultMaxChildWeight
+	}
+	w := g.ContributorCount
+	if w < 1 {
+		w = 1
+	}
+	if w > limit {
+		w = limit
+	}
+	return w
+}
+
+// childNodeID identifies the child that produced a gradient
+func childNodeID(g *GradientMessage) string {
+	if g.SourceTierNodeID != "" {
+		return g.SourceTierNodeID
+	}
+	return g.SourceNodeID
+}
+
+// SetParentClient configures the client used to forward aggregates upward
+func (h *RPCHandler) SetParentClient(client *RPCClient) {
+	h.parent = client
+}
+
+// LastAggregation returns the most recent aggregation result, if any
+func (h *RPCHandler) LastAggregation() (AggregationResponse, bool) {
+	h.resultMu.RLock()
+	defer h.resultMu.RUnlock()
+	if h.lastResult == nil {
+		return AggregationResponse{}, false
+	}
+	return *h.lastResult, true
 }
 
 // GetChildHealth returns health of specific child node
@@ -347,6 +700,7 @@ func (h *RPCHandler) Stats() map[string]interface{} {
 	for _, buf := range h.childBuffers {
 		totalBuffered += len(buf)
 	}
+	openRounds := len(h.rounds)
 	h.bufferMu.Unlock()
 
 	return map[string]interface{}{
@@ -354,8 +708,11 @@ func (h *RPCHandler) Stats() map[string]interface{} {
 		"gradients_received":   atomic.LoadInt64(&h.gradientsReceived),
 		"gradients_aggregated": atomic.LoadInt64(&h.gradientsAggregated),
 		"buffered_gradients":   totalBuffered,
+		"open_rounds":          openRounds,
 		"child_nodes":          len(h.config.ChildNodeIDs),
 		"min_quorum":           h.config.MinQuorumSize,
+		"aggregation_rule":     string(resolveRule(h.config)),
+		"byzantine_filtered":   atomic.LoadInt64(&h.byzantineFiltered),
 	}
 }
 
@@ -0,0 +1,58 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Parent-tier sink for streaming aggregates
+
+package federation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+// RoundSource returns the federation round an aggregate belongs to; ok is
+// false when no round is open. Coordinator.CurrentRound is one.
+type RoundSource func() (round uint64, ok bool)
+
+// ParentTierSink forwards each streaming aggregate to a parent tier as a
+// GradientMessage, so a streaming regional tier can feed the hierarchy. The
+// aggregate joins the federation round given by its RoundSource, not the
+// aggregator's local flush count.
+type ParentTierSink struct {
+	client *RPCClient
+	tierID string
+	rounds RoundSource
+}
+
+// NewParentTierSink creates a sink that forwards through client as tierID
+// into the round reported by rounds
+func NewParentTierSink(client *RPCClient, tierID string, rounds RoundSource) *ParentTierSink {
+	return &ParentTierSink{client: client, tierID: tierID, rounds: rounds}
+}
+
+// SubmitAggregate forwards agg to the parent tier in the current round
+func (s *ParentTierSink) SubmitAggregate(ctx context.Context, agg internal.StreamingAggregate) error {
+	if s.client == nil {
+		return fmt.Errorf("parent tier client is required")
+	}
+	if s.rounds == nil {
+		return fmt.Errorf("parent tier round source is required")
+	}
+	round, ok := s.rounds()
+	if !ok {
+		return fmt.Errorf("no federation round is open for flush %d", agg.RoundID)
+	}
+	return s.client.ForwardGradient(ctx, &GradientMessage{
+		GradientID:       fmt.Sprintf("%s-stream-%d", s.tierID, round),
+		SourceNodeID:     s.tierID,
+		SourceTierNodeID: s.tierID,
+		AggregationRound: round,
+		DimensionCount:   len(agg.Gradient),
+		GradientData:     agg.Gradient,
+		Norm:             l2Norm(agg.Gradient),
+		Timestamp:        agg.FlushedAt,
+		ContributorCount: len(agg.Selected),
+		PathHops:         []string{s.tierID},
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Parent-tier sink tests
+
+package federation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+func TestParentTierSinkForwardsStreamingAggregate(t *testing.T) {
+	parent, parentAddr := startWireTestHandler(t, TierConfig{TierID: "continental-stream", MaxBufferedGradients: 100})
+
+	childConfig := TierConfig{TierID: "regional-stream", ParentTierNodeID: "continental-stream"}
+	coord, err := NewCoordinator(childConfig, "127.0.0.1:0", parentAddr)
+	if err != nil {
+		t.Fatalf("new coordinator: %v", err)
+	}
+	defer coord.Close()
+	client := NewRPCClient(childConfig, parentAddr)
+	defer client.Close()
+	sink := NewParentTierSink(client, "regional-stream", coord.CurrentRound)
+
+	// The aggregator's fourth local flush belongs to federation round 9
+	agg := internal.StreamingAggregate{
+		RoundID:      4,
+		Gradient:     []float64{3, 4},
+		Participants: []string{"edge-1", "edge-2"},
+		FlushedAt:    time.Now(),
+	}
+	if err := sink.SubmitAggregate(context.Background(), agg); err == nil {
+		t.Fatal("expected no forward before a round is open")
+	}
+	if err := coord.OpenRound(AggregationRequest{RoundID: 9}); err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	if err := sink.SubmitAggregate(context.Background(), agg); err != nil {
+		t.Fatalf("submit aggregate: %v", err)
+	}
+
+	select {
+	case got := <-parent.aggregationChan:
+		if got.GradientID != "regional-stream-stream-9" || got.AggregationRound != 9 || got.SourceTierNodeID != "regional-stream" {
+			t.Fatalf("parent received wrong aggregate: %+v", got)
+		}
+		if got.Norm != 5 || got.GradientData[0] != 3 || got.GradientData[1] != 4 {
+			t.Fatalf("parent aggregate = %v (norm %v), want [3 4] (norm 5)", got.GradientData, got.Norm)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("parent never received forwarded aggregate")
+	}
+}
@@ -30,9 +30,13 @@ type GradientMessage struct {
 	GradientData   []float64
 	Norm           float64
 	Timestamp      time.Time
+	// ContributorCount is the number of edge gradients this message stands
+	// for; tiers weight children by it so unbalanced subtrees average
+	// correctly. 0 counts as 1 (an edge gradient).
+	ContributorCount int
 
 	// Provenance
-	PathHops []string // Breadcrumb trail of tier nodes
+	PathHops []string // Breadcrumb trail of tier nodes along one path
 	Proof    []byte   // Byzantine resilience proof
 }
 
@@ -46,16 +50,24 @@ type FederationHealth struct {
 	LastHealthCheck    time.Time
 }
 
+// DefaultMaxChildWeight caps the ContributorCount a child may claim when
+// TierConfig.MaxChildWeight is unset. The count is self-reported, so the cap
+// bounds how far one child can pull a weighted mean.
+const DefaultMaxChildWeight = 1000
+
 // TierConfig describes a tier in the federation hierarchy
 type TierConfig struct {
-	TierID                 string    // "regional-1", "continental-1", etc.
-	Level                  TierLevel // Regional, Continental, Global
-	ParentTierNodeID       string    // Parent in hierarchy (empty if root)
-	ChildNodeIDs           []string  // Direct children
-	MinQuorumSize          int       // Minimum children for consensus
-	AggregationTimeoutMs   int       // Max time to wait for children
-	ByzantineToleranceFrac float64   // Fraud resilience (default 0.33)
-	MaxBufferedGradients   int       // Circuit breaker
+	TierID                 string          // "regional-1", "continental-1", etc.
+	Level                  TierLevel       // Regional, Continental, Global
+	ParentTierNodeID       string          // Parent in hierarchy (empty if root)
+	ChildNodeIDs           []string        // Direct children
+	MinQuorumSize          int             // Minimum children for consensus
+	AggregationTimeoutMs   int             // Max time to wait for children
+	ByzantineToleranceFrac float64         // Fraud resilience (default 0.33)
+	MaxBufferedGradients   int             // Circuit breaker
+	MaxFrameBytes          int             // Wire frame limit (0 = DefaultMaxFrameBytes)
+	AggregationRule        AggregationRule // Robust aggregator (default multi_krum)
+	MaxChildWeight         int             // Caps a child's claimed ContributorCount (0 = DefaultMaxChildWeight)
 }
 
 // AggregationRequest represents a request from parent tier
@@ -71,8 +83,21 @@ type AggregationResponse struct {
 	RoundID           uint64
 	TierNodeID        string
 	AggregatedCount   int
-	SkippedCount      int // Byzantine filtered
+	SkippedCount      int      // Byzantine filtered
+	FilteredNodeIDs   []string // Children whose gradients were filtered
 	GradientResult    []float64
 	GradientNorm      float64
+	ContributorCount  int // Edge gradients behind GradientResult
 	AggregationTimeMs int64
 }
+
+// GlobalModel is the final aggregate published by the root tier for a round
+type GlobalModel struct {
+	RoundID          uint64
+	RootTierNodeID   string
+	GradientData     []float64
+	Norm             float64
+	ContributorCount int      // Edge gradients behind the model
+	PathHops         []string // Tier nodes along one path from a regional tier to the root
+	PublishedAt      time.Time
+}
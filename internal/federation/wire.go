@@ -0,0 +1,523 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Federation wire protocol: framed, versioned gradient encoding
+
+package federation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"time"
+)
+
+// Frame layout (all integers big-endian):
+//
+//	uint32 length | uint8 version | uint8 type | body[length-2]
+//
+// length covers version, type and body. Each request frame is answered by
+// exactly one response frame on the same connection.
+const (
+	WireVersion1 uint8 = 1
+
+	// DefaultMaxFrameBytes bounds a single frame when TierConfig.MaxFrameBytes is unset
+	DefaultMaxFrameBytes = 16 * 1024 * 1024
+
+	frameHeaderBytes = 4
+	frameMetaBytes   = 2 // version + type
+	maxWireString    = math.MaxUint16
+	maxWireHops      = math.MaxUint16
+)
+
+// Frame types
+const (
+	MsgGradient uint8 = 0 // single GradientMessage
+	MsgBatch    uint8 = 1 // uint32 count + GradientMessage * count
+	MsgHealth   uint8 = 2 // empty request, uint8 healthy response
+	MsgAck      uint8 = 3 // uint32 count + AckCode * count
+)
+
+// AckCode is the per-message status returned by the receiving tier
+type AckCode uint8
+
+const (
+	AckOK AckCode = iota
+	NackMalformed
+	NackUnsupportedVersion
+	NackTooLarge
+	NackInvalidGradient
+	NackBufferFull
+	NackUnknownType
+	NackRoundClosed
+	NackDuplicate
+)
+
+// String returns a stable name for the code
+func (c AckCode) String() string {
+	switch c {
+	case AckOK:
+		return "ok"
+	case NackMalformed:
+		return "malformed"
+	case NackUnsupportedVersion:
+		return "unsupported_version"
+	case NackTooLarge:
+		return "too_large"
+	case NackInvalidGradient:
+		return "invalid_gradient"
+	case NackBufferFull:
+		return "buffer_full"
+	case NackUnknownType:
+		return "unknown_type"
+	case NackRoundClosed:
+		return "round_closed"
+	case NackDuplicate:
+		return "duplicate"
+	default:
+		return fmt.Sprintf("code_%d", uint8(c))
+	}
+}
+
+// closesConnection reports whether the server drops the stream after sending c
+func (c AckCode) closesConnection() bool {
+	return c == NackTooLarge || c == NackUnsupportedVersion || c == NackUnknownType
+}
+
+// NackError reports a message rejected by the receiving tier
+type NackError struct {
+	Code  AckCode
+	Index int // position within the batch (0 for single sends)
+}
+
+func (e *NackError) Error() string {
+	return fmt.Sprintf("gradient %d rejected by parent: %s", e.Index, e.Code)
+}
+
+var (
+	errFrameTooLarge      = errors.New("frame exceeds maximum size")
+	errUnsupportedVersion = errors.New("unsupported wire version")
+	errTruncated          = errors.New("truncated message")
+)
+
+// resolveMaxFrameBytes returns the effective frame limit for a tier
+func resolveMaxFrameBytes(config TierConfig) int {
+	if config.MaxFrameBytes > 0 {
+		return config.MaxFrameBytes
+	}
+	return DefaultMaxFrameBytes
+}
+
+// writeFrame writes one length-prefixed frame
+func writeFrame(w io.Writer, msgType uint8, body []byte, maxBytes int) error {
+	length := frameMetaBytes + len(body)
+	if length > maxBytes {
+		return fmt.Errorf("%w: %d > %d", errFrameTooLarge, length, maxBytes)
+	}
+	buf := make([]byte, frameHeaderBytes+length)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(length))
+	buf[4] = WireVersion1
+	buf[5] = msgType
+	copy(buf[6:], body)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame reads one frame, enforcing maxBytes before allocating the body
+func readFrame(r io.Reader, maxBytes int) (msgType uint8, body []byte, err error) {
+	var header [frameHeaderBytes + frameMetaBytes]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[0:4]))
+	if length < frameMetaBytes {
+		return 0, nil, errTruncated
+	}
+	if length > maxBytes {
+		return header[5], nil, fmt.Errorf("%w: %d > %d", errFrameTooLarge, length, maxBytes)
+	}
+	if header[4] != WireVersion1 {
+		return header[5], nil, fmt.Errorf("%w: %d", errUnsupportedVersion, header[4])
+	}
+	body = make([]byte, length-frameMetaBytes)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header[5], body, nil
+}
+
+// wireWriter appends primitive fields to a byte slice
+type wireWriter struct {
+	buf []byte
+}
+
+func (w *wireWriter) u8(v uint8)   { w.buf = append(w.buf, v) }
+func (w *wireWriter) u16(v uint16) { w.buf = binary.BigEndian.AppendUint16(w.buf, v) }
+func (w *wireWriter) u32(v uint32) { w.buf = binary.BigEndian.AppendUint32(w.buf, v) }
+func (w *wireWriter) u64(v uint64) { w.buf = binary.BigEndian.AppendUint64(w.buf, v) }
+func (w *wireWriter) f64(v float64) {
+	w.u64(math.Float64bits(v))
+}
+
+func (w *wireWriter) str(s string) error {
+	if len(s) > maxWireString {
+		return fmt.Errorf("string field too long: %d bytes", len(s))
+	}
+	w.u16(uint16(len(s)))
+	w.buf = append(w.buf, s...)
+	return nil
+}
+
+func (w *wireWriter) bytes(b []byte) {
+	w.u32(uint32(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// wireReader consumes primitive fields, latching the first error
+type wireReader struct {
+	buf []byte
+	err error
+}
+
+func (r *wireReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errTruncated
+		return nil
+	}
+	out := r.buf[:n]
+	r.buf = r.buf[n:]
+	return out
+}
+
+func (r *wireReader) u8() uint8 {
+	b := r.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *wireReader) u16() uint16 {
+	b := r.take(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (r *wireReader) u32() uint32 {
+	b := r.take(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *wireReader) u64() uint64 {
+	b := r.take(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *wireReader) f64() float64 { return math.Float64frombits(r.u64()) }
+
+func (r *wireReader) str() string { return string(r.take(int(r.u16()))) }
+
+func (r *wireReader) bytes() []byte {
+	n := int(r.u32())
+	b := r.take(n)
+	if b == nil || n == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// appendGradient encodes a GradientMessage into w
+func appendGradient(w *wireWriter, g *GradientMessage) error {
+	if g == nil {
+		return fmt.Errorf("nil gradient")
+	}
+	if len(g.PathHops) > maxWireHops {
+		return fmt.Errorf("too many path hops: %d", len(g.PathHops))
+	}
+	for _, s := range []string{g.GradientID, g.SourceNodeID, g.SourceTierNodeID} {
+		if err := w.str(s); err != nil {
+			return err
+		}
+	}
+	w.u64(g.AggregationRound)
+	w.u32(uint32(g.DimensionCount))
+	w.u32(uint32(len(g.GradientData)))
+	for _, v := range g.GradientData {
+		w.f64(v)
+	}
+	w.f64(g.Norm)
+	var ts int64
+	if !g.Timestamp.IsZero() {
+		ts = g.Timestamp.UnixNano()
+	}
+	w.u64(uint64(ts))
+	w.u16(uint16(len(g.PathHops)))
+	for _, hop := range g.PathHops {
+		if err := w.str(hop); err != nil {
+			return err
+		}
+	}
+	w.bytes(g.Proof)
+	w.u32(uint32(max(g.ContributorCount, 0)))
+	return nil
+}
+
+// readGradient decodes a GradientMessage from r
+func readGradient(r *wireReader) (*GradientMessage, error) {
+	g := &GradientMessage{
+		GradientID:       r.str(),
+		SourceNodeID:     r.str(),
+		SourceTierNodeID: r.str(),
+		AggregationRound: r.u64(),
+		DimensionCount:   int(r.u32()),
+	}
+	n := int(r.u32())
+	// Each value needs 8 bytes; reject counts the remaining body cannot hold
+	if r.err == nil && n > len(r.buf)/8 {
+		r.err = errTruncated
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	g.GradientData = make([]float64, n)
+	for i := range g.GradientData {
+		g.GradientData[i] = r.f64()
+	}
+	g.Norm = r.f64()
+	if ts := int64(r.u64()); ts != 0 {
+		g.Timestamp = time.Unix(0, ts)
+	}
+	hops := int(r.u16())
+	if hops > 0 {
+		g.PathHops = make([]string, 0, hops)
+		for i := 0; i < hops; i++ {
+			g.PathHops = append(g.PathHops, r.str())
+		}
+	}
+	g.Proof = r.bytes()
+	g.ContributorCount = int(r.u32())
+	if r.err != nil {
+		return nil, r.err
+	}
+	return g, nil
+}
+
+// EncodeGradient returns the wire body of a single gradient
+func EncodeGradient(g *GradientMessage) ([]byte, error) {
+	w := &wireWriter{}
+	if err := appendGradient(w, g); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// DecodeGradient parses a wire body produced by EncodeGradient
+func DecodeGradient(body []byte) (*GradientMessage, error) {
+	r := &wireReader{buf: body}
+	g, err := readGradient(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.buf) != 0 {
+		return nil, fmt.Errorf("trailing %d bytes after gradient", len(r.buf))
+	}
+	return g, nil
+}
+
+// encodeBatch returns the wire body of a gradient batch
+func encodeBatch(gradients []*GradientMessage) ([]byte, error) {
+	w := &wireWriter{}
+	w.u32(uint32(len(gradients)))
+	for _, g := range gradients {
+		if err := appendGradient(w, g); err != nil {
+			return nil, err
+		}
+	}
+	return w.buf, nil
+}
+
+// decodeBatch parses a batch body; per-message validation happens in the caller
+func decodeBatch(body []byte) ([]*GradientMessage, error) {
+	r := &wireReader{buf: body}
+	count := int(r.u32())
+	if r.err != nil {
+		return nil, r.err
+	}
+	gradients := make([]*GradientMessage, 0, min(count, 1024))
+	for i := 0; i < count; i++ {
+		g, err := readGradient(r)
+		if err != nil {
+			return nil, fmt.Errorf("batch entry %d: %w", i, err)
+		}
+		gradients = append(gradients, g)
+	}
+	if len(r.buf) != 0 {
+		return nil, fmt.Errorf("trailing %d bytes after batch", len(r.buf))
+	}
+	return gradients, nil
+}
+
+// validateGradient checks decoded fields for internal consistency
+func validateGradient(g *GradientMessage) AckCode {
+	if len(g.GradientData) == 0 || g.DimensionCount != len(g.GradientData) {
+		return NackInvalidGradient
+	}
+	for _, v := range g.GradientData {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return NackInvalidGradient
+		}
+	}
+	return AckOK
+}
+
+func encodeAcks(codes []AckCode) []byte {
+	w := &wireWriter{}
+	w.u32(uint32(len(codes)))
+	for _, c := range codes {
+		w.u8(uint8(c))
+	}
+	return w.buf
+}
+
+func decodeAcks(body []byte) ([]AckCode, error) {
+	r := &wireReader{buf: body}
+	count := int(r.u32())
+	raw := r.take(count)
+	if r.err != nil {
+		return nil, r.err
+	}
+	codes := make([]AckCode, count)
+	for i, b := range raw {
+		codes[i] = AckCode(b)
+	}
+	return codes, nil
+}
+
+// wireIdleTimeout closes a child connection that sends no frame for this long
+const wireIdleTimeout = 30 * time.Second
+
+// wireServer answers framed requests on a single connection
+type wireServer struct {
+	tierID      string
+	maxBytes    int
+	idleTimeout time.Duration
+	receive     func(*GradientMessage) error
+	healthy     func() bool
+}
+
+// serve reads frames until EOF, a protocol violation or conn close
+func (s *wireServer) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		if s.idleTimeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(s.idleTimeout))
+		}
+
+		msgType, body, err := readFrame(reader, s.maxBytes)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			switch {
+			case errors.Is(err, errFrameTooLarge):
+				s.reject(conn, NackTooLarge)
+			case errors.Is(err, errUnsupportedVersion):
+				s.reject(conn, NackUnsupportedVersion)
+			case errors.Is(err, errTruncated):
+				s.reject(conn, NackMalformed)
+			}
+			// The stream cannot be resynchronised after a bad header
+			return
+		}
+
+		switch msgType {
+		case MsgGradient:
+			code := AckOK
+			g, err := DecodeGradient(body)
+			if err != nil {
+				code = NackMalformed
+			} else {
+				code = s.accept(g)
+			}
+			if err := s.respond(conn, MsgAck, encodeAcks([]AckCode{code})); err != nil {
+				return
+			}
+
+		case MsgBatch:
+			gradients, err := decodeBatch(body)
+			if err != nil {
+				if err := s.respond(conn, MsgAck, encodeAcks([]AckCode{NackMalformed})); err != nil {
+					return
+				}
+				continue
+			}
+			codes := make([]AckCode, len(gradients))
+			for i, g := range gradients {
+				codes[i] = s.accept(g)
+			}
+			if err := s.respond(conn, MsgAck, encodeAcks(codes)); err != nil {
+				return
+			}
+
+		case MsgHealth:
+			status := uint8(1)
+			if s.healthy != nil && !s.healthy() {
+				status = 0
+			}
+			if err := s.respond(conn, MsgHealth, []byte{status}); err != nil {
+				return
+			}
+
+		default:
+			s.reject(conn, NackUnknownType)
+			return
+		}
+	}
+}
+
+// accept validates and hands a decoded gradient to the tier
+func (s *wireServer) accept(g *GradientMessage) AckCode {
+	if code := validateGradient(g); code != AckOK {
+		return code
+	}
+	if err := s.receive(g); err != nil {
+		switch {
+		case errors.Is(err, errRoundClosed):
+			return NackRoundClosed
+		case errors.Is(err, errDuplicateGradient):
+			return NackDuplicate
+		}
+		return NackBufferFull
+	}
+	return AckOK
+}
+
+func (s *wireServer) respond(conn net.Conn, msgType uint8, body []byte) error {
+	if err := writeFrame(conn, msgType, body, s.maxBytes); err != nil {
+		if !errors.Is(err, net.ErrClosed) {
+			log.Printf("WARN: [%s] failed to write response: %v", s.tierID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *wireServer) reject(conn net.Conn, code AckCode) {
+	log.Printf("WARN: [%s] rejecting connection %s: %s", s.tierID, conn.RemoteAddr(), code)
+	_ = s.respond(conn, MsgAck, encodeAcks([]AckCode{code}))
+}
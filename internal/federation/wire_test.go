@@ -0,0 +1,163 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Wire protocol encoding and ACK/NACK tests
+
+package federation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testGradient(id string, values ...float64) *GradientMessage {
+	return &GradientMessage{
+		GradientID:       id,
+		SourceNodeID:     "edge-7",
+		SourceTierNodeID: "regional-1",
+		AggregationRound: 42,
+		DimensionCount:   len(values),
+		GradientData:     values,
+		Norm:             1.5,
+		Timestamp:        time.Unix(0, 1700000000123456789),
+		PathHops:         []string{"regional-1", "continental-1"},
+		Proof:            []byte{0xde, 0xad, 0xbe, 0xef},
+		ContributorCount: 6,
+	}
+}
+
+func TestWireGradientRoundTrip(t *testing.T) {
+	in := testGradient("grad-1", 0.25, -1, 3.5)
+
+	body, err := EncodeGradient(in)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out, err := DecodeGradient(body)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !out.Timestamp.Equal(in.Timestamp) {
+		t.Fatalf("timestamp mismatch: %v != %v", out.Timestamp, in.Timestamp)
+	}
+	out.Timestamp = in.Timestamp
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("round trip mismatch:\n in=%+v\nout=%+v", in, out)
+	}
+
+	if _, err := DecodeGradient(body[:len(body)-3]); err == nil {
+		t.Fatal("expected truncated body to fail decoding")
+	}
+}
+
+func TestWireFrameSizeEnforced(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, MsgGradient, make([]byte, 64), 32); !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("expected writer to refuse oversized frame, got %v", err)
+	}
+
+	if err := writeFrame(&buf, MsgGradient, make([]byte, 64), 1024); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := readFrame(bytes.NewReader(buf.Bytes()), 32); !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("expected reader to refuse oversized frame, got %v", err)
+	}
+}
+
+func startWireTestHandler(t *testing.T, config TierConfig) (*RPCHandler, string) {
+	t.Helper()
+	handler, err := NewRPCHandler(config, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	if err := handler.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start handler: %v", err)
+	}
+	t.Cleanup(func() { handler.Close() })
+	return handler, handler.listener.Addr().String()
+}
+
+func TestWireAckNack(t *testing.T) {
+	config := TierConfig{TierID: "continental-wire", Level: TierContinental, MaxBufferedGradients: 100}
+	handler, addr := startWireTestHandler(t, config)
+
+	client := NewGRPCClientBackend(TierConfig{TierID: "regional-wire"}, addr)
+	defer client.Close()
+	ctx := context.Background()
+
+	if err := client.SendGradient(ctx, testGradient("ok", 1, 2, 3)); err != nil {
+		t.Fatalf("expected ACK, got %v", err)
+	}
+	select {
+	case got := <-handler.aggregationChan:
+		if got.GradientID != "ok" || got.GradientData[2] != 3 || got.SourceNodeID != "edge-7" {
+			t.Fatalf("handler received wrong gradient: %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler never received decoded gradient")
+	}
+	if health, ok := handler.GetChildHealth("regional-1"); !ok || health.GradientsReceived != 1 {
+		t.Fatalf("expected the gradient credited to child tier regional-1, got %+v", health)
+	}
+
+	bad := testGradient("nan", 1, math.NaN())
+	var nack *NackError
+	if err := client.SendGradient(ctx, bad); !errors.As(err, &nack) || nack.Code != NackInvalidGradient {
+		t.Fatalf("expected invalid_gradient NACK, got %v", err)
+	}
+
+	if err := client.SendGradient(ctx, testGradient("again", 1, 2, 3)); !errors.As(err, &nack) || nack.Code != NackDuplicate {
+		t.Fatalf("expected a second gradient from the child to be NACKed as duplicate, got %v", err)
+	}
+
+	mismatched := testGradient("dims", 1, 2)
+	mismatched.DimensionCount = 5
+	b0, b2 := testGradient("b0", 1), testGradient("b2", 2)
+	b0.SourceTierNodeID, b2.SourceTierNodeID = "regional-2", "regional-3"
+	accepted, err := client.SendBatch(ctx, []*GradientMessage{
+		b0,
+		mismatched,
+		b2,
+	})
+	if err != nil || accepted != 2 {
+		t.Fatalf("expected 2 of 3 accepted, got %d err=%v", accepted, err)
+	}
+
+	if err := client.Health(ctx); err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+}
+
+func TestWireOversizedFrameRejected(t *testing.T) {
+	config := TierConfig{TierID: "continental-small", Level: TierContinental, MaxBufferedGradients: 100, MaxFrameBytes: 256}
+	_, addr := startWireTestHandler(t, config)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	body, err := EncodeGradient(testGradient("big", make([]float64, 512)...))
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if err := writeFrame(conn, MsgGradient, body, DefaultMaxFrameBytes); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	msgType, resp, err := readFrame(conn, DefaultMaxFrameBytes)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	codes, err := decodeAcks(resp)
+	if err != nil || msgType != MsgAck || len(codes) != 1 || codes[0] != NackTooLarge {
+		t.Fatalf("expected too_large NACK, got type=%d codes=%v err=%v", msgType, codes, err)
+	}
+}
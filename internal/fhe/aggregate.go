@@ -1,39 +1,264 @@
 package fhe
 
-import "fmt"
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
 
-// EncryptedUpdate is a deterministic stand-in for threshold-FHE ciphertext payloads.
-// Values are intentionally opaque to orchestrator logic in higher layers.
+// DefaultFixedPointScale maps float gradients to integers with ~6 decimal digits.
+const DefaultFixedPointScale = 1 << 20
+
+// EncryptedUpdate is a vector of Paillier ciphertexts under a threshold key.
+// Count tracks how many contributions were homomorphically summed into it.
 type EncryptedUpdate struct {
-	Contributor string  `json:"contributor"`
-	Values      []int64 `json:"values"`
+	Contributor string
+	KeyID       uint64
+	Scale       float64
+	Count       int
+	Ciphertexts []*big.Int
+}
+
+// PartialDecryption is one Shamir share's contribution c^(2*Delta*s_i) per coordinate.
+type PartialDecryption struct {
+	NodeID string
+	Index  int
+	Values []*big.Int
 }
 
-func AggregateCiphertexts(updates []EncryptedUpdate) (EncryptedUpdate, error) {
+// EncodeFixedPoint converts values to plaintexts in Z_N, mapping negatives to N-|x|.
+func EncodeFixedPoint(pk *PublicKey, values []float64, scale float64) ([]*big.Int, error) {
+	if scale <= 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return nil, fmt.Errorf("fixed-point scale must be positive and finite")
+	}
+	out := make([]*big.Int, len(values))
+	for i, v := range values {
+		scaled := math.Round(v * scale)
+		if math.IsNaN(scaled) || math.IsInf(scaled, 0) || math.Abs(scaled) >= 1<<62 {
+			return nil, fmt.Errorf("value %d (%v) not representable at scale %v", i, v, scale)
+		}
+		m := big.NewInt(int64(scaled))
+		if m.Sign() < 0 {
+			m.Add(m, pk.N)
+		}
+		out[i] = m
+	}
+	return out, nil
+}
+
+// DecodeFixedPoint interprets plaintexts in the centred range (-N/2, N/2].
+func DecodeFixedPoint(pk *PublicKey, plaintexts []*big.Int, scale float64) []float64 {
+	half := new(big.Int).Rsh(pk.N, 1)
+	out := make([]float64, len(plaintexts))
+	for i, m := range plaintexts {
+		x := new(big.Int).Set(m)
+		if x.Cmp(half) > 0 {
+			x.Sub(x, pk.N)
+		}
+		f, _ := new(big.Float).SetInt(x).Float64()
+		out[i] = f / scale
+	}
+	return out
+}
+
+// Encrypt fixed-point encodes values and encrypts each coordinate as
+// c = (1+N)^m * r^N mod N^2 with fresh randomness r.
+func Encrypt(pk *PublicKey, contributor string, values []float64, scale float64) (EncryptedUpdate, error) {
+	if len(values) == 0 {
+		return EncryptedUpdate{}, fmt.Errorf("empty update vector")
+	}
+	plaintexts, err := EncodeFixedPoint(pk, values, scale)
+	if err != nil {
+		return EncryptedUpdate{}, err
+	}
+	cts := make([]*big.Int, len(plaintexts))
+	for i, m := range plaintexts {
+		c, err := encryptPlaintext(pk, m)
+		if err != nil {
+			return EncryptedUpdate{}, err
+		}
+		cts[i] = c
+	}
+	return EncryptedUpdate{Contributor: contributor, KeyID: pk.KeyID(), Scale: scale, Count: 1, Ciphertexts: cts}, nil
+}
+
+func encryptPlaintext(pk *PublicKey, m *big.Int) (*big.Int, error) {
+	var r *big.Int
+	for {
+		candidate, err := rand.Int(rand.Reader, pk.N)
+		if err != nil {
+			return nil, fmt.Errorf("sample randomness: %w", err)
+		}
+		if candidate.Sign() > 0 && new(big.Int).GCD(nil, nil, candidate, pk.N).Cmp(one) == 0 {
+			r = candidate
+			break
+		}
+	}
+	// (1+N)^m = 1 + m*N mod N^2
+	gm := new(big.Int).Mul(m, pk.N)
+	gm.Add(gm, one)
+	rn := new(big.Int).Exp(r, pk.N, pk.NSquared)
+	gm.Mul(gm, rn)
+	return gm.Mod(gm, pk.NSquared), nil
+}
+
+// AggregateCiphertexts homomorphically sums updates by multiplying ciphertexts mod N^2.
+func AggregateCiphertexts(pk *PublicKey, updates []EncryptedUpdate) (EncryptedUpdate, error) {
 	if len(updates) == 0 {
 		return EncryptedUpdate{}, fmt.Errorf("no encrypted updates provided")
 	}
-	dim := len(updates[0].Values)
+	dim := len(updates[0].Ciphertexts)
 	if dim == 0 {
 		return EncryptedUpdate{}, fmt.Errorf("empty encrypted vector")
 	}
-	acc := make([]int64, dim)
+	keyID := pk.KeyID()
+	scale := updates[0].Scale
+	acc := make([]*big.Int, dim)
+	for i := range acc {
+		acc[i] = big.NewInt(1)
+	}
+	count := 0
 	for _, update := range updates {
-		if len(update.Values) != dim {
+		if update.KeyID != keyID {
+			return EncryptedUpdate{}, fmt.Errorf("update from %q encrypted under a different key", update.Contributor)
+		}
+		if update.Scale != scale {
+			return EncryptedUpdate{}, fmt.Errorf("mismatched fixed-point scales")
+		}
+		if len(update.Ciphertexts) != dim {
 			return EncryptedUpdate{}, fmt.Errorf("mismatched ciphertext dimensions")
 		}
-		for i := range update.Values {
-			acc[i] += update.Values[i]
+		for i, c := range update.Ciphertexts {
+			if c == nil || c.Sign() <= 0 || c.Cmp(pk.NSquared) >= 0 {
+				return EncryptedUpdate{}, fmt.Errorf("ciphertext %d from %q out of range", i, update.Contributor)
+			}
+			acc[i].Mul(acc[i], c)
+			acc[i].Mod(acc[i], pk.NSquared)
 		}
+		count += update.Count
 	}
-	return EncryptedUpdate{Contributor: "threshold-aggregate", Values: acc}, nil
+	return EncryptedUpdate{Contributor: "threshold-aggregate", KeyID: keyID, Scale: scale, Count: count, Ciphertexts: acc}, nil
 }
 
-func DecryptAggregate(aggregate EncryptedUpdate, participants []string, shares map[string]KeyShare, threshold int) ([]int64, error) {
-	if !HasQuorum(participants, shares, threshold) {
-		return nil, fmt.Errorf("insufficient key-share quorum")
+// PartialDecrypt produces one partial decryption per secret the holder owns.
+func PartialDecrypt(pk *PublicKey, share KeyShare, aggregate EncryptedUpdate) ([]PartialDecryption, error) {
+	if aggregate.KeyID != pk.KeyID() {
+		return nil, fmt.Errorf("aggregate encrypted under a different key")
+	}
+	if len(share.Secrets) == 0 {
+		return nil, fmt.Errorf("share for node %q carries no secret material", share.NodeID)
+	}
+	out := make([]PartialDecryption, 0, len(share.Secrets))
+	for _, s := range share.Secrets {
+		if s.Value == nil || s.Index <= 0 || s.Index > pk.TotalShares {
+			return nil, fmt.Errorf("invalid secret share index %d for node %q", s.Index, share.NodeID)
+		}
+		exp := new(big.Int).Mul(pk.delta, s.Value)
+		exp.Lsh(exp, 1)
+		values := make([]*big.Int, len(aggregate.Ciphertexts))
+		for i, c := range aggregate.Ciphertexts {
+			values[i] = new(big.Int).Exp(c, exp, pk.NSquared)
+		}
+		out = append(out, PartialDecryption{NodeID: strings.TrimSpace(share.NodeID), Index: s.Index, Values: values})
 	}
-	out := make([]int64, len(aggregate.Values))
-	copy(out, aggregate.Values)
 	return out, nil
 }
+
+// CombinePartialDecryptions recovers the fixed-point sum from at least
+// Threshold distinct partial decryptions using integer Lagrange coefficients
+// scaled by Delta = TotalShares!.
+func CombinePartialDecryptions(pk *PublicKey, aggregate EncryptedUpdate, partials []PartialDecryption) ([]float64, error) {
+	if aggregate.KeyID != pk.KeyID() {
+		return nil, fmt.Errorf("aggregate encrypted under a different key")
+	}
+	dim := len(aggregate.Ciphertexts)
+	chosen := make([]PartialDecryption, 0, pk.Threshold)
+	seen := map[int]struct{}{}
+	for _, p := range partials {
+		if _, dup := seen[p.Index]; dup {
+			continue
+		}
+		if p.Index <= 0 || p.Index > pk.TotalShares || len(p.Values) != dim {
+			return nil, fmt.Errorf("malformed partial decryption from %q", p.NodeID)
+		}
+		seen[p.Index] = struct{}{}
+		chosen = append(chosen, p)
+		if len(chosen) == pk.Threshold {
+			break
+		}
+	}
+	if len(chosen) < pk.Threshold {
+		return nil, fmt.Errorf("insufficient key-share quorum: have %d of %d shares", len(chosen), pk.Threshold)
+	}
+
+	exps := make([]*big.Int, len(chosen))
+	for k, p := range chosen {
+		num := new(big.Int).Set(pk.delta)
+		den := big.NewInt(1)
+		for _, q := range chosen {
+			if q.Index == p.Index {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(q.Index)))
+			den.Mul(den, big.NewInt(int64(q.Index-p.Index)))
+		}
+		// Delta makes every coefficient integral; keep the factor 2 from Shoup's combiner
+		exps[k] = num.Quo(num, den)
+		exps[k].Lsh(exps[k], 1)
+	}
+
+	// 4*Delta^2 appears in the combined exponent and must be removed mod N
+	fourDelta2 := new(big.Int).Mul(pk.delta, pk.delta)
+	fourDelta2.Lsh(fourDelta2, 2)
+	inv := new(big.Int).ModInverse(fourDelta2, pk.N)
+	if inv == nil {
+		return nil, fmt.Errorf("share count incompatible with modulus")
+	}
+
+	plaintexts := make([]*big.Int, dim)
+	for i := 0; i < dim; i++ {
+		acc := big.NewInt(1)
+		for k, p := range chosen {
+			term := new(big.Int).Exp(p.Values[i], new(big.Int).Abs(exps[k]), pk.NSquared)
+			if exps[k].Sign() < 0 {
+				if term.ModInverse(term, pk.NSquared) == nil {
+					return nil, fmt.Errorf("partial decryption from %q not invertible", p.NodeID)
+				}
+			}
+			acc.Mul(acc, term)
+			acc.Mod(acc, pk.NSquared)
+		}
+		// L(u) = (u-1)/N
+		acc.Sub(acc, one)
+		acc.Quo(acc, pk.N)
+		acc.Mul(acc, inv)
+		plaintexts[i] = acc.Mod(acc, pk.N)
+	}
+	return DecodeFixedPoint(pk, plaintexts, aggregate.Scale), nil
+}
+
+// DecryptAggregate collects partial decryptions from the participating share
+// holders and combines them once their weight meets the key's threshold.
+func DecryptAggregate(pk *PublicKey, aggregate EncryptedUpdate, participants []string, shares map[string]KeyShare) ([]float64, error) {
+	if !HasQuorum(participants, shares, pk.Threshold) {
+		return nil, fmt.Errorf("insufficient key-share quorum")
+	}
+	var partials []PartialDecryption
+	for _, node := range SortedParticipants(participants) {
+		share, ok := shares[node]
+		if !ok {
+			continue
+		}
+		p, err := PartialDecrypt(pk, share, aggregate)
+		if err != nil {
+			return nil, err
+		}
+		partials = append(partials, p...)
+		if len(partials) >= pk.Threshold {
+			break
+		}
+	}
+	return CombinePartialDecryptions(pk, aggregate, partials)
+}
@@ -1,14 +1,170 @@
 package fhe
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"math/big"
 	"sort"
 	"strings"
 )
 
+// MinModulusBits is the smallest Paillier modulus accepted by GenerateThresholdKey.
+const MinModulusBits = 512
+
+// DefaultModulusBits is the modulus size used for production key ceremonies.
+const DefaultModulusBits = 2048
+
+var one = big.NewInt(1)
+
+// KeyShare is one holder's slice of the threshold decryption key. A holder of
+// weight w owns w Shamir shares, so the threshold is counted in weight units.
 type KeyShare struct {
-	NodeID string
-	Weight int
+	NodeID  string
+	Weight  int
+	Secrets []SecretShare
+}
+
+// SecretShare is a single Shamir evaluation f(Index) of the decryption exponent.
+type SecretShare struct {
+	Index int
+	Value *big.Int
+}
+
+// PublicKey is the threshold Paillier public key shared by all contributors.
+type PublicKey struct {
+	N           *big.Int
+	NSquared    *big.Int
+	Threshold   int
+	TotalShares int
+	delta       *big.Int // TotalShares!
+}
+
+// KeyID fingerprints the modulus so ciphertexts under different keys are never mixed.
+func (pk *PublicKey) KeyID() uint64 {
+	sum := sha256.Sum256(pk.N.Bytes())
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// ciphertextBytes is the fixed serialized width of one ciphertext.
+func (pk *PublicKey) ciphertextBytes() int {
+	return (pk.NSquared.BitLen() + 7) / 8
+}
+
+// NewPublicKey rebuilds a public key distributed out of band.
+func NewPublicKey(n *big.Int, threshold, totalShares int) (*PublicKey, error) {
+	if n == nil || n.BitLen() < MinModulusBits {
+		return nil, fmt.Errorf("modulus must be at least %d bits", MinModulusBits)
+	}
+	if threshold <= 0 || threshold > totalShares {
+		return nil, fmt.Errorf("threshold %d outside [1,%d]", threshold, totalShares)
+	}
+	delta := big.NewInt(1)
+	for i := 2; i <= totalShares; i++ {
+		delta.Mul(delta, big.NewInt(int64(i)))
+	}
+	return &PublicKey{
+		N:           new(big.Int).Set(n),
+		NSquared:    new(big.Int).Mul(n, n),
+		Threshold:   threshold,
+		TotalShares: totalShares,
+		delta:       delta,
+	}, nil
+}
+
+// GenerateThresholdKey runs a trusted-dealer ceremony for threshold Paillier
+// (Shoup / Damgard-Jurik style). The decryption exponent d satisfies
+// d = 0 mod lambda(N) and d = 1 mod N and is Shamir-shared over Z_{N*lambda}
+// with a degree threshold-1 polynomial. Holders receive one share per unit of
+// weight; the dealer's copy of the factorisation is discarded on return.
+func GenerateThresholdKey(bits int, holders []KeyShare, threshold int) (*PublicKey, []KeyShare, error) {
+	if bits < MinModulusBits {
+		return nil, nil, fmt.Errorf("modulus must be at least %d bits", MinModulusBits)
+	}
+	if err := ValidateShares(holders, threshold); err != nil {
+		return nil, nil, err
+	}
+	total := 0
+	for _, h := range holders {
+		total += h.Weight
+	}
+	if threshold > total {
+		return nil, nil, fmt.Errorf("threshold %d exceeds total share weight %d", threshold, total)
+	}
+
+	var n, lambda *big.Int
+	for {
+		p, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate prime: %w", err)
+		}
+		q, err := rand.Prime(rand.Reader, bits-bits/2)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate prime: %w", err)
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		n = new(big.Int).Mul(p, q)
+		pm1 := new(big.Int).Sub(p, one)
+		qm1 := new(big.Int).Sub(q, one)
+		phi := new(big.Int).Mul(pm1, qm1)
+		if new(big.Int).GCD(nil, nil, n, phi).Cmp(one) != 0 {
+			continue
+		}
+		gcd := new(big.Int).GCD(nil, nil, pm1, qm1)
+		lambda = phi.Div(phi, gcd)
+		break
+	}
+
+	pk, err := NewPublicKey(n, threshold, total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// d = lambda * (lambda^-1 mod N)
+	d := new(big.Int).ModInverse(lambda, n)
+	d.Mul(d, lambda)
+
+	modulus := new(big.Int).Mul(n, lambda)
+	coeffs := make([]*big.Int, threshold)
+	coeffs[0] = d
+	for i := 1; i < threshold; i++ {
+		c, err := rand.Int(rand.Reader, modulus)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sample polynomial: %w", err)
+		}
+		coeffs[i] = c
+	}
+
+	ordered := make([]KeyShare, len(holders))
+	copy(ordered, holders)
+	sort.Slice(ordered, func(i, j int) bool {
+		return strings.TrimSpace(ordered[i].NodeID) < strings.TrimSpace(ordered[j].NodeID)
+	})
+	index := 1
+	for i := range ordered {
+		ordered[i].NodeID = strings.TrimSpace(ordered[i].NodeID)
+		ordered[i].Secrets = make([]SecretShare, ordered[i].Weight)
+		for w := 0; w < ordered[i].Weight; w++ {
+			ordered[i].Secrets[w] = SecretShare{Index: index, Value: evalPoly(coeffs, index, modulus)}
+			index++
+		}
+	}
+	return pk, ordered, nil
+}
+
+// evalPoly evaluates coeffs at x modulo m using Horner's rule.
+func evalPoly(coeffs []*big.Int, x int, m *big.Int) *big.Int {
+	bx := big.NewInt(int64(x))
+	acc := new(big.Int)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		acc.Mul(acc, bx)
+		acc.Add(acc, coeffs[i])
+		acc.Mod(acc, m)
+	}
+	return acc
 }
 
 func ValidateShares(shares []KeyShare, threshold int) error {
@@ -27,6 +183,9 @@ func ValidateShares(shares []KeyShare, threshold int) error {
 		if s.Weight <= 0 {
 			return fmt.Errorf("share weight must be positive")
 		}
+		if len(s.Secrets) != 0 && len(s.Secrets) != s.Weight {
+			return fmt.Errorf("share for node %q carries %d secrets for weight %d", node, len(s.Secrets), s.Weight)
+		}
 		if _, ok := seen[node]; ok {
 			return fmt.Errorf("duplicate share for node %q", node)
 		}
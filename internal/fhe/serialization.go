@@ -1,13 +1,86 @@
 package fhe
 
-import "encoding/json"
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Serialized update layout (big-endian):
+//
+//	magic "MFHE" | version u8 | contributor_len u16 | contributor |
+//	key_id u64 | scale f64 | count u32 | dim u32 | width u16 | dim*width ciphertext bytes
+const (
+	updateMagic     = "MFHE"
+	UpdateVersion1  = 1
+	updateHeaderLen = 4 + 1 + 2
+)
 
 func MarshalUpdate(update EncryptedUpdate) ([]byte, error) {
-	return json.Marshal(update)
+	if len(update.Contributor) > math.MaxUint16 {
+		return nil, fmt.Errorf("contributor id too long")
+	}
+	if update.Count < 0 || update.Count > math.MaxUint32 {
+		return nil, fmt.Errorf("contribution count out of range")
+	}
+	width := 0
+	for i, c := range update.Ciphertexts {
+		if c == nil || c.Sign() < 0 {
+			return nil, fmt.Errorf("ciphertext %d is invalid", i)
+		}
+		if n := (c.BitLen() + 7) / 8; n > width {
+			width = n
+		}
+	}
+	if width > math.MaxUint16 {
+		return nil, fmt.Errorf("ciphertext width %d too large", width)
+	}
+
+	out := make([]byte, 0, updateHeaderLen+len(update.Contributor)+26+len(update.Ciphertexts)*width)
+	out = append(out, updateMagic...)
+	out = append(out, UpdateVersion1)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(update.Contributor)))
+	out = append(out, update.Contributor...)
+	out = binary.BigEndian.AppendUint64(out, update.KeyID)
+	out = binary.BigEndian.AppendUint64(out, math.Float64bits(update.Scale))
+	out = binary.BigEndian.AppendUint32(out, uint32(update.Count))
+	out = binary.BigEndian.AppendUint32(out, uint32(len(update.Ciphertexts)))
+	out = binary.BigEndian.AppendUint16(out, uint16(width))
+	for _, c := range update.Ciphertexts {
+		buf := make([]byte, width)
+		out = append(out, c.FillBytes(buf)...)
+	}
+	return out, nil
 }
 
 func UnmarshalUpdate(raw []byte) (EncryptedUpdate, error) {
 	var out EncryptedUpdate
-	err := json.Unmarshal(raw, &out)
-	return out, err
+	if len(raw) < updateHeaderLen || string(raw[:4]) != updateMagic {
+		return out, fmt.Errorf("not an encrypted update")
+	}
+	if raw[4] != UpdateVersion1 {
+		return out, fmt.Errorf("unsupported encrypted update version %d", raw[4])
+	}
+	clen := int(binary.BigEndian.Uint16(raw[5:7]))
+	rest := raw[updateHeaderLen:]
+	if len(rest) < clen+26 {
+		return out, fmt.Errorf("truncated encrypted update")
+	}
+	out.Contributor = string(rest[:clen])
+	rest = rest[clen:]
+	out.KeyID = binary.BigEndian.Uint64(rest[0:8])
+	out.Scale = math.Float64frombits(binary.BigEndian.Uint64(rest[8:16]))
+	out.Count = int(binary.BigEndian.Uint32(rest[16:20]))
+	dim := int(binary.BigEndian.Uint32(rest[20:24]))
+	width := int(binary.BigEndian.Uint16(rest[24:26]))
+	rest = rest[26:]
+	if uint64(len(rest)) != uint64(dim)*uint64(width) {
+		return EncryptedUpdate{}, fmt.Errorf("ciphertext payload length mismatch")
+	}
+	out.Ciphertexts = make([]*big.Int, dim)
+	for i := range out.Ciphertexts {
+		out.Ciphertexts[i] = new(big.Int).SetBytes(rest[i*width : (i+1)*width])
+	}
+	return out, nil
 }
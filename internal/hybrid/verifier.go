@@ -3,6 +3,7 @@ package hybrid
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -39,10 +40,12 @@ const (
 
 // VerifyRequest defines a hybrid proof verification operation.
 type VerifyRequest struct {
-	Mode         HybridMode `json:"mode"`
-	SNARKProof   []byte     `json:"snark_proof"`
-	STARKProof   []byte     `json:"stark_proof"`
-	STARKBackend string     `json:"stark_backend"`
+	Mode              HybridMode `json:"mode"`
+	SNARKCircuitID    string     `json:"snark_circuit_id"`
+	SNARKPublicInputs []byte     `json:"snark_public_inputs"`
+	SNARKProof        []byte     `json:"snark_proof"`
+	STARKProof        []byte     `json:"stark_proof"`
+	STARKBackend      string     `json:"stark_backend"`
 }
 
 // VerifyResult reports per-scheme status and final policy decision.
@@ -55,9 +58,15 @@ type VerifyResult struct {
 	STARKBackend string `json:"stark_backend"`
 }
 
+// SNARKStatement names the circuit and public inputs a SNARK proof must satisfy.
+type SNARKStatement struct {
+	CircuitID    string
+	PublicInputs []byte
+}
+
 // SNARKVerifier abstracts zk-SNARK verification backend.
 type SNARKVerifier interface {
-	Verify(proof []byte) (bool, error)
+	Verify(statement SNARKStatement, proof []byte) (bool, error)
 }
 
 // STARKVerifier abstracts zk-STARK verification backend.
@@ -69,7 +78,7 @@ type STARKVerifier interface {
 // SNARKAccelerator provides an optional fast-path verifier for SNARK proofs.
 type SNARKAccelerator interface {
 	BackendName() string
-	Verify(ctx context.Context, proof []byte) (bool, error)
+	Verify(ctx context.Context, statement SNARKStatement, proof []byte) (bool, error)
 }
 
 var (
@@ -160,7 +169,10 @@ func VerifyHybrid(req VerifyRequest) (VerifyResult, error) {
 	if err != nil {
 		return VerifyResult{}, err
 	}
-	snarkOK, snarkBackend, snarkErr := verifySNARKWithAcceleration(req.SNARKProof)
+	snarkOK, snarkBackend, snarkErr := verifySNARKWithAcceleration(SNARKStatement{
+		CircuitID:    req.SNARKCircuitID,
+		PublicInputs: req.SNARKPublicInputs,
+	}, req.SNARKProof)
 	starkOK, starkErr := starkVerifier.Verify(req.STARKProof)
 
 	result := VerifyResult{
@@ -192,7 +204,7 @@ func VerifyHybrid(req VerifyRequest) (VerifyResult, error) {
 	return result, nil
 }
 
-func verifySNARKWithAcceleration(proof []byte) (bool, string, error) {
+func verifySNARKWithAcceleration(statement SNARKStatement, proof []byte) (bool, string, error) {
 	accel := currentSNARKAccelerator()
 	if accel != nil {
 		timeout := 2 * time.Second
@@ -203,27 +215,30 @@ func verifySNARKWithAcceleration(proof []byte) (bool, string, error) {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		ok, err := accel.Verify(ctx, proof)
+		ok, err := accel.Verify(ctx, statement, proof)
 		if err == nil {
 			return ok, accel.BackendName(), nil
 		}
-		cpuOK, cpuErr := defaultSNARKBridge.Verify(proof)
+		cpuOK, cpuErr := defaultSNARKBridge.Verify(statement, proof)
 		return cpuOK, "cpu_fallback", errors.Join(fmt.Errorf("snark accelerator %s failed: %w", accel.BackendName(), err), cpuErr)
 	}
-	ok, err := defaultSNARKBridge.Verify(proof)
+	ok, err := defaultSNARKBridge.Verify(statement, proof)
 	return ok, "cpu", err
 }
 
 type snarkVerifier struct{}
 
-func (snarkVerifier) Verify(proof []byte) (bool, error) {
+func (snarkVerifier) Verify(statement SNARKStatement, proof []byte) (bool, error) {
 	if len(proof) == 0 {
 		return false, fmt.Errorf("snark proof missing")
 	}
+	if strings.TrimSpace(statement.CircuitID) == "" {
+		return false, fmt.Errorf("snark circuit id missing")
+	}
 	if len(proof) < 128 {
 		proof = append(proof, make([]byte, 128-len(proof))...)
 	}
-	ok, err := internalpkg.VerifyProof(proof, nil)
+	ok, err := internalpkg.VerifyProof(statement.CircuitID, proof, statement.PublicInputs)
 	if err != nil {
 		return false, fmt.Errorf("snark verify failed: %w", err)
 	}
@@ -410,7 +425,9 @@ type externalSNARKAccelerator struct {
 
 func (externalSNARKAccelerator) BackendName() string { return "external_accelerator" }
 
-func (v externalSNARKAccelerator) Verify(ctx context.Context, proof []byte) (bool, error) {
+// Verify pipes the proof to the command on stdin; the statement is passed as
+// MOHAWK_SNARK_CIRCUIT_ID and hex-encoded MOHAWK_SNARK_PUBLIC_INPUTS.
+func (v externalSNARKAccelerator) Verify(ctx context.Context, statement SNARKStatement, proof []byte) (bool, error) {
 	if len(proof) == 0 {
 		return false, fmt.Errorf("accelerated snark proof missing")
 	}
@@ -423,6 +440,10 @@ func (v externalSNARKAccelerator) Verify(ctx context.Context, proof []byte) (boo
 	}
 	cmd := exec.CommandContext(ctx, bin, args...)
 	cmd.Stdin = strings.NewReader(string(proof))
+	cmd.Env = append(os.Environ(),
+		"MOHAWK_SNARK_CIRCUIT_ID="+statement.CircuitID,
+		"MOHAWK_SNARK_PUBLIC_INPUTS="+hex.EncodeToString(statement.PublicInputs),
+	)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return false, fmt.Errorf("accelerated snark backend failed: %w (%s)", err, strings.TrimSpace(string(out)))
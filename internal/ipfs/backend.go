@@ -13,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ipfs/go-cid"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
 )
 
@@ -88,6 +90,8 @@ func (b *Backend) PutCheckpoint(ctx context.Context, name string, payload []byte
 	return addResp.Hash, nil
 }
 
+// GetCheckpoint returns the UnixFS file at cid as served by the node, without
+// verifying it; use checkpoint.NewStore(b) for verified reads.
 func (b *Backend) GetCheckpoint(ctx context.Context, cid string) ([]byte, error) {
 	if !b.Enabled() {
 		return nil, fmt.Errorf("ipfs backend is not configured")
@@ -121,3 +125,90 @@ func (b *Backend) GetCheckpoint(ctx context.Context, cid string) ([]byte, error)
 	metrics.ObserveIPFSOperation("get", true)
 	return payload, nil
 }
+
+// blockPutResponse is the /api/v0/block/put reply
+type blockPutResponse struct {
+	Key  string `json:"Key"`
+	Size int    `json:"Size"`
+}
+
+// PutBlock stores data as a raw, pinned block and checks that the node
+// addressed it as c, making Backend a checkpoint.BlockStore.
+func (b *Backend) PutBlock(ctx context.Context, c cid.Cid, data []byte) error {
+	if !b.Enabled() {
+		return fmt.Errorf("ipfs backend is not configured")
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("data", c.String())
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/api/v0/block/put?cid-codec=raw&mhtype=sha2-256&pin=true", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := b.client.Do(req)
+	if err != nil {
+		metrics.ObserveIPFSOperation("put_block", false)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		metrics.ObserveIPFSOperation("put_block", false)
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("ipfs block put failed: %s", strings.TrimSpace(string(bodyBytes)))
+	}
+	var putResp blockPutResponse
+	if err := json.NewDecoder(resp.Body).Decode(&putResp); err != nil {
+		metrics.ObserveIPFSOperation("put_block", false)
+		return err
+	}
+	if putResp.Key != c.String() {
+		metrics.ObserveIPFSOperation("put_block", false)
+		return fmt.Errorf("ipfs stored block as %s, expected %s", putResp.Key, c)
+	}
+	metrics.ObserveIPFSOperation("put_block", true)
+	return nil
+}
+
+// GetBlock fetches the raw block c. The caller verifies it.
+func (b *Backend) GetBlock(ctx context.Context, c cid.Cid) ([]byte, error) {
+	if !b.Enabled() {
+		return nil, fmt.Errorf("ipfs backend is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/api/v0/block/get?arg="+url.QueryEscape(c.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		metrics.ObserveIPFSOperation("get_block", false)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		metrics.ObserveIPFSOperation("get_block", false)
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("ipfs block get failed: %s", strings.TrimSpace(string(bodyBytes)))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, checkpoint.MaxBlockSize+1))
+	if err != nil {
+		metrics.ObserveIPFSOperation("get_block", false)
+		return nil, err
+	}
+	if len(data) > checkpoint.MaxBlockSize {
+		metrics.ObserveIPFSOperation("get_block", false)
+		return nil, fmt.Errorf("block %s: %w", c, checkpoint.ErrTooLarge)
+	}
+	metrics.ObserveIPFSOperation("get_block", true)
+	return data, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SigningKey is one orchestrator public key and the window in which
+// manifests signed by it are accepted.
+type SigningKey struct {
+	KeyID     string    `json:"key_id"`
+	PublicKey []byte    `json:"public_key"`
+	NotBefore time.Time `json:"not_before"`
+	// NotAfter is zero while the key is current; rotation sets it to the end
+	// of the overlap window.
+	NotAfter time.Time `json:"not_after,omitzero"`
+}
+
+// ValidAt reports whether the key's window contains at.
+func (k SigningKey) ValidAt(at time.Time) bool {
+	if at.Before(k.NotBefore) {
+		return false
+	}
+	return k.NotAfter.IsZero() || !at.After(k.NotAfter)
+}
+
+// KeySet is the published set of orchestrator keys, current and previous,
+// served by /orchestrator/keys.
+type KeySet struct {
+	Keys []SigningKey `json:"keys"`
+}
+
+// KeyIDFor derives the key ID of pub: the first 8 bytes of its SHA-256.
+func KeyIDFor(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParseKeySet decodes a JSON key set.
+func ParseKeySet(raw []byte) (*KeySet, error) {
+	var ks KeySet
+	if err := json.Unmarshal(raw, &ks); err != nil {
+		return nil, fmt.Errorf("decode key set: %w", err)
+	}
+	return &ks, nil
+}
+
+// Key returns the public key named keyID if its window contains at.
+func (ks *KeySet) Key(keyID string, at time.Time) (ed25519.PublicKey, error) {
+	for _, k := range ks.Keys {
+		if k.KeyID != keyID {
+			continue
+		}
+		if len(k.PublicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s is not an ed25519 key", keyID)
+		}
+		if !k.ValidAt(at) {
+			return nil, fmt.Errorf("key %s is not valid at %s", keyID, at.UTC().Format(time.RFC3339))
+		}
+		return ed25519.PublicKey(k.PublicKey), nil
+	}
+	return nil, fmt.Errorf("key %s not in key set", keyID)
+}
+
+// Verify checks m's signature against the key named by m.KeyID, which must
+// be valid at at.
+func (ks *KeySet) Verify(m *Manifest, at time.Time) error {
+	if m.KeyID == "" {
+		return errors.New("manifest has no key_id")
+	}
+	pk, err := ks.Key(m.KeyID, at)
+	if err != nil {
+		return err
+	}
+	return verifyWithKey(m, pk)
+}
+
+// isKeySet reports whether raw holds a JSON key set rather than a key.
+func isKeySet(raw []byte) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// storedKey is one keystore entry: the public window plus the private seed,
+// which is dropped once the key's window has closed
+type storedKey struct {
+	SigningKey
+	Seed []byte `json:"seed"`
+}
+
+// Keystore holds the orchestrator's manifest signing keys: one current key
+// and the previous keys still inside their overlap window. Retired keys keep
+// only their public half, so records they signed can still be verified
+// through History. With a path, every
+// change is persisted to that file (mode 0600) so the identity survives
+// restarts.
+type Keystore struct {
+	mu   sync.RWMutex
+	path string
+	keys []storedKey // keys[len-1] is current
+	now  func() time.Time
+}
+
+// NewKeystore returns an in-memory keystore whose current key is priv.
+func NewKeystore(priv ed25519.PrivateKey) *Keystore {
+	k := &Keystore{now: time.Now}
+	k.keys = []storedKey{k.newEntry(priv)}
+	return k
+}
+
+// OpenKeystore loads the keystore file at path, creating it with a fresh key
+// if it does not exist.
+func OpenKeystore(path string) (*Keystore, error) {
+	k := &Keystore{path: path, now: time.Now}
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate signing key: %w", err)
+		}
+		k.keys = []storedKey{k.newEntry(priv)}
+		if err := k.persistLocked(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	case err != nil:
+		return nil, fmt.Errorf("read keystore %q: %w", path, err)
+	}
+
+	if err := json.Unmarshal(raw, &k.keys); err != nil {
+		return nil, fmt.Errorf("decode keystore %q: %w", path, err)
+	}
+	if len(k.keys) == 0 {
+		return nil, fmt.Errorf("keystore %q holds no keys", path)
+	}
+	for i, key := range k.keys {
+		retired := len(key.Seed) == 0 && i < len(k.keys)-1
+		if len(key.Seed) != ed25519.SeedSize && !retired {
+			return nil, fmt.Errorf("keystore %q: key %s has a malformed seed", path, key.KeyID)
+		}
+	}
+	return k, nil
+}
+
+// LoadPrivateKeyFile reads a PKCS#8 PEM Ed25519 private key, as written by
+// `openssl genpkey -algorithm ed25519`.
+func LoadPrivateKeyFile(path string) (ed25519.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read signing key %q: %w", path, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("decode signing key pem %q", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key %q: %w", path, err)
+	}
+	priv, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not ed25519", path)
+	}
+	return priv, nil
+}
+
+func (k *Keystore) newEntry(priv ed25519.PrivateKey) storedKey {
+	pub := priv.Public().(ed25519.PublicKey)
+	return storedKey{
+		SigningKey: SigningKey{
+			KeyID:     KeyIDFor(pub),
+			PublicKey: pub,
+			NotBefore: k.now().UTC(),
+		},
+		Seed: priv.Seed(),
+	}
+}
+
+// Current returns the public half of the key that signs new manifests.
+func (k *Keystore) Current() SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.keys[len(k.keys)-1].SigningKey
+}
+
+// KeySet returns the keys that are not yet expired, oldest first.
+func (k *Keystore) KeySet() KeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.now()
+	ks := KeySet{Keys: make([]SigningKey, 0, len(k.keys))}
+	for _, key := range k.keys {
+		if key.NotAfter.IsZero() || !now.After(key.NotAfter) {
+			ks.Keys = append(ks.Keys, key.SigningKey)
+		}
+	}
+	return ks
+}
+
+// History returns every key the keystore has held, retired ones included,
+// oldest first. Long-lived records such as model versions verify against it;
+// manifests use KeySet.
+func (k *Keystore) History() KeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ks := KeySet{Keys: make([]SigningKey, 0, len(k.keys))}
+	for _, key := range k.keys {
+		ks.Keys = append(ks.Keys, key.SigningKey)
+	}
+	return ks
+}
+
+// Sign stamps m with the current key ID and signs it.
+func (k *Keystore) Sign(m *Manifest) error {
+	k.mu.RLock()
+	current := k.keys[len(k.keys)-1]
+	k.mu.RUnlock()
+
+	m.KeyID = current.KeyID
+	data, err := SigningBytes(m)
+	if err != nil {
+		return err
+	}
+	m.Signature = ed25519.Sign(ed25519.NewKeyFromSeed(current.Seed), data)
+	return nil
+}
+
+// SignPayload signs orchestrator records other than manifests. encode
+// receives the current key ID, so the record can carry it under the
+// signature; SignPayload returns that key ID and the signature.
+func (k *Keystore) SignPayload(encode func(keyID string) ([]byte, error)) (string, []byte, error) {
+	k.mu.RLock()
+	current := k.keys[len(k.keys)-1]
+	k.mu.RUnlock()
+
+	data, err := encode(current.KeyID)
+	if err != nil {
+		return "", nil, err
+	}
+	return current.KeyID, ed25519.Sign(ed25519.NewKeyFromSeed(current.Seed), data), nil
+}
+
+// Rotate makes a fresh key current. The previous key stays valid for
+// overlap, so manifests it signed keep verifying while nodes pick up the new
+// key set; keys whose window has closed are retired, keeping only their
+// public half.
+func (k *Keystore) Rotate(overlap time.Duration) (SigningKey, error) {
+	if overlap < 0 {
+		return SigningKey{}, fmt.Errorf("overlap %s must not be negative", overlap)
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("generate signing key: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now().UTC()
+	kept := make([]storedKey, 0, len(k.keys)+1)
+	for _, key := range k.keys {
+		if key.NotAfter.IsZero() {
+			key.NotAfter = now.Add(overlap)
+		}
+		if now.After(key.NotAfter) {
+			key.Seed = nil
+		}
+		kept = append(kept, key)
+	}
+	entry := k.newEntry(priv)
+	previous := k.keys
+	k.keys = append(kept, entry)
+	if err := k.persistLocked(); err != nil {
+		k.keys = previous
+		return SigningKey{}, err
+	}
+	return entry.SigningKey, nil
+}
+
+func (k *Keystore) persistLocked() error {
+	if k.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return fmt.Errorf("create keystore directory: %w", err)
+	}
+	raw, err := json.MarshalIndent(k.keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode keystore: %w", err)
+	}
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("write keystore temp file: %w", err)
+	}
+	if err := os.Rename(tmp, k.path); err != nil {
+		return fmt.Errorf("commit keystore file: %w", err)
+	}
+	return nil
+}
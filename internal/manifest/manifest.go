@@ -17,12 +17,14 @@
 package manifest
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/hva"
 )
@@ -34,9 +36,23 @@ const (
 	CapSubmitGrad Capability = "SUBMIT_GRADIENTS"
 )
 
+// Manifest schema versions. A v1 manifest is signed over json.Marshal of the
+// struct and never expires; v2 is signed over its RFC 8785 canonical JSON and
+// carries an issue time, an expiry and a nonce that make it single-use.
+const (
+	SchemaV1             = 1
+	SchemaV2             = 2
+	CurrentSchemaVersion = SchemaV2
+)
+
+// DefaultClockSkew is the tolerance applied to issued_at and expires_at.
+const DefaultClockSkew = 30 * time.Second
+
 // Manifest defines the execution parameters for a node.
 // Reference: /proofs/communication.md
 type Manifest struct {
+	// SchemaVersion selects the signed encoding; 0 is read as v1.
+	SchemaVersion    int          `json:"schema_version,omitempty"`
 	TaskID           string       `json:"task_id"`
 	NodeID           string       `json:"node_id"`
 	WasmModuleSHA256 string       `json:"wasm_module_sha256"`
@@ -47,37 +63,175 @@ type Manifest struct {
 	MaxGradNorm float64 `json:"max_grad_norm"`
 	Epsilon     float64 `json:"epsilon"`
 	Delta       float64 `json:"delta"`
-	Signature   []byte  `json:"signature"`
-	PayloadSize int     `json:"-"` // Internal tracking for Theorem 3
+	// KeyID names the orchestrator key that signed the manifest; it is
+	// covered by the signature.
+	KeyID string `json:"key_id,omitempty"`
+	// v2 freshness and binding: the manifest is valid from IssuedAt to
+	// ExpiresAt, for one use of Nonce by NodeID, in round RoundID of the
+	// model at ModelCID. ModelVersion names that model in the orchestrator's
+	// registry, whose lineage (kind, parent_cid) says whether ModelCID is a
+	// full model or a delta over its parent.
+	IssuedAt     time.Time `json:"issued_at,omitzero"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	RoundID      uint64    `json:"round_id,omitempty"`
+	ModelCID     string    `json:"model_cid,omitempty"`
+	ModelVersion uint64    `json:"model_version,omitempty"`
+	Nonce        string    `json:"nonce,omitempty"`
+	Signature    []byte    `json:"signature"`
+	PayloadSize  int       `json:"-"` // Internal tracking for Theorem 3
+}
+
+// Version returns the manifest's schema version, reading 0 as v1.
+func (m *Manifest) Version() int {
+	if m.SchemaVersion == 0 {
+		return SchemaV1
+	}
+	return m.SchemaVersion
+}
+
+// VerifyOptions tunes Verify beyond the signature check.
+type VerifyOptions struct {
+	// Now is the verification time (default time.Now()).
+	Now time.Time
+	// ClockSkew tolerates clock drift on issued_at and expires_at
+	// (default DefaultClockSkew).
+	ClockSkew time.Duration
+	// Replay, when set, accepts each v2 nonce once.
+	Replay *ReplayGuard
+	// RequireV2 refuses v1 manifests, which can be replayed indefinitely.
+	RequireV2 bool
+}
+
+// Verify checks m's signature against orchestratorPub (see VerifySignature),
+// then that a v2 manifest is complete and inside its validity window, and
+// finally records its nonce with opts.Replay.
+func Verify(m *Manifest, orchestratorPub []byte, opts VerifyOptions) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.ClockSkew <= 0 {
+		opts.ClockSkew = DefaultClockSkew
+	}
+	switch m.Version() {
+	case SchemaV1:
+		if opts.RequireV2 {
+			return errors.New("manifest schema v1 is not accepted")
+		}
+	case SchemaV2:
+	default:
+		return fmt.Errorf("unsupported manifest schema version %d", m.SchemaVersion)
+	}
+
+	if err := verifySignatureAt(m, orchestratorPub, opts.Now); err != nil {
+		return err
+	}
+	if m.Version() == SchemaV1 {
+		return nil
+	}
+	if err := m.checkFreshness(opts.Now, opts.ClockSkew); err != nil {
+		return err
+	}
+	if opts.Replay != nil {
+		return opts.Replay.Use(m, opts.ClockSkew)
+	}
+	return nil
+}
+
+// checkFreshness enforces a v2 manifest's required fields and window
+func (m *Manifest) checkFreshness(now time.Time, skew time.Duration) error {
+	if m.IssuedAt.IsZero() || m.ExpiresAt.IsZero() || m.Nonce == "" {
+		return errors.New("manifest v2 requires issued_at, expires_at and nonce")
+	}
+	if !m.ExpiresAt.After(m.IssuedAt) {
+		return errors.New("manifest expires before it is issued")
+	}
+	if now.Add(skew).Before(m.IssuedAt) {
+		return fmt.Errorf("manifest issued in the future at %s", m.IssuedAt.UTC().Format(time.RFC3339))
+	}
+	if now.Add(-skew).After(m.ExpiresAt) {
+		return fmt.Errorf("manifest expired at %s", m.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return nil
 }
 
 // VerifySignature validates the manifest authenticity via Ed25519.
+// orchestratorPub is a PKIX DER key, the raw 32-byte key served by
+// /orchestrator/pubkey, or the JSON key set served by /orchestrator/keys.
+// A key set verifies with the key named by m.KeyID if it is valid now; a
+// single key must match m.KeyID when the manifest carries one. v2 manifests
+// must also be inside their validity window; Verify adds replay protection.
 func VerifySignature(m *Manifest, orchestratorPub []byte) error {
-	sig := m.Signature
-	m.Signature = nil
-	defer func() { m.Signature = sig }()
+	return Verify(m, orchestratorPub, VerifyOptions{})
+}
+
+func verifySignatureAt(m *Manifest, orchestratorPub []byte, at time.Time) error {
+	if isKeySet(orchestratorPub) {
+		ks, err := ParseKeySet(orchestratorPub)
+		if err != nil {
+			return err
+		}
+		return ks.Verify(m, at)
+	}
 
-	data, err := json.Marshal(m)
+	pk, err := parseOrchestratorKey(orchestratorPub)
 	if err != nil {
 		return err
 	}
+	if m.KeyID != "" && m.KeyID != KeyIDFor(pk) {
+		return fmt.Errorf("manifest signed by key %s, not %s", m.KeyID, KeyIDFor(pk))
+	}
+	return verifyWithKey(m, pk)
+}
 
-	pub, err := x509.ParsePKIXPublicKey(orchestratorPub)
+// SigningBytes returns the encoding of m covered by its signature: the
+// struct's JSON for v1, and canonical JSON without the signature for v2.
+func SigningBytes(m *Manifest) ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	raw, err := json.Marshal(&unsigned)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if m.Version() == SchemaV1 {
+		return raw, nil
 	}
 
-	pk, ok := pub.(ed25519.PublicKey)
-	if !ok {
-		return errors.New("not ed25519 key")
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
 	}
+	delete(fields, "signature")
+	return CanonicalJSON(fields)
+}
 
-	if !ed25519.Verify(pk, data, sig) {
+func verifyWithKey(m *Manifest, pk ed25519.PublicKey) error {
+	data, err := SigningBytes(m)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pk, data, m.Signature) {
 		return errors.New("invalid manifest signature")
 	}
 	return nil
 }
 
+func parseOrchestratorKey(raw []byte) (ed25519.PublicKey, error) {
+	if len(raw) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(raw), nil
+	}
+	pub, err := x509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("not ed25519 key")
+	}
+	return pk, nil
+}
+
 // ValidateCommunicationComplexity enforces Theorem 3.
 // Reference: /proofs/communication.md
 func (m *Manifest) ValidateCommunicationComplexity(d int, n int) error {
@@ -0,0 +1,63 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrManifestReplayed reports a v2 manifest whose nonce was already used.
+var ErrManifestReplayed = errors.New("manifest nonce already used")
+
+// ReplayGuard remembers the nonces of accepted v2 manifests until they
+// expire, so each manifest is accepted once per node.
+type ReplayGuard struct {
+	mu   sync.Mutex
+	seen map[string]time.Time // node_id/nonce -> forget after
+	now  func() time.Time
+}
+
+// NewReplayGuard returns an empty replay guard.
+func NewReplayGuard() *ReplayGuard {
+	return &ReplayGuard{seen: make(map[string]time.Time), now: time.Now}
+}
+
+// Use records m's nonce, failing with ErrManifestReplayed if it was already
+// recorded. Entries are kept until skew past the manifest's expiry, after
+// which Verify refuses the manifest anyway.
+func (g *ReplayGuard) Use(m *Manifest, skew time.Duration) error {
+	if m.Nonce == "" {
+		return errors.New("manifest has no nonce")
+	}
+	key := m.NodeID + "/" + m.Nonce
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for k, forgetAt := range g.seen {
+		if now.After(forgetAt) {
+			delete(g.seen, k)
+		}
+	}
+	if _, used := g.seen[key]; used {
+		return fmt.Errorf("task %s: %w", m.TaskID, ErrManifestReplayed)
+	}
+	g.seen[key] = m.ExpiresAt.Add(skew)
+	return nil
+}
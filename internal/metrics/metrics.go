@@ -252,6 +252,33 @@ var (
 		[]string{"setting"},
 	)
 
+	// thinkerDecisionsTotal counts per-update thinker-clause governance decisions.
+	thinkerDecisionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_thinker_decisions_total",
+			Help: "Per-update thinker-clause governance decisions by outcome.",
+		},
+		[]string{"decision"},
+	)
+
+	// privacyBudgetRemaining tracks remaining epsilon per privacy-budget scope.
+	privacyBudgetRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mohawk_privacy_budget_remaining",
+			Help: "Remaining (epsilon, delta)-DP budget by scope (global, shard, tier) and id.",
+		},
+		[]string{"scope", "id"},
+	)
+
+	// streamingChunkRejectionsTotal counts gradient chunks refused by the streaming aggregator.
+	streamingChunkRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_streaming_chunk_rejections_total",
+			Help: "Gradient chunks and tensors rejected by the streaming aggregator, by reason.",
+		},
+		[]string{"reason"},
+	)
+
 	// migrationRequestsTotal counts migration API requests by endpoint and result.
 	migrationRequestsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -448,6 +475,9 @@ func init() {
 		pqcPolicyModeInfo,
 		pqcPolicyEpochUnix,
 		thinkerClauseConfig,
+		thinkerDecisionsTotal,
+		privacyBudgetRemaining,
+		streamingChunkRejectionsTotal,
 		migrationRequestsTotal,
 		migrationRequestLatency,
 		migrationSignaturePathTotal,
@@ -673,6 +703,31 @@ func ObserveThinkerClauseValue(setting string, value float64) {
 	thinkerClauseConfig.WithLabelValues(setting).Set(value)
 }
 
+// ObserveThinkerDecisions adds count governance decisions of the given outcome
+// (rejected, review, retained).
+func ObserveThinkerDecisions(decision string, count int) {
+	if count <= 0 {
+		return
+	}
+	decision = sanitizeLabel(decision, "unknown")
+	thinkerDecisionsTotal.WithLabelValues(decision).Add(float64(count))
+}
+
+// ObservePrivacyBudgetRemaining sets the remaining epsilon for a budget scope
+// (global, shard or tier) and id.
+func ObservePrivacyBudgetRemaining(scope string, id string, remaining float64) {
+	scope = sanitizeLabel(scope, "unknown")
+	id = sanitizeLabel(id, "global")
+	privacyBudgetRemaining.WithLabelValues(scope, id).Set(remaining)
+}
+
+// ObserveStreamingChunkRejection records a chunk or tensor the streaming
+// aggregator refused.
+func ObserveStreamingChunkRejection(reason string) {
+	reason = sanitizeLabel(reason, "unknown")
+	streamingChunkRejectionsTotal.WithLabelValues(reason).Inc()
+}
+
 // ObserveMigrationRequest records migration endpoint request result and latency.
 func ObserveMigrationRequest(endpoint string, success bool, latencyMS float64) {
 	endpoint = sanitizeLabel(endpoint, "unknown")
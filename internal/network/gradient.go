@@ -15,9 +15,8 @@
 package network
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -41,25 +40,60 @@ type GradientMessage struct {
 	Round       int       `json:"round"`
 	Gradients   []float64 `json:"gradients"`
 	TimestampMS int64     `json:"timestamp_ms"`
+	// Nonce is the nonce of the signed manifest that issued TaskID. The
+	// orchestrator only accepts a node's gradient with a nonce it issued to
+	// that node, so peers cannot submit as one another.
+	Nonce string `json:"nonce,omitempty"`
 }
 
 // GradientAck is the aggregator's response to a gradient submission.
+// The KEX fields echo the parameters the aggregator actually negotiated.
 type GradientAck struct {
-	Accepted        bool   `json:"accepted"`
-	Reason          string `json:"reason,omitempty"`
-	NegotiatedKEX   string `json:"negotiated_kex,omitempty"`
-	KEXPublicKeyLen int    `json:"kex_public_key_len,omitempty"`
-	BatchAccepted   int    `json:"batch_accepted,omitempty"`
-	BatchRejected   int    `json:"batch_rejected,omitempty"`
+	Accepted          bool   `json:"accepted"`
+	Reason            string `json:"reason,omitempty"`
+	NegotiatedKEX     string `json:"negotiated_kex,omitempty"`
+	KEXPublicKeyLen   int    `json:"kex_public_key_len,omitempty"`
+	KEXCipherSuite    string `json:"kex_cipher_suite,omitempty"`
+	KEXTranscriptHash []byte `json:"kex_transcript_hash,omitempty"`
+	BatchAccepted     int    `json:"batch_accepted,omitempty"`
+	BatchRejected     int    `json:"batch_rejected,omitempty"`
 }
 
+// Stream exchange on /mohawk/gradient/1.0.0 (newline-delimited JSON):
+//
+//	initiator -> gradientEnvelope{kex_mode, kex_public_key}
+//	responder -> kexHello{accepted, kex_mode, kex_public_key, kem_ciphertext}
+//	initiator -> sealedEnvelope{nonce, ciphertext=AEAD(gradientPayload)}
+//	responder -> sealedEnvelope{nonce, ciphertext=AEAD(GradientAck)}
+//
+// Gradients never cross the stream in plaintext.
 type gradientEnvelope struct {
-	KEXMode      string            `json:"kex_mode,omitempty"`
-	KEXPublicKey []byte            `json:"kex_public_key,omitempty"`
-	Message      GradientMessage   `json:"message"`
-	Messages     []GradientMessage `json:"messages,omitempty"`
+	KEXMode      string `json:"kex_mode,omitempty"`
+	KEXPublicKey []byte `json:"kex_public_key,omitempty"`
+}
+
+type kexHello struct {
+	Accepted      bool   `json:"accepted"`
+	Reason        string `json:"reason,omitempty"`
+	KEXMode       string `json:"kex_mode,omitempty"`
+	KEXPublicKey  []byte `json:"kex_public_key,omitempty"`
+	KEMCiphertext []byte `json:"kem_ciphertext,omitempty"`
+}
+
+type sealedEnvelope struct {
+	Nonce      []byte `json:"nonce,omitempty"`
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+	Error      string `json:"error,omitempty"` // set in place of ciphertext when the envelope could not be opened
+}
+
+type gradientPayload struct {
+	Message  *GradientMessage  `json:"message,omitempty"`
+	Messages []GradientMessage `json:"messages,omitempty"`
 }
 
+// maxGradientStreamBytes bounds what a single gradient stream may send.
+const maxGradientStreamBytes = 64 << 20
+
 // RegisterGradientHandler installs the /mohawk/gradient/1.0.0 stream handler on h.
 // onGradient is called for each inbound message; the returned *GradientAck is written back
 // to the stream. If onGradient returns nil, a default accepted=true ack is sent.
@@ -68,76 +102,89 @@ func RegisterGradientHandler(h corehost.Host, onGradient func(*GradientMessage)
 }
 
 // RegisterGradientHandlerWithKEX installs the gradient stream handler with explicit KEX mode checks.
+// Every stream runs the ephemeral handshake for expectedMode before any gradient is read.
 func RegisterGradientHandlerWithKEX(h corehost.Host, expectedMode KEXMode, onGradient func(*GradientMessage) *GradientAck) {
 	h.SetStreamHandler(GradientProtocol, func(s corenetwork.Stream) {
 		defer s.Close()
-		payload, err := io.ReadAll(bufio.NewReader(s))
-		if err != nil {
-			log.Printf("gradient: failed to read stream payload: %v", err)
-			resetStreamWithLog(s, "read_payload")
+		dec := json.NewDecoder(io.LimitReader(s, maxGradientStreamBytes))
+
+		var env gradientEnvelope
+		if err := dec.Decode(&env); err != nil {
+			log.Printf("gradient: failed to read kex hello: %v", err)
+			resetStreamWithLog(s, "read_hello")
+			return
+		}
+		if len(env.KEXPublicKey) == 0 {
+			writeJSONWithLog(s, &kexHello{Accepted: false, Reason: "kex handshake required before gradient submission"}, "missing_kex")
+			return
+		}
+		mode := ParseKEXMode(env.KEXMode)
+		if mode == "" {
+			writeJSONWithLog(s, &kexHello{Accepted: false, Reason: fmt.Sprintf("unsupported kex mode %q", env.KEXMode)}, "unsupported_kex")
+			return
+		}
+		if expectedMode != "" && mode != expectedMode {
+			writeJSONWithLog(s, &kexHello{Accepted: false, Reason: fmt.Sprintf("kex mismatch expected=%s got=%s", expectedMode, mode)}, "kex_mismatch")
 			return
 		}
 
-		var msg GradientMessage
-		ackMeta := &GradientAck{}
-
-		var env gradientEnvelope
-		if err := json.Unmarshal(payload, &env); err == nil && (env.Message.NodeID != "" || len(env.Messages) > 0) {
-			mode := ParseKEXMode(env.KEXMode)
-			if mode == "" {
-				writeAckWithLog(s, &GradientAck{Accepted: false, Reason: fmt.Sprintf("unsupported kex mode %q", env.KEXMode)}, "unsupported_kex")
-				return
-			}
-			if expectedMode != "" && mode != expectedMode {
-				writeAckWithLog(s, &GradientAck{Accepted: false, Reason: fmt.Sprintf("kex mismatch expected=%s got=%s", expectedMode, mode)}, "kex_mismatch")
-				return
-			}
-			expectedBytes := mode.ExpectedPublicKeyBytes()
-			if expectedBytes > 0 && len(env.KEXPublicKey) != expectedBytes {
-				writeAckWithLog(s, &GradientAck{Accepted: false, Reason: fmt.Sprintf("kex public key bytes mismatch expected=%d got=%d", expectedBytes, len(env.KEXPublicKey))}, "kex_key_mismatch")
-				return
-			}
-			ackMeta.NegotiatedKEX = string(mode)
-			ackMeta.KEXPublicKeyLen = len(env.KEXPublicKey)
-			if len(env.Messages) > 0 {
-				batchAck := &GradientAck{Accepted: true, NegotiatedKEX: ackMeta.NegotiatedKEX, KEXPublicKeyLen: ackMeta.KEXPublicKeyLen}
-				for i := range env.Messages {
-					ack := onGradient(&env.Messages[i])
-					if ack == nil {
-						batchAck.BatchAccepted++
-						continue
-					}
-					if ack.Accepted {
-						batchAck.BatchAccepted++
-					} else {
-						batchAck.BatchRejected++
-						batchAck.Accepted = false
-						if batchAck.Reason == "" {
-							batchAck.Reason = ack.Reason
-						}
-					}
-				}
-				writeAckWithLog(s, batchAck, "batch_ack")
-				return
-			}
-			msg = env.Message
-		} else if err := json.Unmarshal(payload, &msg); err != nil {
-			log.Printf("gradient: failed to decode payload: %v", err)
-			resetStreamWithLog(s, "decode_payload")
+		binding := kexBinding(s.Conn().RemotePeer(), s.Conn().LocalPeer())
+		resp, session, err := RespondKEX(mode, env.KEXPublicKey, binding)
+		if err != nil {
+			writeJSONWithLog(s, &kexHello{Accepted: false, Reason: err.Error()}, "kex_respond")
 			return
 		}
+		writeJSONWithLog(s, &kexHello{
+			Accepted:      true,
+			KEXMode:       string(mode),
+			KEXPublicKey:  resp.PublicKey,
+			KEMCiphertext: resp.KEMCiphertext,
+		}, "kex_hello")
 
-		ack := onGradient(&msg)
-		if ack == nil {
-			ack = &GradientAck{Accepted: true}
+		var sealed sealedEnvelope
+		if err := dec.Decode(&sealed); err != nil {
+			log.Printf("gradient: failed to read sealed envelope: %v", err)
+			resetStreamWithLog(s, "read_envelope")
+			return
 		}
-		if ack.NegotiatedKEX == "" {
-			ack.NegotiatedKEX = ackMeta.NegotiatedKEX
+		plaintext, err := session.Open(sealed.Nonce, sealed.Ciphertext)
+		if err != nil {
+			writeJSONWithLog(s, &sealedEnvelope{Error: err.Error()}, "open_envelope")
+			return
+		}
+		var payload gradientPayload
+		if err := json.Unmarshal(plaintext, &payload); err != nil || (payload.Message == nil && len(payload.Messages) == 0) {
+			writeSealedAck(s, session, &GradientAck{Accepted: false, Reason: "malformed gradient payload"})
+			return
 		}
-		if ack.KEXPublicKeyLen == 0 {
-			ack.KEXPublicKeyLen = ackMeta.KEXPublicKeyLen
+
+		var ack *GradientAck
+		if len(payload.Messages) > 0 {
+			ack = &GradientAck{Accepted: true}
+			for i := range payload.Messages {
+				msgAck := onGradient(&payload.Messages[i])
+				if msgAck == nil || msgAck.Accepted {
+					ack.BatchAccepted++
+					continue
+				}
+				ack.BatchRejected++
+				ack.Accepted = false
+				if ack.Reason == "" {
+					ack.Reason = msgAck.Reason
+				}
+			}
+		} else {
+			ack = onGradient(payload.Message)
+			if ack == nil {
+				ack = &GradientAck{Accepted: true}
+			}
 		}
-		writeAckWithLog(s, ack, "single_ack")
+
+		ack.NegotiatedKEX = string(mode)
+		ack.KEXPublicKeyLen = len(env.KEXPublicKey)
+		ack.KEXCipherSuite = KEXCipherSuite
+		ack.KEXTranscriptHash = session.TranscriptHash
+		writeSealedAck(s, session, ack)
 	})
 }
 
@@ -147,45 +194,10 @@ func SendGradient(ctx context.Context, h corehost.Host, peerID peer.ID, peerAddr
 	return SendGradientWithKEX(ctx, h, peerID, peerAddrs, msg, KEXModeX25519)
 }
 
-// SendGradientWithKEX connects to peerID and delivers msg with explicit KEX negotiation metadata.
+// SendGradientWithKEX connects to peerID and delivers msg encrypted under a fresh mode session.
 func SendGradientWithKEX(ctx context.Context, h corehost.Host, peerID peer.ID, peerAddrs []ma.Multiaddr, msg *GradientMessage, mode KEXMode) (*GradientAck, error) {
-	if len(peerAddrs) > 0 {
-		if err := h.Connect(ctx, peer.AddrInfo{ID: peerID, Addrs: peerAddrs}); err != nil {
-			return nil, fmt.Errorf("gradient: connect to %s: %w", peerID, err)
-		}
-	}
-	if mode == "" {
-		mode = KEXModeX25519
-	}
-	if ParseKEXMode(string(mode)) == "" {
-		return nil, fmt.Errorf("gradient: unsupported kex mode %q", mode)
-	}
-	kexPublicKey, err := generateKEXPublicKey(mode)
-	if err != nil {
-		return nil, err
-	}
 	msg.TimestampMS = time.Now().UnixMilli()
-	s, err := h.NewStream(ctx, peerID, GradientProtocol)
-	if err != nil {
-		return nil, fmt.Errorf("gradient: open stream to %s: %w", peerID, err)
-	}
-	defer s.Close()
-	env := gradientEnvelope{
-		KEXMode:      string(mode),
-		KEXPublicKey: kexPublicKey,
-		Message:      *msg,
-	}
-	if err := json.NewEncoder(s).Encode(&env); err != nil {
-		return nil, fmt.Errorf("gradient: send: %w", err)
-	}
-	if err := s.CloseWrite(); err != nil {
-		log.Printf("gradient: close write failed: %v", err)
-	}
-	var ack GradientAck
-	if err := json.NewDecoder(bufio.NewReader(s)).Decode(&ack); err != nil {
-		return nil, fmt.Errorf("gradient: read ack: %w", err)
-	}
-	return &ack, nil
+	return exchangeGradientPayload(ctx, h, peerID, peerAddrs, mode, gradientPayload{Message: msg}, "send")
 }
 
 // SendGradientBatch sends multiple gradient updates over a single stream.
@@ -193,11 +205,23 @@ func SendGradientBatch(ctx context.Context, h corehost.Host, peerID peer.ID, pee
 	return SendGradientBatchWithKEX(ctx, h, peerID, peerAddrs, msgs, KEXModeX25519)
 }
 
-// SendGradientBatchWithKEX sends multiple gradient updates over a single stream with explicit KEX metadata.
+// SendGradientBatchWithKEX sends multiple gradient updates over a single encrypted stream.
 func SendGradientBatchWithKEX(ctx context.Context, h corehost.Host, peerID peer.ID, peerAddrs []ma.Multiaddr, msgs []GradientMessage, mode KEXMode) (*GradientAck, error) {
 	if len(msgs) == 0 {
 		return &GradientAck{Accepted: true, BatchAccepted: 0, BatchRejected: 0}, nil
 	}
+	now := time.Now().UnixMilli()
+	for i := range msgs {
+		if msgs[i].TimestampMS == 0 {
+			msgs[i].TimestampMS = now
+		}
+	}
+	return exchangeGradientPayload(ctx, h, peerID, peerAddrs, mode, gradientPayload{Messages: msgs}, "send batch")
+}
+
+// exchangeGradientPayload runs the handshake, sends the sealed payload and
+// verifies the sealed ack echoes the negotiated session.
+func exchangeGradientPayload(ctx context.Context, h corehost.Host, peerID peer.ID, peerAddrs []ma.Multiaddr, mode KEXMode, payload gradientPayload, op string) (*GradientAck, error) {
 	if len(peerAddrs) > 0 {
 		if err := h.Connect(ctx, peer.AddrInfo{ID: peerID, Addrs: peerAddrs}); err != nil {
 			return nil, fmt.Errorf("gradient: connect to %s: %w", peerID, err)
@@ -209,50 +233,82 @@ func SendGradientBatchWithKEX(ctx context.Context, h corehost.Host, peerID peer.
 	if ParseKEXMode(string(mode)) == "" {
 		return nil, fmt.Errorf("gradient: unsupported kex mode %q", mode)
 	}
-	kexPublicKey, err := generateKEXPublicKey(mode)
+	initiator, err := NewKEXInitiator(mode)
 	if err != nil {
 		return nil, err
 	}
-	now := time.Now().UnixMilli()
-	for i := range msgs {
-		if msgs[i].TimestampMS == 0 {
-			msgs[i].TimestampMS = now
-		}
-	}
+	mode = initiator.mode
 
 	s, err := h.NewStream(ctx, peerID, GradientProtocol)
 	if err != nil {
 		return nil, fmt.Errorf("gradient: open stream to %s: %w", peerID, err)
 	}
 	defer s.Close()
-	env := gradientEnvelope{
-		KEXMode:      string(mode),
-		KEXPublicKey: kexPublicKey,
-		Messages:     msgs,
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetDeadline(deadline)
+	}
+
+	enc := json.NewEncoder(s)
+	dec := json.NewDecoder(io.LimitReader(s, maxGradientStreamBytes))
+
+	if err := enc.Encode(&gradientEnvelope{KEXMode: string(mode), KEXPublicKey: initiator.PublicKey()}); err != nil {
+		return nil, fmt.Errorf("gradient: %s hello: %w", op, err)
 	}
-	if err := json.NewEncoder(s).Encode(&env); err != nil {
-		return nil, fmt.Errorf("gradient: send batch: %w", err)
+	var hello kexHello
+	if err := dec.Decode(&hello); err != nil {
+		return nil, fmt.Errorf("gradient: read kex hello: %w", err)
+	}
+	if !hello.Accepted {
+		return &GradientAck{Accepted: false, Reason: hello.Reason}, nil
+	}
+	session, err := initiator.Complete(KEXResponse{
+		Mode:          KEXMode(hello.KEXMode),
+		PublicKey:     hello.KEXPublicKey,
+		KEMCiphertext: hello.KEMCiphertext,
+	}, kexBinding(h.ID(), peerID))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: encode payload: %w", err)
+	}
+	nonce, ciphertext, err := session.Seal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(&sealedEnvelope{Nonce: nonce, Ciphertext: ciphertext}); err != nil {
+		return nil, fmt.Errorf("gradient: %s: %w", op, err)
 	}
 	if err := s.CloseWrite(); err != nil {
-		log.Printf("gradient: close write for batch failed: %v", err)
+		log.Printf("gradient: close write failed: %v", err)
+	}
+
+	var sealedAck sealedEnvelope
+	if err := dec.Decode(&sealedAck); err != nil {
+		return nil, fmt.Errorf("gradient: read ack: %w", err)
+	}
+	if sealedAck.Error != "" {
+		return &GradientAck{Accepted: false, Reason: sealedAck.Error}, nil
+	}
+	ackBytes, err := session.Open(sealedAck.Nonce, sealedAck.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: open ack: %w", err)
 	}
 	var ack GradientAck
-	if err := json.NewDecoder(bufio.NewReader(s)).Decode(&ack); err != nil {
-		return nil, fmt.Errorf("gradient: read batch ack: %w", err)
+	if err := json.Unmarshal(ackBytes, &ack); err != nil {
+		return nil, fmt.Errorf("gradient: decode ack: %w", err)
+	}
+	if ack.NegotiatedKEX != string(mode) || !bytes.Equal(ack.KEXTranscriptHash, session.TranscriptHash) {
+		return nil, fmt.Errorf("gradient: ack does not echo negotiated session (kex=%q)", ack.NegotiatedKEX)
 	}
 	return &ack, nil
 }
 
-func generateKEXPublicKey(mode KEXMode) ([]byte, error) {
-	bytes := mode.ExpectedPublicKeyBytes()
-	if bytes <= 0 {
-		return nil, fmt.Errorf("gradient: invalid expected public key size for mode %q", mode)
-	}
-	key := make([]byte, bytes)
-	if _, err := rand.Read(key); err != nil {
-		return nil, fmt.Errorf("gradient: generate kex public key: %w", err)
-	}
-	return key, nil
+// kexBinding ties session keys to the libp2p identities of both ends.
+func kexBinding(initiator, responder peer.ID) []byte {
+	return []byte(string(initiator) + "|" + string(responder))
 }
 
 func resetStreamWithLog(s corenetwork.Stream, context string) {
@@ -261,9 +317,25 @@ func resetStreamWithLog(s corenetwork.Stream, context string) {
 	}
 }
 
-func writeAckWithLog(s corenetwork.Stream, ack *GradientAck, context string) {
-	if err := json.NewEncoder(s).Encode(ack); err != nil {
-		log.Printf("gradient: ack encode failed (%s): %v", context, err)
-		resetStreamWithLog(s, context+"_ack_encode")
+func writeJSONWithLog(s corenetwork.Stream, v any, context string) {
+	if err := json.NewEncoder(s).Encode(v); err != nil {
+		log.Printf("gradient: encode failed (%s): %v", context, err)
+		resetStreamWithLog(s, context+"_encode")
+	}
+}
+
+func writeSealedAck(s corenetwork.Stream, session *KEXSession, ack *GradientAck) {
+	plaintext, err := json.Marshal(ack)
+	if err != nil {
+		log.Printf("gradient: ack marshal failed: %v", err)
+		resetStreamWithLog(s, "ack_marshal")
+		return
+	}
+	nonce, ciphertext, err := session.Seal(plaintext)
+	if err != nil {
+		log.Printf("gradient: ack seal failed: %v", err)
+		resetStreamWithLog(s, "ack_seal")
+		return
 	}
+	writeJSONWithLog(s, &sealedEnvelope{Nonce: nonce, Ciphertext: ciphertext}, "sealed_ack")
 }
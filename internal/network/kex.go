@@ -0,0 +1,227 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// KEXCipherSuite names the key schedule and AEAD used for gradient sessions.
+const KEXCipherSuite = "hkdf-sha256/aes-256-gcm"
+
+const (
+	x25519PublicKeyBytes = 32
+	kexSessionInfo       = "mohawk/gradient/1.0.0 session keys"
+	kexTranscriptLabel   = "mohawk/gradient/1.0.0 transcript"
+)
+
+var errKEXAuthentication = errors.New("gradient: envelope authentication failed")
+
+// KEXInitiator holds the ephemeral secrets of the side that opens a gradient stream.
+// For the hybrid mode the public key is X25519 (32 bytes) || ML-KEM-768
+// encapsulation key (1184 bytes), matching KEXMode.ExpectedPublicKeyBytes.
+type KEXInitiator struct {
+	mode   KEXMode
+	x25519 *ecdh.PrivateKey
+	mlkem  *mlkem.DecapsulationKey768
+}
+
+// KEXResponse is the responder's half of the handshake.
+type KEXResponse struct {
+	Mode          KEXMode
+	PublicKey     []byte // responder ephemeral X25519 public key
+	KEMCiphertext []byte // ML-KEM-768 ciphertext (hybrid mode only)
+}
+
+// KEXSession is an established, directional AEAD session. Keys are bound to
+// the full handshake transcript and the caller-supplied binding (peer IDs).
+type KEXSession struct {
+	Mode           KEXMode
+	TranscriptHash []byte
+	send           cipher.AEAD
+	recv           cipher.AEAD
+}
+
+// NewKEXInitiator generates fresh ephemeral keys for mode.
+func NewKEXInitiator(mode KEXMode) (*KEXInitiator, error) {
+	mode = ParseKEXMode(string(mode))
+	if mode == "" {
+		return nil, fmt.Errorf("gradient: unsupported kex mode")
+	}
+	xk, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: generate x25519 key: %w", err)
+	}
+	k := &KEXInitiator{mode: mode, x25519: xk}
+	if mode == KEXModeHybridX25519MLKEM768 {
+		dk, err := mlkem.GenerateKey768()
+		if err != nil {
+			return nil, fmt.Errorf("gradient: generate ml-kem-768 key: %w", err)
+		}
+		k.mlkem = dk
+	}
+	return k, nil
+}
+
+// PublicKey returns the initiator's wire public key for the hello message.
+func (k *KEXInitiator) PublicKey() []byte {
+	pub := append([]byte(nil), k.x25519.PublicKey().Bytes()...)
+	if k.mlkem != nil {
+		pub = append(pub, k.mlkem.EncapsulationKey().Bytes()...)
+	}
+	return pub
+}
+
+// Complete derives the initiator's session from the responder's reply.
+func (k *KEXInitiator) Complete(resp KEXResponse, binding []byte) (*KEXSession, error) {
+	if ParseKEXMode(string(resp.Mode)) != k.mode {
+		return nil, fmt.Errorf("gradient: responder negotiated %q, expected %q", resp.Mode, k.mode)
+	}
+	peerPub, err := ecdh.X25519().NewPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: invalid responder x25519 key: %w", err)
+	}
+	dhShared, err := k.x25519.ECDH(peerPub)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: x25519: %w", err)
+	}
+	secret := dhShared
+	if k.mode == KEXModeHybridX25519MLKEM768 {
+		kemShared, err := k.mlkem.Decapsulate(resp.KEMCiphertext)
+		if err != nil {
+			return nil, fmt.Errorf("gradient: ml-kem decapsulate: %w", err)
+		}
+		secret = append(secret, kemShared...)
+	} else if len(resp.KEMCiphertext) != 0 {
+		return nil, fmt.Errorf("gradient: unexpected kem ciphertext for mode %q", k.mode)
+	}
+	return deriveKEXSession(k.mode, secret, k.PublicKey(), resp, binding, true)
+}
+
+// RespondKEX validates an initiator public key, generates the responder's
+// ephemeral contribution and derives the responder's session.
+func RespondKEX(mode KEXMode, initiatorPublicKey []byte, binding []byte) (KEXResponse, *KEXSession, error) {
+	mode = ParseKEXMode(string(mode))
+	if mode == "" {
+		return KEXResponse{}, nil, fmt.Errorf("gradient: unsupported kex mode")
+	}
+	if want := mode.ExpectedPublicKeyBytes(); len(initiatorPublicKey) != want {
+		return KEXResponse{}, nil, fmt.Errorf("gradient: kex public key bytes mismatch expected=%d got=%d", want, len(initiatorPublicKey))
+	}
+
+	peerPub, err := ecdh.X25519().NewPublicKey(initiatorPublicKey[:x25519PublicKeyBytes])
+	if err != nil {
+		return KEXResponse{}, nil, fmt.Errorf("gradient: invalid initiator x25519 key: %w", err)
+	}
+	xk, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return KEXResponse{}, nil, fmt.Errorf("gradient: generate x25519 key: %w", err)
+	}
+	secret, err := xk.ECDH(peerPub)
+	if err != nil {
+		return KEXResponse{}, nil, fmt.Errorf("gradient: x25519: %w", err)
+	}
+
+	resp := KEXResponse{Mode: mode, PublicKey: xk.PublicKey().Bytes()}
+	if mode == KEXModeHybridX25519MLKEM768 {
+		ek, err := mlkem.NewEncapsulationKey768(initiatorPublicKey[x25519PublicKeyBytes:])
+		if err != nil {
+			return KEXResponse{}, nil, fmt.Errorf("gradient: invalid ml-kem-768 encapsulation key: %w", err)
+		}
+		kemShared, ciphertext := ek.Encapsulate()
+		resp.KEMCiphertext = ciphertext
+		secret = append(secret, kemShared...)
+	}
+
+	session, err := deriveKEXSession(mode, secret, initiatorPublicKey, resp, binding, false)
+	if err != nil {
+		return KEXResponse{}, nil, err
+	}
+	return resp, session, nil
+}
+
+// deriveKEXSession runs HKDF-SHA256 over the combined secret, salted with the
+// transcript hash, and splits the output into per-direction AES-256-GCM keys.
+func deriveKEXSession(mode KEXMode, secret, initiatorPub []byte, resp KEXResponse, binding []byte, initiator bool) (*KEXSession, error) {
+	th := sha256.New()
+	for _, part := range [][]byte{[]byte(kexTranscriptLabel), []byte(mode), initiatorPub, resp.PublicKey, resp.KEMCiphertext, binding} {
+		th.Write(binary.BigEndian.AppendUint32(nil, uint32(len(part))))
+		th.Write(part)
+	}
+	transcript := th.Sum(nil)
+
+	keys, err := hkdf.Key(sha256.New, secret, transcript, kexSessionInfo, 64)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: hkdf: %w", err)
+	}
+	i2r, err := newGCM(keys[:32])
+	if err != nil {
+		return nil, err
+	}
+	r2i, err := newGCM(keys[32:])
+	if err != nil {
+		return nil, err
+	}
+
+	session := &KEXSession{Mode: mode, TranscriptHash: transcript}
+	if initiator {
+		session.send, session.recv = i2r, r2i
+	} else {
+		session.send, session.recv = r2i, i2r
+	}
+	return session, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: aes: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("gradient: gcm: %w", err)
+	}
+	return aead, nil
+}
+
+// Seal encrypts plaintext for the peer, authenticating the transcript hash.
+func (s *KEXSession) Seal(plaintext []byte) (nonce, ciphertext []byte, err error) {
+	nonce = make([]byte, s.send.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("gradient: nonce: %w", err)
+	}
+	return nonce, s.send.Seal(nil, nonce, plaintext, s.TranscriptHash), nil
+}
+
+// Open decrypts a message sealed by the peer's session.
+func (s *KEXSession) Open(nonce, ciphertext []byte) ([]byte, error) {
+	if len(nonce) != s.recv.NonceSize() {
+		return nil, errKEXAuthentication
+	}
+	plaintext, err := s.recv.Open(nil, nonce, ciphertext, s.TranscriptHash)
+	if err != nil {
+		return nil, errKEXAuthentication
+	}
+	return plaintext, nil
+}
@@ -21,6 +21,7 @@ const (
 	KEXModeHybridX25519MLKEM768 KEXMode         = "x25519-mlkem768-hybrid"
 	AggregationModePlaintext    AggregationMode = "plaintext"
 	AggregationModeFHEThreshold AggregationMode = "fhe_threshold_v1"
+	AggregationModeSecAgg       AggregationMode = "secagg_pairwise_v1"
 )
 
 func ParseKEXMode(raw string) KEXMode {
@@ -42,13 +43,15 @@ func ParseAggregationMode(raw string) AggregationMode {
 		return AggregationModePlaintext
 	case string(AggregationModeFHEThreshold), "fhe", "tfhe", "threshold-fhe":
 		return AggregationModeFHEThreshold
+	case string(AggregationModeSecAgg), "secagg", "secure-aggregation", "pairwise-mask", "bonawitz":
+		return AggregationModeSecAgg
 	default:
 		return AggregationMode("")
 	}
 }
 
 func SupportedAggregationModes() []AggregationMode {
-	return []AggregationMode{AggregationModePlaintext, AggregationModeFHEThreshold}
+	return []AggregationMode{AggregationModePlaintext, AggregationModeFHEThreshold, AggregationModeSecAgg}
 }
 
 func ParseKEXModeStrict(raw string) (KEXMode, error) {
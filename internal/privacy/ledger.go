@@ -0,0 +1,311 @@
+package privacy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLedgerTampered is returned when a budget ledger fails hash-chain
+// verification on open.
+var ErrLedgerTampered = errors.New("privacy ledger hash chain verification failed")
+
+// SpendRecord is one privacy charge in a BudgetLedger. Epsilon is in the
+// consumer's additive budget unit. RDP optionally carries a per-order RDP step
+// as big.Rat strings, with "" for unbounded orders, for accountants that
+// replay curves exactly.
+//
+// Tier is only set on records written before tier charges moved to Shard; it
+// is kept so those records still verify. Read the sub-ledger with ShardID.
+type SpendRecord struct {
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Shard     string    `json:"shard,omitempty"`
+	Tier      string    `json:"tier,omitempty"`
+	Epsilon   float64   `json:"epsilon"`
+	RDP       []string  `json:"rdp,omitempty"`
+	PrevHash  string    `json:"prev_hash,omitempty"`
+	Hash      string    `json:"hash"`
+}
+
+// ShardID returns the sub-ledger the record was charged to, falling back to
+// the legacy Tier field.
+func (r SpendRecord) ShardID() string {
+	if r.Shard != "" {
+		return r.Shard
+	}
+	return r.Tier
+}
+
+// BudgetTotals sums recorded Epsilon globally and per shard.
+type BudgetTotals struct {
+	Global float64            `json:"global"`
+	Shards map[string]float64 `json:"shards"`
+}
+
+type ledgerHead struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// BudgetLedger is an append-only, hash-chained log of privacy spend.
+//
+// Each record is fsynced before Charge returns, and a head file holding the
+// last sequence number and hash is replaced atomically afterwards. Opening a
+// ledger replays the log and fails with ErrLedgerTampered if a record was
+// altered, reordered or removed. A torn final line from a crash mid-append is
+// discarded, since that charge was never acknowledged.
+type BudgetLedger struct {
+	mu       sync.Mutex
+	path     string
+	headPath string
+	file     *os.File
+	prev     string
+	seq      uint64
+	records  []SpendRecord
+	totals   BudgetTotals
+	// failed blocks further charges after a write whose outcome is unknown.
+	failed error
+}
+
+// OpenBudgetLedger opens or creates the ledger at path and replays it.
+func OpenBudgetLedger(path string) (*BudgetLedger, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("ledger path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("ensure ledger dir: %w", err)
+	}
+	l := &BudgetLedger{
+		path:     path,
+		headPath: path + ".head",
+		totals:   BudgetTotals{Shards: map[string]float64{}},
+	}
+	if err := l.replay(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open ledger: %w", err)
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	l.file = f
+	return l, nil
+}
+
+// Charge appends rec, fsyncs it and returns the stored record. Seq,
+// Timestamp and the chain hashes are assigned by the ledger.
+func (l *BudgetLedger) Charge(rec SpendRecord) (SpendRecord, error) {
+	if math.IsNaN(rec.Epsilon) || math.IsInf(rec.Epsilon, 0) || rec.Epsilon < 0 {
+		return SpendRecord{}, fmt.Errorf("epsilon must be finite and non-negative")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return SpendRecord{}, fmt.Errorf("ledger is closed")
+	}
+	if l.failed != nil {
+		return SpendRecord{}, fmt.Errorf("ledger unavailable after failed write: %w", l.failed)
+	}
+	rec.Seq = l.seq + 1
+	rec.Timestamp = time.Now().UTC()
+	rec.PrevHash = l.prev
+	hash, err := recordHash(rec)
+	if err != nil {
+		return SpendRecord{}, err
+	}
+	rec.Hash = hash
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return SpendRecord{}, fmt.Errorf("marshal spend record: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		l.failed = err
+		return SpendRecord{}, fmt.Errorf("append ledger: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		l.failed = err
+		return SpendRecord{}, fmt.Errorf("fsync ledger: %w", err)
+	}
+	// The record is durable from here on, so count it even if the head update fails.
+	l.applyLocked(rec)
+	if err := l.writeHead(ledgerHead{Seq: rec.Seq, Hash: rec.Hash}); err != nil {
+		l.failed = err
+		return rec, err
+	}
+	return rec, nil
+}
+
+// Records returns a copy of every replayed and appended record.
+func (l *BudgetLedger) Records() []SpendRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]SpendRecord(nil), l.records...)
+}
+
+// Totals returns cumulative Epsilon globally and per shard.
+func (l *BudgetLedger) Totals() BudgetTotals {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := BudgetTotals{
+		Global: l.totals.Global,
+		Shards: make(map[string]float64, len(l.totals.Shards)),
+	}
+	for k, v := range l.totals.Shards {
+		out.Shards[k] = v
+	}
+	return out
+}
+
+// Close releases the ledger file.
+func (l *BudgetLedger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *BudgetLedger) applyLocked(rec SpendRecord) {
+	l.seq = rec.Seq
+	l.prev = rec.Hash
+	l.records = append(l.records, rec)
+	l.totals.Global += rec.Epsilon
+	if shard := rec.ShardID(); shard != "" {
+		l.totals.Shards[shard] += rec.Epsilon
+	}
+}
+
+func (l *BudgetLedger) replay() error {
+	raw, err := os.ReadFile(l.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read ledger: %w", err)
+	}
+	if n := bytes.LastIndexByte(raw, '\n'); n+1 < len(raw) {
+		// Torn final append: the charge was never acknowledged.
+		if err := os.Truncate(l.path, int64(n+1)); err != nil {
+			return fmt.Errorf("truncate torn ledger record: %w", err)
+		}
+		raw = raw[:n+1]
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec SpendRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("%w: record %d: %v", ErrLedgerTampered, l.seq+1, err)
+		}
+		if rec.Seq != l.seq+1 || rec.PrevHash != l.prev {
+			return fmt.Errorf("%w: record %d out of sequence", ErrLedgerTampered, l.seq+1)
+		}
+		want, err := recordHash(rec)
+		if err != nil {
+			return err
+		}
+		if rec.Hash != want {
+			return fmt.Errorf("%w: record %d hash mismatch", ErrLedgerTampered, rec.Seq)
+		}
+		l.applyLocked(rec)
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return fmt.Errorf("scan ledger: %w", err)
+	}
+	return l.checkHead()
+}
+
+// checkHead rejects logs that end before the last acknowledged record. The log
+// may be one record ahead if the process stopped between fsync and head update.
+func (l *BudgetLedger) checkHead() error {
+	raw, err := os.ReadFile(l.headPath)
+	if os.IsNotExist(err) {
+		// The first record may be durable before the head file is created.
+		if l.seq > 1 {
+			return fmt.Errorf("%w: head file missing for non-empty ledger", ErrLedgerTampered)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read ledger head: %w", err)
+	}
+	var head ledgerHead
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return fmt.Errorf("%w: head: %v", ErrLedgerTampered, err)
+	}
+	if head.Seq > l.seq || head.Seq+1 < l.seq {
+		return fmt.Errorf("%w: ledger has %d records, head expects %d", ErrLedgerTampered, l.seq, head.Seq)
+	}
+	if head.Seq > 0 && l.records[head.Seq-1].Hash != head.Hash {
+		return fmt.Errorf("%w: head hash mismatch at record %d", ErrLedgerTampered, head.Seq)
+	}
+	return nil
+}
+
+func (l *BudgetLedger) writeHead(head ledgerHead) error {
+	raw, err := json.Marshal(head)
+	if err != nil {
+		return fmt.Errorf("marshal ledger head: %w", err)
+	}
+	tmp := l.headPath + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("write ledger head: %w", err)
+	}
+	if _, err := f.Write(raw); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write ledger head: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fsync ledger head: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write ledger head: %w", err)
+	}
+	if err := os.Rename(tmp, l.headPath); err != nil {
+		return fmt.Errorf("replace ledger head: %w", err)
+	}
+	return syncDir(filepath.Dir(l.headPath))
+}
+
+// recordHash is sha256(prev_hash || canonical JSON of rec without its hash).
+func recordHash(rec SpendRecord) (string, error) {
+	rec.Hash = ""
+	canonical, err := json.Marshal(rec)
+	if err != nil {
+		return "", fmt.Errorf("marshal spend record for hash: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(rec.PrevHash), canonical...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("open ledger dir: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("fsync ledger dir: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,13 @@ import (
 	"math"
 	"strings"
 	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
 )
 
+// orchestratorSource tags Orchestrator allocations in a BudgetLedger.
+const orchestratorSource = "orchestrator"
+
 type SensitivityClass string
 
 const (
@@ -37,8 +42,10 @@ type Orchestrator struct {
 	mu          sync.Mutex
 	totalBudget float64
 	consumed    float64
+	shardSpend  map[string]float64
 	defaultMin  float64
 	defaultMax  float64
+	ledger      *BudgetLedger
 }
 
 func NewOrchestrator(totalBudget float64, minEps float64, maxEps float64) *Orchestrator {
@@ -54,7 +61,27 @@ func NewOrchestrator(totalBudget float64, minEps float64, maxEps float64) *Orche
 	if minEps > maxEps {
 		minEps, maxEps = maxEps, minEps
 	}
-	return &Orchestrator{totalBudget: totalBudget, defaultMin: minEps, defaultMax: maxEps}
+	return &Orchestrator{totalBudget: totalBudget, shardSpend: map[string]float64{}, defaultMin: minEps, defaultMax: maxEps}
+}
+
+// NewPersistentOrchestrator is NewOrchestrator backed by ledger. Prior
+// allocations are replayed from the ledger, and each new allocation is
+// durably recorded before it is returned.
+func NewPersistentOrchestrator(totalBudget float64, minEps float64, maxEps float64, ledger *BudgetLedger) (*Orchestrator, error) {
+	if ledger == nil {
+		return nil, fmt.Errorf("budget ledger is required")
+	}
+	o := NewOrchestrator(totalBudget, minEps, maxEps)
+	o.ledger = ledger
+	for _, rec := range ledger.Records() {
+		if rec.Source != orchestratorSource {
+			continue
+		}
+		o.consumed += rec.Epsilon
+		o.shardSpend[rec.Shard] += rec.Epsilon
+	}
+	o.exportRemainingLocked()
+	return o, nil
 }
 
 func (o *Orchestrator) RemainingBudget() float64 {
@@ -63,6 +90,13 @@ func (o *Orchestrator) RemainingBudget() float64 {
 	return math.Max(0, o.totalBudget-o.consumed)
 }
 
+// ShardConsumed returns the epsilon allocated to shardID so far.
+func (o *Orchestrator) ShardConsumed(shardID string) float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.shardSpend[strings.TrimSpace(shardID)]
+}
+
 func (o *Orchestrator) Allocate(req AllocationRequest) (AllocationResult, error) {
 	req.ShardID = strings.TrimSpace(req.ShardID)
 	if req.ShardID == "" {
@@ -95,10 +129,24 @@ func (o *Orchestrator) Allocate(req AllocationRequest) (AllocationResult, error)
 	if o.consumed+requested > o.totalBudget {
 		return AllocationResult{}, fmt.Errorf("global epsilon budget exhausted: consumed=%.6f requested=%.6f total=%.6f", o.consumed, requested, o.totalBudget)
 	}
+	if o.ledger != nil {
+		if _, err := o.ledger.Charge(SpendRecord{Source: orchestratorSource, Shard: req.ShardID, Epsilon: requested}); err != nil {
+			return AllocationResult{}, fmt.Errorf("record allocation: %w", err)
+		}
+	}
 	o.consumed += requested
+	if o.shardSpend == nil {
+		o.shardSpend = map[string]float64{}
+	}
+	o.shardSpend[req.ShardID] += requested
+	o.exportRemainingLocked()
 	return AllocationResult{ShardID: req.ShardID, AllocatedEps: requested, RemainingBudget: math.Max(0, o.totalBudget-o.consumed)}, nil
 }
 
+func (o *Orchestrator) exportRemainingLocked() {
+	metrics.ObservePrivacyBudgetRemaining("global", orchestratorSource, math.Max(0, o.totalBudget-o.consumed))
+}
+
 func baseBySensitivity(class SensitivityClass) float64 {
 	switch strings.ToLower(strings.TrimSpace(string(class))) {
 	case string(SensitivityHealthcare), string(SensitivityCritical):
@@ -15,6 +15,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"math/big"
 	"os"
 	"runtime"
 	"strconv"
@@ -23,6 +25,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	corehost "github.com/libp2p/go-libp2p/core/host"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/expfmt"
@@ -483,7 +486,12 @@ func InitializeNode(configJSON *C.char) *C.char {
 	state.startedAt = time.Now().UTC()
 	state.meshPlan = meshPlan
 	state.host = host
-	state.aggregator = internalpkg.NewAggregator(internalpkg.Regional)
+	aggregator, err := newAggregator()
+	if err != nil {
+		_ = host.Close()
+		return marshalResult(false, fmt.Sprintf("Failed to initialize aggregator: %v", err), "")
+	}
+	state.aggregator = aggregator
 
 	msg := fmt.Sprintf("Node %s initialized with config: %s", config.NodeID, config.ConfigPath)
 	log.Println(msg)
@@ -511,7 +519,8 @@ func VerifyZKProof(proofJSON *C.char) *C.char {
 			fmt.Sprintf("Failed to parse proof payload: %v", err), "")
 	}
 	proofBytes := extractProofBytes(payload)
-	valid, err := internalpkg.VerifyProof(proofBytes, nil)
+	circuitID, _ := payload["circuit_id"].(string)
+	valid, err := verifyGroth16(circuitID, proofBytes, payload["public_inputs"])
 	latencyMS := float64(time.Since(started).Microseconds()) / 1000.0
 	if err != nil {
 		metrics.ObserveProofVerification("groth16", false, latencyMS)
@@ -523,13 +532,14 @@ func VerifyZKProof(proofJSON *C.char) *C.char {
 		metrics.ObserveProofVerification("groth16", false, latencyMS)
 		metrics.ObserveAcceleratorOp("cpu", "proof_verify", false)
 		metrics.ObserveAcceleratorOpLatency("cpu", "proof_verify", latencyMS)
-		return marshalResultEC(false, "PROOF_INVALID", "pairing check failed: proof does not satisfy genesis VK", "")
+		return marshalResultEC(false, "PROOF_INVALID", fmt.Sprintf("pairing check failed: proof does not satisfy circuit %q verifying key", circuitID), "")
 	}
 	metrics.ObserveProofVerification("groth16", true, latencyMS)
 	metrics.ObserveAcceleratorOp("cpu", "proof_verify", true)
 	metrics.ObserveAcceleratorOpLatency("cpu", "proof_verify", latencyMS)
 	data, _ := json.Marshal(map[string]any{
 		"valid":                valid,
+		"circuit_id":           circuitID,
 		"verification_time_ms": latencyMS,
 	})
 	return marshalResult(true, "Proof verified", string(data))
@@ -540,7 +550,12 @@ func AggregateUpdates(updatesJSON *C.char) *C.char {
 	updatesStr := C.GoString(updatesJSON)
 	state.mu.Lock()
 	if state.aggregator == nil {
-		state.aggregator = internalpkg.NewAggregator(internalpkg.Regional)
+		aggregator, err := newAggregator()
+		if err != nil {
+			state.mu.Unlock()
+			return marshalResult(false, fmt.Sprintf("Failed to initialize aggregator: %v", err), "")
+		}
+		state.aggregator = aggregator
 	}
 	aggregator := state.aggregator
 	state.mu.Unlock()
@@ -553,6 +568,24 @@ func AggregateUpdates(updatesJSON *C.char) *C.char {
 	return marshalResult(true, "Updates aggregated successfully", string(data))
 }
 
+// newAggregator builds the regional aggregator, backing its privacy accountant
+// with the ledger at MOHAWK_DP_LEDGER_PATH and applying the thinker clauses
+// from MOHAWK_CAPABILITIES_PATH when set.
+func newAggregator() (*internalpkg.Aggregator, error) {
+	thinker, err := internalpkg.ThinkerClausesFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	aggregator := internalpkg.NewAggregator(internalpkg.Regional)
+	if path := strings.TrimSpace(os.Getenv("MOHAWK_DP_LEDGER_PATH")); path != "" {
+		if aggregator, err = internalpkg.NewPersistentAggregator(internalpkg.Regional, path); err != nil {
+			return nil, err
+		}
+	}
+	aggregator.Thinker = thinker
+	return aggregator, nil
+}
+
 func aggregateUpdatesCore(updatesStr string, aggregator *internalpkg.Aggregator) (map[string]any, error) {
 	updates, options, err := parseAggregateUpdatesRequest(updatesStr)
 	if err != nil {
@@ -600,7 +633,7 @@ func aggregateUpdatesCore(updatesStr string, aggregator *internalpkg.Aggregator)
 	if err != nil {
 		return nil, err
 	}
-	return map[string]any{
+	response := map[string]any{
 		"count":            batchResult.InputCount,
 		"selected_count":   batchResult.SelectedCount,
 		"active_nodes":     batchResult.ActiveNodes,
@@ -608,7 +641,14 @@ func aggregateUpdatesCore(updatesStr string, aggregator *internalpkg.Aggregator)
 		"max_grad_norm":    batchResult.MaxGradNorm,
 		"multi_krum":       batchResult.UsedMultiKrum,
 		"used_fallback":    batchResult.UsedFallback,
-	}, nil
+		"aggregate":        batchResult.Aggregate,
+		"noise_multiplier": batchResult.NoiseMultiplier,
+		"clip_norm":        batchResult.ClipNorm,
+	}
+	if batchResult.Governance != nil {
+		response["governance"] = batchResult.Governance
+	}
+	return response, nil
 }
 
 func parseAggregateUpdatesRequest(updatesStr string) ([]aggregateUpdatePayload, internalpkg.BatchProcessingOptions, error) {
@@ -946,8 +986,10 @@ func safeIntFromCInt(v C.int) (int, error) {
 func BatchVerifyProofs(payloadJSON *C.char) *C.char {
 	raw := C.GoString(payloadJSON)
 	var proofs []struct {
-		ID    string `json:"id"`
-		Proof string `json:"proof"`
+		ID           string `json:"id"`
+		CircuitID    string `json:"circuit_id"`
+		Proof        string `json:"proof"`
+		PublicInputs any    `json:"public_inputs"`
 	}
 	if err := json.Unmarshal([]byte(raw), &proofs); err != nil {
 		return marshalResult(false, fmt.Sprintf("parse error: %v", err), "")
@@ -960,7 +1002,6 @@ func BatchVerifyProofs(payloadJSON *C.char) *C.char {
 	}
 
 	results := make([]verifyResult, len(proofs))
-	var wg sync.WaitGroup
 	batchStartAll := time.Now()
 	tune := accelerator.BuildAutoTuneProfile(len(proofs))
 	workers := tune.RecommendedWorker
@@ -970,25 +1011,65 @@ func BatchVerifyProofs(payloadJSON *C.char) *C.char {
 	if len(proofs) > 0 && workers > len(proofs) {
 		workers = len(proofs)
 	}
-	sem := make(chan struct{}, workers)
 
+	// Well-formed entries are grouped by circuit so each group can share one
+	// random-linear-combination pairing check.
+	type circuitBatch struct {
+		indices []int
+		proofs  [][]byte
+		inputs  [][]byte
+	}
+	batches := map[string]*circuitBatch{}
+	var order []string
 	for i, p := range proofs {
+		results[i].ID = p.ID
+		proofBytes := decodeProofString(p.Proof)
+		if len(proofBytes) < internalpkg.ProofBytes {
+			_, err := verifyGroth16(p.CircuitID, proofBytes, nil)
+			results[i].Error = err.Error()
+			metrics.ObserveProofVerification("groth16", false, 0)
+			continue
+		}
+		inputs, err := extractPublicInputs(p.PublicInputs)
+		if err != nil {
+			results[i].Error = err.Error()
+			metrics.ObserveProofVerification("groth16", false, 0)
+			continue
+		}
+		b, ok := batches[p.CircuitID]
+		if !ok {
+			b = &circuitBatch{}
+			batches[p.CircuitID] = b
+			order = append(order, p.CircuitID)
+		}
+		b.indices = append(b.indices, i)
+		b.proofs = append(b.proofs, proofBytes)
+		b.inputs = append(b.inputs, inputs)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for _, circuitID := range order {
 		wg.Add(1)
-		go func(idx int, id, proof string) {
+		go func(circuitID string, b *circuitBatch) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			proofBytes := decodeProofString(proof)
 			batchStart := time.Now()
-			valid, err := internalpkg.VerifyProof(proofBytes, nil)
-			batchLatency := float64(time.Since(batchStart).Microseconds()) / 1000.0
-			metrics.ObserveProofVerification("groth16", valid && err == nil, batchLatency)
-			r := verifyResult{ID: id, Valid: valid}
-			if err != nil {
-				r.Error = err.Error()
+			res, err := internalpkg.BatchVerifyProofs(circuitID, b.proofs, b.inputs)
+			perProof := float64(time.Since(batchStart).Microseconds()) / 1000.0 / float64(len(b.indices))
+			for j, idx := range b.indices {
+				switch {
+				case err != nil:
+					results[idx].Error = err.Error()
+				case res.Errors[j] != nil:
+					results[idx].Error = res.Errors[j].Error()
+				default:
+					results[idx].Valid = res.Valid[j]
+				}
+				metrics.ObserveProofVerification("groth16", results[idx].Valid, perProof)
 			}
-			results[idx] = r
-		}(i, p.ID, p.Proof)
+		}(circuitID, batches[circuitID])
 	}
 	wg.Wait()
 
@@ -1035,6 +1116,8 @@ func VerifyHybridProof(payloadJSON *C.char) *C.char {
 	}
 	var req struct {
 		Mode          string `json:"mode"`
+		SNARKCircuit  string `json:"snark_circuit_id"`
+		SNARKInputs   any    `json:"snark_public_inputs"`
 		SNARKProof    string `json:"snark_proof"`
 		STARKProof    string `json:"stark_proof"`
 		STARKBackend  string `json:"stark_backend"`
@@ -1052,13 +1135,21 @@ func VerifyHybridProof(payloadJSON *C.char) *C.char {
 		return marshalResult(false, fmt.Sprintf("unauthorized: %v", err), "")
 	}
 
+	snarkInputs, err := extractPublicInputs(req.SNARKInputs)
+	if err != nil {
+		observeHybridFailure("cpu")
+		return marshalResult(false, err.Error(), "")
+	}
+
 	tune := accelerator.BuildAutoTuneProfile(len(req.SNARKProof) + len(req.STARKProof))
 
 	result, err := hybrid.VerifyHybrid(hybrid.VerifyRequest{
-		Mode:         hybrid.HybridMode(req.Mode),
-		SNARKProof:   []byte(req.SNARKProof),
-		STARKProof:   []byte(req.STARKProof),
-		STARKBackend: req.STARKBackend,
+		Mode:              hybrid.HybridMode(req.Mode),
+		SNARKCircuitID:    req.SNARKCircuit,
+		SNARKPublicInputs: snarkInputs,
+		SNARKProof:        []byte(req.SNARKProof),
+		STARKProof:        []byte(req.STARKProof),
+		STARKBackend:      req.STARKBackend,
 	})
 	available := hybrid.AvailableSTARKBackends()
 	observedBackend := string(tune.SelectedDevice.Backend)
@@ -1413,6 +1504,60 @@ func decodeProofString(s string) []byte {
 	return []byte(s)
 }
 
+// verifyGroth16 checks the proof size before decoding the statement so that
+// truncated proofs report PROOF_TOO_SHORT whatever inputs accompany them.
+func verifyGroth16(circuitID string, proof []byte, rawInputs any) (bool, error) {
+	if len(proof) < internalpkg.ProofBytes {
+		return internalpkg.VerifyProof(circuitID, proof, nil)
+	}
+	publicInputs, err := extractPublicInputs(rawInputs)
+	if err != nil {
+		return false, err
+	}
+	return internalpkg.VerifyProof(circuitID, proof, publicInputs)
+}
+
+// extractPublicInputs accepts either an encoded byte string (hex/base64 of
+// concatenated 32-byte scalars) or a JSON array of decimal scalars.
+func extractPublicInputs(raw any) ([]byte, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		return decodeProofString(v), nil
+	case []any:
+		out := make([]byte, 0, len(v)*fr.Bytes)
+		for i, item := range v {
+			var text string
+			switch x := item.(type) {
+			case string:
+				text = strings.TrimSpace(x)
+			case float64:
+				if x != math.Trunc(x) || x < 0 || x > 1<<53 {
+					return nil, fmt.Errorf("invalid public inputs: element %d is not an exact integer; pass it as a decimal string", i)
+				}
+				text = strconv.FormatInt(int64(x), 10)
+			default:
+				return nil, fmt.Errorf("invalid public inputs: element %d has unsupported type %T", i, item)
+			}
+			var e fr.Element
+			n, ok := new(big.Int).SetString(text, 0)
+			if !ok || n.Sign() < 0 || n.Cmp(fr.Modulus()) >= 0 {
+				return nil, fmt.Errorf("invalid public inputs: element %d is not a canonical field element", i)
+			}
+			e.SetBigInt(n)
+			b := e.Bytes()
+			out = append(out, b[:]...)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("invalid public inputs: unsupported type %T", raw)
+	}
+}
+
 func extractNodeIDArg(raw string) string {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -1437,6 +1582,10 @@ func classifyProofError(err error) string {
 	}
 	msg := err.Error()
 	switch {
+	case strings.Contains(msg, "unknown circuit"):
+		return "PROOF_UNKNOWN_CIRCUIT"
+	case strings.Contains(msg, "invalid public inputs"):
+		return "PROOF_INPUT_INVALID"
 	case strings.Contains(msg, "too short") || strings.Contains(msg, "invalid proof size"):
 		return "PROOF_TOO_SHORT"
 	case strings.Contains(msg, "not a valid BN254") || strings.Contains(msg, "invalid proof A") ||
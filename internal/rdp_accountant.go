@@ -23,17 +23,32 @@ import (
 	"sync"
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/privacy"
 )
 
+// rdpLedgerSource tags RDPAccountant steps in a privacy.BudgetLedger.
+const rdpLedgerSource = "rdp_accountant"
+
 // RDPAccountant tracks cumulative privacy leakage using Rényi Differential Privacy.
 // It implements Theorem 2: sequential composition of RDP mechanisms.
+//
+// Steps are composed on an RDP curve over Orders, and (ε, δ) is reported at
+// the best order. TotalEpsilon and ShardEpsilon keep the legacy ledger at the
+// single order Alpha.
 type RDPAccountant struct {
 	mu           sync.RWMutex
-	Alpha        float64  // Rényi divergence order
-	TotalEpsilon *big.Rat // Cumulative RDP epsilon
-	MaxBudget    *big.Rat // Target (ε, δ)-DP limit (e.g., 2.0)
-	TargetDelta  float64  // Fixed delta (e.g., 10⁻⁵)
+	Alpha        float64   // Reference order for the TotalEpsilon ledger
+	Orders       []float64 // Rényi orders tracked by the curve
+	TotalEpsilon *big.Rat  // Cumulative RDP epsilon at Alpha
+	MaxBudget    *big.Rat  // Target (ε, δ)-DP limit (e.g., 2.0)
+	TargetDelta  float64   // Fixed delta (e.g., 10⁻⁵)
 	ShardEpsilon map[string]*big.Rat
+
+	curve        *rdpCurve
+	shardCurves  map[string]*rdpCurve
+	ledger       *privacy.BudgetLedger
+	ledgerSource string
+	ledgerErr    error
 }
 
 // NewRDPAccountant initializes the accountant with research-backed defaults.
@@ -42,16 +57,21 @@ func NewRDPAccountant(maxEpsilon float64, delta float64) *RDPAccountant {
 	if maxBudget.SetFloat64(maxEpsilon) == nil {
 		maxBudget = new(big.Rat)
 	}
+	orders := append([]float64(nil), DefaultRDPOrders...)
 	return &RDPAccountant{
 		Alpha:        10.0, // Optimized alpha for hierarchical composition
+		Orders:       orders,
 		TotalEpsilon: new(big.Rat),
 		MaxBudget:    maxBudget,
 		TargetDelta:  delta,
 		ShardEpsilon: map[string]*big.Rat{},
+		curve:        newRDPCurve(len(orders)),
+		shardCurves:  map[string]*rdpCurve{},
 	}
 }
 
-// RecordStep adds the RDP epsilon of a new mechanism to the running sum.
+// RecordStep adds the RDP epsilon of a new mechanism at order Alpha. The
+// curve treats it as a bound for every order up to Alpha only.
 // Reference: /proofs/differential_privacy.md
 func (a *RDPAccountant) RecordStep(epsilon float64) {
 	rat := ratFromFloat64(epsilon)
@@ -66,7 +86,8 @@ func (a *RDPAccountant) RecordStepRat(epsilon *big.Rat) {
 	if epsilon == nil {
 		return
 	}
-	a.TotalEpsilon.Add(a.TotalEpsilon, epsilon)
+	a.ensureCurvesLocked()
+	_ = a.commitLocked("", stepFromScalar(a.Orders, a.Alpha, epsilon), epsilon)
 }
 
 // RecordShardStep tracks epsilon usage in a shard-level sub-ledger while
@@ -82,17 +103,11 @@ func (a *RDPAccountant) RecordShardStepRat(shardID string, epsilon *big.Rat) {
 	}
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	if a.ShardEpsilon == nil {
-		a.ShardEpsilon = map[string]*big.Rat{}
-	}
-	if _, ok := a.ShardEpsilon[shardID]; !ok {
-		a.ShardEpsilon[shardID] = new(big.Rat)
-	}
-	a.ShardEpsilon[shardID].Add(a.ShardEpsilon[shardID], epsilon)
-	a.TotalEpsilon.Add(a.TotalEpsilon, epsilon)
+	a.ensureCurvesLocked()
+	_ = a.commitLocked(shardID, stepFromScalar(a.Orders, a.Alpha, epsilon), epsilon)
 }
 
-// GetShardEpsilonRat returns the shard-level cumulative epsilon.
+// GetShardEpsilonRat returns the shard-level cumulative epsilon at Alpha.
 func (a *RDPAccountant) GetShardEpsilonRat(shardID string) *big.Rat {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -106,44 +121,292 @@ func (a *RDPAccountant) GetShardEpsilonRat(shardID string) *big.Rat {
 	return new(big.Rat).Set(v)
 }
 
+// GetShardEpsilon converts a shard's RDP curve to (ε, δ)-DP at its best order.
+func (a *RDPAccountant) GetShardEpsilon(shardID string) float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	curve, ok := a.shardCurves[shardID]
+	if !ok {
+		return 0
+	}
+	eps, _ := curve.epsilon(a.Orders, a.TargetDelta)
+	return eps
+}
+
 // RecordGaussianStepRDP records one Gaussian mechanism step using the standard
-// RDP composition formula epsilon(alpha) = alpha/(2*sigma^2).
+// RDP bound epsilon(alpha) = alpha/(2*sigma^2) at every tracked order.
 func (a *RDPAccountant) RecordGaussianStepRDP(sigma float64) error {
-	if sigma <= 0 {
-		return fmt.Errorf("sigma must be positive")
+	return a.RecordSubsampledGaussianStep(1, sigma)
+}
+
+// RecordSubsampledGaussianStep records one Gaussian mechanism step with noise
+// multiplier sigma applied to a Poisson sample of the population with rate q.
+func (a *RDPAccountant) RecordSubsampledGaussianStep(q, sigma float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ensureCurvesLocked()
+	step, err := subsampledGaussianStep(a.Orders, q, sigma)
+	if err != nil {
+		return err
+	}
+	return a.commitLocked("", step, a.stepAtAlpha(step, q, sigma))
+}
+
+// RecordShardSubsampledGaussianStep is RecordSubsampledGaussianStep charged to
+// a shard sub-ledger as well as the global curve.
+func (a *RDPAccountant) RecordShardSubsampledGaussianStep(shardID string, q, sigma float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ensureCurvesLocked()
+	step, err := subsampledGaussianStep(a.Orders, q, sigma)
+	if err != nil {
+		return err
+	}
+	return a.commitLocked(shardID, step, a.stepAtAlpha(step, q, sigma))
+}
+
+// stepAtAlpha returns the step's value at Alpha for the legacy ledger.
+func (a *RDPAccountant) stepAtAlpha(step []*big.Rat, q, sigma float64) *big.Rat {
+	for i, order := range a.Orders {
+		if order == a.Alpha && step[i] != nil {
+			return step[i]
+		}
+	}
+	return ratFromFloat64(subsampledGaussianRDP(a.Alpha, q, sigma))
+}
+
+func (a *RDPAccountant) ensureCurvesLocked() {
+	if len(a.Orders) == 0 {
+		a.Orders = append([]float64(nil), DefaultRDPOrders...)
+	}
+	if a.curve == nil {
+		a.curve = newRDPCurve(len(a.Orders))
+	}
+	if a.shardCurves == nil {
+		a.shardCurves = map[string]*rdpCurve{}
+	}
+	if a.ShardEpsilon == nil {
+		a.ShardEpsilon = map[string]*big.Rat{}
+	}
+	if a.TotalEpsilon == nil {
+		a.TotalEpsilon = new(big.Rat)
+	}
+}
+
+// AttachLedger replays the accountant's prior steps from ledger and makes
+// every later step durable before it is applied. It must be called before any
+// step is recorded in memory.
+func (a *RDPAccountant) AttachLedger(ledger *privacy.BudgetLedger) error {
+	return a.AttachLedgerSource(ledger, rdpLedgerSource)
+}
+
+// AttachLedgerSource is AttachLedger for accountants that share a ledger with
+// others; only records tagged with source are replayed and written.
+func (a *RDPAccountant) AttachLedgerSource(ledger *privacy.BudgetLedger, source string) error {
+	if ledger == nil || source == "" {
+		return fmt.Errorf("ledger and source are required")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ledger != nil {
+		return fmt.Errorf("accountant already has a ledger")
+	}
+	a.ensureCurvesLocked()
+	if !a.curve.empty() {
+		return fmt.Errorf("accountant has unrecorded steps")
+	}
+	for _, rec := range ledger.Records() {
+		if rec.Source != source {
+			continue
+		}
+		step, atAlpha, err := a.decodeLedgerStep(rec)
+		if err != nil {
+			return fmt.Errorf("replay ledger record %d: %w", rec.Seq, err)
+		}
+		a.applyLocked(rec.ShardID(), step, atAlpha)
+	}
+	a.ledger = ledger
+	a.ledgerSource = source
+	a.exportRemainingLocked("")
+	for shardID := range a.shardCurves {
+		a.exportRemainingLocked(shardID)
 	}
-	epsilon := a.Alpha / (2.0 * sigma * sigma)
-	a.RecordStep(epsilon)
 	return nil
 }
 
-// GetCurrentEpsilon converts cumulative RDP to standard (ε, δ)-DP.
-// Conversion formula: ε = ε_rdp + log(1/δ) / (α - 1)
-func (a *RDPAccountant) GetCurrentEpsilon() float64 {
+// commitLocked writes the step to the ledger, if any, and then applies it.
+// A failed write is remembered so CheckBudget fails closed.
+func (a *RDPAccountant) commitLocked(shardID string, step []*big.Rat, atAlpha *big.Rat) error {
+	if a.ledger != nil {
+		if err := a.appendLedgerLocked(shardID, step, atAlpha); err != nil {
+			a.ledgerErr = err
+			return err
+		}
+	}
+	a.applyLocked(shardID, step, atAlpha)
+	if a.ledger != nil {
+		a.exportRemainingLocked("")
+		if shardID != "" {
+			a.exportRemainingLocked(shardID)
+		}
+	}
+	return nil
+}
+
+func (a *RDPAccountant) appendLedgerLocked(shardID string, step []*big.Rat, atAlpha *big.Rat) error {
+	encoded := make([]string, len(step))
+	for i, v := range step {
+		if v != nil {
+			encoded[i] = v.RatString()
+		}
+	}
+	eps, _ := atAlpha.Float64()
+	_, err := a.ledger.Charge(privacy.SpendRecord{
+		Source:  a.ledgerSource,
+		Shard:   shardID,
+		Epsilon: eps,
+		RDP:     encoded,
+	})
+	if err != nil {
+		return fmt.Errorf("record privacy spend: %w", err)
+	}
+	return nil
+}
+
+// decodeLedgerStep rebuilds a recorded step. Records without per-order steps
+// predate them and carried a scalar Epsilon, which replays as RecordStep
+// charged it.
+func (a *RDPAccountant) decodeLedgerStep(rec privacy.SpendRecord) ([]*big.Rat, *big.Rat, error) {
+	if len(rec.RDP) == 0 {
+		if rec.Epsilon < 0 || math.IsNaN(rec.Epsilon) || math.IsInf(rec.Epsilon, 0) {
+			return nil, nil, fmt.Errorf("invalid epsilon %v", rec.Epsilon)
+		}
+		eps := ratFromFloat64(rec.Epsilon)
+		return stepFromScalar(a.Orders, a.Alpha, eps), eps, nil
+	}
+	if len(rec.RDP) != len(a.Orders) {
+		return nil, nil, fmt.Errorf("step has %d orders, accountant tracks %d", len(rec.RDP), len(a.Orders))
+	}
+	step := make([]*big.Rat, len(rec.RDP))
+	atAlpha := (*big.Rat)(nil)
+	for i, raw := range rec.RDP {
+		if raw == "" {
+			continue
+		}
+		v, ok := new(big.Rat).SetString(raw)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid rdp value %q", raw)
+		}
+		step[i] = v
+		if a.Orders[i] == a.Alpha {
+			atAlpha = v
+		}
+	}
+	if atAlpha == nil {
+		atAlpha = ratFromFloat64(rec.Epsilon)
+	}
+	return step, atAlpha, nil
+}
+
+func (a *RDPAccountant) applyLocked(shardID string, step []*big.Rat, atAlpha *big.Rat) {
+	if shardID != "" {
+		a.recordShardLocked(shardID, step, atAlpha)
+		return
+	}
+	a.recordLocked(step, atAlpha)
+}
+
+// exportRemainingLocked publishes the remaining budget for the global curve,
+// or for shardID's curve when it is set.
+func (a *RDPAccountant) exportRemainingLocked(shardID string) {
+	curve, scope, id := a.curve, "global", a.ledgerSource
+	if shardID != "" {
+		curve, scope, id = a.shardCurves[shardID], "shard", shardID
+	}
+	if curve == nil {
+		return
+	}
+	eps, _ := curve.epsilon(a.Orders, a.TargetDelta)
+	metrics.ObservePrivacyBudgetRemaining(scope, id, a.remaining(eps))
+}
+
+func (a *RDPAccountant) remaining(eps float64) float64 {
+	if a.MaxBudget == nil || math.IsInf(eps, 1) {
+		return 0
+	}
+	limit, _ := a.MaxBudget.Float64()
+	return math.Max(0, limit-eps)
+}
+
+// RemainingBudget returns how much of MaxBudget the global curve has left.
+func (a *RDPAccountant) RemainingBudget() float64 {
+	eps := a.GetCurrentEpsilon()
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	return a.remaining(eps)
+}
 
-	if a.TotalEpsilon.Sign() == 0 {
-		return 0
+// ShardRemainingBudget returns how much of MaxBudget a shard's curve has left.
+func (a *RDPAccountant) ShardRemainingBudget(shardID string) float64 {
+	eps := a.GetShardEpsilon(shardID)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.remaining(eps)
+}
+
+func (a *RDPAccountant) recordLocked(step []*big.Rat, atAlpha *big.Rat) {
+	a.curve.add(step)
+	a.TotalEpsilon.Add(a.TotalEpsilon, atAlpha)
+}
+
+func (a *RDPAccountant) recordShardLocked(shardID string, step []*big.Rat, atAlpha *big.Rat) {
+	if _, ok := a.shardCurves[shardID]; !ok {
+		a.shardCurves[shardID] = newRDPCurve(len(a.Orders))
 	}
+	a.shardCurves[shardID].add(step)
+	if _, ok := a.ShardEpsilon[shardID]; !ok {
+		a.ShardEpsilon[shardID] = new(big.Rat)
+	}
+	a.ShardEpsilon[shardID].Add(a.ShardEpsilon[shardID], atAlpha)
+	a.recordLocked(step, atAlpha)
+}
 
-	conversion := math.Log(1.0/a.TargetDelta) / (a.Alpha - 1.0)
-	total, _ := a.TotalEpsilon.Float64()
-	return total + conversion
+// ComposedShardEpsilon converts the sequential composition of the listed
+// shards' curves to (ε, δ)-DP, for data that passes through every one of
+// them. Unknown shards contribute nothing. It also returns the order used.
+func (a *RDPAccountant) ComposedShardEpsilon(shardIDs ...string) (float64, float64) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	sum := newRDPCurve(len(a.Orders))
+	for _, id := range shardIDs {
+		if c, ok := a.shardCurves[id]; ok {
+			sum.compose(c)
+		}
+	}
+	return sum.epsilon(a.Orders, a.TargetDelta)
 }
 
-// GetCurrentEpsilonRat returns the current epsilon as a rational value derived
-// from the rational ledger plus the converted (epsilon, delta) term.
-func (a *RDPAccountant) GetCurrentEpsilonRat() *big.Rat {
+// GetCurrentEpsilon converts the cumulative RDP curve to standard (ε, δ)-DP
+// at the order that gives the smallest ε.
+func (a *RDPAccountant) GetCurrentEpsilon() float64 {
+	eps, _ := a.GetCurrentEpsilonWithOrder()
+	return eps
+}
+
+// GetCurrentEpsilonWithOrder returns the current (ε, δ) epsilon and the Rényi
+// order it was obtained at; the order is 0 before any step is recorded.
+func (a *RDPAccountant) GetCurrentEpsilonWithOrder() (float64, float64) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	if a.TotalEpsilon.Sign() == 0 {
-		return new(big.Rat)
+	if a.curve == nil {
+		return 0, 0
 	}
-	conversion := math.Log(1.0/a.TargetDelta) / (a.Alpha - 1.0)
-	current := new(big.Rat).Set(a.TotalEpsilon)
-	current.Add(current, ratFromFloat64(conversion))
-	return current
+	return a.curve.epsilon(a.Orders, a.TargetDelta)
+}
+
+// GetCurrentEpsilonRat returns the current epsilon as a rational value.
+func (a *RDPAccountant) GetCurrentEpsilonRat() *big.Rat {
+	return ratFromFloat64(a.GetCurrentEpsilon())
 }
 
 // MaxBudgetFloat returns the configured epsilon budget as float64 for callers
@@ -161,22 +424,29 @@ func (a *RDPAccountant) MaxBudgetFloat() float64 {
 // CheckBudget verifies if the system is still within the verified privacy bound.
 func (a *RDPAccountant) CheckBudget() error {
 	a.mu.RLock()
-	defer a.mu.RUnlock()
-
-	if a.TotalEpsilon.Sign() == 0 {
+	ledgerErr := a.ledgerErr
+	a.mu.RUnlock()
+	if ledgerErr != nil {
+		return fmt.Errorf("privacy ledger unavailable: %w", ledgerErr)
+	}
+	current, order := a.GetCurrentEpsilonWithOrder()
+	if current == 0 {
 		return nil
 	}
+	if !math.IsInf(current, 0) {
+		metrics.ObserveFormalRDPComposition("accountant", current)
+	}
 
-	conversion := math.Log(1.0/a.TargetDelta) / (a.Alpha - 1.0)
-	current := new(big.Rat).Set(a.TotalEpsilon)
-	current.Add(current, ratFromFloat64(conversion))
-	if currentFloat, ok := current.Float64(); ok {
-		metrics.ObserveFormalRDPComposition("accountant", currentFloat)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if math.IsInf(current, 1) {
+		return fmt.Errorf("privacy budget exhausted: no tracked Rényi order bounds the recorded steps")
 	}
-	if current.Cmp(a.MaxBudget) > 0 {
+	if ratFromFloat64(current).Cmp(a.MaxBudget) > 0 {
 		return fmt.Errorf(
-			"privacy budget exhausted: current ε=%s exceeds limit ε=%s",
-			current.RatString(),
+			"privacy budget exhausted: current ε=%.6f (α=%g) exceeds limit ε=%s",
+			current,
+			order,
 			a.MaxBudget.RatString(),
 		)
 	}
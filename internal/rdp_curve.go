@@ -0,0 +1,183 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Reference: /proofs/differential_privacy.md
+// RDP curves over a grid of orders, the Poisson-subsampled Gaussian bound and
+// the (ε, δ) conversion used by RDPAccountant.
+package internal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// DefaultRDPOrders is the grid of Rényi orders tracked by RDPAccountant. It
+// includes the legacy order 10 used by the TotalEpsilon ledger.
+var DefaultRDPOrders = []float64{
+	1.25, 1.5, 1.75, 2, 2.5, 3, 3.5, 4, 5, 6, 7, 8, 10, 12, 14, 16,
+	20, 24, 28, 32, 48, 64, 96, 128, 256,
+}
+
+// rdpCurve is a drift-free RDP ledger with one entry per order. An unbounded
+// order has absorbed a step that gave no guarantee there.
+type rdpCurve struct {
+	eps       []*big.Rat
+	unbounded []bool
+}
+
+func newRDPCurve(orders int) *rdpCurve {
+	c := &rdpCurve{eps: make([]*big.Rat, orders), unbounded: make([]bool, orders)}
+	for i := range c.eps {
+		c.eps[i] = new(big.Rat)
+	}
+	return c
+}
+
+// add composes one step; nil entries mark the order unbounded.
+func (c *rdpCurve) add(step []*big.Rat) {
+	for i, v := range step {
+		if v == nil {
+			c.unbounded[i] = true
+			continue
+		}
+		c.eps[i].Add(c.eps[i], v)
+	}
+}
+
+// compose adds every order of other into c.
+func (c *rdpCurve) compose(other *rdpCurve) {
+	for i, v := range other.eps {
+		c.eps[i].Add(c.eps[i], v)
+		c.unbounded[i] = c.unbounded[i] || other.unbounded[i]
+	}
+}
+
+func (c *rdpCurve) empty() bool {
+	for i, v := range c.eps {
+		if v.Sign() != 0 || c.unbounded[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// epsilon converts the curve to (ε, δ)-DP at the best order and returns the
+// order used. It returns +Inf when every order is unbounded.
+func (c *rdpCurve) epsilon(orders []float64, delta float64) (float64, float64) {
+	if c.empty() {
+		return 0, 0
+	}
+	best, bestOrder := math.Inf(1), 0.0
+	for i, alpha := range orders {
+		if c.unbounded[i] {
+			continue
+		}
+		rdp, _ := c.eps[i].Float64()
+		if eps := rdpToDP(alpha, rdp, delta); eps < best {
+			best, bestOrder = eps, alpha
+		}
+	}
+	return best, bestOrder
+}
+
+// rdpToDP applies the conversion of Canonne, Kamath and Steinke (2020,
+// Prop. 12): ε = ε_rdp + log((α-1)/α) - (log δ + log α)/(α-1).
+// It is never looser than the classic ε_rdp + log(1/δ)/(α-1).
+func rdpToDP(alpha, rdp, delta float64) float64 {
+	eps := rdp + math.Log1p(-1/alpha) - (math.Log(delta)+math.Log(alpha))/(alpha-1)
+	return math.Max(eps, 0)
+}
+
+// stepFromScalar turns an RDP guarantee known only at order alpha into a curve
+// step. RDP is non-decreasing in the order, so the value also bounds every
+// lower order; higher orders are left unbounded.
+func stepFromScalar(orders []float64, alpha float64, epsilon *big.Rat) []*big.Rat {
+	step := make([]*big.Rat, len(orders))
+	for i, order := range orders {
+		if order <= alpha {
+			step[i] = epsilon
+		}
+	}
+	return step
+}
+
+// subsampledGaussianStep returns the RDP curve of one Gaussian mechanism with
+// noise multiplier sigma applied to a Poisson sample with rate q.
+func subsampledGaussianStep(orders []float64, q, sigma float64) ([]*big.Rat, error) {
+	if sigma <= 0 || math.IsNaN(sigma) || math.IsInf(sigma, 0) {
+		return nil, fmt.Errorf("sigma must be positive")
+	}
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return nil, fmt.Errorf("sampling rate %v must lie in [0, 1]", q)
+	}
+	step := make([]*big.Rat, len(orders))
+	for i, alpha := range orders {
+		eps := subsampledGaussianRDP(alpha, q, sigma)
+		if math.IsInf(eps, 0) || math.IsNaN(eps) {
+			continue
+		}
+		step[i] = ratFromFloat64(eps)
+	}
+	return step, nil
+}
+
+// subsampledGaussianRDP computes ε(α) for the Poisson-subsampled Gaussian.
+// At q = 1 it is the Gaussian bound α/(2σ²). For q < 1 and integer α it is
+// the exact binomial expansion of Mironov, Talwar and Zhang (2019):
+//
+//	A_α = Σ_k C(α,k) (1-q)^(α-k) q^k exp((k²-k)/(2σ²)),  ε(α) = log A_α / (α-1)
+//
+// Fractional orders use the bound of the next integer order, which is valid
+// because ε(α) is non-decreasing in α.
+func subsampledGaussianRDP(alpha, q, sigma float64) float64 {
+	switch {
+	case q == 0:
+		return 0
+	case q == 1:
+		return alpha / (2 * sigma * sigma)
+	}
+	n := math.Ceil(alpha)
+	if n < 2 {
+		n = 2
+	}
+	logQ, log1mQ := math.Log(q), math.Log1p(-q)
+	logA := math.Inf(-1)
+	for k := 0.0; k <= n; k++ {
+		term := logBinomial(n, k) + (n-k)*log1mQ + k*logQ + (k*k-k)/(2*sigma*sigma)
+		logA = logAddExp(logA, term)
+	}
+	// Guard against tiny negative values from rounding near q·α ≈ 0.
+	return math.Max(logA/(n-1), 0)
+}
+
+func logBinomial(n, k float64) float64 {
+	a, _ := math.Lgamma(n + 1)
+	b, _ := math.Lgamma(k + 1)
+	c, _ := math.Lgamma(n - k + 1)
+	return a - b - c
+}
+
+func logAddExp(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSubsampledGaussianRDPMatchesClosedForms(t *testing.T) {
+	const sigma = 1.3
+	for _, alpha := range DefaultRDPOrders {
+		if got, want := subsampledGaussianRDP(alpha, 1, sigma), alpha/(2*sigma*sigma); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("q=1 alpha=%g: got %g, want %g", alpha, got, want)
+		}
+	}
+
+	// For α = 2 the expansion reduces to log(1 + q²(e^(1/σ²) - 1)).
+	q := 0.01
+	want := math.Log1p(q * q * math.Expm1(1/(sigma*sigma)))
+	if got := subsampledGaussianRDP(2, q, sigma); math.Abs(got-want) > 1e-15 {
+		t.Fatalf("alpha=2: got %g, want %g", got, want)
+	}
+	if subsampledGaussianRDP(1.5, q, sigma) != subsampledGaussianRDP(2, q, sigma) {
+		t.Fatal("fractional order should use the next integer order bound")
+	}
+	if subsampledGaussianRDP(32, q, sigma) >= 32/(2*sigma*sigma) {
+		t.Fatal("subsampling should amplify privacy")
+	}
+}
+
+func TestRDPToDPTighterThanClassicConversion(t *testing.T) {
+	for _, alpha := range DefaultRDPOrders {
+		classic := 1 + math.Log(1/1e-5)/(alpha-1)
+		if got := rdpToDP(alpha, 1, 1e-5); got > classic {
+			t.Fatalf("alpha=%g: conversion %g looser than classic %g", alpha, got, classic)
+		}
+	}
+}
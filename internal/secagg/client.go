@@ -0,0 +1,506 @@
+// Package secagg implements Bonawitz-style secure aggregation with pairwise
+// masking. Every pair of clients derives a shared mask via X25519 that cancels
+// in the sum, each client adds a private self-mask, and both secrets are
+// Shamir-shared so the server can unmask the sum when clients drop out. The
+// server only ever learns the aggregate of the surviving clients.
+//
+// Before revealing shares, survivors confirm the survivor set to each other
+// with pairwise MACs the server cannot forge. A client reveals only once
+// Threshold survivors confirmed the same set, and Threshold must exceed half
+// the roster, so a server cannot show some clients that a peer dropped and
+// others that it survived to collect both of its secrets.
+package secagg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	secretBytes    = 32
+	shareKeyInfo   = "mohawk/secagg/1 share encryption"
+	confirmKeyInfo = "mohawk/secagg/1 survivor confirmation"
+	maskStreamInfo = "mohawk/secagg/1 mask"
+)
+
+// Params are fixed for a round and must match on every client and the server.
+//
+// ClipNorm bounds each client's contribution: MaskInput scales larger
+// gradients down to it, since the server cannot see or clip masked inputs.
+// Differential privacy on the sum relies on this bound.
+type Params struct {
+	Threshold int     // shares needed to reconstruct a secret, and minimum survivors
+	Dim       int     // gradient length
+	Scale     float64 // fixed-point scale applied before masking
+	ClipNorm  float64 // ℓ₂ bound on each client's gradient
+}
+
+// Advertisement carries a client's public keys for the round.
+type Advertisement struct {
+	ClientID      string
+	EncPublicKey  []byte // X25519 key used to encrypt share bundles
+	MaskPublicKey []byte // X25519 key used to derive pairwise masks
+}
+
+// EncryptedShare is a share bundle from one client to another, relayed
+// (and unreadable) by the server.
+type EncryptedShare struct {
+	From       string
+	To         string
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// MaskedInput is a client's fixed-point gradient plus all of its masks.
+type MaskedInput struct {
+	ClientID string
+	Vector   []uint64
+}
+
+// SurvivorConfirmation is a survivor's MAC over the survivor set it was
+// sent, one tag per other survivor under their pairwise key.
+type SurvivorConfirmation struct {
+	ClientID string
+	Tags     map[string][]byte // recipient -> tag
+}
+
+// UnmaskResponse reveals, per peer, either the mask-key share (peer dropped)
+// or the self-seed share (peer survived) - never both.
+type UnmaskResponse struct {
+	ClientID       string
+	MaskKeyShares  map[string]Share
+	SelfSeedShares map[string]Share
+}
+
+type shareBundle struct {
+	MaskKey  Share `json:"mask_key"`
+	SelfSeed Share `json:"self_seed"`
+}
+
+// Client holds one participant's secrets for a single aggregation round.
+type Client struct {
+	id       string
+	params   Params
+	encKey   *ecdh.PrivateKey
+	maskKey  *ecdh.PrivateKey
+	selfSeed []byte
+
+	roster    map[string]Advertisement
+	received  map[string]shareBundle // from sender, collected in MaskInput
+	masked    bool
+	survivors []string // sorted, fixed by ConfirmSurvivors
+	revealed  bool
+}
+
+// NewClient generates fresh per-round keys and a self-mask seed.
+func NewClient(id string, params Params) (*Client, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("client id is required")
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	encKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate encryption key: %w", err)
+	}
+	maskKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate mask key: %w", err)
+	}
+	seed := make([]byte, secretBytes)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generate self-mask seed: %w", err)
+	}
+	return &Client{id: id, params: params, encKey: encKey, maskKey: maskKey, selfSeed: seed}, nil
+}
+
+func (p Params) validate() error {
+	if p.Threshold < 2 {
+		return fmt.Errorf("threshold must be at least 2")
+	}
+	if p.Dim <= 0 {
+		return fmt.Errorf("dimension must be positive")
+	}
+	if p.Scale <= 0 || math.IsNaN(p.Scale) || math.IsInf(p.Scale, 0) {
+		return fmt.Errorf("fixed-point scale must be positive and finite")
+	}
+	if p.ClipNorm <= 0 || math.IsNaN(p.ClipNorm) || math.IsInf(p.ClipNorm, 0) {
+		return fmt.Errorf("clip norm must be positive and finite")
+	}
+	if p.ClipNorm*p.Scale >= 1<<52 {
+		return fmt.Errorf("clip norm %v is not representable at scale %v", p.ClipNorm, p.Scale)
+	}
+	return nil
+}
+
+// ID returns the client identifier.
+func (c *Client) ID() string { return c.id }
+
+// Advertise publishes the client's public keys (round 0).
+func (c *Client) Advertise() Advertisement {
+	return Advertisement{ClientID: c.id, EncPublicKey: c.encKey.PublicKey().Bytes(), MaskPublicKey: c.maskKey.PublicKey().Bytes()}
+}
+
+// ShareKeys Shamir-shares the mask key and self-mask seed across the roster
+// and encrypts each bundle to its recipient (round 1).
+func (c *Client) ShareKeys(roster []Advertisement) ([]EncryptedShare, error) {
+	byID, ids, err := indexRoster(roster, c.params.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	if 2*c.params.Threshold <= len(ids) {
+		return nil, fmt.Errorf("threshold %d must exceed half the roster of %d", c.params.Threshold, len(ids))
+	}
+	if _, ok := byID[c.id]; !ok {
+		return nil, fmt.Errorf("client %q missing from roster", c.id)
+	}
+	c.roster = byID
+
+	xs := make([]int, len(ids))
+	for i := range ids {
+		xs[i] = i + 1
+	}
+	keyShares, err := splitSecret(c.maskKey.Bytes(), xs, c.params.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	seedShares, err := splitSecret(c.selfSeed, xs, c.params.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]EncryptedShare, 0, len(ids))
+	for i, to := range ids {
+		plain, err := json.Marshal(shareBundle{MaskKey: keyShares[i], SelfSeed: seedShares[i]})
+		if err != nil {
+			return nil, err
+		}
+		aead, err := c.shareAEAD(byID[to])
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("nonce: %w", err)
+		}
+		out = append(out, EncryptedShare{
+			From:       c.id,
+			To:         to,
+			Nonce:      nonce,
+			Ciphertext: aead.Seal(nil, nonce, plain, shareAAD(c.id, to)),
+		})
+	}
+	return out, nil
+}
+
+// MaskInput decrypts the share bundles addressed to this client and returns
+// the masked gradient (round 2), scaled down to Params.ClipNorm if it is
+// longer. Pairwise masks are added for every peer that completed ShareKeys,
+// i.e. every sender present in inbox.
+func (c *Client) MaskInput(gradient []float64, inbox []EncryptedShare) (MaskedInput, error) {
+	if c.roster == nil {
+		return MaskedInput{}, fmt.Errorf("ShareKeys must run before MaskInput")
+	}
+	if c.masked {
+		return MaskedInput{}, fmt.Errorf("client %q already submitted a masked input", c.id)
+	}
+	if len(gradient) != c.params.Dim {
+		return MaskedInput{}, fmt.Errorf("gradient length %d, want %d", len(gradient), c.params.Dim)
+	}
+
+	received := make(map[string]shareBundle, len(inbox))
+	for _, es := range inbox {
+		if es.To != c.id {
+			return MaskedInput{}, fmt.Errorf("share from %q addressed to %q", es.From, es.To)
+		}
+		ad, ok := c.roster[es.From]
+		if !ok {
+			return MaskedInput{}, fmt.Errorf("share from unknown client %q", es.From)
+		}
+		aead, err := c.shareAEAD(ad)
+		if err != nil {
+			return MaskedInput{}, err
+		}
+		plain, err := aead.Open(nil, es.Nonce, es.Ciphertext, shareAAD(es.From, es.To))
+		if err != nil {
+			return MaskedInput{}, fmt.Errorf("share from %q failed authentication", es.From)
+		}
+		var bundle shareBundle
+		if err := json.Unmarshal(plain, &bundle); err != nil {
+			return MaskedInput{}, fmt.Errorf("decode share from %q: %w", es.From, err)
+		}
+		received[es.From] = bundle
+	}
+	if _, ok := received[c.id]; !ok {
+		return MaskedInput{}, fmt.Errorf("inbox is missing the client's own share")
+	}
+	if len(received) < c.params.Threshold {
+		return MaskedInput{}, fmt.Errorf("only %d clients shared keys, threshold is %d", len(received), c.params.Threshold)
+	}
+	c.received = received
+
+	clipped, err := clipToNorm(gradient, c.params.ClipNorm)
+	if err != nil {
+		return MaskedInput{}, err
+	}
+	vec, err := encodeFixedPoint(clipped, c.params.Scale)
+	if err != nil {
+		return MaskedInput{}, err
+	}
+	addMask(vec, c.selfSeed, 1)
+	for peer := range received {
+		if peer == c.id {
+			continue
+		}
+		seed, err := pairwiseSeed(c.maskKey, c.roster[peer].MaskPublicKey)
+		if err != nil {
+			return MaskedInput{}, err
+		}
+		addMask(vec, seed, pairSign(c.id, peer))
+	}
+	c.masked = true
+	return MaskedInput{ClientID: c.id, Vector: vec}, nil
+}
+
+// ConfirmSurvivors fixes the survivor set the client will unmask for and
+// returns its confirmation for the other survivors (round 3). survivors are
+// the clients whose masked input reached the server; every other client that
+// shared keys is treated as dropped. It can be called once.
+func (c *Client) ConfirmSurvivors(survivors []string) (SurvivorConfirmation, error) {
+	if !c.masked {
+		return SurvivorConfirmation{}, fmt.Errorf("client %q has not submitted a masked input", c.id)
+	}
+	if c.survivors != nil {
+		return SurvivorConfirmation{}, fmt.Errorf("client %q already confirmed survivors", c.id)
+	}
+	alive := make(map[string]bool, len(survivors))
+	for _, s := range survivors {
+		if _, ok := c.received[s]; !ok {
+			return SurvivorConfirmation{}, fmt.Errorf("survivor %q never shared keys", s)
+		}
+		alive[s] = true
+	}
+	if !alive[c.id] {
+		return SurvivorConfirmation{}, fmt.Errorf("client %q is not in the survivor set", c.id)
+	}
+	if len(alive) < c.params.Threshold {
+		return SurvivorConfirmation{}, fmt.Errorf("only %d survivors, threshold is %d", len(alive), c.params.Threshold)
+	}
+	set := make([]string, 0, len(alive))
+	for s := range alive {
+		set = append(set, s)
+	}
+	sort.Strings(set)
+
+	conf := SurvivorConfirmation{ClientID: c.id, Tags: make(map[string][]byte, len(set)-1)}
+	for _, peer := range set {
+		if peer == c.id {
+			continue
+		}
+		tag, err := c.survivorTag(c.id, peer, set)
+		if err != nil {
+			return SurvivorConfirmation{}, err
+		}
+		conf.Tags[peer] = tag
+	}
+	c.survivors = set
+	return conf, nil
+}
+
+// Unmask reveals the shares the server needs to remove masks (round 4). It
+// requires confirmations of the client's survivor set from at least Threshold
+// survivors, counting itself.
+func (c *Client) Unmask(confirmations []SurvivorConfirmation) (UnmaskResponse, error) {
+	if c.survivors == nil {
+		return UnmaskResponse{}, fmt.Errorf("ConfirmSurvivors must run before Unmask")
+	}
+	if c.revealed {
+		return UnmaskResponse{}, fmt.Errorf("client %q already revealed shares", c.id)
+	}
+	alive := make(map[string]bool, len(c.survivors))
+	for _, s := range c.survivors {
+		alive[s] = true
+	}
+	confirmed := map[string]bool{c.id: true}
+	for _, conf := range confirmations {
+		if !alive[conf.ClientID] || confirmed[conf.ClientID] {
+			continue
+		}
+		want, err := c.survivorTag(conf.ClientID, c.id, c.survivors)
+		if err != nil {
+			return UnmaskResponse{}, err
+		}
+		if !hmac.Equal(conf.Tags[c.id], want) {
+			return UnmaskResponse{}, fmt.Errorf("client %q confirmed a different survivor set", conf.ClientID)
+		}
+		confirmed[conf.ClientID] = true
+	}
+	if len(confirmed) < c.params.Threshold {
+		return UnmaskResponse{}, fmt.Errorf("only %d survivors confirmed the survivor set, threshold is %d", len(confirmed), c.params.Threshold)
+	}
+
+	resp := UnmaskResponse{ClientID: c.id, MaskKeyShares: map[string]Share{}, SelfSeedShares: map[string]Share{}}
+	for peer, bundle := range c.received {
+		if alive[peer] {
+			resp.SelfSeedShares[peer] = bundle.SelfSeed
+		} else {
+			resp.MaskKeyShares[peer] = bundle.MaskKey
+		}
+	}
+	c.revealed = true
+	return resp, nil
+}
+
+// survivorTag is from's MAC to to over the sorted survivor set, keyed by
+// their encryption-key agreement.
+func (c *Client) survivorTag(from, to string, survivors []string) ([]byte, error) {
+	peer := from
+	if peer == c.id {
+		peer = to
+	}
+	key, err := c.pairKey(c.roster[peer], confirmKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(shareAAD(from, to))
+	for _, s := range survivors {
+		var n [2]byte
+		binary.BigEndian.PutUint16(n[:], uint16(len(s)))
+		mac.Write(n[:])
+		mac.Write([]byte(s))
+	}
+	return mac.Sum(nil), nil
+}
+
+func (c *Client) shareAEAD(peer Advertisement) (cipher.AEAD, error) {
+	key, err := c.pairKey(peer, shareKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pairKey derives a key shared with peer from their encryption keys
+func (c *Client) pairKey(peer Advertisement, info string) ([]byte, error) {
+	pub, err := ecdh.X25519().NewPublicKey(peer.EncPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key for %q: %w", peer.ClientID, err)
+	}
+	shared, err := c.encKey.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement with %q: %w", peer.ClientID, err)
+	}
+	return hkdf.Key(sha256.New, shared, nil, info, 32)
+}
+
+func shareAAD(from, to string) []byte {
+	aad := binary.BigEndian.AppendUint16(nil, uint16(len(from)))
+	aad = append(aad, from...)
+	return append(aad, to...)
+}
+
+// indexRoster validates and sorts the advertised clients; share X coordinates
+// are the 1-based positions in this order.
+func indexRoster(roster []Advertisement, threshold int) (map[string]Advertisement, []string, error) {
+	byID := make(map[string]Advertisement, len(roster))
+	for _, ad := range roster {
+		if _, dup := byID[ad.ClientID]; dup {
+			return nil, nil, fmt.Errorf("duplicate advertisement for %q", ad.ClientID)
+		}
+		if len(ad.EncPublicKey) != 32 || len(ad.MaskPublicKey) != 32 {
+			return nil, nil, fmt.Errorf("malformed keys for %q", ad.ClientID)
+		}
+		byID[ad.ClientID] = ad
+	}
+	if len(byID) < threshold {
+		return nil, nil, fmt.Errorf("roster has %d clients, threshold is %d", len(byID), threshold)
+	}
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return byID, ids, nil
+}
+
+func pairwiseSeed(own *ecdh.PrivateKey, peerPub []byte) ([]byte, error) {
+	pub, err := ecdh.X25519().NewPublicKey(peerPub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mask key: %w", err)
+	}
+	return own.ECDH(pub)
+}
+
+// pairSign is +1 for the lexicographically smaller id so pair masks cancel.
+func pairSign(self, peer string) int {
+	if self < peer {
+		return 1
+	}
+	return -1
+}
+
+// addMask adds sign*PRG(seed) to vec in Z_{2^64}.
+func addMask(vec []uint64, seed []byte, sign int) {
+	key := sha256.Sum256(append([]byte(maskStreamInfo), seed...))
+	block, _ := aes.NewCipher(key[:])
+	stream := make([]byte, 8*len(vec))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(stream, stream)
+	for i := range vec {
+		m := binary.BigEndian.Uint64(stream[8*i:])
+		if sign > 0 {
+			vec[i] += m
+		} else {
+			vec[i] -= m
+		}
+	}
+}
+
+// clipToNorm returns values scaled to ℓ₂ norm maxNorm if they exceed it
+func clipToNorm(values []float64, maxNorm float64) ([]float64, error) {
+	var norm float64
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("value %d (%v) is not finite", i, v)
+		}
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	out := append([]float64(nil), values...)
+	if norm > maxNorm {
+		scale := maxNorm / norm
+		for i := range out {
+			out[i] *= scale
+		}
+	}
+	return out, nil
+}
+
+func encodeFixedPoint(values []float64, scale float64) ([]uint64, error) {
+	out := make([]uint64, len(values))
+	for i, v := range values {
+		scaled := math.Round(v * scale)
+		if math.IsNaN(scaled) || math.IsInf(scaled, 0) || math.Abs(scaled) >= 1<<52 {
+			return nil, fmt.Errorf("value %d (%v) not representable at scale %v", i, v, scale)
+		}
+		out[i] = uint64(int64(scaled))
+	}
+	return out, nil
+}
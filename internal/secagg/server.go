@@ -0,0 +1,277 @@
+package secagg
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Sum is the unmasked aggregate of the surviving clients.
+type Sum struct {
+	Vector       []float64
+	Contributors []string
+	Dropped      []string
+	ClipNorm     float64 // ℓ₂ bound each contributor's gradient was clipped to
+}
+
+// Server relays shares and unmasks the sum. It never sees an individual
+// client's unmasked gradient.
+type Server struct {
+	params Params
+
+	mu        sync.Mutex
+	roster    map[string]Advertisement
+	inboxes   map[string][]EncryptedShare
+	sharers   map[string]bool
+	inputs    map[string][]uint64
+	confirms  map[string]SurvivorConfirmation
+	responses map[string]UnmaskResponse
+	closed    bool
+}
+
+// NewServer creates the aggregator side of one round.
+func NewServer(params Params) (*Server, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &Server{
+		params:    params,
+		roster:    map[string]Advertisement{},
+		inboxes:   map[string][]EncryptedShare{},
+		sharers:   map[string]bool{},
+		inputs:    map[string][]uint64{},
+		confirms:  map[string]SurvivorConfirmation{},
+		responses: map[string]UnmaskResponse{},
+	}, nil
+}
+
+// Advertise registers a client's public keys.
+func (s *Server) Advertise(ad Advertisement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sharers) > 0 {
+		return fmt.Errorf("advertisement phase closed")
+	}
+	if _, dup := s.roster[ad.ClientID]; dup {
+		return fmt.Errorf("duplicate advertisement for %q", ad.ClientID)
+	}
+	s.roster[ad.ClientID] = ad
+	return nil
+}
+
+// Roster returns the advertisements every client needs for ShareKeys.
+func (s *Server) Roster() []Advertisement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Advertisement, 0, len(s.roster))
+	for _, ad := range s.roster {
+		out = append(out, ad)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClientID < out[j].ClientID })
+	return out
+}
+
+// SubmitShares routes one client's encrypted bundles to their recipients.
+func (s *Server) SubmitShares(from string, shares []EncryptedShare) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.roster[from]; !ok {
+		return fmt.Errorf("unknown client %q", from)
+	}
+	if len(s.inputs) > 0 {
+		return fmt.Errorf("key sharing phase closed")
+	}
+	if s.sharers[from] {
+		return fmt.Errorf("client %q already shared keys", from)
+	}
+	if len(shares) != len(s.roster) {
+		return fmt.Errorf("client %q sent %d shares for a roster of %d", from, len(shares), len(s.roster))
+	}
+	for _, es := range shares {
+		if es.From != from {
+			return fmt.Errorf("share from %q submitted by %q", es.From, from)
+		}
+		if _, ok := s.roster[es.To]; !ok {
+			return fmt.Errorf("share addressed to unknown client %q", es.To)
+		}
+	}
+	for _, es := range shares {
+		s.inboxes[es.To] = append(s.inboxes[es.To], es)
+	}
+	s.sharers[from] = true
+	return nil
+}
+
+// Inbox returns the bundles addressed to clientID from clients that shared
+// keys. Clients fetch it once the sharing phase has ended.
+func (s *Server) Inbox(clientID string) []EncryptedShare {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EncryptedShare(nil), s.inboxes[clientID]...)
+}
+
+// SubmitMaskedInput records a masked gradient.
+func (s *Server) SubmitMaskedInput(in MaskedInput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.confirms) > 0 || len(s.responses) > 0 {
+		return fmt.Errorf("masked input phase closed")
+	}
+	if !s.sharers[in.ClientID] {
+		return fmt.Errorf("client %q did not share keys", in.ClientID)
+	}
+	if _, dup := s.inputs[in.ClientID]; dup {
+		return fmt.Errorf("client %q already submitted an input", in.ClientID)
+	}
+	if len(in.Vector) != s.params.Dim {
+		return fmt.Errorf("masked input length %d, want %d", len(in.Vector), s.params.Dim)
+	}
+	s.inputs[in.ClientID] = append([]uint64(nil), in.Vector...)
+	return nil
+}
+
+// Survivors lists clients whose masked input arrived; it is sent to every
+// survivor to confirm.
+func (s *Server) Survivors() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.inputs))
+	for id := range s.inputs {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SubmitConfirmation records a survivor's confirmation of the survivor set.
+func (s *Server) SubmitConfirmation(conf SurvivorConfirmation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inputs[conf.ClientID]; !ok {
+		return fmt.Errorf("client %q is not a survivor", conf.ClientID)
+	}
+	if _, dup := s.confirms[conf.ClientID]; dup {
+		return fmt.Errorf("client %q already confirmed survivors", conf.ClientID)
+	}
+	s.confirms[conf.ClientID] = conf
+	return nil
+}
+
+// Confirmations returns the survivor confirmations every survivor checks
+// before unmasking.
+func (s *Server) Confirmations() []SurvivorConfirmation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]SurvivorConfirmation, 0, len(s.confirms))
+	for _, conf := range s.confirms {
+		out = append(out, conf)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClientID < out[j].ClientID })
+	return out
+}
+
+// SubmitUnmask records a survivor's revealed shares.
+func (s *Server) SubmitUnmask(resp UnmaskResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inputs[resp.ClientID]; !ok {
+		return fmt.Errorf("client %q is not a survivor", resp.ClientID)
+	}
+	for peer := range resp.MaskKeyShares {
+		if _, alive := s.inputs[peer]; alive {
+			return fmt.Errorf("client %q revealed a mask-key share of survivor %q", resp.ClientID, peer)
+		}
+	}
+	s.responses[resp.ClientID] = resp
+	return nil
+}
+
+// Finalize removes the self masks of survivors and the dangling pairwise masks
+// of dropped clients, returning the decoded sum.
+func (s *Server) Finalize() (Sum, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return Sum{}, fmt.Errorf("round already finalized")
+	}
+	t := s.params.Threshold
+	if len(s.inputs) < t {
+		return Sum{}, fmt.Errorf("only %d masked inputs, threshold is %d", len(s.inputs), t)
+	}
+	if len(s.responses) < t {
+		return Sum{}, fmt.Errorf("only %d unmask responses, threshold is %d", len(s.responses), t)
+	}
+	// Clipped inputs bound every coordinate, so the sum decodes unless this
+	// many of them could wrap Z_{2^64}
+	if float64(len(s.inputs))*s.params.ClipNorm*s.params.Scale >= 1<<63 {
+		return Sum{}, fmt.Errorf("sum of %d inputs may overflow the fixed-point encoding", len(s.inputs))
+	}
+
+	sum := make([]uint64, s.params.Dim)
+	survivors := make([]string, 0, len(s.inputs))
+	for id, vec := range s.inputs {
+		survivors = append(survivors, id)
+		for i, v := range vec {
+			sum[i] += v
+		}
+	}
+	sort.Strings(survivors)
+
+	for _, v := range survivors {
+		var shares []Share
+		for _, r := range s.responses {
+			if sh, ok := r.SelfSeedShares[v]; ok {
+				shares = append(shares, sh)
+			}
+		}
+		seed, err := combineShares(shares, t, secretBytes)
+		if err != nil {
+			return Sum{}, fmt.Errorf("reconstruct self mask of %q: %w", v, err)
+		}
+		addMask(sum, seed, -1)
+	}
+
+	var dropped []string
+	for u := range s.sharers {
+		if _, alive := s.inputs[u]; !alive {
+			dropped = append(dropped, u)
+		}
+	}
+	sort.Strings(dropped)
+	for _, u := range dropped {
+		var shares []Share
+		for _, r := range s.responses {
+			if sh, ok := r.MaskKeyShares[u]; ok {
+				shares = append(shares, sh)
+			}
+		}
+		raw, err := combineShares(shares, t, secretBytes)
+		if err != nil {
+			return Sum{}, fmt.Errorf("reconstruct mask key of dropped %q: %w", u, err)
+		}
+		key, err := ecdh.X25519().NewPrivateKey(raw)
+		if err != nil {
+			return Sum{}, fmt.Errorf("reconstructed mask key of %q invalid: %w", u, err)
+		}
+		if !bytes.Equal(key.PublicKey().Bytes(), s.roster[u].MaskPublicKey) {
+			return Sum{}, fmt.Errorf("reconstructed mask key of %q does not match its advertisement", u)
+		}
+		for _, v := range survivors {
+			seed, err := pairwiseSeed(key, s.roster[v].MaskPublicKey)
+			if err != nil {
+				return Sum{}, err
+			}
+			// survivor v added pairSign(v,u)*PRG(s_uv); remove it
+			addMask(sum, seed, -pairSign(v, u))
+		}
+	}
+
+	out := make([]float64, len(sum))
+	for i, v := range sum {
+		out[i] = float64(int64(v)) / s.params.Scale
+	}
+	s.closed = true
+	return Sum{Vector: out, Contributors: survivors, Dropped: dropped, ClipNorm: s.params.ClipNorm}, nil
+}
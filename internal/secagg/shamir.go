@@ -0,0 +1,92 @@
+package secagg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// fieldPrime is the Mersenne prime 2^521-1; every 32-byte secret fits below it.
+var fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 521), big.NewInt(1))
+
+// Share is one Shamir evaluation (X, f(X)) of a secret polynomial.
+type Share struct {
+	X int
+	Y []byte
+}
+
+// splitSecret shares secret among xs so that any threshold of them recover it.
+func splitSecret(secret []byte, xs []int, threshold int) ([]Share, error) {
+	if threshold <= 0 || threshold > len(xs) {
+		return nil, fmt.Errorf("threshold %d outside [1,%d]", threshold, len(xs))
+	}
+	coeffs := make([]*big.Int, threshold)
+	coeffs[0] = new(big.Int).SetBytes(secret)
+	for i := 1; i < threshold; i++ {
+		c, err := rand.Int(rand.Reader, fieldPrime)
+		if err != nil {
+			return nil, fmt.Errorf("sample polynomial: %w", err)
+		}
+		coeffs[i] = c
+	}
+	shares := make([]Share, len(xs))
+	for i, x := range xs {
+		bx := big.NewInt(int64(x))
+		acc := new(big.Int)
+		for k := threshold - 1; k >= 0; k-- {
+			acc.Mul(acc, bx)
+			acc.Add(acc, coeffs[k])
+			acc.Mod(acc, fieldPrime)
+		}
+		shares[i] = Share{X: x, Y: acc.Bytes()}
+	}
+	return shares, nil
+}
+
+// combineShares interpolates f(0) from at least threshold distinct shares and
+// returns it as a size-byte big-endian value.
+func combineShares(shares []Share, threshold, size int) ([]byte, error) {
+	seen := map[int]struct{}{}
+	var pts []Share
+	for _, s := range shares {
+		if s.X <= 0 {
+			return nil, fmt.Errorf("invalid share index %d", s.X)
+		}
+		if _, ok := seen[s.X]; ok {
+			continue
+		}
+		seen[s.X] = struct{}{}
+		pts = append(pts, s)
+		if len(pts) == threshold {
+			break
+		}
+	}
+	if len(pts) < threshold {
+		return nil, fmt.Errorf("have %d of %d shares", len(pts), threshold)
+	}
+
+	secret := new(big.Int)
+	for i, pi := range pts {
+		num := big.NewInt(1)
+		den := big.NewInt(1)
+		for j, pj := range pts {
+			if i == j {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(-pj.X)))
+			den.Mul(den, big.NewInt(int64(pi.X-pj.X)))
+		}
+		num.Mod(num, fieldPrime)
+		den.Mod(den, fieldPrime)
+		den.ModInverse(den, fieldPrime)
+		term := new(big.Int).SetBytes(pi.Y)
+		term.Mul(term, num)
+		term.Mul(term, den)
+		secret.Add(secret, term)
+		secret.Mod(secret, fieldPrime)
+	}
+	if (secret.BitLen()+7)/8 > size {
+		return nil, fmt.Errorf("reconstructed secret exceeds %d bytes", size)
+	}
+	return secret.FillBytes(make([]byte, size)), nil
+}
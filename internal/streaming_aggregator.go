@@ -5,22 +5,77 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/cluster"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/metrics"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/transport"
 )
 
-// ChunkAssembly buffers gradient chunks until complete
+// Chunk rejection reasons. Each rejection is counted against the chunk's
+// NodeID; only rejections of signature-verified tensors affect reputation.
+var (
+	ErrChunkOutOfRange    = errors.New("chunk index or total out of range")
+	ErrChunkTotalMismatch = errors.New("chunk total disagrees with tensor")
+	ErrChunkNodeMismatch  = errors.New("chunk node disagrees with tensor")
+	ErrChunkDuplicate     = errors.New("conflicting chunk for an index already received")
+	ErrChunkHashMismatch  = errors.New("chunk hash does not match payload")
+	ErrChunkUnsigned      = errors.New("chunk is missing its hash or tensor signature")
+	ErrChunkUnknownNode   = errors.New("chunk node has no registered key")
+	ErrTensorSignature    = errors.New("tensor signature verification failed")
+	ErrTensorDimension    = errors.New("tensor dimension disagrees with round majority")
+	ErrNodeUnhealthy      = errors.New("node is marked unhealthy by the topology")
+	ErrTensorReplayed     = errors.New("tensor was already consumed by an earlier round")
+)
+
+var chunkRejectionReasons = map[error]string{
+	ErrChunkOutOfRange:    "out_of_range",
+	ErrChunkTotalMismatch: "total_mismatch",
+	ErrChunkNodeMismatch:  "node_mismatch",
+	ErrChunkDuplicate:     "duplicate",
+	ErrChunkHashMismatch:  "hash_mismatch",
+	ErrChunkUnsigned:      "unsigned",
+	ErrChunkUnknownNode:   "unknown_node",
+	ErrTensorSignature:    "bad_signature",
+	ErrTensorDimension:    "dimension_mismatch",
+	ErrTensorReplayed:     "replayed",
+}
+
+const (
+	// defaultRejectionPenaltyThreshold is the count of rejected
+	// signature-verified tensors from which a node's reputation is penalized
+	// on every further one.
+	defaultRejectionPenaltyThreshold = 3
+	// rejectionReputationPenalty is subtracted from a repeat offender's reputation.
+	rejectionReputationPenalty = 0.1
+)
+
+// ChunkAssembly buffers gradient chunks until complete. When signatures are
+// required each signing of a tensor assembles separately, so chunks carrying
+// a forged proof cannot displace the genuine ones.
 type ChunkAssembly struct {
 	chunks    map[int]transport.GradientChunk
 	total     int
 	created   time.Time
 	assembled time.Time
+	tensorID  string
 	nodeID    string
+	proof     []byte
+	// pending marks a complete tensor awaiting batched signature verification;
+	// ready marks one that may be aggregated, and verified one whose
+	// signature checked out against its node's key
+	pending  bool
+	ready    bool
+	verified bool
 }
 
 // StreamingAggregatorOptions preserves the older tunable constructor surface.
@@ -29,13 +84,34 @@ type StreamingAggregatorOptions struct {
 	TensorTimeoutSec   float64
 	CheckpointInterval time.Duration
 	EnableByzantine    bool
+	// NodeKeys, when non-empty, requires every chunk to carry a valid Hash and
+	// every tensor a valid signature from its node's key.
+	NodeKeys map[string]ed25519.PublicKey
+	// Topology, when set, receives reputation penalties for repeat offenders,
+	// and tensors from nodes it marks unhealthy are dropped and refused.
+	Topology *cluster.Topology
+	// RejectionPenaltyThreshold is the per-node count of rejected
+	// signature-verified tensors at which penalties start (default 3).
+	// Other rejections are counted but never penalized, as anyone can send
+	// a chunk claiming a node's ID.
+	RejectionPenaltyThreshold int
+	// QuorumSize is the number of ready tensors a flush needs (0 = tier default).
+	QuorumSize int
+	// Sinks receive every flushed aggregate.
+	Sinks []AggregateSink
+	// DPSigma, when positive, clips each tensor to DPClipNorm (default 1) and
+	// adds Gaussian noise to every aggregate, charging a subsampled Gaussian
+	// step at DPSamplingRate (default 1) per flush.
+	DPSigma        float64
+	DPClipNorm     float64
+	DPSamplingRate float64
 }
 
 // StreamingAggregator accepts unordered chunks instead of full tensors
 type StreamingAggregator struct {
 	tier                Tier
 	trans               transport.Transport
-	chunkBuffers        map[string]*ChunkAssembly // nodeID -> assembly
+	chunkBuffers        map[string]*ChunkAssembly // assemblyKey -> assembly
 	mu                  sync.RWMutex
 	batchTimeout        time.Duration
 	quorumSize          int
@@ -45,8 +121,28 @@ type StreamingAggregator struct {
 	tensorTimeout       time.Duration
 	enableByzantine     bool
 	totalChunksIngested int64
+	totalChunksRejected int64
 	totalGradients      int64
 	totalTensorsReady   int64
+
+	// Integrity
+	nodeKeys         map[string]ed25519.PublicKey
+	verifier         *BatchVerifier
+	topology         *cluster.Topology
+	rejections       map[string]int64 // nodeID -> rejected chunks and tensors
+	offenses         map[string]int64 // nodeID -> rejected signature-verified tensors
+	penaltyThreshold int
+	// consumed records the signed tensors each node has had flushed, since
+	// the signature does not bind a round and a captured tensor could
+	// otherwise be replayed into later rounds
+	consumed map[string]map[string]struct{} // nodeID -> tensor IDs
+
+	// Output
+	sinks          []AggregateSink
+	rounds         uint64
+	dpSigma        float64
+	dpClipNorm     float64
+	dpSamplingRate float64
 }
 
 // NewStreamingAggregator creates a streaming aggregator
@@ -67,31 +163,127 @@ func NewStreamingAggregator(t Tier, trans transport.Transport, opts ...Streaming
 		}
 	}
 
-	return &StreamingAggregator{
+	penaltyThreshold := config.RejectionPenaltyThreshold
+	if penaltyThreshold <= 0 {
+		penaltyThreshold = defaultRejectionPenaltyThreshold
+	}
+	quorumSize := config.QuorumSize
+	if quorumSize <= 0 {
+		quorumSize = getTierQuorum(t)
+	}
+	dpClipNorm := config.DPClipNorm
+	if dpClipNorm <= 0 {
+		dpClipNorm = 1.0
+	}
+	dpSamplingRate := config.DPSamplingRate
+	if dpSamplingRate <= 0 || dpSamplingRate > 1 {
+		dpSamplingRate = 1.0
+	}
+	nodeKeys := make(map[string]ed25519.PublicKey, len(config.NodeKeys))
+	for nodeID, key := range config.NodeKeys {
+		nodeKeys[nodeID] = key
+	}
+
+	a := &StreamingAggregator{
 		tier:               t,
 		trans:              trans,
 		chunkBuffers:       make(map[string]*ChunkAssembly),
 		batchTimeout:       config.CheckpointInterval,
-		quorumSize:         getTierQuorum(t),
+		quorumSize:         quorumSize,
 		accountant:         NewRDPAccountant(2.0, 1e-7),
 		liveness:           NewStragglerMonitor(),
 		maxBufferedTensors: config.MaxBufferedTensors,
 		tensorTimeout:      time.Duration(config.TensorTimeoutSec * float64(time.Second)),
 		enableByzantine:    config.EnableByzantine,
+		nodeKeys:           nodeKeys,
+		verifier:           NewBatchVerifier(0),
+		topology:           config.Topology,
+		rejections:         make(map[string]int64),
+		offenses:           make(map[string]int64),
+		penaltyThreshold:   penaltyThreshold,
+		consumed:           make(map[string]map[string]struct{}),
+		sinks:              append([]AggregateSink(nil), config.Sinks...),
+		dpSigma:            config.DPSigma,
+		dpClipNorm:         dpClipNorm,
+		dpSamplingRate:     dpSamplingRate,
+	}
+	if a.topology != nil {
+		a.topology.OnChange(a.handleTopologyEvent)
+	}
+	return a
+}
+
+// handleTopologyEvent drops buffered tensors from a node that turned unhealthy
+func (a *StreamingAggregator) handleTopologyEvent(ev cluster.TopologyEvent) {
+	if ev.Kind != cluster.NodeUnhealthy {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, assembly := range a.chunkBuffers {
+		if assembly.nodeID == ev.NodeID {
+			delete(a.chunkBuffers, key)
+		}
 	}
 }
 
-// IngestChunk is the hot path - non-blocking chunk ingestion
+// AddSink registers a sink for every later flushed aggregate.
+func (a *StreamingAggregator) AddSink(sink AggregateSink) {
+	if sink == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sinks = append(a.sinks, sink)
+}
+
+// RegisterNodeKey adds a node's Ed25519 key. Once any key is registered,
+// chunks must carry a Hash and a tensor signature, and unknown nodes are refused.
+func (a *StreamingAggregator) RegisterNodeKey(nodeID string, key ed25519.PublicKey) error {
+	if nodeID == "" || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("node id and a %d-byte ed25519 public key are required", ed25519.PublicKeySize)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.nodeKeys[nodeID] = key
+	return nil
+}
+
+// RejectedChunks returns how many chunks and tensors from nodeID were rejected.
+func (a *StreamingAggregator) RejectedChunks(nodeID string) int64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.rejections[nodeID]
+}
+
+// IngestChunk is the hot path - non-blocking chunk ingestion. Chunks with an
+// out-of-range index, a Total or NodeID that disagrees with the tensor, a
+// conflicting duplicate index or a wrong Hash are rejected. An exact
+// redelivery of a chunk already held is ignored. Chunks from nodes the
+// topology marks unhealthy are refused.
 func (a *StreamingAggregator) IngestChunk(chunk transport.GradientChunk) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	a.totalChunksIngested++
 
-	bufferKey := chunk.ID
-	if bufferKey == "" {
-		bufferKey = chunk.NodeID
+	tensorID := chunk.ID
+	if tensorID == "" {
+		tensorID = chunk.NodeID
+	}
+
+	// Not counted as a rejection: the node already lost its standing
+	if a.topology != nil && !a.topology.IsHealthy(chunk.NodeID) {
+		return fmt.Errorf("chunk for tensor %s from %s refused: %w", tensorID, chunk.NodeID, ErrNodeUnhealthy)
+	}
+
+	if err := a.verifyChunkLocked(chunk); err != nil {
+		return a.rejectLocked(chunk.NodeID, tensorID, err)
+	}
+	if _, ok := a.consumed[chunk.NodeID][tensorID]; ok {
+		return a.rejectLocked(chunk.NodeID, tensorID, ErrTensorReplayed)
 	}
+	bufferKey := a.assemblyKeyLocked(tensorID, chunk.Proof)
 
 	if a.maxBufferedTensors > 0 {
 		_, exists := a.chunkBuffers[bufferKey]
@@ -103,27 +295,207 @@ func (a *StreamingAggregator) IngestChunk(chunk transport.GradientChunk) error {
 	// Create assembly buffer if needed
 	if a.chunkBuffers[bufferKey] == nil {
 		a.chunkBuffers[bufferKey] = &ChunkAssembly{
-			chunks:  make(map[int]transport.GradientChunk),
-			total:   chunk.Total,
-			created: time.Now(),
-			nodeID:  chunk.NodeID,
+			chunks:   make(map[int]transport.GradientChunk),
+			total:    chunk.Total,
+			created:  time.Now(),
+			tensorID: tensorID,
+			nodeID:   chunk.NodeID,
+			proof:    chunk.Proof,
 		}
 	}
 
 	assembly := a.chunkBuffers[bufferKey]
+	if err := a.checkAssemblyLocked(assembly, chunk); err != nil {
+		if errors.Is(err, errChunkRedelivered) {
+			return nil
+		}
+		return a.rejectLocked(chunk.NodeID, tensorID, err)
+	}
 	assembly.chunks[chunk.Index] = chunk
 
 	// Check if tensor is complete
 	if len(assembly.chunks) == assembly.total {
-		a.totalGradients++
-		a.totalTensorsReady++
 		assembly.assembled = time.Now()
-		// In production: trigger aggregation here
+		if a.signaturesRequiredLocked() {
+			// Signatures are checked in batches by VerifyPendingTensors
+			assembly.pending = true
+		} else {
+			a.markReadyLocked(assembly)
+		}
+	}
+
+	return nil
+}
+
+// errChunkRedelivered marks an exact duplicate, as multi-path transports may deliver
+var errChunkRedelivered = errors.New("chunk already received")
+
+func (a *StreamingAggregator) signaturesRequiredLocked() bool {
+	return len(a.nodeKeys) > 0
+}
+
+// assemblyKeyLocked names the buffer a chunk joins: its tensor, and when
+// signatures are required also the proof it carries
+func (a *StreamingAggregator) assemblyKeyLocked(tensorID string, proof []byte) string {
+	if !a.signaturesRequiredLocked() {
+		return tensorID
+	}
+	return tensorID + "\x00" + string(proof)
+}
+
+// verifyChunkLocked checks what a chunk says about itself: its position, its
+// hash and, when signatures are required, that it is signed by a known node.
+func (a *StreamingAggregator) verifyChunkLocked(chunk transport.GradientChunk) error {
+	if chunk.Total <= 0 || chunk.Index < 0 || chunk.Index >= chunk.Total {
+		return fmt.Errorf("%w: index=%d total=%d", ErrChunkOutOfRange, chunk.Index, chunk.Total)
+	}
+	if a.signaturesRequiredLocked() {
+		if _, ok := a.nodeKeys[chunk.NodeID]; !ok {
+			return fmt.Errorf("%w: %q", ErrChunkUnknownNode, chunk.NodeID)
+		}
+		if len(chunk.Hash) == 0 || len(chunk.Proof) != ed25519.SignatureSize {
+			return ErrChunkUnsigned
+		}
 	}
+	if len(chunk.Hash) > 0 && !bytes.Equal(chunk.Hash, transport.ChunkHash(chunk)) {
+		return fmt.Errorf("%w: index %d", ErrChunkHashMismatch, chunk.Index)
+	}
+	return nil
+}
 
+// checkAssemblyLocked checks a chunk against the tensor it joins.
+func (a *StreamingAggregator) checkAssemblyLocked(assembly *ChunkAssembly, chunk transport.GradientChunk) error {
+	if chunk.Total != assembly.total {
+		return fmt.Errorf("%w: got %d, tensor has %d", ErrChunkTotalMismatch, chunk.Total, assembly.total)
+	}
+	if chunk.NodeID != assembly.nodeID {
+		return fmt.Errorf("%w: got %q, tensor is from %q", ErrChunkNodeMismatch, chunk.NodeID, assembly.nodeID)
+	}
+	if prev, ok := assembly.chunks[chunk.Index]; ok {
+		if samePayload(prev, chunk) {
+			return errChunkRedelivered
+		}
+		return fmt.Errorf("%w: index %d", ErrChunkDuplicate, chunk.Index)
+	}
 	return nil
 }
 
+func samePayload(a, b transport.GradientChunk) bool {
+	if len(a.Hash) > 0 && len(b.Hash) > 0 {
+		return bytes.Equal(a.Hash, b.Hash)
+	}
+	if len(a.Payload) != len(b.Payload) {
+		return false
+	}
+	for i := range a.Payload {
+		if math.Float32bits(a.Payload[i]) != math.Float32bits(b.Payload[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *StreamingAggregator) markReadyLocked(assembly *ChunkAssembly) {
+	assembly.pending = false
+	assembly.ready = true
+	a.totalGradients++
+	a.totalTensorsReady++
+}
+
+// rejectLocked counts a rejection against nodeID and returns err.
+// Attribution uses the NodeID the chunk claims, which is not authenticated,
+// so the node's reputation is left alone.
+func (a *StreamingAggregator) rejectLocked(nodeID, tensorID string, err error) error {
+	reason := "unknown"
+	for sentinel, label := range chunkRejectionReasons {
+		if errors.Is(err, sentinel) {
+			reason = label
+			break
+		}
+	}
+	a.totalChunksRejected++
+	a.rejections[nodeID]++
+	metrics.ObserveStreamingChunkRejection(reason)
+	return fmt.Errorf("chunk for tensor %s from %s rejected: %w", tensorID, nodeID, err)
+}
+
+// rejectVerifiedLocked rejects a tensor whose signature verified against
+// nodeID's registered key, so the node is known to have sent it, and
+// penalizes repeat offenders in the topology.
+func (a *StreamingAggregator) rejectVerifiedLocked(nodeID, tensorID string, err error) error {
+	err = a.rejectLocked(nodeID, tensorID, err)
+	a.offenses[nodeID]++
+	if a.topology != nil && a.offenses[nodeID] >= int64(a.penaltyThreshold) {
+		a.topology.UpdateReputation(nodeID, -rejectionReputationPenalty)
+	}
+	return err
+}
+
+// VerifyPendingTensors checks the signatures of every complete tensor awaiting
+// verification in one BatchVerifier pass. Tensors that verify become ready for
+// aggregation, and any other signing of the same tensor is dropped; the rest
+// are dropped and counted against their node. It returns the number of
+// tensors verified.
+func (a *StreamingAggregator) VerifyPendingTensors() int {
+	a.mu.Lock()
+	var keys []string
+	var pubKeys []ed25519.PublicKey
+	var messages, signatures [][]byte
+	for key, assembly := range a.chunkBuffers {
+		if !assembly.pending {
+			continue
+		}
+		hashes := make([][]byte, assembly.total)
+		for i := range hashes {
+			hashes[i] = assembly.chunks[i].Hash
+		}
+		keys = append(keys, key)
+		pubKeys = append(pubKeys, a.nodeKeys[assembly.nodeID])
+		messages = append(messages, transport.TensorDigest(assembly.tensorID, assembly.nodeID, hashes))
+		signatures = append(signatures, assembly.proof)
+	}
+	a.mu.Unlock()
+	if len(keys) == 0 {
+		return 0
+	}
+
+	results, err := a.verifier.VerifySignatures(pubKeys, messages, signatures)
+	if err != nil {
+		log.Printf("WARNING: tensor signature batch failed: %v", err)
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	verified := 0
+	for i, key := range keys {
+		assembly, ok := a.chunkBuffers[key]
+		if !ok || !assembly.pending {
+			continue
+		}
+		if results[i] {
+			a.markReadyLocked(assembly)
+			assembly.verified = true
+			verified++
+			a.dropOtherSigningsLocked(key, assembly)
+			continue
+		}
+		delete(a.chunkBuffers, key)
+		_ = a.rejectLocked(assembly.nodeID, assembly.tensorID, ErrTensorSignature)
+	}
+	return verified
+}
+
+// dropOtherSigningsLocked discards the other assemblies of a verified tensor;
+// they carry proofs that can no longer be used.
+func (a *StreamingAggregator) dropOtherSigningsLocked(key string, verified *ChunkAssembly) {
+	for other, assembly := range a.chunkBuffers {
+		if other != key && assembly.tensorID == verified.tensorID && assembly.nodeID == verified.nodeID {
+			delete(a.chunkBuffers, other)
+		}
+	}
+}
+
 // RunAggregationLoop consumes chunks from transport
 func (a *StreamingAggregator) RunAggregationLoop(ctx context.Context) {
 	chunkChan, _ := a.trans.Receive(ctx)
@@ -134,12 +506,17 @@ func (a *StreamingAggregator) RunAggregationLoop(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case chunk := <-chunkChan:
+		case chunk, ok := <-chunkChan:
+			if !ok {
+				return // transport closed
+			}
 			if err := a.IngestChunk(chunk); err != nil {
 				log.Printf("Chunk ingestion error: %v", err)
 			}
 		case <-ticker.C:
-			a.flushPartialAggregation()
+			if _, err := a.Flush(ctx); err != nil {
+				log.Printf("WARNING: [%v streaming-aggregator] flush failed: %v", a.tier, err)
+			}
 			a.checkpointStaleBuffers()
 		}
 	}
@@ -165,43 +542,113 @@ func (a *StreamingAggregator) checkpointStaleBuffers() {
 	}
 }
 
-// flushPartialAggregation triggers Byzantine filtering and aggregation
-func (a *StreamingAggregator) flushPartialAggregation() {
-	a.mu.Lock()
+// Flush aggregates the ready tensors once quorum is met and hands the
+// aggregate to each sink. A node contributes at most one tensor per round, its
+// earliest assembled; its later tensors stay buffered for the next round.
+// Tensors whose dimension disagrees with the majority are rejected against
+// their node. Signed tensors are remembered once flushed, and IngestChunk
+// refuses them afterwards. With a DP noise multiplier
+// the round is charged to the accountant and withheld if the budget is
+// exhausted. Flush returns nil when quorum is not met, including when the
+// dimension check leaves too few tensors.
+func (a *StreamingAggregator) Flush(ctx context.Context) (*StreamingAggregate, error) {
+	a.VerifyPendingTensors()
 
-	// Collect assembled gradients
-	var assemblies []*ChunkAssembly
-	var nodeIDs []string
+	a.mu.Lock()
 
-	for nodeID, assembly := range a.chunkBuffers {
-		if len(assembly.chunks) == assembly.total {
-			assemblies = append(assemblies, assembly)
-			nodeIDs = append(nodeIDs, nodeID)
+	// Collect one assembled gradient per node
+	byNode := make(map[string]string)
+	for key, assembly := range a.chunkBuffers {
+		if !assembly.ready {
+			continue
+		}
+		if prev, ok := byNode[assembly.nodeID]; ok && !assembledBefore(assembly, key, a.chunkBuffers[prev], prev) {
+			continue
 		}
+		byNode[assembly.nodeID] = key
+	}
+	keys := make([]string, 0, len(byNode))
+	for _, key := range byNode {
+		keys = append(keys, key)
 	}
 
 	// Need minimum quorum for Byzantine filtering
-	if len(assemblies) < a.quorumSize {
+	if len(keys) == 0 || len(keys) < a.quorumSize {
 		a.mu.Unlock()
-		return
+		return nil, nil
 	}
+	sort.Strings(keys)
 
 	// Convert chunk assemblies to full gradient tensors
-	gradients := make([][]float64, len(assemblies))
-	for i, assembly := range assemblies {
-		gradient := a.assembleGradientFromChunks(assembly)
-		gradients[i] = gradient
+	nodeIDs := make([]string, len(keys))
+	ids := make([]string, len(keys))
+	verified := make([]bool, len(keys))
+	assembled := make([][]float64, len(keys))
+	for i, key := range keys {
+		assembly := a.chunkBuffers[key]
+		nodeIDs[i] = assembly.nodeID
+		ids[i] = assembly.tensorID
+		verified[i] = assembly.verified
+		assembled[i] = a.assembleGradientFromChunks(assembly)
 	}
+	consume := func(i int) {
+		delete(a.chunkBuffers, keys[i])
+		if !verified[i] {
+			return
+		}
+		if a.consumed[nodeIDs[i]] == nil {
+			a.consumed[nodeIDs[i]] = make(map[string]struct{})
+		}
+		a.consumed[nodeIDs[i]][ids[i]] = struct{}{}
+	}
+	var tensorIDs, participants []string
+	var accepted []int
+	var gradients [][]float64
+	majority := majorityDimension(assembled)
+	for i, g := range assembled {
+		if len(g) != majority {
+			consume(i)
+			err := fmt.Errorf("%w: %d != %d", ErrTensorDimension, len(g), majority)
+			if verified[i] {
+				_ = a.rejectVerifiedLocked(nodeIDs[i], ids[i], err)
+			} else {
+				_ = a.rejectLocked(nodeIDs[i], ids[i], err)
+			}
+			continue
+		}
+		tensorIDs = append(tensorIDs, ids[i])
+		participants = append(participants, nodeIDs[i])
+		accepted = append(accepted, i)
+		gradients = append(gradients, g)
+	}
+
+	// Rejecting mismatched tensors can cost quorum; the survivors stay
+	// buffered for the next flush
+	if len(gradients) == 0 || len(gradients) < a.quorumSize {
+		a.mu.Unlock()
+		return nil, nil
+	}
+	for _, i := range accepted {
+		consume(i)
+	}
+	a.rounds++
+	round := a.rounds
+	sinks := append([]AggregateSink(nil), a.sinks...)
 	a.mu.Unlock()
 
 	// Apply MultiKrum Byzantine filtering
-	byzantineF := len(assemblies) / 3 // Assume up to 1/3 Byzantine attackers
-	selected, _, scores, err := a.applyMultiKrumFiltering(gradients, byzantineF)
+	byzantineF := len(gradients) / 3 // Assume up to 1/3 Byzantine attackers
+	selected, selectedGrads, scores, err := a.applyMultiKrumFiltering(gradients, byzantineF)
 
 	if err != nil {
 		log.Printf("WARNING: MultiKrum filtering failed: %v", err)
 		// Fall back to simple mean aggregation
-		selected = a.getFallbackSelection(len(gradients))
+		selected, selectedGrads = a.getFallbackSelection(len(gradients)), gradients
+	}
+
+	gradient, err := a.releaseAggregate(selectedGrads)
+	if err != nil {
+		return nil, fmt.Errorf("round %d: %w", round, err)
 	}
 
 	// Track results
@@ -213,17 +660,69 @@ func (a *StreamingAggregator) flushPartialAggregation() {
 
 	a.mu.Unlock()
 
-	log.Printf("[%v streaming-aggregator] flushed %d gradients (selected %d after MultiKrum, scores: %v, epsilon: %.4f)",
-		a.tier, len(assemblies), len(selected), scores, epsilon)
+	result := &StreamingAggregate{
+		RoundID:      round,
+		Tier:         a.tier,
+		Gradient:     gradient,
+		Participants: participants,
+		TensorIDs:    tensorIDs,
+		Selected:     selected,
+		Scores:       scores,
+		Epsilon:      epsilon,
+		FlushedAt:    time.Now(),
+	}
 
-	// Clean up processed assemblies
-	a.mu.Lock()
-	for i, nodeID := range nodeIDs {
-		if i < len(selected) {
-			delete(a.chunkBuffers, nodeID)
+	log.Printf("[%v streaming-aggregator] flushed round %d: %d gradients (selected %d after MultiKrum, scores: %v, epsilon: %.4f)",
+		a.tier, round, len(gradients), len(selected), scores, epsilon)
+
+	for _, sink := range sinks {
+		if err := sink.SubmitAggregate(ctx, *result); err != nil {
+			log.Printf("WARNING: [%v streaming-aggregator] aggregate sink failed for round %d: %v", a.tier, round, err)
 		}
 	}
-	a.mu.Unlock()
+	return result, nil
+}
+
+// assembledBefore orders tensors by assembly time, then by key.
+func assembledBefore(a *ChunkAssembly, aKey string, b *ChunkAssembly, bKey string) bool {
+	if !a.assembled.Equal(b.assembled) {
+		return a.assembled.Before(b.assembled)
+	}
+	return aKey < bKey
+}
+
+// releaseAggregate averages the selected gradients. With a DP noise
+// multiplier each gradient is clipped, Gaussian noise is added and the round
+// is charged before anything is released.
+func (a *StreamingAggregator) releaseAggregate(selected [][]float64) ([]float64, error) {
+	if a.dpSigma <= 0 {
+		return a.aggregateGradients(selected), nil
+	}
+	if err := a.accountant.RecordSubsampledGaussianStep(a.dpSamplingRate, a.dpSigma); err != nil {
+		return nil, fmt.Errorf("privacy accounting failed: %w", err)
+	}
+	if err := a.accountant.CheckBudget(); err != nil {
+		return nil, fmt.Errorf("privacy guard triggered: %w", err)
+	}
+	weights := make([]float64, len(selected))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return noisyWeightedMean(selected, weights, a.dpClipNorm, a.dpSigma)
+}
+
+// majorityDimension returns the most common gradient dimension, preferring
+// the smaller dimension on ties.
+func majorityDimension(gradients [][]float64) int {
+	votes := make(map[int]int)
+	dim := -1
+	for _, g := range gradients {
+		votes[len(g)]++
+		if n := votes[len(g)]; dim < 0 || n > votes[dim] || (n == votes[dim] && len(g) < dim) {
+			dim = len(g)
+		}
+	}
+	return dim
 }
 
 // assembleGradientFromChunks reconstructs full gradient from chunks
@@ -321,6 +820,8 @@ func (a *StreamingAggregator) GetStats() map[string]interface{} {
 
 	return map[string]interface{}{
 		"total_chunks_ingested": a.totalChunksIngested,
+		"total_chunks_rejected": a.totalChunksRejected,
+		"rounds_flushed":        a.rounds,
 		"total_gradients":       a.totalGradients,
 		"total_tensors_ready":   a.totalTensorsReady,
 		"active_assemblies":     len(a.chunkBuffers),
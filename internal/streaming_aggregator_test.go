@@ -5,9 +5,15 @@
 package internal
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/cluster"
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/transport"
 )
 
@@ -234,6 +240,484 @@ func TestStreamingAggregatorBufferOverflow(t *testing.T) {
 	t.Logf("✓ Buffer overflow test passed (rejected when full)")
 }
 
+// integrityTensor builds a tensor of total chunks, sealed with key when set
+func integrityTensor(t *testing.T, tensorID, nodeID string, total int, key ed25519.PrivateKey) []transport.GradientChunk {
+	t.Helper()
+	chunks := make([]transport.GradientChunk, total)
+	for i := range chunks {
+		chunks[i] = transport.GradientChunk{
+			ID:      tensorID,
+			NodeID:  nodeID,
+			Index:   i,
+			Total:   total,
+			Payload: []float32{float32(i), float32(i) + 0.5},
+		}
+	}
+	if key != nil {
+		if err := transport.SealTensor(chunks, key); err != nil {
+			t.Fatalf("seal tensor: %v", err)
+		}
+	}
+	return chunks
+}
+
+// TestStreamingAggregatorRejectsInconsistentChunks verifies structural and hash checks
+func TestStreamingAggregatorRejectsInconsistentChunks(t *testing.T) {
+	topo := cluster.NewTopology()
+	_ = topo.RegisterNode("node-bad", cluster.EdgeNode)
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors:        100,
+		Topology:                  topo,
+		RejectionPenaltyThreshold: 2,
+	})
+
+	chunks := integrityTensor(t, "tensor-bad", "node-bad", 3, nil)
+	for i := range chunks {
+		chunks[i].Hash = transport.ChunkHash(chunks[i])
+	}
+	if err := agg.IngestChunk(chunks[0]); err != nil {
+		t.Fatalf("first chunk: %v", err)
+	}
+	if err := agg.IngestChunk(chunks[0]); err != nil {
+		t.Fatalf("exact redelivery should be ignored, got %v", err)
+	}
+
+	outOfRange := chunks[1]
+	outOfRange.Index = 3
+	wrongTotal := chunks[1]
+	wrongTotal.Total = 4
+	wrongTotal.Hash = transport.ChunkHash(wrongTotal)
+	conflicting := chunks[0]
+	conflicting.Payload = []float32{9, 9}
+	conflicting.Hash = transport.ChunkHash(conflicting)
+	tampered := chunks[1]
+	tampered.Payload = []float32{1, 100}
+
+	cases := []struct {
+		name  string
+		chunk transport.GradientChunk
+		want  error
+	}{
+		{"out of range", outOfRange, ErrChunkOutOfRange},
+		{"total mismatch", wrongTotal, ErrChunkTotalMismatch},
+		{"duplicate index", conflicting, ErrChunkDuplicate},
+		{"tampered payload", tampered, ErrChunkHashMismatch},
+	}
+	for _, tc := range cases {
+		if err := agg.IngestChunk(tc.chunk); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	if n := agg.RejectedChunks("node-bad"); n != int64(len(cases)) {
+		t.Fatalf("expected %d rejections for node-bad, got %d", len(cases), n)
+	}
+	if stats := agg.GetStats(); stats["total_chunks_rejected"] != int64(len(cases)) || stats["total_tensors_ready"] != int64(0) {
+		t.Fatalf("unexpected stats after rejections: %v", stats)
+	}
+	// Anyone can claim node-bad's ID, so unauthenticated rejections never
+	// cost it reputation
+	for i := 0; i < 5; i++ {
+		_ = agg.IngestChunk(outOfRange)
+	}
+	if node, _ := topo.GetNode("node-bad"); node.Reputation != 1 {
+		t.Fatalf("expected unauthenticated rejections not to penalize, got reputation %v", node.Reputation)
+	}
+	topo.UpdateReputation("node-bad", -1)
+	if unhealthy := topo.HealthCheck(); len(unhealthy) != 1 || unhealthy[0] != "node-bad" {
+		t.Fatalf("expected node-bad to be marked unhealthy, got %v", unhealthy)
+	}
+	// The unhealthy notification drops its buffered tensor and later chunks are refused
+	if stats := agg.GetStats(); stats["active_assemblies"] != 0 {
+		t.Fatalf("expected node-bad's assembly to be dropped, got %v", stats["active_assemblies"])
+	}
+	if err := agg.IngestChunk(chunks[1]); !errors.Is(err, ErrNodeUnhealthy) {
+		t.Fatalf("expected ErrNodeUnhealthy, got %v", err)
+	}
+}
+
+// TestStreamingAggregatorVerifiesTensorSignatures verifies batched signature checks
+func TestStreamingAggregatorVerifiesTensorSignatures(t *testing.T) {
+	goodPub, goodKey, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	_, forgerKey, _ := ed25519.GenerateKey(nil)
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		NodeKeys:           map[string]ed25519.PublicKey{"node-a": goodPub},
+	})
+	if err := agg.RegisterNodeKey("node-b", otherPub); err != nil {
+		t.Fatalf("register key: %v", err)
+	}
+
+	for _, c := range integrityTensor(t, "tensor-a", "node-a", 4, goodKey) {
+		if err := agg.IngestChunk(c); err != nil {
+			t.Fatalf("signed chunk rejected: %v", err)
+		}
+	}
+	for _, c := range integrityTensor(t, "tensor-b", "node-b", 4, forgerKey) {
+		if err := agg.IngestChunk(c); err != nil {
+			t.Fatalf("chunk hashes are valid, signature is checked per tensor: %v", err)
+		}
+	}
+	if err := agg.IngestChunk(integrityTensor(t, "tensor-c", "node-a", 2, nil)[0]); !errors.Is(err, ErrChunkUnsigned) {
+		t.Fatalf("expected unsigned chunk to be rejected, got %v", err)
+	}
+	if err := agg.IngestChunk(integrityTensor(t, "tensor-d", "node-z", 2, goodKey)[0]); !errors.Is(err, ErrChunkUnknownNode) {
+		t.Fatalf("expected unknown node to be rejected, got %v", err)
+	}
+	// A forged first chunk must not pin the proof the genuine tensor needs
+	forged := integrityTensor(t, "tensor-e", "node-a", 2, forgerKey)
+	genuine := integrityTensor(t, "tensor-e", "node-a", 2, goodKey)
+	if err := agg.IngestChunk(forged[0]); err != nil {
+		t.Fatalf("forged chunk: %v", err)
+	}
+	for _, c := range genuine {
+		if err := agg.IngestChunk(c); err != nil {
+			t.Fatalf("genuine chunk after a forged one rejected: %v", err)
+		}
+	}
+
+	if ready := agg.GetStats()["total_tensors_ready"]; ready != int64(0) {
+		t.Fatalf("tensors must wait for signature verification, got %v ready", ready)
+	}
+	if verified := agg.VerifyPendingTensors(); verified != 2 {
+		t.Fatalf("expected 2 tensors verified, got %d", verified)
+	}
+	if agg.RejectedChunks("node-b") != 1 || agg.RejectedChunks("node-a") != 1 {
+		t.Fatalf("unexpected per-node rejections: node-a=%d node-b=%d", agg.RejectedChunks("node-a"), agg.RejectedChunks("node-b"))
+	}
+	agg.mu.RLock()
+	defer agg.mu.RUnlock()
+	for _, assembly := range agg.chunkBuffers {
+		if assembly.tensorID == "tensor-b" {
+			t.Fatal("tensor with a bad signature should be dropped")
+		}
+		if assembly.tensorID == "tensor-e" && !assembly.ready {
+			t.Fatal("the forged signing of a verified tensor should be dropped")
+		}
+	}
+}
+
+// TestStreamingAggregatorPenalizesOnlyVerifiedOffenses verifies reputation
+// only drops for tensors the node is known to have signed
+func TestStreamingAggregatorPenalizesOnlyVerifiedOffenses(t *testing.T) {
+	topo := cluster.NewTopology()
+	keys := make(map[string]ed25519.PublicKey)
+	private := make(map[string]ed25519.PrivateKey)
+	for _, id := range []string{"node-a", "node-b", "node-bad"} {
+		_ = topo.RegisterNode(id, cluster.EdgeNode)
+		pub, priv, _ := ed25519.GenerateKey(nil)
+		keys[id], private[id] = pub, priv
+	}
+	_, forgerKey, _ := ed25519.GenerateKey(nil)
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors:        100,
+		NodeKeys:                  keys,
+		Topology:                  topo,
+		QuorumSize:                2,
+		RejectionPenaltyThreshold: 1,
+	})
+	send := func(chunks []transport.GradientChunk) {
+		t.Helper()
+		for _, c := range chunks {
+			if err := agg.IngestChunk(c); err != nil {
+				t.Fatalf("ingest %s[%d]: %v", c.ID, c.Index, err)
+			}
+		}
+	}
+
+	// A tensor forged in node-a's name fails verification without penalty
+	send(integrityTensor(t, "forged", "node-a", 2, forgerKey))
+	if agg.VerifyPendingTensors(); agg.RejectedChunks("node-a") != 1 {
+		t.Fatalf("expected the forged tensor to be counted, got %d", agg.RejectedChunks("node-a"))
+	}
+	if node, _ := topo.GetNode("node-a"); node.Reputation != 1 {
+		t.Fatalf("expected no penalty for a forged tensor, got reputation %v", node.Reputation)
+	}
+
+	// node-bad's own signed tensor has the wrong dimension
+	send(integrityTensor(t, "a1", "node-a", 2, private["node-a"]))
+	send(integrityTensor(t, "b1", "node-b", 2, private["node-b"]))
+	send(integrityTensor(t, "bad1", "node-bad", 3, private["node-bad"]))
+	if res, err := agg.Flush(context.Background()); err != nil || res == nil || len(res.Participants) != 2 {
+		t.Fatalf("expected a flush without node-bad, got %+v (%v)", res, err)
+	}
+	if node, _ := topo.GetNode("node-bad"); node.Reputation >= 1 {
+		t.Fatalf("expected node-bad to be penalized for its signed tensor, got reputation %v", node.Reputation)
+	}
+	if node, _ := topo.GetNode("node-a"); node.Reputation != 1 {
+		t.Fatalf("expected node-a to keep its reputation, got %v", node.Reputation)
+	}
+}
+
+// TestStreamingAggregatorRejectsReplayedTensors verifies a signed tensor
+// counts in one round only
+func TestStreamingAggregatorRejectsReplayedTensors(t *testing.T) {
+	keys := make(map[string]ed25519.PublicKey)
+	private := make(map[string]ed25519.PrivateKey)
+	for _, id := range []string{"node-a", "node-b"} {
+		pub, priv, _ := ed25519.GenerateKey(nil)
+		keys[id], private[id] = pub, priv
+	}
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		NodeKeys:           keys,
+		QuorumSize:         2,
+	})
+	send := func(chunks []transport.GradientChunk) error {
+		for _, c := range chunks {
+			if err := agg.IngestChunk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	captured := integrityTensor(t, "a1", "node-a", 2, private["node-a"])
+	if err := errors.Join(send(captured), send(integrityTensor(t, "b1", "node-b", 2, private["node-b"]))); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if res, err := agg.Flush(context.Background()); err != nil || res == nil {
+		t.Fatalf("expected round 1 to flush, got %v (%v)", res, err)
+	}
+
+	if err := send(captured); !errors.Is(err, ErrTensorReplayed) {
+		t.Fatalf("expected the replayed tensor to be refused, got %v", err)
+	}
+	if err := send(integrityTensor(t, "b2", "node-b", 2, private["node-b"])); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if res, err := agg.Flush(context.Background()); err != nil || res != nil {
+		t.Fatalf("expected the replay not to count towards quorum, got %v (%v)", res, err)
+	}
+	if err := send(integrityTensor(t, "a2", "node-a", 2, private["node-a"])); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if res, err := agg.Flush(context.Background()); err != nil || res == nil || res.TensorIDs[0] != "a2" {
+		t.Fatalf("expected fresh tensors to flush, got %+v (%v)", res, err)
+	}
+}
+
+// TestStreamingAggregatorFlushEmitsAggregate verifies quorum, sink delivery and dimension checks
+func TestStreamingAggregatorFlushEmitsAggregate(t *testing.T) {
+	var got []StreamingAggregate
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		QuorumSize:         3,
+		Sinks: []AggregateSink{AggregateSinkFunc(func(_ context.Context, a StreamingAggregate) error {
+			got = append(got, a)
+			return nil
+		})},
+	})
+	ingest := func(tensorID, nodeID string, payload []float32) {
+		t.Helper()
+		if err := agg.IngestChunk(transport.GradientChunk{ID: tensorID, NodeID: nodeID, Index: 0, Total: 1, Payload: payload}); err != nil {
+			t.Fatalf("ingest %s: %v", tensorID, err)
+		}
+	}
+
+	ingest("t1", "node-a", []float32{1, 2})
+	ingest("t2", "node-b", []float32{3, 4})
+	if res, err := agg.Flush(context.Background()); err != nil || res != nil || len(got) != 0 {
+		t.Fatalf("expected no aggregate below quorum, got %v (err=%v)", res, err)
+	}
+
+	ingest("t3", "node-c", []float32{5, 6, 7})
+	if res, err := agg.Flush(context.Background()); err != nil || res != nil || len(got) != 0 {
+		t.Fatalf("expected no aggregate once the odd-dimension tensor is rejected, got %v (err=%v)", res, err)
+	}
+	if n := agg.RejectedChunks("node-c"); n != 1 {
+		t.Fatalf("expected the odd-dimension tensor to be rejected once, got %d", n)
+	}
+
+	ingest("t4", "node-d", []float32{2, 3})
+	res, err := agg.Flush(context.Background())
+	if err != nil || res == nil {
+		t.Fatalf("expected an aggregate at quorum, got %v (err=%v)", res, err)
+	}
+	if len(got) != 1 || got[0].RoundID != 1 || res.RoundID != 1 {
+		t.Fatalf("expected round 1 delivered to the sink once, got %+v", got)
+	}
+	if len(res.Participants) != 3 || res.Participants[0] != "node-a" || res.Participants[2] != "node-d" || len(res.Selected) != 3 {
+		t.Fatalf("unexpected participants %v / selected %v", res.Participants, res.Selected)
+	}
+	if len(res.Gradient) != 2 || res.Gradient[0] != 2 || res.Gradient[1] != 3 {
+		t.Fatalf("expected mean [2 3], got %v", res.Gradient)
+	}
+	if stats := agg.GetStats(); stats["active_assemblies"] != 0 || stats["rounds_flushed"] != uint64(1) {
+		t.Fatalf("expected cleared buffers after flush, got %v", stats)
+	}
+}
+
+// TestStreamingAggregatorFlushWithholdsRoundBelowQuorum verifies the
+// dimension check cannot leave a lone tensor to be released as an aggregate
+func TestStreamingAggregatorFlushWithholdsRoundBelowQuorum(t *testing.T) {
+	var got []StreamingAggregate
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		QuorumSize:         3,
+		Sinks: []AggregateSink{AggregateSinkFunc(func(_ context.Context, a StreamingAggregate) error {
+			got = append(got, a)
+			return nil
+		})},
+	})
+	for i, dim := range []int{5, 7, 9} {
+		id := fmt.Sprintf("t%d", i)
+		if err := agg.IngestChunk(transport.GradientChunk{ID: id, NodeID: fmt.Sprintf("node-%d", i), Index: 0, Total: 1, Payload: make([]float32, dim)}); err != nil {
+			t.Fatalf("ingest %s: %v", id, err)
+		}
+	}
+
+	if res, err := agg.Flush(context.Background()); err != nil || res != nil || len(got) != 0 {
+		t.Fatalf("expected the round to be withheld, got %v (err=%v), sinks saw %d", res, err, len(got))
+	}
+	if stats := agg.GetStats(); stats["active_assemblies"] != 1 || stats["rounds_flushed"] != uint64(0) {
+		t.Fatalf("expected the surviving tensor to stay buffered, got %v", stats)
+	}
+}
+
+// TestStreamingAggregatorFlushTakesOneTensorPerNode verifies a node cannot
+// outweigh others by streaming several tensors into one round
+func TestStreamingAggregatorFlushTakesOneTensorPerNode(t *testing.T) {
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		QuorumSize:         2,
+	})
+	ingest := func(tensorID, nodeID string, payload []float32) {
+		t.Helper()
+		if err := agg.IngestChunk(transport.GradientChunk{ID: tensorID, NodeID: nodeID, Index: 0, Total: 1, Payload: payload}); err != nil {
+			t.Fatalf("ingest %s: %v", tensorID, err)
+		}
+	}
+
+	ingest("a1", "node-a", []float32{1, 1})
+	ingest("a2", "node-a", []float32{100, 100})
+	ingest("a3", "node-a", []float32{100, 100})
+	if res, err := agg.Flush(context.Background()); err != nil || res != nil {
+		t.Fatalf("expected one node's tensors not to make quorum, got %v (err=%v)", res, err)
+	}
+
+	ingest("b1", "node-b", []float32{3, 3})
+	res, err := agg.Flush(context.Background())
+	if err != nil || res == nil {
+		t.Fatalf("expected an aggregate at quorum, got %v (err=%v)", res, err)
+	}
+	if len(res.Participants) != 2 || res.TensorIDs[0] != "a1" || res.TensorIDs[1] != "b1" {
+		t.Fatalf("expected node-a's earliest tensor and node-b's, got %v / %v", res.Participants, res.TensorIDs)
+	}
+	if res.Gradient[0] != 2 || res.Gradient[1] != 2 {
+		t.Fatalf("expected mean [2 2], got %v", res.Gradient)
+	}
+	if stats := agg.GetStats(); stats["active_assemblies"] != 2 {
+		t.Fatalf("expected node-a's later tensors to stay buffered, got %v", stats)
+	}
+}
+
+// TestStreamingAggregatorFlushChargesPrivacy verifies the DP release path
+func TestStreamingAggregatorFlushChargesPrivacy(t *testing.T) {
+	agg := NewStreamingAggregator(Regional, transport.NewMRCAdapter("test-node", 4), StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		QuorumSize:         2,
+		DPSigma:            5,
+		DPSamplingRate:     0.01,
+	})
+	for i, nodeID := range []string{"node-a", "node-b"} {
+		chunk := transport.GradientChunk{ID: fmt.Sprintf("t%d", i), NodeID: nodeID, Index: 0, Total: 1, Payload: []float32{10, 10}}
+		if err := agg.IngestChunk(chunk); err != nil {
+			t.Fatalf("ingest: %v", err)
+		}
+	}
+	res, err := agg.Flush(context.Background())
+	if err != nil || res == nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if res.Epsilon <= 0 {
+		t.Fatalf("expected the noised round to be charged, got ε=%v", res.Epsilon)
+	}
+	if len(res.Gradient) != 2 || res.Gradient[0] == 10 {
+		t.Fatalf("expected a clipped, noised aggregate, got %v", res.Gradient)
+	}
+}
+
+// TestStreamingAggregatorOverNetworkTransport verifies chunks sprayed over TCP paths reach a flushed aggregate
+func TestStreamingAggregatorOverNetworkTransport(t *testing.T) {
+	receiver := transport.NewMRCAdapter("agg-1", 4)
+	addr, err := receiver.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer receiver.Close()
+
+	aggregates := make(chan StreamingAggregate, 1)
+	agg := NewStreamingAggregator(Regional, receiver, StreamingAggregatorOptions{
+		MaxBufferedTensors: 100,
+		CheckpointInterval: 20 * time.Millisecond,
+		QuorumSize:         2,
+		Sinks: []AggregateSink{AggregateSinkFunc(func(_ context.Context, a StreamingAggregate) error {
+			aggregates <- a
+			return nil
+		})},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go agg.RunAggregationLoop(ctx)
+
+	for _, nodeID := range []string{"node-a", "node-b"} {
+		pub, priv, _ := ed25519.GenerateKey(nil)
+		if err := agg.RegisterNodeKey(nodeID, pub); err != nil {
+			t.Fatalf("register key: %v", err)
+		}
+		sender := transport.NewMRCAdapter(nodeID, 3)
+		defer sender.Close()
+		if err := sender.RegisterDestinationAddr("agg-1", addr.String()); err != nil {
+			t.Fatalf("register destination: %v", err)
+		}
+		for _, chunk := range integrityTensor(t, "tensor-"+nodeID, nodeID, 4, priv) {
+			if err := sender.SendChunk(ctx, "agg-1", chunk); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		}
+	}
+
+	select {
+	case a := <-aggregates:
+		if len(a.Participants) != 2 || len(a.Gradient) != 8 || a.Gradient[7] != 3.5 {
+			t.Fatalf("unexpected aggregate %+v", a)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no aggregate flushed from network-delivered chunks")
+	}
+	if n := agg.GetStats()["total_chunks_rejected"]; n != int64(0) {
+		t.Fatalf("redundant copies should be dropped by the transport, got %v rejections", n)
+	}
+}
+
+type memoryCheckpointStore map[string][]byte
+
+func (m memoryCheckpointStore) PutCheckpoint(_ context.Context, name string, payload []byte) (string, error) {
+	m[name] = payload
+	return name, nil
+}
+
+// TestCheckpointSinkStoresAggregate verifies the checkpoint naming and payload
+func TestCheckpointSinkStoresAggregate(t *testing.T) {
+	store := memoryCheckpointStore{}
+	sink := CheckpointSink{Store: store, Prefix: "regional"}
+	if err := sink.SubmitAggregate(context.Background(), StreamingAggregate{RoundID: 7, Gradient: []float64{1.5}, Participants: []string{"node-a"}}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	var stored StreamingAggregate
+	if err := json.Unmarshal(store["regional-round-7.json"], &stored); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if stored.RoundID != 7 || len(stored.Gradient) != 1 || stored.Gradient[0] != 1.5 {
+		t.Fatalf("unexpected checkpoint %+v", stored)
+	}
+	if err := (CheckpointSink{}).SubmitAggregate(context.Background(), stored); err == nil {
+		t.Fatal("expected an error without a store")
+	}
+}
+
 // BenchmarkStreamingAggregatorIngest measures chunk ingestion throughput
 func BenchmarkStreamingAggregatorIngest(b *testing.B) {
 	mrc := transport.NewMRCAdapter("bench-node", 4)
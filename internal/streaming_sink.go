@@ -0,0 +1,109 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Aggregate sinks: where each streaming round's result goes
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StreamingAggregate is the result of one StreamingAggregator flush.
+type StreamingAggregate struct {
+	RoundID uint64 `json:"round_id"`
+	Tier    Tier   `json:"tier"`
+	// Gradient is the mean of the selected tensors, clipped and noised when
+	// the aggregator runs with a DP noise multiplier
+	Gradient []float64 `json:"gradient"`
+	// Participants and TensorIDs list every tensor considered, in the same order
+	Participants []string `json:"participants"`
+	TensorIDs    []string `json:"tensor_ids"`
+	// Selected indexes the Participants that went into Gradient; Scores holds
+	// each participant's Multi-Krum score (all zero when too few to filter)
+	Selected  []int     `json:"selected"`
+	Scores    []float64 `json:"scores,omitempty"`
+	Epsilon   float64   `json:"epsilon"`
+	FlushedAt time.Time `json:"flushed_at"`
+}
+
+// AggregateSink receives each round's aggregate, e.g. to forward it to a
+// parent tier, checkpoint it or register it as a model. Sinks share the
+// aggregate's slices and must not modify them.
+type AggregateSink interface {
+	SubmitAggregate(ctx context.Context, agg StreamingAggregate) error
+}
+
+// AggregateSinkFunc adapts a function to AggregateSink.
+type AggregateSinkFunc func(ctx context.Context, agg StreamingAggregate) error
+
+// SubmitAggregate calls f.
+func (f AggregateSinkFunc) SubmitAggregate(ctx context.Context, agg StreamingAggregate) error {
+	return f(ctx, agg)
+}
+
+// CheckpointStore persists named checkpoint payloads; ipfs.Backend satisfies it.
+type CheckpointStore interface {
+	PutCheckpoint(ctx context.Context, name string, payload []byte) (string, error)
+}
+
+// CheckpointSink writes each aggregate to a CheckpointStore as JSON under
+// "<Prefix>-round-<RoundID>.json".
+type CheckpointSink struct {
+	Store  CheckpointStore
+	Prefix string
+}
+
+// SubmitAggregate stores agg as a checkpoint.
+func (s CheckpointSink) SubmitAggregate(ctx context.Context, agg StreamingAggregate) error {
+	if s.Store == nil {
+		return fmt.Errorf("checkpoint store is required")
+	}
+	payload, err := json.Marshal(agg)
+	if err != nil {
+		return fmt.Errorf("marshal aggregate: %w", err)
+	}
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "streaming"
+	}
+	if _, err := s.Store.PutCheckpoint(ctx, fmt.Sprintf("%s-round-%d.json", prefix, agg.RoundID), payload); err != nil {
+		return fmt.Errorf("store aggregate checkpoint: %w", err)
+	}
+	return nil
+}
+
+// ModelPublisher records an aggregate as the model version for a round; the
+// orchestrator's model publisher satisfies it.
+type ModelPublisher interface {
+	PublishAggregate(ctx context.Context, round uint64, agg StreamingAggregate) error
+}
+
+// ModelRegistrySink registers each aggregate as a model version. Round maps
+// the aggregate to the round it is published under; when nil the
+// aggregator's flush count is used, which restarts with the process, so a
+// long-lived registry needs a Round.
+type ModelRegistrySink struct {
+	Publisher ModelPublisher
+	Round     func() (round uint64, ok bool)
+}
+
+// SubmitAggregate publishes agg as a model version.
+func (s ModelRegistrySink) SubmitAggregate(ctx context.Context, agg StreamingAggregate) error {
+	if s.Publisher == nil {
+		return fmt.Errorf("model publisher is required")
+	}
+	round := agg.RoundID
+	if s.Round != nil {
+		var ok bool
+		if round, ok = s.Round(); !ok {
+			return fmt.Errorf("no round is open for flush %d", agg.RoundID)
+		}
+	}
+	if err := s.Publisher.PublishAggregate(ctx, round, agg); err != nil {
+		return fmt.Errorf("publish aggregate as model version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// madToSigma scales the median absolute deviation to a standard deviation
+// under a normal model.
+const madToSigma = 1.4826
+
+// ThinkerClauses are the outlier-governance rules from the thinker_clauses
+// block of capabilities.json.
+//
+// Updates are scored by the robust z-score of their distance from the
+// coordinate-wise median. Scores above OutlierDistanceZScoreCap are rejected.
+// Scores at or above ManualReviewRequiredAboveZScore are minority outliers:
+// they are queued for review under EscalationLabel and, with PreserveOutliers,
+// up to MinorityRetentionMax of the aggregated set is filled with them.
+type ThinkerClauses struct {
+	Enabled                         bool    `json:"enabled"`
+	PreserveOutliers                bool    `json:"preserve_outliers"`
+	MinorityRetentionMin            float64 `json:"minority_retention_min"`
+	MinorityRetentionMax            float64 `json:"minority_retention_max"`
+	OutlierDistanceZScoreCap        float64 `json:"outlier_distance_zscore_cap"`
+	ManualReviewRequiredAboveZScore float64 `json:"manual_review_required_above_zscore"`
+	EscalationLabel                 string  `json:"escalation_label"`
+}
+
+// ReviewItem is an update routed to manual review.
+type ReviewItem struct {
+	Index  int     `json:"index"`
+	ZScore float64 `json:"zscore"`
+	Label  string  `json:"label"`
+	// Aggregated is true when the update was also retained as a minority outlier.
+	Aggregated bool `json:"aggregated"`
+}
+
+// GovernanceReport records thinker-clause decisions. Indices refer to the
+// updates passed to ProcessGradientBatch.
+type GovernanceReport struct {
+	ZScores          map[int]float64 `json:"zscores"`
+	Rejected         []int           `json:"rejected"`
+	RetainedOutliers []int           `json:"retained_outliers"`
+	ReviewQueue      []ReviewItem    `json:"review_queue"`
+	// MinorityFloorMet reports whether MinorityRetentionMin was reached, or
+	// every available minority outlier was retained.
+	MinorityFloorMet bool `json:"minority_floor_met"`
+}
+
+// LoadThinkerClauses reads the thinker_clauses block of a capabilities file.
+func LoadThinkerClauses(path string) (ThinkerClauses, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ThinkerClauses{}, err
+	}
+	var caps struct {
+		Thinker ThinkerClauses `json:"thinker_clauses"`
+	}
+	if err := json.Unmarshal(raw, &caps); err != nil {
+		return ThinkerClauses{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := caps.Thinker.Validate(); err != nil {
+		return ThinkerClauses{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return caps.Thinker, nil
+}
+
+// ThinkerClausesFromEnv loads the clauses from the capabilities file named by
+// MOHAWK_CAPABILITIES_PATH. It returns nil when the variable is unset or the
+// clauses are disabled, and an error when the file cannot be used.
+func ThinkerClausesFromEnv() (*ThinkerClauses, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_CAPABILITIES_PATH"))
+	if path == "" {
+		return nil, nil
+	}
+	clauses, err := LoadThinkerClauses(path)
+	if err != nil {
+		return nil, fmt.Errorf("MOHAWK_CAPABILITIES_PATH: %w", err)
+	}
+	if !clauses.Enabled {
+		return nil, nil
+	}
+	return &clauses, nil
+}
+
+// Validate checks that the clause thresholds are consistent.
+func (c ThinkerClauses) Validate() error {
+	if c.MinorityRetentionMin < 0 || c.MinorityRetentionMax < 0 || c.MinorityRetentionMax >= 1 {
+		return fmt.Errorf("minority retention bounds must lie in [0, 1)")
+	}
+	if c.MinorityRetentionMin > c.MinorityRetentionMax {
+		return fmt.Errorf("minority_retention_min %.3f exceeds minority_retention_max %.3f", c.MinorityRetentionMin, c.MinorityRetentionMax)
+	}
+	if c.OutlierDistanceZScoreCap < 0 || c.ManualReviewRequiredAboveZScore < 0 {
+		return fmt.Errorf("z-score thresholds must be non-negative")
+	}
+	if c.OutlierDistanceZScoreCap > 0 && c.ManualReviewRequiredAboveZScore > c.OutlierDistanceZScoreCap {
+		return fmt.Errorf("manual review threshold %.3f exceeds z-score cap %.3f", c.ManualReviewRequiredAboveZScore, c.OutlierDistanceZScoreCap)
+	}
+	return nil
+}
+
+// governanceSplit partitions envelopes into inliers and minority outlier
+// candidates (lowest z first), dropping updates above the cap.
+type governanceSplit struct {
+	inliers    []updateEnvelope
+	candidates []updateEnvelope
+	report     GovernanceReport
+}
+
+func applyThinkerClauses(envelopes []updateEnvelope, c ThinkerClauses) (governanceSplit, error) {
+	vectors := envelopesToVectors(envelopes)
+	if err := validateCoordinateInput(vectors, 0); err != nil {
+		return governanceSplit{}, fmt.Errorf("thinker clauses: %w", err)
+	}
+	z := distanceZScores(vectors)
+
+	split := governanceSplit{report: GovernanceReport{ZScores: make(map[int]float64, len(envelopes))}}
+	label := c.EscalationLabel
+	if label == "" {
+		label = "thinker-review"
+	}
+	for i, env := range envelopes {
+		split.report.ZScores[env.index] = z[i]
+		switch {
+		case c.OutlierDistanceZScoreCap > 0 && z[i] > c.OutlierDistanceZScoreCap:
+			split.report.Rejected = append(split.report.Rejected, env.index)
+		case c.ManualReviewRequiredAboveZScore > 0 && z[i] >= c.ManualReviewRequiredAboveZScore:
+			env.zscore = z[i]
+			split.candidates = append(split.candidates, env)
+			split.report.ReviewQueue = append(split.report.ReviewQueue, ReviewItem{Index: env.index, ZScore: z[i], Label: label})
+		default:
+			split.inliers = append(split.inliers, env)
+		}
+	}
+	sort.SliceStable(split.candidates, func(a, b int) bool { return split.candidates[a].zscore < split.candidates[b].zscore })
+	sort.Ints(split.report.Rejected)
+	return split, nil
+}
+
+// retainMinority picks how many candidates join an aggregated set of base
+// inliers so that they make up at most MinorityRetentionMax of the result.
+func (s *governanceSplit) retainMinority(base int, c ThinkerClauses) []updateEnvelope {
+	available := len(s.candidates)
+	if !c.PreserveOutliers || available == 0 {
+		s.report.MinorityFloorMet = available == 0 || c.MinorityRetentionMin == 0
+		return nil
+	}
+	limit := int(math.Floor(c.MinorityRetentionMax * float64(base) / (1 - c.MinorityRetentionMax)))
+	floor := int(math.Ceil(c.MinorityRetentionMin * float64(base) / (1 - c.MinorityRetentionMin)))
+	keep := available
+	if keep > limit {
+		keep = limit
+	}
+	s.report.MinorityFloorMet = keep >= floor || keep == available
+
+	retained := s.candidates[:keep]
+	for _, env := range retained {
+		s.report.RetainedOutliers = append(s.report.RetainedOutliers, env.index)
+		for i := range s.report.ReviewQueue {
+			if s.report.ReviewQueue[i].Index == env.index {
+				s.report.ReviewQueue[i].Aggregated = true
+			}
+		}
+	}
+	sort.Ints(s.report.RetainedOutliers)
+	return retained
+}
+
+// distanceZScores returns the robust z-score of each update's ℓ₂ distance
+// from the coordinate-wise median, using median/MAD and falling back to
+// mean/std when more than half the distances coincide.
+func distanceZScores(updates [][]float64) []float64 {
+	n := len(updates)
+	z := make([]float64, n)
+	if n < 3 {
+		return z
+	}
+	center, _, err := CoordinateMedianAggregate(updates, 0)
+	if err != nil {
+		return z
+	}
+	dist := make([]float64, n)
+	for i, u := range updates {
+		dist[i] = math.Sqrt(squaredL2(u, center))
+	}
+
+	loc := median(dist)
+	dev := make([]float64, n)
+	for i, d := range dist {
+		dev[i] = math.Abs(d - loc)
+	}
+	scale := madToSigma * median(dev)
+	if scale == 0 {
+		var mean, sq float64
+		for _, d := range dist {
+			mean += d
+		}
+		mean /= float64(n)
+		for _, d := range dist {
+			sq += (d - mean) * (d - mean)
+		}
+		loc, scale = mean, math.Sqrt(sq/float64(n))
+	}
+	if scale == 0 {
+		return z
+	}
+	for i, d := range dist {
+		z[i] = (d - loc) / scale
+	}
+	return z
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
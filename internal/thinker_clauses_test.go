@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testThinker = ThinkerClauses{
+	Enabled:                         true,
+	PreserveOutliers:                true,
+	MinorityRetentionMin:            0.05,
+	MinorityRetentionMax:            0.2,
+	OutlierDistanceZScoreCap:        4.0,
+	ManualReviewRequiredAboveZScore: 3.5,
+	EscalationLabel:                 "thinker-review",
+}
+
+// thinkerBatch returns 40 inliers spread along x, minority updates offset
+// along y by about offset, and one update far beyond the z-score cap.
+func thinkerBatch(minority int, offset float64) [][]float64 {
+	updates := make([][]float64, 0, 41+minority)
+	for i := -20; i < 20; i++ {
+		updates = append(updates, []float64{0.005 * float64(i), 0})
+	}
+	for i := 0; i < minority; i++ {
+		updates = append(updates, []float64{0, offset + 0.0005*float64(i)})
+	}
+	return append(updates, []float64{50, 50})
+}
+
+func TestThinkerClausesClassifyByZScore(t *testing.T) {
+	t.Setenv("MOHAWK_DP_SIGMA", "5")
+	agg := NewAggregator(Regional)
+	agg.Thinker = &testThinker
+	updates := thinkerBatch(2, 0.195)
+
+	result, err := agg.ProcessGradientBatch(updates, len(updates), BatchProcessingOptions{})
+	if err != nil {
+		t.Fatalf("ProcessGradientBatch failed: %v", err)
+	}
+	gov := result.Governance
+	if gov == nil {
+		t.Fatal("expected governance report")
+	}
+	if len(gov.Rejected) != 1 || gov.Rejected[0] != len(updates)-1 {
+		t.Fatalf("rejected=%v, want [%d]", gov.Rejected, len(updates)-1)
+	}
+	if len(gov.ReviewQueue) != 2 {
+		t.Fatalf("review queue=%+v, want 2 entries", gov.ReviewQueue)
+	}
+	for _, item := range gov.ReviewQueue {
+		if item.Label != "thinker-review" || item.ZScore < 3.5 || item.ZScore > 4.0 || !item.Aggregated {
+			t.Fatalf("unexpected review item %+v", item)
+		}
+	}
+	if len(gov.RetainedOutliers) != 2 || !gov.MinorityFloorMet {
+		t.Fatalf("retained=%v floor=%v", gov.RetainedOutliers, gov.MinorityFloorMet)
+	}
+	if result.SelectedCount != len(updates)-1 {
+		t.Fatalf("selected count=%d, want %d", result.SelectedCount, len(updates)-1)
+	}
+	if result.MaxGradNorm > 1 {
+		t.Fatalf("rejected update leaked into aggregate: norm %f", result.MaxGradNorm)
+	}
+}
+
+func TestThinkerClausesBoundMinorityThroughMultiKrum(t *testing.T) {
+	t.Setenv("MOHAWK_DP_SIGMA", "5")
+	agg := NewAggregator(Regional)
+	agg.Thinker = &testThinker
+	updates := thinkerBatch(6, 0.22)
+
+	result, err := agg.ProcessGradientBatch(updates, len(updates), BatchProcessingOptions{
+		ByzantineF: 2,
+		MultiKrumM: 12,
+	})
+	if err != nil {
+		t.Fatalf("ProcessGradientBatch failed: %v", err)
+	}
+	if !result.UsedMultiKrum {
+		t.Fatal("expected multi-krum to be used")
+	}
+	gov := result.Governance
+	// Twelve Krum-selected inliers admit floor(0.2*12/0.8) = 3 minority updates.
+	if len(gov.RetainedOutliers) != 3 || result.SelectedCount != 15 {
+		t.Fatalf("retained=%v selected=%d, want 3 and 15", gov.RetainedOutliers, result.SelectedCount)
+	}
+	if len(gov.ReviewQueue) != 6 {
+		t.Fatalf("review queue has %d entries, want 6", len(gov.ReviewQueue))
+	}
+	aggregated := 0
+	for _, item := range gov.ReviewQueue {
+		if item.Aggregated {
+			aggregated++
+		}
+	}
+	if aggregated != 3 {
+		t.Fatalf("%d review items marked aggregated, want 3", aggregated)
+	}
+}
+
+func TestThinkerClausesWithoutPreserveOutliers(t *testing.T) {
+	t.Setenv("MOHAWK_DP_SIGMA", "5")
+	agg := NewAggregator(Regional)
+	clauses := testThinker
+	clauses.PreserveOutliers = false
+	agg.Thinker = &clauses
+	updates := thinkerBatch(2, 0.195)
+
+	result, err := agg.ProcessGradientBatch(updates, len(updates), BatchProcessingOptions{})
+	if err != nil {
+		t.Fatalf("ProcessGradientBatch failed: %v", err)
+	}
+	if len(result.Governance.RetainedOutliers) != 0 || result.SelectedCount != 40 {
+		t.Fatalf("retained=%v selected=%d", result.Governance.RetainedOutliers, result.SelectedCount)
+	}
+	if result.Governance.MinorityFloorMet {
+		t.Fatal("minority floor should be unmet when outliers are withheld")
+	}
+}
+
+func TestLoadThinkerClauses(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capabilities.json")
+	body := `{"thinker_clauses":{"enabled":true,"preserve_outliers":true,"minority_retention_min":0.05,` +
+		`"minority_retention_max":0.2,"outlier_distance_zscore_cap":4.0,` +
+		`"manual_review_required_above_zscore":3.5,"escalation_label":"thinker-review"}}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	clauses, err := LoadThinkerClauses(path)
+	if err != nil {
+		t.Fatalf("LoadThinkerClauses failed: %v", err)
+	}
+	if clauses != testThinker {
+		t.Fatalf("loaded %+v, want %+v", clauses, testThinker)
+	}
+
+	t.Setenv("MOHAWK_CAPABILITIES_PATH", "")
+	if thinker, err := ThinkerClausesFromEnv(); err != nil || thinker != nil {
+		t.Fatalf("expected no clauses without MOHAWK_CAPABILITIES_PATH, got %+v (%v)", thinker, err)
+	}
+	t.Setenv("MOHAWK_CAPABILITIES_PATH", path)
+	if thinker, err := ThinkerClausesFromEnv(); err != nil || thinker == nil || *thinker != testThinker {
+		t.Fatalf("ThinkerClausesFromEnv = %+v (%v), want %+v", thinker, err, testThinker)
+	}
+	if agg := NewAggregator(Regional); agg.Thinker != nil {
+		t.Fatalf("expected NewAggregator not to load clauses implicitly, got %+v", agg.Thinker)
+	}
+
+	bad := strings.Replace(body, `"minority_retention_min":0.05`, `"minority_retention_min":0.5`, 1)
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadThinkerClauses(path); err == nil {
+		t.Fatal("expected retention min above max to be rejected")
+	}
+	if _, err := ThinkerClausesFromEnv(); err == nil {
+		t.Fatal("expected an invalid capabilities file to be reported, not ignored")
+	}
+}
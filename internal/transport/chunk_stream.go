@@ -0,0 +1,459 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Chunk streams: framing, chunk codec and the shared receive side
+
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// Frame layout (all integers big-endian):
+//
+//	uint32 length | uint8 type | body[length-1]
+//
+// Every chunk frame is answered by one ack frame on the same stream, so each
+// connection or path measures its own round-trip time and loss.
+const (
+	frameChunk uint8 = 1
+	frameAck   uint8 = 2
+
+	// MaxChunkFrameBytes bounds a single chunk frame on the wire
+	MaxChunkFrameBytes = 16 * 1024 * 1024
+
+	// DefaultAckTimeout applies to a send when the context has no deadline
+	DefaultAckTimeout = 5 * time.Second
+
+	// chunkFrameOverhead covers a chunk's IDs, counters, hash and proof on
+	// top of its payload when deriving a frame limit from a chunk size
+	chunkFrameOverhead = 64 * 1024
+
+	dialTimeout      = 2 * time.Second
+	dedupWindow      = 1 << 16
+	maxWireStringLen = math.MaxUint16
+)
+
+// Ack status codes
+const (
+	ackOK uint8 = iota
+	ackBusy
+	ackMalformed
+)
+
+var (
+	// ErrChunkTooLarge is returned for a chunk whose payload exceeds the
+	// transport's ChunkSizeBytes
+	ErrChunkTooLarge = errors.New("chunk payload exceeds configured chunk size")
+
+	errFrameTooLarge = errors.New("chunk frame exceeds maximum size")
+	errTruncated     = errors.New("truncated chunk frame")
+	errPathDown      = errors.New("path has no connection")
+	errClosed        = errors.New("transport is closed")
+)
+
+// SplitTensor cuts values into chunks of at most chunkSizeBytes of payload
+// (4 bytes per value). chunkSizeBytes <= 0 yields a single chunk.
+func SplitTensor(tensorID, nodeID string, values []float32, chunkSizeBytes int) []GradientChunk {
+	per := len(values)
+	if chunkSizeBytes > 0 {
+		per = max(1, chunkSizeBytes/4)
+	}
+	total := max(1, (len(values)+per-1)/max(per, 1))
+	chunks := make([]GradientChunk, total)
+	for i := range chunks {
+		lo := min(i*per, len(values))
+		hi := min(lo+per, len(values))
+		chunks[i] = GradientChunk{
+			ID:      tensorID,
+			NodeID:  nodeID,
+			Index:   i,
+			Total:   total,
+			Payload: values[lo:hi],
+		}
+	}
+	return chunks
+}
+
+// checkChunkSize enforces a transport's ChunkSizeBytes on an outgoing chunk
+func checkChunkSize(chunk GradientChunk, maxPayloadBytes int) error {
+	if maxPayloadBytes > 0 && 4*len(chunk.Payload) > maxPayloadBytes {
+		return fmt.Errorf("%w: %d > %d bytes", ErrChunkTooLarge, 4*len(chunk.Payload), maxPayloadBytes)
+	}
+	return nil
+}
+
+// frameLimit is the largest chunk frame accepted for a ChunkSizeBytes
+func frameLimit(chunkSizeBytes int) int {
+	if chunkSizeBytes <= 0 || chunkSizeBytes+chunkFrameOverhead > MaxChunkFrameBytes {
+		return MaxChunkFrameBytes
+	}
+	return chunkSizeBytes + chunkFrameOverhead
+}
+
+// encodeChunk returns the wire body of a chunk
+func encodeChunk(c GradientChunk) ([]byte, error) {
+	if len(c.ID) > maxWireStringLen || len(c.NodeID) > maxWireStringLen {
+		return nil, fmt.Errorf("chunk identifier too long")
+	}
+	buf := make([]byte, 0, 40+len(c.ID)+len(c.NodeID)+4*len(c.Payload)+len(c.Hash)+len(c.Proof))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(c.ID)))
+	buf = append(buf, c.ID...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(c.NodeID)))
+	buf = append(buf, c.NodeID...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(c.Index))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(c.Total))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(c.SentTime))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(c.Payload)))
+	for _, v := range c.Payload {
+		buf = binary.BigEndian.AppendUint32(buf, math.Float32bits(v))
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(c.Hash)))
+	buf = append(buf, c.Hash...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(c.Proof)))
+	buf = append(buf, c.Proof...)
+	return buf, nil
+}
+
+// decodeChunk parses a body produced by encodeChunk
+func decodeChunk(body []byte) (GradientChunk, error) {
+	r := chunkReader{buf: body}
+	c := GradientChunk{
+		ID:       string(r.take(int(r.u16()))),
+		NodeID:   string(r.take(int(r.u16()))),
+		Index:    int(r.u32()),
+		Total:    int(r.u32()),
+		SentTime: int64(r.u64()),
+	}
+	n := int(r.u32())
+	if r.err == nil && n > len(r.buf)/4 {
+		r.err = errTruncated
+	}
+	if r.err != nil {
+		return GradientChunk{}, r.err
+	}
+	c.Payload = make([]float32, n)
+	for i := range c.Payload {
+		c.Payload[i] = math.Float32frombits(r.u32())
+	}
+	c.Hash = r.bytes()
+	c.Proof = r.bytes()
+	if r.err != nil {
+		return GradientChunk{}, r.err
+	}
+	if len(r.buf) != 0 {
+		return GradientChunk{}, fmt.Errorf("trailing %d bytes after chunk", len(r.buf))
+	}
+	return c, nil
+}
+
+// chunkReader consumes primitive fields, latching the first error
+type chunkReader struct {
+	buf []byte
+	err error
+}
+
+func (r *chunkReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errTruncated
+		return nil
+	}
+	out := r.buf[:n]
+	r.buf = r.buf[n:]
+	return out
+}
+
+func (r *chunkReader) u16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *chunkReader) u32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *chunkReader) u64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *chunkReader) bytes() []byte {
+	b := r.take(int(r.u32()))
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// writeChunkFrame writes one length-prefixed frame
+func writeChunkFrame(w io.Writer, frameType uint8, body []byte) error {
+	length := 1 + len(body)
+	if length > MaxChunkFrameBytes {
+		return fmt.Errorf("%w: %d > %d", errFrameTooLarge, length, MaxChunkFrameBytes)
+	}
+	buf := make([]byte, 5+len(body))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(length))
+	buf[4] = frameType
+	copy(buf[5:], body)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readChunkFrame reads one frame, enforcing maxBytes before allocating
+func readChunkFrame(r io.Reader, maxBytes int) (uint8, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[0:4]))
+	if length < 1 {
+		return 0, nil, errTruncated
+	}
+	if length > maxBytes {
+		return 0, nil, fmt.Errorf("%w: %d > %d", errFrameTooLarge, length, maxBytes)
+	}
+	body := make([]byte, length-1)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header[4], body, nil
+}
+
+// ackError reports a chunk the receiver read but refused
+type ackError struct {
+	dest   string
+	status uint8
+}
+
+func (e *ackError) Error() string {
+	return fmt.Sprintf("chunk refused by %s (status %d)", e.dest, e.status)
+}
+
+// exchangeChunk writes one encoded chunk on rw and waits for its ack. An
+// *ackError leaves the stream usable; any other error does not.
+func exchangeChunk(rw io.ReadWriter, dest string, body []byte) error {
+	if err := writeChunkFrame(rw, frameChunk, body); err != nil {
+		return err
+	}
+	frameType, ack, err := readChunkFrame(rw, 16)
+	if err != nil {
+		return err
+	}
+	if frameType != frameAck || len(ack) != 1 {
+		return fmt.Errorf("unexpected frame type %d from %s", frameType, dest)
+	}
+	if ack[0] != ackOK {
+		return &ackError{dest: dest, status: ack[0]}
+	}
+	return nil
+}
+
+// chunkInbox is the receive queue behind Transport.Receive. It holds at most
+// its buffer size and drops exact copies of recently delivered chunks.
+type chunkInbox struct {
+	mu         sync.Mutex
+	ch         chan GradientChunk
+	seen       map[string]struct{} // recently delivered chunk digests
+	seenOrder  []string            // ring of keys in seen, oldest at seenNext
+	seenNext   int
+	duplicates int64
+	closed     bool
+}
+
+func newChunkInbox(size int) *chunkInbox {
+	if size <= 0 {
+		size = 2000
+	}
+	return &chunkInbox{
+		ch:   make(chan GradientChunk, size),
+		seen: make(map[string]struct{}),
+	}
+}
+
+// deliver queues chunk unless an identical copy was already delivered.
+// Copies are matched on their content, claimed hash and proof, so a forged
+// chunk at the same position cannot shadow the genuine one; the aggregator
+// sees both and rejects the conflict. Duplicates are acknowledged so the
+// sending path still counts a success; a full queue is refused as busy.
+func (b *chunkInbox) deliver(chunk GradientChunk) uint8 {
+	key := dedupKey(chunk)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ackBusy
+	}
+	if _, dup := b.seen[key]; dup {
+		b.duplicates++
+		return ackOK
+	}
+	select {
+	case b.ch <- chunk:
+	default:
+		return ackBusy
+	}
+	// Forget the oldest key once the window is full
+	if len(b.seenOrder) < dedupWindow {
+		b.seenOrder = append(b.seenOrder, key)
+	} else {
+		delete(b.seen, b.seenOrder[b.seenNext])
+		b.seenOrder[b.seenNext] = key
+		b.seenNext = (b.seenNext + 1) % dedupWindow
+	}
+	b.seen[key] = struct{}{}
+	return ackOK
+}
+
+// dedupKey digests everything a chunk copy carries: its ChunkHash, the Hash
+// it claims and its Proof
+func dedupKey(chunk GradientChunk) string {
+	h := sha256.New()
+	h.Write(ChunkHash(chunk))
+	writeUint64(h, uint64(len(chunk.Hash)))
+	h.Write(chunk.Hash)
+	writeUint64(h, uint64(len(chunk.Proof)))
+	h.Write(chunk.Proof)
+	return string(h.Sum(nil))
+}
+
+func (b *chunkInbox) duplicateCount() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.duplicates
+}
+
+// close stops delivery and closes the channel; callers must have stopped
+// every server goroutine first
+func (b *chunkInbox) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.ch)
+	}
+}
+
+// serveChunkStream answers every chunk frame on rw with one ack until the
+// stream ends or sends a malformed frame
+func serveChunkStream(rw io.ReadWriter, inbox *chunkInbox, maxFrameBytes int) {
+	for {
+		frameType, body, err := readChunkFrame(rw, maxFrameBytes)
+		if err != nil {
+			return
+		}
+		status := ackMalformed
+		if frameType == frameChunk {
+			if chunk, err := decodeChunk(body); err == nil {
+				status = inbox.deliver(chunk)
+			}
+		}
+		if err := writeChunkFrame(rw, frameAck, []byte{status}); err != nil || status == ackMalformed {
+			return
+		}
+	}
+}
+
+// chunkServer accepts chunk streams on TCP listeners for one inbox
+type chunkServer struct {
+	inbox         *chunkInbox
+	maxFrameBytes int
+
+	mu        sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+func newChunkServer(inbox *chunkInbox, maxFrameBytes int) *chunkServer {
+	return &chunkServer{inbox: inbox, maxFrameBytes: maxFrameBytes, conns: make(map[net.Conn]struct{})}
+}
+
+// listen binds addr and serves it in the background
+func (s *chunkServer) listen(addr string) (net.Addr, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.serve(ln); err != nil {
+		return nil, err
+	}
+	return ln.Addr(), nil
+}
+
+// serve accepts connections on ln in the background
+func (s *chunkServer) serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = ln.Close()
+		return errClosed
+	}
+	s.listeners = append(s.listeners, ln)
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			if s.closed {
+				s.mu.Unlock()
+				_ = conn.Close()
+				return
+			}
+			s.conns[conn] = struct{}{}
+			s.wg.Add(1)
+			s.mu.Unlock()
+
+			go func() {
+				defer s.wg.Done()
+				serveChunkStream(conn, s.inbox, s.maxFrameBytes)
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return nil
+}
+
+// close stops every listener and connection and waits for them to finish
+func (s *chunkServer) close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	for _, ln := range s.listeners {
+		_ = ln.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// Chunk integrity: per-chunk hashes and per-tensor signatures
+
+package transport
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+)
+
+// ChunkHash returns the SHA-256 digest carried in GradientChunk.Hash. It
+// covers the tensor ID, node ID, index, total and the payload's IEEE-754 bits,
+// so a chunk cannot be moved to another tensor or position unnoticed.
+func ChunkHash(chunk GradientChunk) []byte {
+	h := sha256.New()
+	writeString(h, chunk.ID)
+	writeString(h, chunk.NodeID)
+	writeUint64(h, uint64(chunk.Index))
+	writeUint64(h, uint64(chunk.Total))
+	buf := make([]byte, 4*len(chunk.Payload))
+	for i, v := range chunk.Payload {
+		binary.BigEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	h.Write(buf)
+	return h.Sum(nil)
+}
+
+// TensorDigest is the message a node signs for a whole tensor: SHA-256 over
+// the tensor ID, node ID and every chunk hash in index order.
+func TensorDigest(tensorID, nodeID string, chunkHashes [][]byte) []byte {
+	h := sha256.New()
+	writeString(h, tensorID)
+	writeString(h, nodeID)
+	writeUint64(h, uint64(len(chunkHashes)))
+	for _, ch := range chunkHashes {
+		h.Write(ch)
+	}
+	return h.Sum(nil)
+}
+
+// SealTensor sets Hash on every chunk and stores the node's Ed25519 signature
+// over TensorDigest in every chunk's Proof. chunks must hold the complete
+// tensor in index order.
+func SealTensor(chunks []GradientChunk, key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid ed25519 private key length %d", len(key))
+	}
+	if len(chunks) == 0 {
+		return fmt.Errorf("tensor has no chunks")
+	}
+	hashes := make([][]byte, len(chunks))
+	for i := range chunks {
+		c := &chunks[i]
+		if c.Index != i || c.Total != len(chunks) || c.ID != chunks[0].ID || c.NodeID != chunks[0].NodeID {
+			return fmt.Errorf("chunk %d is not part of tensor %s in index order", i, chunks[0].ID)
+		}
+		c.Hash = ChunkHash(*c)
+		hashes[i] = c.Hash
+	}
+	sig := ed25519.Sign(key, TensorDigest(chunks[0].ID, chunks[0].NodeID, hashes))
+	for i := range chunks {
+		chunks[i].Proof = sig
+	}
+	return nil
+}
+
+func writeString(h hash.Hash, s string) {
+	writeUint64(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeUint64(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
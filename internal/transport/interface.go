@@ -6,6 +6,8 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"time"
 )
 
@@ -34,11 +36,18 @@ type TransportHealth struct {
 
 // Transport is the core abstraction boundary
 // Implementations handle different transport mechanisms:
-//   - MRC Adapter (multi-path packet spraying - Phase-0)
-//   - TCP (fallback/baseline - future)
-//   - QUIC (future, for real-world deployment)
+//   - MRC Adapter (multi-path packet spraying over N TCP connections per destination)
+//   - TCP (single connection per destination - baseline)
+//   - QUIC (UDP, one stream per chunk)
 //   - RDMA/RoCE (future, for HPC supercluster mode)
 type Transport interface {
+	// Listen accepts chunks from remote transports of the same type on addr
+	// and returns the bound address
+	Listen(addr string) (net.Addr, error)
+
+	// RegisterDestinationAddr makes dest reachable at addr
+	RegisterDestinationAddr(dest, addr string) error
+
 	// SendChunk delivers a gradient chunk to destination
 	// MRC mode: sprays across multiple paths
 	SendChunk(ctx context.Context, dest string, chunk GradientChunk) error
@@ -57,19 +66,42 @@ type Transport interface {
 
 // Config for transport selection and tuning
 type Config struct {
-	Type            string // "tcp", "mrc", "quic"
-	LocalAddr       string // Listen address
-	NumPaths        int    // For MRC: concurrent paths (default 4)
-	ChunkSizeBytes  int    // Size of each chunk payload
-	BufferSize      int    // Receive buffer size
-	HealthCheckFreq time.Duration
+	Type            string        // "tcp", "mrc", "quic" (default "mrc")
+	NodeID          string        // Local node ID (default LocalAddr); QUIC receivers are addressed by it
+	LocalAddr       string        // Listen address; empty does not listen
+	NumPaths        int           // For MRC: concurrent paths (default 4)
+	ChunkSizeBytes  int           // Maximum chunk payload in bytes; larger chunks are refused (0 = unlimited)
+	BufferSize      int           // Receive buffer size in chunks (default 2000)
+	HealthCheckFreq time.Duration // MRC health monitor period (default 5s); TCP and QUIC keep-alive period
 }
 
 // NewTransport factory function creates appropriate transport type
 func NewTransport(cfg Config) (Transport, error) {
-	// Phase-0: all use MRC adapter
 	if cfg.NumPaths == 0 {
 		cfg.NumPaths = 4
 	}
-	return NewMRCAdapter(cfg.LocalAddr, cfg.NumPaths), nil
+	nodeID := cfg.NodeID
+	if nodeID == "" {
+		nodeID = cfg.LocalAddr
+	}
+
+	var t Transport
+	switch cfg.Type {
+	case "", "mrc":
+		t = newMRCAdapter(nodeID, cfg)
+	case "tcp":
+		t = NewTCPTransport(nodeID, cfg)
+	case "quic":
+		t = NewQUICTransport(nodeID, cfg)
+	default:
+		return nil, fmt.Errorf("unknown transport type %q", cfg.Type)
+	}
+
+	if cfg.LocalAddr != "" {
+		if _, err := t.Listen(cfg.LocalAddr); err != nil {
+			_ = t.Close()
+			return nil, err
+		}
+	}
+	return t, nil
 }
@@ -9,25 +9,47 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"net"
 	"sync"
 	"time"
 )
 
-// MRCAdapter implements Transport with MRC-like multi-path packet spraying
-// Simulates OpenAI's MRC without requiring specialized hardware
+const (
+	// minPathScore is the score below which a path is unhealthy; network
+	// paths below it are evicted and re-dialled by HealthMonitor
+	minPathScore = 0.5
+	// probationScore is the score of a freshly (re)dialled network path
+	probationScore = 0.75
+	// latencyEWMAWeight weights each new ack round-trip in a path's Latency
+	latencyEWMAWeight = 0.2
+	// sprayWidth is how many of the healthiest paths carry each chunk
+	sprayWidth = 3
+)
+
+// MRCAdapter implements Transport with MRC-like multi-path packet spraying.
+// Destinations registered with RegisterDestinationAddr get one TCP connection
+// per path. RegisterDestination only simulates paths for tests: sends sleep
+// for a synthetic latency and deliver nothing.
 type MRCAdapter struct {
 	nodeID      string
 	paths       map[string]*MRCPath   // pathID -> path
 	pathsByDest map[string][]*MRCPath // destination -> all paths
 	mu          sync.RWMutex
-	inbox       chan GradientChunk
+	inbox       *chunkInbox
+	server      *chunkServer // receive side (see Listen)
 	done        chan struct{}
 	numPaths    int
 	logger      *log.Logger
+	closed      bool
+
+	maxPayloadBytes int           // ChunkSizeBytes; 0 is unlimited
+	healthInterval  time.Duration // HealthMonitor period
+	keepAlive       time.Duration // TCP keep-alive for network paths
 }
 
-// MRCPath represents a virtual path to a destination
-// Maps to a logical route across network fabric
+// MRCPath represents a path to a destination. Network paths own one TCP
+// connection and learn Latency, PacketLoss and Score from acks; simulated
+// paths map to a logical route with a synthetic latency.
 type MRCPath struct {
 	ID             string
 	DestinationID  string
@@ -38,28 +60,49 @@ type MRCPath struct {
 	SuccessCount   int64
 	FailureCount   int64
 	mu             sync.RWMutex
+
+	addr      string // remote address; empty for simulated paths
+	keepAlive time.Duration
+	ioMu      sync.Mutex // serializes request/ack exchanges on conn
+	conn      net.Conn
 }
 
 // NewMRCAdapter creates a multi-path transport adapter
 func NewMRCAdapter(nodeID string, numPaths int) *MRCAdapter {
+	return newMRCAdapter(nodeID, Config{NumPaths: numPaths})
+}
+
+// newMRCAdapter creates an adapter honoring cfg's sizing and health settings
+func newMRCAdapter(nodeID string, cfg Config) *MRCAdapter {
+	numPaths := cfg.NumPaths
 	if numPaths < 1 {
 		numPaths = 4
 	}
 	if numPaths > 16 {
 		numPaths = 16
 	}
+	healthInterval := cfg.HealthCheckFreq
+	if healthInterval <= 0 {
+		healthInterval = 5 * time.Second
+	}
 
+	inbox := newChunkInbox(cfg.BufferSize)
 	return &MRCAdapter{
-		nodeID:      nodeID,
-		paths:       make(map[string]*MRCPath),
-		pathsByDest: make(map[string][]*MRCPath),
-		inbox:       make(chan GradientChunk, 2000),
-		done:        make(chan struct{}),
-		numPaths:    numPaths,
+		nodeID:          nodeID,
+		paths:           make(map[string]*MRCPath),
+		pathsByDest:     make(map[string][]*MRCPath),
+		inbox:           inbox,
+		server:          newChunkServer(inbox, frameLimit(cfg.ChunkSizeBytes)),
+		done:            make(chan struct{}),
+		numPaths:        numPaths,
+		maxPayloadBytes: cfg.ChunkSizeBytes,
+		healthInterval:  healthInterval,
+		keepAlive:       cfg.HealthCheckFreq,
 	}
 }
 
-// RegisterDestination creates multiple virtual paths to a destination
+// RegisterDestination creates simulated paths to a destination. Chunks sent
+// on them are never delivered; use RegisterDestinationAddr to reach a peer.
 func (m *MRCAdapter) RegisterDestination(destID string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -86,8 +129,50 @@ func (m *MRCAdapter) RegisterDestination(destID string) {
 	m.pathsByDest[destID] = paths
 }
 
+// RegisterDestinationAddr creates the adapter's paths to a remote MRC
+// listener at addr, each over its own TCP connection. Paths that fail to
+// dial stay registered and are re-dialled by HealthMonitor; an error is
+// returned only when no path could connect.
+func (m *MRCAdapter) RegisterDestinationAddr(destID, addr string) error {
+	m.mu.Lock()
+	if _, exists := m.pathsByDest[destID]; exists {
+		m.mu.Unlock()
+		return fmt.Errorf("destination %s already registered", destID)
+	}
+	paths := make([]*MRCPath, m.numPaths)
+	for i := range paths {
+		pathID := fmt.Sprintf("%s->%s[%d]", m.nodeID, destID, i)
+		paths[i] = &MRCPath{
+			ID:             pathID,
+			DestinationID:  destID,
+			LastHealthTime: time.Now(),
+			addr:           addr,
+			keepAlive:      m.keepAlive,
+		}
+		m.paths[pathID] = paths[i]
+	}
+	m.pathsByDest[destID] = paths
+	m.mu.Unlock()
+
+	var lastErr error
+	connected := 0
+	for _, p := range paths {
+		if err := p.dial(); err != nil {
+			lastErr = err
+			continue
+		}
+		connected++
+	}
+	if connected == 0 {
+		return fmt.Errorf("no path to %s at %s could connect: %w", destID, addr, lastErr)
+	}
+	return nil
+}
+
 // SendChunk implements packet spraying: send across multiple paths concurrently
-// This is the core MRC behavior: redundancy + speed
+// This is the core MRC behavior: redundancy + speed. It returns on the first
+// acknowledged copy; slower paths finish in the background and still update
+// their health.
 func (m *MRCAdapter) SendChunk(ctx context.Context, dest string, chunk GradientChunk) error {
 	m.mu.RLock()
 	relevantPaths, exists := m.pathsByDest[dest]
@@ -96,47 +181,72 @@ func (m *MRCAdapter) SendChunk(ctx context.Context, dest string, chunk GradientC
 	if !exists || len(relevantPaths) == 0 {
 		return fmt.Errorf("no paths to destination %s", dest)
 	}
+	if err := checkChunkSize(chunk, m.maxPayloadBytes); err != nil {
+		return err
+	}
+
+	// Network paths without a connection wait for HealthMonitor to re-dial
+	live := make([]*MRCPath, 0, len(relevantPaths))
+	for _, p := range relevantPaths {
+		if p.addr == "" || p.connected() {
+			live = append(live, p)
+		}
+	}
 
 	// Select best 2-4 paths based on health score
-	selectedPaths := m.selectBestPaths(relevantPaths, 3)
+	selectedPaths := m.selectBestPaths(live, sprayWidth)
 
 	if len(selectedPaths) == 0 {
 		return fmt.Errorf("all paths to %s are unhealthy", dest)
 	}
 
-	// Spray chunks across selected paths (concurrent)
-	var wg sync.WaitGroup
-	successCount := 0
-	mu := sync.Mutex{}
+	var body []byte
+	if selectedPaths[0].addr != "" {
+		var err error
+		if body, err = encodeChunk(chunk); err != nil {
+			return err
+		}
+	}
 
+	// Spray chunks across selected paths (concurrent)
+	results := make(chan error, len(selectedPaths))
 	for _, path := range selectedPaths {
-		wg.Add(1)
 		go func(p *MRCPath) {
-			defer wg.Done()
-
-			// Simulate network delay based on path latency
-			select {
-			case <-ctx.Done():
-				p.recordFailure()
-				return
-			case <-time.After(p.Latency):
-				// Path succeeded
-				p.recordSuccess()
-				mu.Lock()
-				successCount++
-				mu.Unlock()
-			}
+			results <- p.send(ctx, body)
 		}(path)
 	}
 
-	wg.Wait()
-
 	// At least one path must succeed (MRC guarantee)
-	if successCount == 0 {
-		return fmt.Errorf("all paths failed to %s", dest)
+	var lastErr error
+	for range selectedPaths {
+		err := <-results
+		if err == nil {
+			return nil
+		}
+		lastErr = err
 	}
+	return fmt.Errorf("all paths failed to %s: %w", dest, lastErr)
+}
 
-	return nil
+// send delivers one encoded chunk over the path and records the outcome
+func (p *MRCPath) send(ctx context.Context, body []byte) error {
+	if p.addr != "" {
+		err := p.sendOverConn(ctx, body)
+		if err != nil {
+			p.recordFailure()
+		}
+		return err
+	}
+
+	// Simulate network delay based on path latency
+	select {
+	case <-ctx.Done():
+		p.recordFailure()
+		return ctx.Err()
+	case <-time.After(p.Latency):
+		p.recordSuccess()
+		return nil
+	}
 }
 
 // selectBestPaths returns top-N paths by health score
@@ -174,11 +284,9 @@ func (m *MRCAdapter) selectBestPaths(paths []*MRCPath, maxCount int) []*MRCPath
 	return selected
 }
 
-// Receive returns the inbox channel for gradient chunks
+// Receive returns the inbox channel for gradient chunks arriving on Listen
 func (m *MRCAdapter) Receive(ctx context.Context) (<-chan GradientChunk, error) {
-	// In real implementation, listen on network
-	// For now, just return inbox
-	return m.inbox, nil
+	return m.inbox.ch, nil
 }
 
 // Health returns status of all paths
@@ -188,24 +296,35 @@ func (m *MRCAdapter) Health() []TransportHealth {
 
 	health := make([]TransportHealth, 0, len(m.paths))
 	for _, path := range m.paths {
-		path.mu.RLock()
-		h := TransportHealth{
-			PathID:     path.ID,
-			Latency:    path.Latency,
-			PacketLoss: path.PacketLoss,
-			IsHealthy:  path.Score > 0.5,
-			LastSeen:   path.LastHealthTime,
-		}
-		path.mu.RUnlock()
-		health = append(health, h)
+		health = append(health, path.health())
 	}
 
 	return health
 }
 
+// health snapshots the path's current metrics
+func (p *MRCPath) health() TransportHealth {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	loss := p.PacketLoss
+	if p.addr != "" {
+		// Network paths report loss from every ack outcome since the last dial
+		if total := p.SuccessCount + p.FailureCount; total > 0 {
+			loss = float64(p.FailureCount) / float64(total)
+		}
+	}
+	return TransportHealth{
+		PathID:     p.ID,
+		Latency:    p.Latency,
+		PacketLoss: loss,
+		IsHealthy:  p.Score > minPathScore,
+		LastSeen:   p.LastHealthTime,
+	}
+}
+
 // HealthMonitor continuously updates path health metrics
 func (m *MRCAdapter) HealthMonitor(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(m.healthInterval)
 	defer ticker.Stop()
 
 	for {
@@ -222,10 +341,17 @@ func (m *MRCAdapter) HealthMonitor(ctx context.Context) {
 
 func (m *MRCAdapter) updatePathHealth() {
 	m.mu.RLock()
-	paths := m.paths
+	paths := make([]*MRCPath, 0, len(m.paths))
+	for _, path := range m.paths {
+		paths = append(paths, path)
+	}
 	m.mu.RUnlock()
 
 	for _, path := range paths {
+		if path.addr != "" {
+			m.maintainNetworkPath(path)
+			continue
+		}
 		path.mu.Lock()
 
 		// Calculate packet loss from success/failure ratio
@@ -250,6 +376,54 @@ func (m *MRCAdapter) updatePathHealth() {
 	}
 }
 
+// maintainNetworkPath folds a network path's acks into its score, evicts
+// it once unhealthy and re-dials evicted or broken paths
+func (m *MRCAdapter) maintainNetworkPath(path *MRCPath) {
+	path.mu.Lock()
+	total := path.SuccessCount + path.FailureCount
+	if total > 0 {
+		path.PacketLoss = float64(path.FailureCount) / float64(total)
+	}
+	if path.PacketLoss > 0.2 {
+		path.Score *= 0.9
+	}
+	path.LastHealthTime = time.Now()
+	unhealthy := path.Score < minPathScore
+	path.mu.Unlock()
+
+	if unhealthy && path.connected() {
+		path.evict()
+		if m.logger != nil {
+			m.logger.Printf("mrc: evicted unhealthy path %s", path.ID)
+		}
+		return
+	}
+	m.mu.RLock()
+	closed := m.closed
+	m.mu.RUnlock()
+	if !closed && !path.connected() {
+		if err := path.dial(); err != nil {
+			path.mu.Lock()
+			path.Score = 0
+			path.mu.Unlock()
+		}
+	}
+}
+
+// recordAck marks an acknowledged network send and folds its round-trip
+// time into the path's latency
+func (p *MRCPath) recordAck(rtt time.Duration) {
+	p.mu.Lock()
+	p.SuccessCount++
+	if p.Latency == 0 {
+		p.Latency = rtt
+	} else {
+		p.Latency = time.Duration((1-latencyEWMAWeight)*float64(p.Latency) + latencyEWMAWeight*float64(rtt))
+	}
+	p.Score = min(1.0, p.Score+0.05)
+	p.mu.Unlock()
+}
+
 // recordSuccess marks a successful send on a path
 func (p *MRCPath) recordSuccess() {
 	p.mu.Lock()
@@ -266,9 +440,25 @@ func (p *MRCPath) recordFailure() {
 	p.mu.Unlock()
 }
 
-// Close shuts down the adapter
+// Close shuts down the adapter, its listeners and all path connections
 func (m *MRCAdapter) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	paths := make([]*MRCPath, 0, len(m.paths))
+	for _, path := range m.paths {
+		paths = append(paths, path)
+	}
+	m.mu.Unlock()
+
+	for _, path := range paths {
+		path.evict()
+	}
+	m.server.close()
 	close(m.done)
-	close(m.inbox)
+	m.inbox.close()
 	return nil
 }
@@ -0,0 +1,106 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// MRC network paths: one TCP connection per path
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dial opens the path's TCP connection if it has none
+func (p *MRCPath) dial() error {
+	p.ioMu.Lock()
+	defer p.ioMu.Unlock()
+	if p.conn != nil {
+		return nil
+	}
+	dialer := net.Dialer{Timeout: dialTimeout, KeepAlive: p.keepAlive}
+	conn, err := dialer.Dial("tcp", p.addr)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+
+	// A fresh connection starts on probation with clean loss counters
+	p.mu.Lock()
+	p.SuccessCount, p.FailureCount = 0, 0
+	p.PacketLoss = 0
+	p.Score = probationScore
+	p.LastHealthTime = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+// evict closes the path's connection; HealthMonitor re-dials it
+func (p *MRCPath) evict() {
+	p.ioMu.Lock()
+	defer p.ioMu.Unlock()
+	p.closeConnLocked()
+}
+
+func (p *MRCPath) closeConnLocked() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// connected reports whether a network path currently has a connection
+func (p *MRCPath) connected() bool {
+	p.ioMu.Lock()
+	defer p.ioMu.Unlock()
+	return p.conn != nil
+}
+
+// sendOverConn writes chunk on the path's connection and waits for its ack.
+// Any I/O error leaves the stream out of step, so the connection is dropped.
+func (p *MRCPath) sendOverConn(ctx context.Context, body []byte) error {
+	p.ioMu.Lock()
+	defer p.ioMu.Unlock()
+	if p.conn == nil {
+		return errPathDown
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultAckTimeout)
+	}
+	conn := p.conn
+	_ = conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	start := time.Now()
+	if err := exchangeChunk(conn, p.DestinationID, body); err != nil {
+		var refused *ackError
+		if !errors.As(err, &refused) {
+			p.closeConnLocked()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		return err
+	}
+	p.recordAck(time.Since(start))
+	return nil
+}
+
+// Listen accepts chunks from remote MRC adapters on addr and delivers each
+// distinct chunk to Receive once, however many paths it arrived on.
+func (m *MRCAdapter) Listen(addr string) (net.Addr, error) {
+	bound, err := m.server.listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("mrc listen on %s: %w", addr, err)
+	}
+	return bound, nil
+}
+
+// DuplicateChunks returns how many redundant copies Listen has dropped
+func (m *MRCAdapter) DuplicateChunks() int64 {
+	return m.inbox.duplicateCount()
+}
@@ -0,0 +1,197 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func startMRCReceiver(t *testing.T) (*MRCAdapter, string) {
+	t.Helper()
+	receiver := NewMRCAdapter("agg-1", 4)
+	addr, err := receiver.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { receiver.Close() })
+	return receiver, addr.String()
+}
+
+// TestChunkWireRoundTrip verifies the chunk codec and truncation handling
+func TestChunkWireRoundTrip(t *testing.T) {
+	chunk := GradientChunk{
+		ID:       "tensor-1",
+		NodeID:   "edge-1",
+		Index:    3,
+		Total:    8,
+		Payload:  []float32{1.5, -2, 0},
+		Hash:     []byte{1, 2, 3},
+		Proof:    []byte{4, 5},
+		SentTime: 42,
+	}
+	body, err := encodeChunk(chunk)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := decodeChunk(body)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.ID != chunk.ID || got.NodeID != chunk.NodeID || got.Index != 3 || got.Total != 8 ||
+		got.SentTime != 42 || len(got.Payload) != 3 || got.Payload[0] != 1.5 || got.Payload[1] != -2 ||
+		string(got.Hash) != string(chunk.Hash) || string(got.Proof) != string(chunk.Proof) {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	if _, err := decodeChunk(body[:len(body)-1]); !errors.Is(err, errTruncated) {
+		t.Fatalf("expected truncation error, got %v", err)
+	}
+}
+
+// TestChunkInboxDedupesOnContent verifies a forged chunk at a position
+// cannot shadow the genuine chunk sent later
+func TestChunkInboxDedupesOnContent(t *testing.T) {
+	inbox := newChunkInbox(8)
+	genuine := GradientChunk{ID: "tensor-1", NodeID: "edge-1", Index: 0, Total: 1, Payload: []float32{1}}
+	genuine.Hash = ChunkHash(genuine)
+	forged := genuine
+	forged.Payload = []float32{1000}
+	unsigned := genuine
+	unsigned.Hash = nil
+
+	for _, c := range []GradientChunk{forged, unsigned, genuine, genuine} {
+		if status := inbox.deliver(c); status != ackOK {
+			t.Fatalf("deliver: status %d", status)
+		}
+	}
+	if n := len(inbox.ch); n != 3 {
+		t.Fatalf("expected forged, unsigned and genuine copies queued, got %d", n)
+	}
+	if inbox.duplicateCount() != 1 {
+		t.Fatalf("expected only the exact copy to be dropped, got %d", inbox.duplicateCount())
+	}
+}
+
+// TestMRCNetworkDeliversOnceAcrossPaths verifies spraying over TCP and receive-side dedup
+func TestMRCNetworkDeliversOnceAcrossPaths(t *testing.T) {
+	receiver, addr := startMRCReceiver(t)
+	inbox, _ := receiver.Receive(context.Background())
+
+	sender := NewMRCAdapter("edge-1", 4)
+	defer sender.Close()
+	if err := sender.RegisterDestinationAddr("agg-1", addr); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		chunk := GradientChunk{ID: "tensor-1", NodeID: "edge-1", Index: i, Total: 10, Payload: []float32{float32(i)}}
+		if err := sender.SendChunk(ctx, "agg-1", chunk); err != nil {
+			t.Fatalf("send chunk %d: %v", i, err)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for len(seen) < 10 {
+		select {
+		case c := <-inbox:
+			if seen[c.Index] {
+				t.Fatalf("chunk %d delivered twice", c.Index)
+			}
+			if c.Payload[0] != float32(c.Index) {
+				t.Fatalf("chunk %d carried payload %v", c.Index, c.Payload)
+			}
+			seen[c.Index] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d of 10 chunks delivered", len(seen))
+		}
+	}
+
+	// Redundant copies land after the first ack; wait for them to be dropped
+	deadline := time.Now().Add(2 * time.Second)
+	for receiver.DuplicateChunks() < 10 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if receiver.DuplicateChunks() == 0 {
+		t.Fatal("expected redundant copies to be deduplicated")
+	}
+	select {
+	case c := <-inbox:
+		t.Fatalf("duplicate chunk %d reached the inbox", c.Index)
+	default:
+	}
+
+	measured := false
+	for _, h := range sender.Health() {
+		if h.Latency > 0 && h.IsHealthy {
+			measured = true
+		}
+	}
+	if !measured {
+		t.Fatalf("expected ack round-trips to set path latency: %+v", sender.Health())
+	}
+}
+
+// TestMRCNetworkEvictsAndRedialsPaths verifies HealthMonitor-driven path maintenance
+func TestMRCNetworkEvictsAndRedialsPaths(t *testing.T) {
+	_, addr := startMRCReceiver(t)
+
+	sender := NewMRCAdapter("edge-1", 2)
+	defer sender.Close()
+	if err := sender.RegisterDestinationAddr("agg-1", addr); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	bad := sender.pathsByDest["agg-1"][0]
+	for i := 0; i < 5; i++ {
+		bad.recordFailure()
+	}
+
+	sender.updatePathHealth()
+	if bad.connected() {
+		t.Fatal("expected the lossy path to be evicted")
+	}
+	if !sender.pathsByDest["agg-1"][1].connected() {
+		t.Fatal("healthy path should stay connected")
+	}
+	if err := sender.SendChunk(context.Background(), "agg-1", GradientChunk{ID: "t", NodeID: "edge-1", Total: 1, Payload: []float32{1}}); err != nil {
+		t.Fatalf("send over remaining path: %v", err)
+	}
+
+	sender.updatePathHealth()
+	if !bad.connected() {
+		t.Fatal("expected the evicted path to be re-dialled")
+	}
+	bad.mu.RLock()
+	score, failures := bad.Score, bad.FailureCount
+	bad.mu.RUnlock()
+	if score != probationScore || failures != 0 {
+		t.Fatalf("re-dialled path should start on probation, got score=%v failures=%d", score, failures)
+	}
+}
+
+// TestMRCNetworkFailsWhenReceiverDown verifies sends fail once the listener is gone
+func TestMRCNetworkFailsWhenReceiverDown(t *testing.T) {
+	receiver, addr := startMRCReceiver(t)
+
+	sender := NewMRCAdapter("edge-1", 2)
+	defer sender.Close()
+	if err := sender.RegisterDestinationAddr("agg-1", addr); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	receiver.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sender.SendChunk(ctx, "agg-1", GradientChunk{ID: "t", NodeID: "edge-1", Total: 1, Payload: []float32{1}}); err == nil {
+		t.Fatal("expected send to a closed receiver to fail")
+	}
+	sender.updatePathHealth()
+	for _, h := range sender.Health() {
+		if h.IsHealthy {
+			t.Fatalf("path %s should be unhealthy after failed sends and re-dials", h.PathID)
+		}
+	}
+	if err := NewMRCAdapter("edge-2", 1).RegisterDestinationAddr("agg-1", addr); err == nil {
+		t.Fatal("expected registration to fail with no listener")
+	}
+}
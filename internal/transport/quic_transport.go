@@ -0,0 +1,337 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// QUIC Transport: UDP with one stream per chunk
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/tpm"
+)
+
+// quicALPN identifies the chunk protocol during the QUIC handshake
+const quicALPN = "mohawk-chunks/1"
+
+// QUICTransport implements Transport over one QUIC connection per
+// destination. Every chunk travels on its own stream, so a lost packet only
+// stalls the chunk it belongs to. Both ends authenticate with the node
+// certificates issued by the TPM authority; receivers are addressed by
+// their node ID, which must match the destination ID senders register.
+type QUICTransport struct {
+	nodeID          string
+	maxPayloadBytes int
+	maxFrameBytes   int
+	quicConf        *quic.Config
+
+	mu        sync.RWMutex
+	dests     map[string]*quicDestination
+	inbox     *chunkInbox
+	listeners []*quic.Listener
+	conns     map[*quic.Conn]struct{} // accepted connections
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// quicDestination is one remote receiver and its connection stats
+type quicDestination struct {
+	id    string
+	addr  string
+	stats *MRCPath
+
+	mu   sync.Mutex // guards conn across re-dials
+	conn *quic.Conn
+}
+
+// NewQUICTransport creates a QUIC transport; cfg.Type is ignored.
+// HealthCheckFreq sets the connection keep-alive period.
+func NewQUICTransport(nodeID string, cfg Config) *QUICTransport {
+	return &QUICTransport{
+		nodeID:          nodeID,
+		maxPayloadBytes: cfg.ChunkSizeBytes,
+		maxFrameBytes:   frameLimit(cfg.ChunkSizeBytes),
+		quicConf: &quic.Config{
+			KeepAlivePeriod:    cfg.HealthCheckFreq,
+			MaxIncomingStreams: 1024,
+		},
+		dests: make(map[string]*quicDestination),
+		inbox: newChunkInbox(cfg.BufferSize),
+		conns: make(map[*quic.Conn]struct{}),
+	}
+}
+
+// Listen accepts chunks from remote QUIC transports on the UDP address addr
+func (t *QUICTransport) Listen(addr string) (net.Addr, error) {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("quic listen on %s: %w", addr, err)
+	}
+	return t.serve(pc)
+}
+
+// serve runs a QUIC listener on pc, which it takes ownership of
+func (t *QUICTransport) serve(pc net.PacketConn) (net.Addr, error) {
+	tlsConf, err := tpm.ServerTLSConfig(t.nodeID)
+	if err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("quic server TLS: %w", err)
+	}
+	tlsConf = tlsConf.Clone()
+	tlsConf.NextProtos = []string{quicALPN}
+
+	ln, err := quic.Listen(pc, tlsConf, t.quicConf)
+	if err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("quic listen: %w", err)
+	}
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		_ = ln.Close()
+		return nil, errClosed
+	}
+	t.listeners = append(t.listeners, ln)
+	t.wg.Add(1)
+	t.mu.Unlock()
+
+	go t.acceptLoop(ln)
+	return ln.Addr(), nil
+}
+
+func (t *QUICTransport) acceptLoop(ln *quic.Listener) {
+	defer t.wg.Done()
+	for {
+		conn, err := ln.Accept(context.Background())
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			_ = conn.CloseWithError(0, "closed")
+			return
+		}
+		t.conns[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		go t.serveConn(conn)
+	}
+}
+
+// serveConn answers the chunk on every stream the peer opens
+func (t *QUICTransport) serveConn(conn *quic.Conn) {
+	defer t.wg.Done()
+	defer func() {
+		t.mu.Lock()
+		delete(t.conns, conn)
+		t.mu.Unlock()
+	}()
+	for {
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			return
+		}
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			serveChunkStream(stream, t.inbox, t.maxFrameBytes)
+			_ = stream.Close()
+		}()
+	}
+}
+
+// RegisterDestinationAddr connects to dest's QUIC listener at addr. The
+// destination stays registered when the handshake fails, and the next send
+// retries it.
+func (t *QUICTransport) RegisterDestinationAddr(destID, addr string) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return errClosed
+	}
+	if _, exists := t.dests[destID]; exists {
+		t.mu.Unlock()
+		return fmt.Errorf("destination %s already registered", destID)
+	}
+	d := &quicDestination{
+		id:   destID,
+		addr: addr,
+		stats: &MRCPath{
+			ID:             fmt.Sprintf("%s->%s[quic]", t.nodeID, destID),
+			DestinationID:  destID,
+			LastHealthTime: time.Now(),
+		},
+	}
+	t.dests[destID] = d
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	if _, err := t.connection(ctx, d); err != nil {
+		return fmt.Errorf("dial %s at %s: %w", destID, addr, err)
+	}
+	return nil
+}
+
+// connection returns d's live connection, dialling a new one if needed
+func (t *QUICTransport) connection(ctx context.Context, d *quicDestination) (*quic.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn != nil && d.conn.Context().Err() == nil {
+		return d.conn, nil
+	}
+	tlsConf, err := tpm.ClientTLSConfig(t.nodeID, d.id)
+	if err != nil {
+		return nil, fmt.Errorf("quic client TLS: %w", err)
+	}
+	tlsConf = tlsConf.Clone()
+	tlsConf.NextProtos = []string{quicALPN}
+
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	conn, err := quic.DialAddr(dialCtx, d.addr, tlsConf, t.quicConf)
+	if err != nil {
+		return nil, err
+	}
+	d.conn = conn
+
+	// A fresh connection starts on probation with clean loss counters
+	d.stats.mu.Lock()
+	d.stats.SuccessCount, d.stats.FailureCount = 0, 0
+	d.stats.Score = probationScore
+	d.stats.LastHealthTime = time.Now()
+	d.stats.mu.Unlock()
+	return conn, nil
+}
+
+// SendChunk delivers chunk on a new stream to dest and waits for its ack
+func (t *QUICTransport) SendChunk(ctx context.Context, dest string, chunk GradientChunk) error {
+	t.mu.RLock()
+	d, ok := t.dests[dest]
+	closed := t.closed
+	t.mu.RUnlock()
+	if closed {
+		return errClosed
+	}
+	if !ok {
+		return fmt.Errorf("no connection to destination %s", dest)
+	}
+	if err := checkChunkSize(chunk, t.maxPayloadBytes); err != nil {
+		return err
+	}
+	body, err := encodeChunk(chunk)
+	if err != nil {
+		return err
+	}
+
+	err = t.sendOnStream(ctx, d, body)
+	if err != nil {
+		d.stats.recordFailure()
+	}
+	return err
+}
+
+func (t *QUICTransport) sendOnStream(ctx context.Context, d *quicDestination, body []byte) error {
+	conn, err := t.connection(ctx, d)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", d.id, err)
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultAckTimeout)
+	}
+	_ = stream.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = stream.SetDeadline(time.Now()) })
+	defer stop()
+
+	start := time.Now()
+	if err := exchangeChunk(stream, d.id, body); err != nil {
+		var refused *ackError
+		if !errors.As(err, &refused) {
+			stream.CancelRead(0)
+			stream.CancelWrite(0)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		_ = stream.Close()
+		return err
+	}
+	_ = stream.Close()
+	d.stats.recordAck(time.Since(start))
+	return nil
+}
+
+// Receive returns the channel of chunks arriving on Listen
+func (t *QUICTransport) Receive(ctx context.Context) (<-chan GradientChunk, error) {
+	return t.inbox.ch, nil
+}
+
+// Health returns one entry per destination connection
+func (t *QUICTransport) Health() []TransportHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	health := make([]TransportHealth, 0, len(t.dests))
+	for _, d := range t.dests {
+		h := d.stats.health()
+		d.mu.Lock()
+		if d.conn == nil || d.conn.Context().Err() != nil {
+			h.IsHealthy = false
+		}
+		d.mu.Unlock()
+		health = append(health, h)
+	}
+	return health
+}
+
+// DuplicateChunks returns how many repeated chunks Listen has dropped
+func (t *QUICTransport) DuplicateChunks() int64 {
+	return t.inbox.duplicateCount()
+}
+
+// Close shuts down listeners and every connection
+func (t *QUICTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	for _, ln := range t.listeners {
+		_ = ln.Close()
+	}
+	for conn := range t.conns {
+		_ = conn.CloseWithError(0, "closed")
+	}
+	dests := make([]*quicDestination, 0, len(t.dests))
+	for _, d := range t.dests {
+		dests = append(dests, d)
+	}
+	t.mu.Unlock()
+
+	for _, d := range dests {
+		d.mu.Lock()
+		if d.conn != nil {
+			_ = d.conn.CloseWithError(0, "closed")
+			d.conn = nil
+		}
+		d.mu.Unlock()
+	}
+	t.wg.Wait()
+	t.inbox.close()
+	return nil
+}
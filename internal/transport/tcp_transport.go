@@ -0,0 +1,154 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+// TCP Transport: one connection per destination (baseline)
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// TCPTransport implements Transport over a single TCP connection per
+// destination. Sends to a destination are serialized on its connection, and
+// a broken connection is re-dialled on the next send.
+type TCPTransport struct {
+	nodeID          string
+	maxPayloadBytes int
+	keepAlive       time.Duration
+
+	mu     sync.RWMutex
+	dests  map[string]*MRCPath // destination -> its connection and stats
+	inbox  *chunkInbox
+	server *chunkServer
+	closed bool
+}
+
+// NewTCPTransport creates a TCP transport; cfg.Type is ignored
+func NewTCPTransport(nodeID string, cfg Config) *TCPTransport {
+	inbox := newChunkInbox(cfg.BufferSize)
+	return &TCPTransport{
+		nodeID:          nodeID,
+		maxPayloadBytes: cfg.ChunkSizeBytes,
+		keepAlive:       cfg.HealthCheckFreq,
+		dests:           make(map[string]*MRCPath),
+		inbox:           inbox,
+		server:          newChunkServer(inbox, frameLimit(cfg.ChunkSizeBytes)),
+	}
+}
+
+// Listen accepts chunks from remote transports on addr
+func (t *TCPTransport) Listen(addr string) (net.Addr, error) {
+	bound, err := t.server.listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("tcp listen on %s: %w", addr, err)
+	}
+	return bound, nil
+}
+
+// RegisterDestinationAddr dials dest at addr. The destination stays
+// registered when the dial fails, and the next send retries it.
+func (t *TCPTransport) RegisterDestinationAddr(destID, addr string) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return errClosed
+	}
+	if _, exists := t.dests[destID]; exists {
+		t.mu.Unlock()
+		return fmt.Errorf("destination %s already registered", destID)
+	}
+	path := &MRCPath{
+		ID:             fmt.Sprintf("%s->%s[tcp]", t.nodeID, destID),
+		DestinationID:  destID,
+		LastHealthTime: time.Now(),
+		addr:           addr,
+		keepAlive:      t.keepAlive,
+	}
+	t.dests[destID] = path
+	t.mu.Unlock()
+
+	if err := path.dial(); err != nil {
+		return fmt.Errorf("dial %s at %s: %w", destID, addr, err)
+	}
+	return nil
+}
+
+// SendChunk delivers chunk over the destination's connection and waits for
+// the receiver's ack
+func (t *TCPTransport) SendChunk(ctx context.Context, dest string, chunk GradientChunk) error {
+	t.mu.RLock()
+	path, ok := t.dests[dest]
+	closed := t.closed
+	t.mu.RUnlock()
+	if closed {
+		return errClosed
+	}
+	if !ok {
+		return fmt.Errorf("no connection to destination %s", dest)
+	}
+	if err := checkChunkSize(chunk, t.maxPayloadBytes); err != nil {
+		return err
+	}
+	body, err := encodeChunk(chunk)
+	if err != nil {
+		return err
+	}
+
+	if err := path.dial(); err != nil {
+		path.recordFailure()
+		return fmt.Errorf("dial %s: %w", dest, err)
+	}
+	if err := path.sendOverConn(ctx, body); err != nil {
+		path.recordFailure()
+		return err
+	}
+	return nil
+}
+
+// Receive returns the channel of chunks arriving on Listen
+func (t *TCPTransport) Receive(ctx context.Context) (<-chan GradientChunk, error) {
+	return t.inbox.ch, nil
+}
+
+// Health returns one entry per destination connection
+func (t *TCPTransport) Health() []TransportHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	health := make([]TransportHealth, 0, len(t.dests))
+	for _, path := range t.dests {
+		health = append(health, path.health())
+	}
+	return health
+}
+
+// DuplicateChunks returns how many repeated chunks Listen has dropped
+func (t *TCPTransport) DuplicateChunks() int64 {
+	return t.inbox.duplicateCount()
+}
+
+// Close shuts down the listener and every connection
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	paths := make([]*MRCPath, 0, len(t.dests))
+	for _, path := range t.dests {
+		paths = append(paths, path)
+	}
+	t.mu.Unlock()
+
+	for _, path := range paths {
+		path.evict()
+	}
+	t.server.close()
+	t.inbox.close()
+	return nil
+}
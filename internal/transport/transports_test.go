@@ -0,0 +1,292 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var transportTypes = []string{"tcp", "quic", "mrc"}
+
+// startTransportPair returns a receiver listening on loopback as "agg-1" and
+// a sender with "agg-1" registered
+func startTransportPair(t testing.TB, transportType string, cfg Config) (sender, receiver Transport) {
+	t.Helper()
+	cfg.Type = transportType
+	cfg.NodeID = "agg-1"
+	receiver, err := NewTransport(cfg)
+	if err != nil {
+		t.Fatalf("new receiver: %v", err)
+	}
+	t.Cleanup(func() { receiver.Close() })
+	addr, err := receiver.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	cfg.NodeID = "edge-1"
+	sender, err = NewTransport(cfg)
+	if err != nil {
+		t.Fatalf("new sender: %v", err)
+	}
+	t.Cleanup(func() { sender.Close() })
+	if err := sender.RegisterDestinationAddr("agg-1", addr.String()); err != nil {
+		t.Fatalf("register destination: %v", err)
+	}
+	return sender, receiver
+}
+
+// TestNewTransportHonorsType verifies the factory builds each transport type
+func TestNewTransportHonorsType(t *testing.T) {
+	for typ, want := range map[string]string{"": "*transport.MRCAdapter", "mrc": "*transport.MRCAdapter", "tcp": "*transport.TCPTransport", "quic": "*transport.QUICTransport"} {
+		tr, err := NewTransport(Config{Type: typ, NodeID: "node-1", LocalAddr: "127.0.0.1:0", HealthCheckFreq: time.Second})
+		if err != nil {
+			t.Fatalf("type %q: %v", typ, err)
+		}
+		if got := fmt.Sprintf("%T", tr); got != want {
+			t.Fatalf("type %q built %s, want %s", typ, got, want)
+		}
+		tr.Close()
+	}
+	if _, err := NewTransport(Config{Type: "rdma"}); err == nil {
+		t.Fatal("expected an unknown transport type to be refused")
+	}
+}
+
+// TestTransportsDeliverChunkedTensor verifies delivery, chunk sizing and health for every type
+func TestTransportsDeliverChunkedTensor(t *testing.T) {
+	values := make([]float32, 40)
+	for i := range values {
+		values[i] = float32(i)
+	}
+	for _, typ := range transportTypes {
+		t.Run(typ, func(t *testing.T) {
+			sender, receiver := startTransportPair(t, typ, Config{ChunkSizeBytes: 64})
+			inbox, _ := receiver.Receive(context.Background())
+
+			chunks := SplitTensor("tensor-1", "edge-1", values, 64)
+			if len(chunks) != 3 || len(chunks[2].Payload) != 8 {
+				t.Fatalf("expected 16+16+8 values, got %d chunks", len(chunks))
+			}
+			ctx := context.Background()
+			for _, c := range chunks {
+				if err := sender.SendChunk(ctx, "agg-1", c); err != nil {
+					t.Fatalf("send chunk %d: %v", c.Index, err)
+				}
+			}
+			got := make([]float32, 0, len(values))
+			for range chunks {
+				select {
+				case c := <-inbox:
+					if c.Index*16 != len(got) {
+						t.Fatalf("chunk %d arrived out of order", c.Index)
+					}
+					got = append(got, c.Payload...)
+				case <-time.After(2 * time.Second):
+					t.Fatal("chunk never delivered")
+				}
+			}
+			if len(got) != len(values) || got[39] != 39 {
+				t.Fatalf("reassembled %v", got)
+			}
+
+			oversized := GradientChunk{ID: "tensor-2", NodeID: "edge-1", Total: 1, Payload: make([]float32, 17)}
+			if err := sender.SendChunk(ctx, "agg-1", oversized); !errors.Is(err, ErrChunkTooLarge) {
+				t.Fatalf("expected ErrChunkTooLarge, got %v", err)
+			}
+
+			health := sender.Health()
+			measured := false
+			for _, h := range health {
+				measured = measured || (h.IsHealthy && h.Latency > 0)
+			}
+			if !measured {
+				t.Fatalf("expected a healthy path with measured latency: %+v", health)
+			}
+		})
+	}
+}
+
+// TestTransportsRefuseBeyondBuffer verifies BufferSize bounds the receive queue
+func TestTransportsRefuseBeyondBuffer(t *testing.T) {
+	for _, typ := range transportTypes {
+		t.Run(typ, func(t *testing.T) {
+			sender, _ := startTransportPair(t, typ, Config{BufferSize: 2})
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				if err := sender.SendChunk(ctx, "agg-1", GradientChunk{ID: "t", NodeID: "edge-1", Index: i, Total: 3, Payload: []float32{1}}); err != nil {
+					t.Fatalf("send %d: %v", i, err)
+				}
+			}
+			if err := sender.SendChunk(ctx, "agg-1", GradientChunk{ID: "t", NodeID: "edge-1", Index: 2, Total: 3, Payload: []float32{1}}); err == nil {
+				t.Fatal("expected a full receive buffer to refuse the chunk")
+			}
+		})
+	}
+}
+
+// lossPenalty models one retransmission timeout for a lost TCP segment
+const lossPenalty = 2 * time.Millisecond
+
+// lossyListener hands out connections whose writes are delayed by
+// lossPenalty with probability loss, as a lost and retransmitted segment
+// would be. A byte stream cannot drop data outright.
+type lossyListener struct {
+	net.Listener
+	loss float64
+}
+
+func (l lossyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &lossyConn{Conn: conn, loss: l.loss, rng: rand.New(rand.NewSource(1))}, nil
+}
+
+type lossyConn struct {
+	net.Conn
+	loss float64
+	mu   sync.Mutex
+	rng  *rand.Rand
+}
+
+func (c *lossyConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	lost := c.rng.Float64() < c.loss
+	c.mu.Unlock()
+	if lost {
+		time.Sleep(lossPenalty)
+	}
+	return c.Conn.Write(p)
+}
+
+// lossyPacketConn drops datagrams in both directions with probability loss
+type lossyPacketConn struct {
+	net.PacketConn
+	loss float64
+	mu   sync.Mutex
+	rng  *rand.Rand
+}
+
+func (c *lossyPacketConn) drop() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < c.loss
+}
+
+func (c *lossyPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(p)
+		if err != nil || !c.drop() {
+			return n, addr, err
+		}
+	}
+}
+
+// SetReadBuffer and SetWriteBuffer let quic-go size the underlying socket
+func (c *lossyPacketConn) SetReadBuffer(n int) error {
+	return c.PacketConn.(*net.UDPConn).SetReadBuffer(n)
+}
+
+func (c *lossyPacketConn) SetWriteBuffer(n int) error {
+	return c.PacketConn.(*net.UDPConn).SetWriteBuffer(n)
+}
+
+func (c *lossyPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if c.drop() {
+		return len(p), nil
+	}
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+// startLossyPair is startTransportPair with loss injected at the receiver
+func startLossyPair(b *testing.B, transportType string, loss float64) (sender, receiver Transport) {
+	b.Helper()
+	cfg := Config{Type: transportType, NodeID: "agg-1", BufferSize: 1 << 16}
+	receiver, err := NewTransport(cfg)
+	if err != nil {
+		b.Fatalf("new receiver: %v", err)
+	}
+	b.Cleanup(func() { receiver.Close() })
+
+	var addr net.Addr
+	switch r := receiver.(type) {
+	case *QUICTransport:
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			b.Fatalf("listen: %v", err)
+		}
+		addr, err = r.serve(&lossyPacketConn{PacketConn: pc, loss: loss, rng: rand.New(rand.NewSource(1))})
+		if err != nil {
+			b.Fatalf("serve: %v", err)
+		}
+	default:
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			b.Fatalf("listen: %v", err)
+		}
+		var server *chunkServer
+		switch r := r.(type) {
+		case *TCPTransport:
+			server = r.server
+		case *MRCAdapter:
+			server = r.server
+		}
+		if err := server.serve(lossyListener{Listener: ln, loss: loss}); err != nil {
+			b.Fatalf("serve: %v", err)
+		}
+		addr = ln.Addr()
+	}
+
+	cfg.NodeID = "edge-1"
+	sender, err = NewTransport(cfg)
+	if err != nil {
+		b.Fatalf("new sender: %v", err)
+	}
+	b.Cleanup(func() { sender.Close() })
+	if err := sender.RegisterDestinationAddr("agg-1", addr.String()); err != nil {
+		b.Fatalf("register destination: %v", err)
+	}
+	return sender, receiver
+}
+
+// BenchmarkTransportLoopback compares tcp, quic and mrc on loopback with
+// injected loss, sending 1 KiB chunks from parallel goroutines
+func BenchmarkTransportLoopback(b *testing.B) {
+	payload := make([]float32, 256)
+	for _, typ := range transportTypes {
+		for _, loss := range []float64{0, 0.05} {
+			b.Run(fmt.Sprintf("%s/loss=%.0f%%", typ, loss*100), func(b *testing.B) {
+				sender, receiver := startLossyPair(b, typ, loss)
+				inbox, _ := receiver.Receive(context.Background())
+				go func() {
+					for range inbox {
+					}
+				}()
+
+				var next, failed atomic.Int64
+				ctx := context.Background()
+				b.SetBytes(int64(4 * len(payload)))
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						i := int(next.Add(1))
+						chunk := GradientChunk{ID: "bench", NodeID: "edge-1", Index: i, Total: i + 1, Payload: payload}
+						if err := sender.SendChunk(ctx, "agg-1", chunk); err != nil {
+							failed.Add(1)
+						}
+					}
+				})
+				b.StopTimer()
+				b.ReportMetric(float64(failed.Load())/float64(b.N), "failures/op")
+			})
+		}
+	}
+}
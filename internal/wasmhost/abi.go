@@ -0,0 +1,248 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Host-function ABI exposed to task and verifier modules.
+//
+// Imports provided by the "mohawk" host module:
+//
+//	log(level, ptr, len i32)                  requires manifest.CapLog
+//	submit_gradient(ptr, len i32) -> i32      requires manifest.CapSubmitGrad
+//	input_len(kind i32) -> i32                proof (0) or public inputs (1)
+//	read_input(kind, ptr, len i32) -> i32     copies up to len bytes, returns count
+//
+// submit_gradient takes little-endian f32 values and returns 0 on success.
+// input_len/read_input return -1 outside a verify_proof call. Calling an
+// import whose capability was not granted traps the guest.
+//
+// Guest exports:
+//
+//	memory                                    linear memory, required for buffers
+//	verify_proof(proof_len i32) -> i32        legacy; pull bytes with read_input
+//	verify_proof(proof_ptr, proof_len, inputs_ptr, inputs_len i32) -> i32
+//	alloc(size i32) -> i32                    required by the 4-arg verify_proof
+//	dealloc(ptr, size i32)                    optional, called after verify_proof
+//	run_task()                                entry point for RunTask
+package wasmhost
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// HostModuleName is the import module guests link the ABI from.
+const HostModuleName = "mohawk"
+
+// Input kinds accepted by input_len and read_input.
+const (
+	InputProof        = 0
+	InputPublicInputs = 1
+)
+
+// Config controls the sandbox a guest module runs in.
+type Config struct {
+	// Capabilities gate the log and submit_gradient imports.
+	Capabilities []manifest.Capability
+	// MaxMemPages caps guest linear memory in 64 KiB pages; 0 keeps the
+	// runtime default of 65536.
+	MaxMemPages uint32
+	// Logger receives guest log lines; nil writes them to the standard logger.
+	Logger func(level int32, msg string)
+	// GradientSink receives submitted gradients; nil rejects submissions.
+	GradientSink func(ctx context.Context, gradient []float32) error
+}
+
+// ConfigFromManifest verifies the orchestrator signature on m, and for v2
+// manifests its expiry and (with opts.Replay) single use, and returns a
+// Config carrying its capabilities and memory limit.
+func ConfigFromManifest(m *manifest.Manifest, orchestratorPub []byte, opts ...manifest.VerifyOptions) (Config, error) {
+	if m == nil {
+		return Config{}, fmt.Errorf("manifest is required")
+	}
+	var verify manifest.VerifyOptions
+	if len(opts) > 0 {
+		verify = opts[0]
+	}
+	if err := manifest.Verify(m, orchestratorPub, verify); err != nil {
+		return Config{}, fmt.Errorf("manifest rejected: %w", err)
+	}
+	return Config{
+		Capabilities: slices.Clone(m.Capabilities),
+		MaxMemPages:  m.MaxMemPages,
+	}, nil
+}
+
+// Granted reports whether the config allows capability.
+func (c Config) Granted(capability manifest.Capability) bool {
+	return slices.Contains(c.Capabilities, capability)
+}
+
+type callInputsKey struct{}
+
+// callInputs holds the buffers of the verify_proof call in flight.
+type callInputs struct {
+	proof        []byte
+	publicInputs []byte
+}
+
+func (in *callInputs) buffer(kind uint32) ([]byte, bool) {
+	if in == nil {
+		return nil, false
+	}
+	switch kind {
+	case InputProof:
+		return in.proof, true
+	case InputPublicInputs:
+		return in.publicInputs, true
+	}
+	return nil, false
+}
+
+func inputsFromContext(ctx context.Context) *callInputs {
+	in, _ := ctx.Value(callInputsKey{}).(*callInputs)
+	return in
+}
+
+// instantiateHostModule registers the "mohawk" imports on r.
+func instantiateHostModule(ctx context.Context, r wazero.Runtime, cfg Config) error {
+	_, err := r.NewHostModuleBuilder(HostModuleName).
+		NewFunctionBuilder().WithFunc(cfg.hostLog).Export("log").
+		NewFunctionBuilder().WithFunc(cfg.hostSubmitGradient).Export("submit_gradient").
+		NewFunctionBuilder().WithFunc(hostInputLen).Export("input_len").
+		NewFunctionBuilder().WithFunc(hostReadInput).Export("read_input").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("instantiate %s host module: %w", HostModuleName, err)
+	}
+	return nil
+}
+
+func (c Config) require(capability manifest.Capability) {
+	if !c.Granted(capability) {
+		// wazero turns host panics into a trap returned from the export call.
+		panic(fmt.Errorf("capability %s not granted by manifest", capability))
+	}
+}
+
+func (c Config) hostLog(_ context.Context, m api.Module, level int32, ptr, length uint32) {
+	c.require(manifest.CapLog)
+	msg, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		panic(fmt.Errorf("log: buffer [%d,+%d) out of bounds", ptr, length))
+	}
+	if c.Logger != nil {
+		c.Logger(level, string(msg))
+		return
+	}
+	log.Printf("wasm[%s] level=%d: %s", m.Name(), level, msg)
+}
+
+func (c Config) hostSubmitGradient(ctx context.Context, m api.Module, ptr, length uint32) int32 {
+	c.require(manifest.CapSubmitGrad)
+	if length%4 != 0 {
+		return 1
+	}
+	raw, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		panic(fmt.Errorf("submit_gradient: buffer [%d,+%d) out of bounds", ptr, length))
+	}
+	if c.GradientSink == nil {
+		return 1
+	}
+	grad := make([]float32, len(raw)/4)
+	for i := range grad {
+		grad[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	if err := c.GradientSink(ctx, grad); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func hostInputLen(ctx context.Context, kind uint32) int32 {
+	buf, ok := inputsFromContext(ctx).buffer(kind)
+	if !ok || len(buf) > math.MaxInt32 {
+		return -1
+	}
+	return int32(len(buf))
+}
+
+func hostReadInput(ctx context.Context, m api.Module, kind, ptr, length uint32) int32 {
+	buf, ok := inputsFromContext(ctx).buffer(kind)
+	if !ok {
+		return -1
+	}
+	if uint64(length) < uint64(len(buf)) {
+		buf = buf[:length]
+	}
+	if len(buf) > math.MaxInt32 || !m.Memory().Write(ptr, buf) {
+		return -1
+	}
+	return int32(len(buf))
+}
+
+// copyIn allocates len(data) bytes through the guest's alloc export and
+// writes data there. Empty buffers are passed as pointer 0.
+func (h *Host) copyIn(ctx context.Context, data []byte) (uint32, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	size, err := safeUint32FromInt(len(data))
+	if err != nil {
+		return 0, err
+	}
+	alloc := h.mod.ExportedFunction("alloc")
+	if alloc == nil {
+		return 0, fmt.Errorf("wasm module missing required export: alloc")
+	}
+	mem := h.mod.Memory()
+	if mem == nil {
+		return 0, fmt.Errorf("wasm module missing required export: memory")
+	}
+	res, err := alloc.Call(ctx, uint64(size))
+	if err != nil {
+		return 0, fmt.Errorf("guest alloc failed: %w", err)
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("guest alloc returned no results")
+	}
+	ptr := api.DecodeU32(res[0])
+	if !mem.Write(ptr, data) {
+		return 0, fmt.Errorf("guest alloc returned out-of-bounds buffer [%d,+%d)", ptr, size)
+	}
+	return ptr, nil
+}
+
+// release hands a copyIn buffer back to the guest's dealloc export, if any.
+func (h *Host) release(ctx context.Context, ptr uint32, size int) {
+	dealloc := h.mod.ExportedFunction("dealloc")
+	if dealloc == nil || size == 0 {
+		return
+	}
+	_, _ = dealloc.Call(ctx, uint64(ptr), uint64(size))
+}
+
+func safeUint32FromInt(v int) (uint32, error) {
+	if v < 0 || uint64(v) > math.MaxUint32 {
+		return 0, fmt.Errorf("value %d does not fit in a wasm i32", v)
+	}
+	return uint32(v), nil
+}
@@ -0,0 +1,361 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmhost
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+// Wasm opcodes used by the hand-assembled guests below.
+const (
+	opLoop      = 0x03
+	opBr        = 0x0c
+	opEnd       = 0x0b
+	opCall      = 0x10
+	opDrop      = 0x1a
+	opLocalGet  = 0x20
+	opGlobalGet = 0x23
+	opGlobalSet = 0x24
+	opLoad8U    = 0x2d
+	opI32Const  = 0x41
+	opI32Eq     = 0x46
+	opI32Add    = 0x6a
+	opI32And    = 0x71
+	valI32      = 0x7f
+)
+
+func wasmName(s string) []byte {
+	return append(encodeVarUint32(uint32(len(s))), s...)
+}
+
+func wasmFuncType(params, results int) []byte {
+	out := append([]byte{0x60}, encodeVarUint32(uint32(params))...)
+	for i := 0; i < params; i++ {
+		out = append(out, valI32)
+	}
+	out = append(out, encodeVarUint32(uint32(results))...)
+	for i := 0; i < results; i++ {
+		out = append(out, valI32)
+	}
+	return out
+}
+
+func wasmVec(items ...[]byte) []byte {
+	out := encodeVarUint32(uint32(len(items)))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func wasmBody(instrs ...byte) []byte {
+	body := append([]byte{0x00}, instrs...) // no locals
+	body = append(body, opEnd)
+	return append(encodeVarUint32(uint32(len(body))), body...)
+}
+
+func wasmImportFunc(name string, typeIdx byte) []byte {
+	out := append(wasmName(HostModuleName), wasmName(name)...)
+	return append(out, 0x00, typeIdx)
+}
+
+func wasmExport(name string, kind, idx byte) []byte {
+	return append(wasmName(name), kind, idx)
+}
+
+func wasmModule(sections map[byte][]byte) []byte {
+	out := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	for id := byte(1); id <= 11; id++ {
+		if payload, ok := sections[id]; ok {
+			out = appendSection(out, id, payload)
+		}
+	}
+	return out
+}
+
+// taskModule logs "hi" and submits the f32 gradient [1.5, -2] from run_task.
+func taskModule() []byte {
+	grad := make([]byte, 8)
+	binary.LittleEndian.PutUint32(grad, math.Float32bits(1.5))
+	binary.LittleEndian.PutUint32(grad[4:], math.Float32bits(-2))
+	data := append([]byte{0x00, opI32Const, 16, opEnd}, wasmName(string(grad))...)
+
+	return wasmModule(map[byte][]byte{
+		1: wasmVec(wasmFuncType(3, 0), wasmFuncType(2, 1), wasmFuncType(0, 0)),
+		2: wasmVec(wasmImportFunc("log", 0), wasmImportFunc("submit_gradient", 1)),
+		3: wasmVec([]byte{2}),
+		5: wasmVec([]byte{0x00, 0x01}),
+		7: wasmVec(wasmExport("memory", 0x02, 0), wasmExport("run_task", 0x00, 2)),
+		10: wasmVec(wasmBody(
+			opI32Const, 1, opI32Const, 0, opI32Const, 2, opCall, 0,
+			opI32Const, 16, opI32Const, 8, opCall, 1, opDrop,
+		)),
+		11: wasmVec(append([]byte{0x00, opI32Const, 0, opEnd}, wasmName("hi")...), data),
+	})
+}
+
+// allocVerifierModule exports a bump allocator and a four-argument
+// verify_proof that accepts when proof[0] == inputs[0].
+func allocVerifierModule() []byte {
+	return wasmModule(map[byte][]byte{
+		1: wasmVec(wasmFuncType(1, 1), wasmFuncType(4, 1)),
+		3: wasmVec([]byte{0}, []byte{1}),
+		5: wasmVec([]byte{0x00, 0x01}),
+		6: wasmVec([]byte{valI32, 0x01, opI32Const, 0x80, 0x08, opEnd}), // heap at 1024
+		7: wasmVec(wasmExport("memory", 0x02, 0), wasmExport("alloc", 0x00, 0), wasmExport("verify_proof", 0x00, 1)),
+		10: wasmVec(
+			wasmBody(opGlobalGet, 0, opGlobalGet, 0, opLocalGet, 0, opI32Add, opGlobalSet, 0),
+			wasmBody(opLocalGet, 0, opLoad8U, 0, 0, opLocalGet, 2, opLoad8U, 0, 0, opI32Eq),
+		),
+	})
+}
+
+// readInputVerifierModule exports a legacy verify_proof(len) that pulls the
+// proof through read_input and accepts when all bytes arrived and proof[0] == 42.
+func readInputVerifierModule() []byte {
+	return wasmModule(map[byte][]byte{
+		1: wasmVec(wasmFuncType(3, 1), wasmFuncType(1, 1)),
+		2: wasmVec(wasmImportFunc("read_input", 0)),
+		3: wasmVec([]byte{1}),
+		5: wasmVec([]byte{0x00, 0x01}),
+		7: wasmVec(wasmExport("memory", 0x02, 0), wasmExport("verify_proof", 0x00, 1)),
+		10: wasmVec(wasmBody(
+			opI32Const, InputProof, opI32Const, 0, opLocalGet, 0, opCall, 0,
+			opLocalGet, 0, opI32Eq,
+			opI32Const, 0, opLoad8U, 0, 0, opI32Const, 42, opI32Eq,
+			opI32And,
+		)),
+	})
+}
+
+func TestRunTaskHonorsCapabilities(t *testing.T) {
+	ctx := context.Background()
+	var logged string
+	var submitted []float32
+	host, err := NewHostWithConfig(ctx, taskModule(), Config{
+		Capabilities: []manifest.Capability{manifest.CapLog, manifest.CapSubmitGrad},
+		Logger:       func(_ int32, msg string) { logged = msg },
+		GradientSink: func(_ context.Context, g []float32) error {
+			submitted = g
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	defer host.Close(ctx)
+
+	if !host.HasExport("run_task") || host.HasExport("verify_proof") {
+		t.Fatal("expected HasExport to report only run_task")
+	}
+	if err := host.RunTask(ctx, 0); err != nil {
+		t.Fatalf("run_task: %v", err)
+	}
+	if logged != "hi" {
+		t.Fatalf("logged %q, want %q", logged, "hi")
+	}
+	if len(submitted) != 2 || submitted[0] != 1.5 || submitted[1] != -2 {
+		t.Fatalf("submitted %v", submitted)
+	}
+}
+
+func TestRunTaskTrapsOnUngrantedCapability(t *testing.T) {
+	ctx := context.Background()
+	host, err := NewHostWithConfig(ctx, taskModule(), Config{
+		Capabilities: []manifest.Capability{manifest.CapLog},
+		Logger:       func(int32, string) {},
+		GradientSink: func(context.Context, []float32) error {
+			t.Fatal("gradient sink reached without SUBMIT_GRADIENTS")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	defer host.Close(ctx)
+
+	err = host.RunTask(ctx, 0)
+	if err == nil || !strings.Contains(err.Error(), string(manifest.CapSubmitGrad)) {
+		t.Fatalf("expected capability trap, got %v", err)
+	}
+}
+
+// TestShippedTaskModulesUseABI runs the task modules built from
+// wasm-modules/*/src/lib.rs against the mohawk host module.
+func TestShippedTaskModulesUseABI(t *testing.T) {
+	ctx := context.Background()
+	for name, want := range map[string][]float32{
+		"fl_task":      {0.1, 0.2, 0.3},
+		"flower_task":  {0.05, 0.12, 0.09, 0.14},
+		"pytorch_task": {0.11, 0.07, 0.19, 0.03},
+	} {
+		t.Run(name, func(t *testing.T) {
+			wasm, err := os.ReadFile(filepath.Join("..", "..", "wasm-modules", name, "target", "wasm32-unknown-unknown", "release", name+".wasm"))
+			if err != nil {
+				t.Fatalf("read module: %v", err)
+			}
+			var logged []string
+			var submitted []float32
+			host, err := NewHostWithConfig(ctx, wasm, Config{
+				Capabilities: []manifest.Capability{manifest.CapLog, manifest.CapSubmitGrad},
+				MaxMemPages:  64,
+				Logger:       func(_ int32, msg string) { logged = append(logged, msg) },
+				GradientSink: func(_ context.Context, g []float32) error {
+					submitted = g
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("instantiate: %v", err)
+			}
+			defer host.Close(ctx)
+
+			if err := host.RunTask(ctx, 0); err != nil {
+				t.Fatalf("run_task: %v", err)
+			}
+			if !slices.Equal(submitted, want) || len(logged) == 0 {
+				t.Fatalf("submitted %v after logging %q, want %v", submitted, logged, want)
+			}
+		})
+	}
+}
+
+func TestVerifyCopiesProofAndInputsIntoGuest(t *testing.T) {
+	ctx := context.Background()
+	host, err := NewHost(ctx, allocVerifierModule())
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	defer host.Close(ctx)
+
+	ok, err := host.VerifyWithInputs(ctx, []byte{7, 1, 2}, []byte{7}, 0)
+	if err != nil || !ok {
+		t.Fatalf("expected match, got %v %v", ok, err)
+	}
+	ok, err = host.VerifyWithInputs(ctx, []byte{7, 1, 2}, []byte{8}, 0)
+	if err != nil || ok {
+		t.Fatalf("expected mismatch, got %v %v", ok, err)
+	}
+}
+
+func TestVerifyLegacyExportReadsProof(t *testing.T) {
+	ctx := context.Background()
+	host, err := NewHost(ctx, readInputVerifierModule())
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	defer host.Close(ctx)
+
+	if ok, err := host.Verify(ctx, []byte{42, 0, 0, 0}, 0); err != nil || !ok {
+		t.Fatalf("expected proof to reach guest, got %v %v", ok, err)
+	}
+	if ok, err := host.Verify(ctx, []byte{41, 0, 0, 0}, 0); err != nil || ok {
+		t.Fatalf("expected guest to reject proof, got %v %v", ok, err)
+	}
+}
+
+func TestMaxMemPagesLimitsGuestMemory(t *testing.T) {
+	ctx := context.Background()
+	wasm := wasmModule(map[byte][]byte{
+		5: wasmVec([]byte{0x00, 0x02}),
+	})
+	if _, err := NewHostWithConfig(ctx, wasm, Config{MaxMemPages: 1}); err == nil {
+		t.Fatal("expected module needing 2 pages to be rejected under a 1-page limit")
+	}
+	host, err := NewHostWithConfig(ctx, wasm, Config{MaxMemPages: 2})
+	if err != nil {
+		t.Fatalf("instantiate within limit: %v", err)
+	}
+	_ = host.Close(ctx)
+}
+
+func TestRunTaskTimesOutLoopingGuest(t *testing.T) {
+	ctx := context.Background()
+	wasm := wasmModule(map[byte][]byte{
+		1:  wasmVec(wasmFuncType(0, 0)),
+		3:  wasmVec([]byte{0}),
+		7:  wasmVec(wasmExport("run_task", 0x00, 0)),
+		10: wasmVec(wasmBody(opLoop, 0x40, opBr, 0, opEnd)),
+	})
+	host, err := NewHostWithConfig(ctx, wasm, Config{})
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	defer host.Close(ctx)
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() { done <- host.RunTask(ctx, 100) }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Fatalf("expected a timeout error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("guest stopped after %v, want about 100ms", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("looping guest was not stopped at its deadline")
+	}
+}
+
+func TestConfigFromManifestRequiresSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("keygen: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	m := &manifest.Manifest{
+		TaskID:       "task-1",
+		NodeID:       "node-1",
+		Capabilities: []manifest.Capability{manifest.CapLog},
+		MaxMemPages:  4,
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	m.Signature = ed25519.Sign(priv, data)
+
+	cfg, err := ConfigFromManifest(m, der)
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if !cfg.Granted(manifest.CapLog) || cfg.Granted(manifest.CapSubmitGrad) || cfg.MaxMemPages != 4 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	m.Capabilities = append(m.Capabilities, manifest.CapSubmitGrad)
+	if _, err := ConfigFromManifest(m, der); err == nil {
+		t.Fatal("expected tampered capabilities to be rejected")
+	}
+}
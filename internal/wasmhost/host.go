@@ -66,16 +66,33 @@ func NewRegistry() *Registry {
 // It enables the wazero compilation cache so that modules are compiled once
 // and reused on subsequent startups, and enables WASM SIMD intrinsics where
 // the host CPU supports them (detected automatically by wazero).
+// The module is granted no capabilities; use NewHostWithConfig for tasks.
 func NewHost(ctx context.Context, wasmBin []byte) (*Host, error) {
+	return NewHostWithConfig(ctx, wasmBin, Config{})
+}
+
+// NewHostWithConfig initializes a Wasm environment whose "mohawk" imports and
+// memory limit follow config. Guests are closed when their call's context is
+// done, so the execution deadline stops a guest that never returns.
+func NewHostWithConfig(ctx context.Context, wasmBin []byte, config Config) (*Host, error) {
 	if err := ValidateModuleLimits(wasmBin); err != nil {
 		return nil, err
 	}
 
 	cfg := wazero.NewRuntimeConfig().
-		WithCompilationCache(newCompilationCache(ctx))
+		WithCompilationCache(newCompilationCache(ctx)).
+		WithCloseOnContextDone(true)
+	if config.MaxMemPages > 0 {
+		cfg = cfg.WithMemoryLimitPages(config.MaxMemPages)
+	}
 
 	r := wazero.NewRuntimeWithConfig(ctx, cfg)
 
+	if err := instantiateHostModule(ctx, r, config); err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+
 	mod, err := r.Instantiate(ctx, wasmBin)
 	if err != nil {
 		r.Close(ctx)
@@ -462,18 +479,29 @@ func (r *Registry) Close(ctx context.Context) error {
 	return nil
 }
 
+// HasExport reports whether the module exports a function called name.
+func (h *Host) HasExport(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.mod.ExportedFunction(name) != nil
+}
+
 // Verify executes the "verify_proof" Wasm export and enforces the per-manifest
 // execution deadline. maxMillis == 0 falls back to DefaultMaxMillis.
 func (h *Host) Verify(ctx context.Context, proof []byte, maxMillis uint64) (bool, error) {
-	if maxMillis == 0 {
-		maxMillis = DefaultMaxMillis
-	}
-	deadline, err := safeDurationFromMillis(maxMillis)
+	return h.VerifyWithInputs(ctx, proof, nil, maxMillis)
+}
+
+// VerifyWithInputs is Verify with public inputs made available to the guest.
+// Four-argument verify_proof exports receive both buffers copied into guest
+// memory; legacy one-argument exports read them through read_input.
+func (h *Host) VerifyWithInputs(ctx context.Context, proof, publicInputs []byte, maxMillis uint64) (bool, error) {
+	execCtx, cancel, err := withExecDeadline(ctx, maxMillis)
 	if err != nil {
 		return false, err
 	}
-	execCtx, cancel := context.WithTimeout(ctx, deadline)
 	defer cancel()
+	execCtx = context.WithValue(execCtx, callInputsKey{}, &callInputs{proof: proof, publicInputs: publicInputs})
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -488,12 +516,27 @@ func (h *Host) Verify(ctx context.Context, proof []byte, maxMillis uint64) (bool
 	if err != nil {
 		return false, err
 	}
-	results, err := fn.Call(execCtx, proofLen)
-	if err != nil {
-		if execCtx.Err() != nil {
-			return false, fmt.Errorf("wasm execution timed out after %dms: %w", maxMillis, execCtx.Err())
+	var results []uint64
+	switch params := len(fn.Definition().ParamTypes()); params {
+	case 1:
+		results, err = fn.Call(execCtx, proofLen)
+	case 4:
+		proofPtr, cerr := h.copyIn(execCtx, proof)
+		if cerr != nil {
+			return false, cerr
 		}
-		return false, fmt.Errorf("wasm execution error: %w", err)
+		defer h.release(execCtx, proofPtr, len(proof))
+		inputsPtr, cerr := h.copyIn(execCtx, publicInputs)
+		if cerr != nil {
+			return false, cerr
+		}
+		defer h.release(execCtx, inputsPtr, len(publicInputs))
+		results, err = fn.Call(execCtx, uint64(proofPtr), proofLen, uint64(inputsPtr), uint64(len(publicInputs)))
+	default:
+		return false, fmt.Errorf("wasm verify_proof takes %d params; want 1 or 4", params)
+	}
+	if err != nil {
+		return false, execError(execCtx, maxMillis, err)
 	}
 
 	if len(results) == 0 {
@@ -503,6 +546,50 @@ func (h *Host) Verify(ctx context.Context, proof []byte, maxMillis uint64) (bool
 	return results[0] == 1, nil
 }
 
+// RunTask executes the "run_task" export of a task module under the same
+// deadline rules as Verify.
+func (h *Host) RunTask(ctx context.Context, maxMillis uint64) error {
+	execCtx, cancel, err := withExecDeadline(ctx, maxMillis)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fn := h.mod.ExportedFunction("run_task")
+	if fn == nil {
+		return fmt.Errorf("wasm module missing required export: run_task")
+	}
+	if _, err := fn.Call(execCtx); err != nil {
+		return execError(execCtx, maxMillis, err)
+	}
+	return nil
+}
+
+func withExecDeadline(ctx context.Context, maxMillis uint64) (context.Context, context.CancelFunc, error) {
+	if maxMillis == 0 {
+		maxMillis = DefaultMaxMillis
+	}
+	deadline, err := safeDurationFromMillis(maxMillis)
+	if err != nil {
+		return nil, nil, err
+	}
+	execCtx, cancel := context.WithTimeout(ctx, deadline)
+	return execCtx, cancel, nil
+}
+
+func execError(execCtx context.Context, maxMillis uint64, err error) error {
+	if execCtx.Err() != nil {
+		if maxMillis == 0 {
+			maxMillis = DefaultMaxMillis
+		}
+		return fmt.Errorf("wasm execution timed out after %dms: %w", maxMillis, execCtx.Err())
+	}
+	return fmt.Errorf("wasm execution error: %w", err)
+}
+
 // FastVerify is an optimized alias for Verify with the default timeout.
 func (h *Host) FastVerify(ctx context.Context, proof []byte) (bool, error) {
 	return h.Verify(ctx, proof, DefaultMaxMillis)
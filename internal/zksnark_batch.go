@@ -0,0 +1,179 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Reference: /proofs/cryptography.md
+// Theorem 5: batched Groth16 verification for thousands of client proofs.
+//
+// For N proofs under one VK and random 128-bit scalars rⱼ the batch check is
+//
+//	Π e(−rⱼ·Aⱼ, Bⱼ) · e((Σrⱼ)·α, β) · e(Σ rⱼ·vk_xⱼ, γ) · e(Σ rⱼ·Cⱼ, δ) = 1
+//
+// which costs N+3 Miller loops and a single final exponentiation instead of
+// 4N and N. A forged proof passes only if it cancels against the others under
+// scalars it cannot predict, i.e. with probability about 2⁻¹²⁸. When the
+// aggregate check fails the batch is bisected to isolate the invalid proofs.
+package internal
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// batchChallengeBits is the size of the random linear-combination scalars.
+const batchChallengeBits = 128
+
+// BatchProofResult reports the per-proof outcome of a batch verification.
+type BatchProofResult struct {
+	Valid []bool
+	// Errors holds the parse error for malformed proofs or inputs; nil for
+	// well-formed entries, including ones that failed the pairing check.
+	Errors []error
+	// PairingChecks counts aggregate pairing checks, including bisection rounds.
+	PairingChecks int
+}
+
+// AllValid reports whether every proof in the batch verified.
+func (r BatchProofResult) AllValid() bool {
+	for _, ok := range r.Valid {
+		if !ok {
+			return false
+		}
+	}
+	return len(r.Valid) > 0
+}
+
+// BatchVerifyProofs verifies proofs[i] against publicInputs[i] under the
+// verifying key registered for circuitID.
+func BatchVerifyProofs(circuitID string, proofs [][]byte, publicInputs [][]byte) (BatchProofResult, error) {
+	vk, err := LookupVerifyingKey(circuitID)
+	if err != nil {
+		return BatchProofResult{}, err
+	}
+	return BatchVerifyProofsWithKey(vk, proofs, publicInputs)
+}
+
+// BatchVerifyProofsWithKey is BatchVerifyProofs with an explicit verifying key.
+// publicInputs may be nil when the circuit has no public inputs.
+func BatchVerifyProofsWithKey(vk *VerifyingKey, proofs [][]byte, publicInputs [][]byte) (BatchProofResult, error) {
+	if len(proofs) == 0 {
+		return BatchProofResult{}, errors.New("batch is empty")
+	}
+	if publicInputs != nil && len(publicInputs) != len(proofs) {
+		return BatchProofResult{}, fmt.Errorf("public inputs for %d proofs, got %d proofs", len(publicInputs), len(proofs))
+	}
+
+	result := BatchProofResult{Valid: make([]bool, len(proofs)), Errors: make([]error, len(proofs))}
+	entries := make([]decodedProof, 0, len(proofs))
+	for i, proof := range proofs {
+		var inputs []byte
+		if publicInputs != nil {
+			inputs = publicInputs[i]
+		}
+		entry, err := decodeProof(vk, proof, inputs)
+		if err != nil {
+			result.Errors[i] = err
+			continue
+		}
+		entry.index = i
+		entries = append(entries, entry)
+	}
+
+	if err := bisectBatch(vk, entries, &result); err != nil {
+		return BatchProofResult{}, err
+	}
+	return result, nil
+}
+
+// bisectBatch marks entries valid when their aggregate check passes and
+// otherwise recurses on both halves until single invalid proofs are isolated.
+func bisectBatch(vk *VerifyingKey, entries []decodedProof, result *BatchProofResult) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ok, err := aggregatePairingCheck(vk, entries)
+	if err != nil {
+		return err
+	}
+	result.PairingChecks++
+	if ok {
+		for _, e := range entries {
+			result.Valid[e.index] = true
+		}
+		return nil
+	}
+	if len(entries) == 1 {
+		return nil
+	}
+	mid := len(entries) / 2
+	if err := bisectBatch(vk, entries[:mid], result); err != nil {
+		return err
+	}
+	return bisectBatch(vk, entries[mid:], result)
+}
+
+// aggregatePairingCheck runs the random-linear-combination Groth16 check.
+// Fresh scalars are drawn for every call so bisection rounds stay sound.
+func aggregatePairingCheck(vk *VerifyingKey, entries []decodedProof) (bool, error) {
+	n := len(entries)
+	scalars := make([]fr.Element, n)
+	bound := new(big.Int).Lsh(big.NewInt(1), batchChallengeBits)
+	for i := range scalars {
+		r, err := rand.Int(rand.Reader, bound)
+		if err != nil {
+			return false, fmt.Errorf("sample batch scalar: %w", err)
+		}
+		// r ∈ [1, 2¹²⁸]
+		scalars[i].SetBigInt(r.Add(r, big.NewInt(1)))
+	}
+
+	g1 := make([]bn254.G1Affine, 0, n+3)
+	g2 := make([]bn254.G2Affine, 0, n+3)
+
+	cs := make([]bn254.G1Affine, n)
+	xs := make([]bn254.G1Affine, n)
+	var sumR fr.Element
+	for i, e := range entries {
+		var negRA bn254.G1Affine
+		negRA.ScalarMultiplication(&e.a, scalars[i].BigInt(new(big.Int)))
+		negRA.Neg(&negRA)
+		g1 = append(g1, negRA)
+		g2 = append(g2, e.b)
+		cs[i] = e.c
+		xs[i] = e.vkX
+		sumR.Add(&sumR, &scalars[i])
+	}
+
+	var alphaR, sumX, sumC bn254.G1Affine
+	alphaR.ScalarMultiplication(&vk.Alpha, sumR.BigInt(new(big.Int)))
+	if _, err := sumX.MultiExp(xs, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, fmt.Errorf("batch vk_x combination failed: %w", err)
+	}
+	if _, err := sumC.MultiExp(cs, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, fmt.Errorf("batch C combination failed: %w", err)
+	}
+	g1 = append(g1, alphaR, sumX, sumC)
+	g2 = append(g2, vk.Beta, vk.Gamma, vk.Delta)
+
+	ok, err := bn254.PairingCheck(g1, g2)
+	if err != nil {
+		return false, fmt.Errorf("pairing computation failed: %w", err)
+	}
+	return ok, nil
+}
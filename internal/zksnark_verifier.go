@@ -22,103 +22,66 @@
 //	[32:96] — B: G2 point (compressed, 64 bytes)
 //	[96:128]— C: G1 point (compressed, 32 bytes)
 //
-// Verification equation:  e(-A, B) · e(α, β) · e(IC₀, γ) · e(C, δ) = 1
-// Genesis VK uses canonical BN254 generator points (α=G1, β=G2, γ=G2, δ=G2, IC₀=G1).
-// A valid genesis proof satisfying this VK with no public inputs: A=G1, B=G2, C=−G1.
+// Public inputs are concatenated 32-byte big-endian BN254 scalars, optionally
+// prefixed by gnark's 12-byte public witness header.
+//
+// Verification equation:  e(-A, B) · e(α, β) · e(vk_x, γ) · e(C, δ) = 1
+// where vk_x = IC₀ + Σ xᵢ·ICᵢ and the key is looked up by circuit ID.
 package internal
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
 
 // ProofBytes is the wire size of a compressed BN254 Groth16 proof.
 const ProofBytes = bn254.SizeOfG1AffineCompressed + bn254.SizeOfG2AffineCompressed + bn254.SizeOfG1AffineCompressed
 
-// genesisVK holds the protocol genesis verification key.
-// All points are set to canonical BN254 generators in init().
-var genesisVK struct {
-	Alpha bn254.G1Affine
-	Beta  bn254.G2Affine
-	Gamma bn254.G2Affine
-	Delta bn254.G2Affine
-	IC0   bn254.G1Affine // constant IC term for zero-public-input statements
-}
-
-func init() {
-	_, _, g1, g2 := bn254.Generators()
-	genesisVK.Alpha = g1
-	genesisVK.Beta = g2
-	genesisVK.Gamma = g2
-	genesisVK.Delta = g2
-	genesisVK.IC0 = g1
-}
-
-// GenesisProofBytes returns a deterministic BN254 Groth16 proof valid under the
-// genesis verification key with no public inputs.
-// Proof: A=G1gen, B=G2gen, C=−G1gen satisfies e(−A,B)·e(α,β)·e(IC₀,γ)·e(C,δ)=1.
-func GenesisProofBytes() []byte {
-	_, _, g1, g2 := bn254.Generators()
-	var negG1 bn254.G1Affine
-	negG1.Neg(&g1)
-
-	aB := g1.Bytes()
-	bB := g2.Bytes()
-	cB := negG1.Bytes()
-
-	proof := make([]byte, ProofBytes)
-	copy(proof[0:32], aB[:])
-	copy(proof[32:96], bB[:])
-	copy(proof[96:128], cB[:])
-	return proof
-}
+// publicWitnessHeaderBytes is gnark's witness header: nbPublic | nbSecret | nbElements (uint32 each).
+const publicWitnessHeaderBytes = 12
 
-// VerifyProof performs BN254 Groth16 pairing verification against the genesis VK.
+// VerifyProof performs BN254 Groth16 pairing verification of proof against the
+// verifying key registered for circuitID, bound to the given public inputs.
 // Proof layout (128 bytes, compressed): A [0:32] | B [32:96] | C [96:128].
-// The inputs parameter is reserved for public statement scalars; currently unused
-// in zero-public-input mode (IC length = 1).
 //
 // Active Guard — Theorem 5: verification must complete within 15 ms.
 // The four Miller-loop pairing check on BN254 is O(1) in the number of nodes
 // because proof size is constant regardless of the aggregation scale.
-func VerifyProof(proof []byte, inputs []byte) (bool, error) {
-	start := time.Now()
-
+func VerifyProof(circuitID string, proof []byte, publicInputs []byte) (bool, error) {
 	if len(proof) < ProofBytes {
 		return false, fmt.Errorf(
 			"invalid proof size: got %d bytes, need %d (BN254 compressed: G1[32]+G2[64]+G1[32])",
 			len(proof), ProofBytes)
 	}
-
-	var pA bn254.G1Affine
-	var pB bn254.G2Affine
-	var pC bn254.G1Affine
-
-	if _, err := pA.SetBytes(proof[0:32]); err != nil {
-		return false, fmt.Errorf("invalid proof A (G1): %w", err)
-	}
-	if _, err := pB.SetBytes(proof[32:96]); err != nil {
-		return false, fmt.Errorf("invalid proof B (G2): %w", err)
-	}
-	if _, err := pC.SetBytes(proof[96:128]); err != nil {
-		return false, fmt.Errorf("invalid proof C (G1): %w", err)
+	vk, err := LookupVerifyingKey(circuitID)
+	if err != nil {
+		return false, err
 	}
+	return VerifyProofWithKey(vk, proof, publicInputs)
+}
 
-	// Reject proofs with any degenerate infinity point.
-	if pA.IsInfinity() || pB.IsInfinity() || pC.IsInfinity() {
-		return false, errors.New("degenerate proof: one or more points are at infinity")
+// VerifyProofWithKey verifies proof against an explicit verifying key.
+func VerifyProofWithKey(vk *VerifyingKey, proof []byte, publicInputs []byte) (bool, error) {
+	start := time.Now()
+
+	p, err := decodeProof(vk, proof, publicInputs)
+	if err != nil {
+		return false, err
 	}
 
-	// Groth16 multi-pairing check:  e(−A, B) · e(α, β) · e(IC₀, γ) · e(C, δ) = 1
+	// Groth16 multi-pairing check:  e(−A, B) · e(α, β) · e(vk_x, γ) · e(C, δ) = 1
 	var negA bn254.G1Affine
-	negA.Neg(&pA)
+	negA.Neg(&p.a)
 
 	ok, err := bn254.PairingCheck(
-		[]bn254.G1Affine{negA, genesisVK.Alpha, genesisVK.IC0, pC},
-		[]bn254.G2Affine{pB, genesisVK.Beta, genesisVK.Gamma, genesisVK.Delta},
+		[]bn254.G1Affine{negA, vk.Alpha, p.vkX, p.c},
+		[]bn254.G2Affine{p.b, vk.Beta, vk.Gamma, vk.Delta},
 	)
 	if err != nil {
 		return false, fmt.Errorf("pairing computation failed: %w", err)
@@ -131,3 +94,80 @@ func VerifyProof(proof []byte, inputs []byte) (bool, error) {
 
 	return ok, nil
 }
+
+// decodedProof is a parsed proof together with its public-input commitment.
+type decodedProof struct {
+	index int // position in a batch
+	a     bn254.G1Affine
+	b     bn254.G2Affine
+	c     bn254.G1Affine
+	vkX   bn254.G1Affine
+}
+
+// decodeProof parses the proof points and computes vk_x for publicInputs.
+func decodeProof(vk *VerifyingKey, proof []byte, publicInputs []byte) (decodedProof, error) {
+	var p decodedProof
+	if len(proof) < ProofBytes {
+		return p, fmt.Errorf(
+			"invalid proof size: got %d bytes, need %d (BN254 compressed: G1[32]+G2[64]+G1[32])",
+			len(proof), ProofBytes)
+	}
+
+	inputs, err := ParsePublicInputs(publicInputs)
+	if err != nil {
+		return p, err
+	}
+	if len(inputs) != vk.NbPublicInputs() {
+		return p, fmt.Errorf("invalid public inputs: got %d, circuit expects %d", len(inputs), vk.NbPublicInputs())
+	}
+
+	if _, err := p.a.SetBytes(proof[0:32]); err != nil {
+		return p, fmt.Errorf("invalid proof A (G1): %w", err)
+	}
+	if _, err := p.b.SetBytes(proof[32:96]); err != nil {
+		return p, fmt.Errorf("invalid proof B (G2): %w", err)
+	}
+	if _, err := p.c.SetBytes(proof[96:128]); err != nil {
+		return p, fmt.Errorf("invalid proof C (G1): %w", err)
+	}
+
+	// Reject proofs with any degenerate infinity point.
+	if p.a.IsInfinity() || p.b.IsInfinity() || p.c.IsInfinity() {
+		return p, errors.New("degenerate proof: one or more points are at infinity")
+	}
+
+	// vk_x = IC₀ + Σ xᵢ·ICᵢ binds the proof to the public statement.
+	p.vkX = vk.IC[0]
+	if len(inputs) > 0 {
+		var acc bn254.G1Affine
+		if _, err := acc.MultiExp(vk.IC[1:], inputs, ecc.MultiExpConfig{}); err != nil {
+			return p, fmt.Errorf("public input commitment failed: %w", err)
+		}
+		p.vkX.Add(&p.vkX, &acc)
+	}
+	return p, nil
+}
+
+// ParsePublicInputs decodes concatenated 32-byte big-endian scalars, accepting
+// an optional gnark public witness header. Non-canonical scalars are rejected.
+func ParsePublicInputs(raw []byte) ([]fr.Element, error) {
+	if len(raw)%fr.Bytes == publicWitnessHeaderBytes {
+		nbPublic := binary.BigEndian.Uint32(raw[0:4])
+		nbElements := binary.BigEndian.Uint32(raw[8:12])
+		body := raw[publicWitnessHeaderBytes:]
+		if nbPublic != nbElements || int(nbElements)*fr.Bytes != len(body) {
+			return nil, errors.New("invalid public inputs: malformed witness header")
+		}
+		raw = body
+	}
+	if len(raw)%fr.Bytes != 0 {
+		return nil, fmt.Errorf("invalid public inputs: %d bytes is not a multiple of %d", len(raw), fr.Bytes)
+	}
+	out := make([]fr.Element, len(raw)/fr.Bytes)
+	for i := range out {
+		if err := out[i].SetBytesCanonical(raw[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, fmt.Errorf("invalid public inputs: element %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
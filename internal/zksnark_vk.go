@@ -0,0 +1,201 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Reference: /proofs/cryptography.md
+// Groth16 verifying keys and the per-circuit registry used by VerifyProof.
+//
+// Serialization matches gnark's groth16/bn254 VerifyingKey.WriteTo (compressed
+// points, or uncompressed when written with WriteRawTo):
+//
+//	[α]1 | [β]1 | [β]2 | [γ]2 | [δ]1 | [δ]2 | uint32 len(K) | K[0..n] (G1)
+//	| uint32 len(publicCommitted) ... | uint32 nbCommitmentKeys
+//
+// Keys with Pedersen commitments are rejected; circuits must not use
+// api.Commit until the verifier supports the commitment PoK.
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// VerifyingKeyExt is the file extension LoadVerifyingKeysFromDir picks up.
+const VerifyingKeyExt = ".vk"
+
+// VerifyingKey is a BN254 Groth16 verifying key. IC[0] is the constant term;
+// IC[i] weights public input i.
+type VerifyingKey struct {
+	Alpha   bn254.G1Affine
+	BetaG1  bn254.G1Affine
+	Beta    bn254.G2Affine
+	Gamma   bn254.G2Affine
+	DeltaG1 bn254.G1Affine
+	Delta   bn254.G2Affine
+	IC      []bn254.G1Affine
+}
+
+// NbPublicInputs returns the number of public inputs the circuit expects.
+func (vk *VerifyingKey) NbPublicInputs() int {
+	return len(vk.IC) - 1
+}
+
+// WriteTo writes the key in gnark's compressed encoding.
+func (vk *VerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	return vk.writeTo(bn254.NewEncoder(w))
+}
+
+// WriteRawTo writes the key in gnark's uncompressed encoding.
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.writeTo(bn254.NewEncoder(w, bn254.RawEncoding()))
+}
+
+func (vk *VerifyingKey) writeTo(enc *bn254.Encoder) (int64, error) {
+	for _, v := range []interface{}{&vk.Alpha, &vk.BetaG1, &vk.Beta, &vk.Gamma, &vk.DeltaG1, &vk.Delta, vk.IC, [][]uint64{}} {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	// no commitment keys
+	if err := enc.Encode(uint32(0)); err != nil {
+		return enc.BytesWritten(), err
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a key written by gnark or WriteTo/WriteRawTo. Points are
+// subgroup-checked.
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	dec := bn254.NewDecoder(r)
+	for _, v := range []interface{}{&vk.Alpha, &vk.BetaG1, &vk.Beta, &vk.Gamma, &vk.DeltaG1, &vk.Delta, &vk.IC} {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), fmt.Errorf("decode verifying key: %w", err)
+		}
+	}
+	var publicCommitted [][]uint64
+	var nbCommitments uint32
+	if err := dec.Decode(&publicCommitted); err != nil {
+		return dec.BytesRead(), fmt.Errorf("decode verifying key commitments: %w", err)
+	}
+	if err := dec.Decode(&nbCommitments); err != nil {
+		return dec.BytesRead(), fmt.Errorf("decode verifying key commitments: %w", err)
+	}
+	if len(publicCommitted) != 0 || nbCommitments != 0 {
+		return dec.BytesRead(), errors.New("verifying keys with commitments are not supported")
+	}
+	if len(vk.IC) == 0 {
+		return dec.BytesRead(), errors.New("verifying key has no IC terms")
+	}
+	return dec.BytesRead(), nil
+}
+
+var (
+	vkRegistryMu sync.RWMutex
+	vkRegistry   = map[string]*VerifyingKey{}
+)
+
+func init() {
+	if dir := strings.TrimSpace(os.Getenv("MOHAWK_GROTH16_VK_DIR")); dir != "" {
+		if _, err := LoadVerifyingKeysFromDir(dir); err != nil {
+			log.Printf("groth16: %v", err)
+		}
+	}
+}
+
+// RegisterVerifyingKey adds or replaces the key used for circuitID.
+func RegisterVerifyingKey(circuitID string, vk *VerifyingKey) error {
+	circuitID = strings.TrimSpace(circuitID)
+	if circuitID == "" {
+		return errors.New("circuit id is required")
+	}
+	if vk == nil || len(vk.IC) == 0 {
+		return fmt.Errorf("verifying key for circuit %q has no IC terms", circuitID)
+	}
+	vkRegistryMu.Lock()
+	defer vkRegistryMu.Unlock()
+	vkRegistry[circuitID] = vk
+	return nil
+}
+
+// UnregisterVerifyingKey removes circuitID from the registry.
+func UnregisterVerifyingKey(circuitID string) {
+	vkRegistryMu.Lock()
+	defer vkRegistryMu.Unlock()
+	delete(vkRegistry, strings.TrimSpace(circuitID))
+}
+
+// LookupVerifyingKey returns the key registered for circuitID.
+func LookupVerifyingKey(circuitID string) (*VerifyingKey, error) {
+	vkRegistryMu.RLock()
+	defer vkRegistryMu.RUnlock()
+	vk, ok := vkRegistry[strings.TrimSpace(circuitID)]
+	if !ok {
+		return nil, fmt.Errorf("unknown circuit %q: no verifying key registered", circuitID)
+	}
+	return vk, nil
+}
+
+// RegisteredCircuits lists the circuit IDs with a verifying key.
+func RegisteredCircuits() []string {
+	vkRegistryMu.RLock()
+	defer vkRegistryMu.RUnlock()
+	out := make([]string, 0, len(vkRegistry))
+	for id := range vkRegistry {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LoadVerifyingKeyFile reads a gnark-serialized key and registers it as circuitID.
+func LoadVerifyingKeyFile(circuitID, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open verifying key: %w", err)
+	}
+	defer f.Close()
+	vk := new(VerifyingKey)
+	if _, err := vk.ReadFrom(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return RegisterVerifyingKey(circuitID, vk)
+}
+
+// LoadVerifyingKeysFromDir registers every <circuit-id>.vk file in dir.
+func LoadVerifyingKeysFromDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read verifying key dir: %w", err)
+	}
+	var loaded []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != VerifyingKeyExt {
+			continue
+		}
+		id := strings.TrimSuffix(e.Name(), VerifyingKeyExt)
+		if err := LoadVerifyingKeyFile(id, filepath.Join(dir, e.Name())); err != nil {
+			return loaded, err
+		}
+		loaded = append(loaded, id)
+	}
+	return loaded, nil
+}
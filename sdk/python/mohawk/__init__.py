@@ -18,7 +18,9 @@ from .exceptions import (
     AttestationError,
     InitializationError,
     MohawkError,
+    ProofCircuitError,
     ProofDegenerateError,
+    ProofInputError,
     ProofPairingError,
     ProofStructureError,
     ProofTooShortError,
@@ -76,6 +78,8 @@ __all__ = [
     "ProofStructureError",
     "ProofPairingError",
     "ProofDegenerateError",
+    "ProofCircuitError",
+    "ProofInputError",
     "AggregationError",
     "AttestationError",
     # Accelerator
@@ -247,6 +247,8 @@ class MohawkNode:
         stark_proof: str,
         mode: str = "prefer_snark",
         stark_backend: str = "simulated_fri",
+        snark_circuit_id: Optional[str] = None,
+        snark_public_inputs: Optional[Any] = None,
         auth_token: Optional[str] = None,
         role: Optional[str] = None,
     ) -> JsonDict:
@@ -256,6 +258,10 @@ class MohawkNode:
             "stark_proof": stark_proof,
             "stark_backend": stark_backend,
         }
+        if snark_circuit_id is not None:
+            payload["snark_circuit_id"] = snark_circuit_id
+        if snark_public_inputs is not None:
+            payload["snark_public_inputs"] = snark_public_inputs
         if auth_token is not None:
             payload["auth_token"] = auth_token
         if role is not None:
@@ -43,6 +43,18 @@ class ProofDegenerateError(VerificationError):
     pass
 
 
+class ProofCircuitError(VerificationError):
+    """Raised when no verifying key is registered for the requested circuit_id."""
+
+    pass
+
+
+class ProofInputError(VerificationError):
+    """Raised when public inputs are malformed or do not match the circuit's arity."""
+
+    pass
+
+
 class AggregationError(MohawkError):
     """Raised when federated learning aggregation fails."""
 
@@ -61,6 +73,8 @@ _ERROR_CODE_MAP: dict[str, type[VerificationError]] = {
     "PROOF_POINT_INVALID": ProofStructureError,
     "PROOF_DEGENERATE": ProofDegenerateError,
     "PROOF_PAIRING_FAILED": ProofPairingError,
+    "PROOF_UNKNOWN_CIRCUIT": ProofCircuitError,
+    "PROOF_INPUT_INVALID": ProofInputError,
     "PROOF_LATENCY_EXCEEDED": VerificationError,
     "PROOF_INVALID": VerificationError,
     "PROOF_PARSE_ERROR": VerificationError,
@@ -17,6 +17,8 @@ class HybridProofCheck:
     stark_proof: str
     mode: str = "prefer_snark"
     stark_backend: str = "simulated_fri"
+    snark_circuit_id: Optional[str] = None
+    snark_public_inputs: Optional[Any] = None
     auth_token: Optional[str] = None
     role: Optional[str] = None
 
@@ -27,6 +29,8 @@ class HybridProofCheck:
             stark_proof=str(payload["stark_proof"]),
             mode=str(payload.get("mode", "prefer_snark")),
             stark_backend=str(payload.get("stark_backend", "simulated_fri")),
+            snark_circuit_id=payload.get("snark_circuit_id"),
+            snark_public_inputs=payload.get("snark_public_inputs"),
             auth_token=payload.get("auth_token"),
             role=payload.get("role"),
         )
@@ -38,6 +42,10 @@ class HybridProofCheck:
             "mode": self.mode,
             "stark_backend": self.stark_backend,
         }
+        if self.snark_circuit_id is not None:
+            payload["snark_circuit_id"] = self.snark_circuit_id
+        if self.snark_public_inputs is not None:
+            payload["snark_public_inputs"] = self.snark_public_inputs
         if self.auth_token is not None:
             payload["auth_token"] = self.auth_token
         if self.role is not None:
@@ -0,0 +1,282 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/checkpoint"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/ipfs"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func newCheckpointDir(t *testing.T) (*checkpoint.Dir, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir, err := checkpoint.NewDir(root)
+	if err != nil {
+		t.Fatalf("new dir: %v", err)
+	}
+	return dir, root
+}
+
+// fakeBlockAPI serves the kubo block API from memory; swap replaces every
+// returned block with other content, as a compromised gateway would.
+func fakeBlockAPI(t *testing.T, swap bool) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	blocks := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v0/block/put":
+			file, _, err := r.FormFile("data")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			c := checkpoint.BlockCID(data).String()
+			blocks[c] = data
+			_ = json.NewEncoder(w).Encode(map[string]any{"Key": c, "Size": len(data)})
+		case "/api/v0/block/get":
+			data, ok := blocks[r.URL.Query().Get("arg")]
+			if !ok {
+				http.Error(w, "block not found", http.StatusInternalServerError)
+				return
+			}
+			if swap {
+				data = bytes.ToUpper(data)
+			}
+			_, _ = w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckpointStore_ChunkedRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir, _ := newCheckpointDir(t)
+	store := checkpoint.NewStore(dir, checkpoint.Options{ChunkSize: 1024})
+	payload := bytes.Repeat([]byte("gradient-"), 1000) // 9000 bytes, 9 chunks
+
+	id, err := store.Put(ctx, "model.bin", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if again, _ := checkpoint.PutBytes(ctx, store, "model.bin", payload); again != id {
+		t.Fatalf("expected content addressing to be deterministic, got %s and %s", id, again)
+	}
+	got, err := checkpoint.ReadAll(ctx, store, id, 0)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected payload back, got %d bytes (%v)", len(got), err)
+	}
+	if _, err := checkpoint.ReadAll(ctx, store, id, 100); !errors.Is(err, checkpoint.ErrTooLarge) {
+		t.Fatalf("expected read limit to apply, got %v", err)
+	}
+
+	bounded := checkpoint.NewStore(dir, checkpoint.Options{ChunkSize: 1024, MaxBytes: 2048})
+	if _, err := bounded.Put(ctx, "model.bin", bytes.NewReader(payload)); !errors.Is(err, checkpoint.ErrTooLarge) {
+		t.Fatalf("expected MaxBytes to apply, got %v", err)
+	}
+	if _, err := store.Get(ctx, checkpoint.BlockCID([]byte("missing")).String()); !errors.Is(err, checkpoint.ErrNotFound) {
+		t.Fatalf("expected a missing checkpoint to be not found, got %v", err)
+	}
+	chunk := checkpoint.BlockCID(payload[:1024]).String()
+	if _, err := store.Get(ctx, chunk); err == nil {
+		t.Fatal("expected a chunk block not to read as a checkpoint")
+	}
+}
+
+func TestCheckpointStore_DetectsTamperedBlocks(t *testing.T) {
+	ctx := context.Background()
+	dir, root := newCheckpointDir(t)
+	store := checkpoint.NewStore(dir, checkpoint.Options{ChunkSize: 16})
+	payload := []byte("0123456789abcdef-second-chunk")
+	id, err := checkpoint.PutBytes(ctx, store, "state.json", payload)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	second := checkpoint.BlockCID(payload[16:]).String()
+	if err := os.WriteFile(filepath.Join(root, second), []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	if _, err := checkpoint.ReadAll(ctx, store, id, 0); !errors.Is(err, checkpoint.ErrHashMismatch) {
+		t.Fatalf("expected a tampered chunk to fail verification, got %v", err)
+	}
+	if err := dir.PutBlock(ctx, checkpoint.BlockCID([]byte("a")), []byte("b")); !errors.Is(err, checkpoint.ErrHashMismatch) {
+		t.Fatalf("expected a mislabelled block to be refused, got %v", err)
+	}
+}
+
+func TestCheckpointStore_ReplicatedFallbackAndRepair(t *testing.T) {
+	ctx := context.Background()
+	local, localRoot := newCheckpointDir(t)
+	remote, _ := newCheckpointDir(t)
+	store := checkpoint.NewStore(checkpoint.NewReplicated(local, remote), checkpoint.Options{ChunkSize: 8})
+	payload := []byte("replicated checkpoint payload")
+
+	id, err := checkpoint.PutBytes(ctx, store, "state.json", payload)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := os.RemoveAll(localRoot); err != nil {
+		t.Fatalf("drop local copy: %v", err)
+	}
+	if err := os.MkdirAll(localRoot, 0o700); err != nil {
+		t.Fatalf("recreate local dir: %v", err)
+	}
+
+	got, err := checkpoint.ReadAll(ctx, store, id, 0)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected the remote copy to serve the read, got %q (%v)", got, err)
+	}
+	got, err = checkpoint.ReadAll(ctx, checkpoint.NewStore(local), id, 0)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected the read to repair the local copy, got %q (%v)", got, err)
+	}
+
+	// A corrupt local block is overwritten by the repair, not kept
+	first := checkpoint.BlockCID(payload[:8]).String()
+	if err := os.WriteFile(filepath.Join(localRoot, first), []byte("corrupt!"), 0o600); err != nil {
+		t.Fatalf("corrupt local copy: %v", err)
+	}
+	if got, err := checkpoint.ReadAll(ctx, store, id, 0); err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected the remote copy to serve the read, got %q (%v)", got, err)
+	}
+	if got, err := checkpoint.ReadAll(ctx, checkpoint.NewStore(local), id, 0); err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected the read to repair the corrupt local block, got %q (%v)", got, err)
+	}
+}
+
+func TestIPFSBlockStore_VerifiesGatewayContent(t *testing.T) {
+	ctx := context.Background()
+	payload := bytes.Repeat([]byte("weights"), 200)
+
+	honest := checkpoint.NewStore(ipfs.NewBackend(fakeBlockAPI(t, false).URL), checkpoint.Options{ChunkSize: 512})
+	id, err := checkpoint.PutBytes(ctx, honest, "model.bin", payload)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := checkpoint.ReadAll(ctx, honest, id, 0)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected payload back from ipfs, got %d bytes (%v)", len(got), err)
+	}
+	dir, _ := newCheckpointDir(t)
+	if local, _ := checkpoint.PutBytes(ctx, checkpoint.NewStore(dir, checkpoint.Options{ChunkSize: 512}), "model.bin", payload); local != id {
+		t.Fatalf("expected ipfs and local stores to agree on the cid, got %s and %s", id, local)
+	}
+
+	swapped := checkpoint.NewStore(ipfs.NewBackend(fakeBlockAPI(t, true).URL), checkpoint.Options{ChunkSize: 512})
+	id, err = checkpoint.PutBytes(ctx, swapped, "model.bin", payload)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := checkpoint.ReadAll(ctx, swapped, id, 0); !errors.Is(err, checkpoint.ErrHashMismatch) {
+		t.Fatalf("expected a swapped payload to be refused, got %v", err)
+	}
+}
+
+// unixfsNode encodes a dag-pb UnixFS file node as /api/v0/add writes it
+func unixfsNode(content []byte, filesize uint64, links []cid.Cid) []byte {
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, 2) // File
+	if len(content) > 0 {
+		data = protowire.AppendTag(data, 2, protowire.BytesType)
+		data = protowire.AppendBytes(data, content)
+	}
+	data = protowire.AppendTag(data, 3, protowire.VarintType)
+	data = protowire.AppendVarint(data, filesize)
+	var node []byte
+	for _, link := range links {
+		var pl []byte
+		pl = protowire.AppendTag(pl, 1, protowire.BytesType)
+		pl = protowire.AppendBytes(pl, link.Bytes())
+		node = protowire.AppendTag(node, 2, protowire.BytesType)
+		node = protowire.AppendBytes(node, pl)
+	}
+	node = protowire.AppendTag(node, 1, protowire.BytesType)
+	return protowire.AppendBytes(node, data)
+}
+
+func putLegacyBlock(t *testing.T, dir *checkpoint.Dir, c cid.Cid, data []byte) {
+	t.Helper()
+	if err := dir.PutBlock(context.Background(), c, data); err != nil {
+		t.Fatalf("put legacy block %s: %v", c, err)
+	}
+}
+
+func TestCheckpointStore_ReadsLegacyUnixFSFiles(t *testing.T) {
+	ctx := context.Background()
+	dir, root := newCheckpointDir(t)
+	store := checkpoint.NewStore(dir)
+
+	// A single-block file matches what `ipfs add` returns for it
+	hello := []byte("hello world\n")
+	leaf := unixfsNode(hello, uint64(len(hello)), nil)
+	sum, _ := multihash.Sum(leaf, multihash.SHA2_256, -1)
+	helloCID := cid.NewCidV0(sum)
+	if got := helloCID.String(); got != "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o" {
+		t.Fatalf("expected the kubo CIDv0 for hello world, got %s", got)
+	}
+	putLegacyBlock(t, dir, helloCID, leaf)
+	if _, err := checkpoint.ParseID(helloCID.String()); err != nil {
+		t.Fatalf("expected a CIDv0 to parse as a checkpoint id: %v", err)
+	}
+	got, err := checkpoint.ReadAll(ctx, store, helloCID.String(), 0)
+	if err != nil || !bytes.Equal(got, hello) {
+		t.Fatalf("expected the legacy file back, got %q (%v)", got, err)
+	}
+
+	// A multi-block file with raw leaves under a CIDv0 root
+	parts := [][]byte{[]byte("legacy-"), []byte("checkpoint-"), []byte("payload")}
+	var leaves []cid.Cid
+	for _, part := range parts {
+		c := checkpoint.BlockCID(part)
+		putLegacyBlock(t, dir, c, part)
+		leaves = append(leaves, c)
+	}
+	payload := bytes.Join(parts, nil)
+	node := unixfsNode(nil, uint64(len(payload)), leaves)
+	sum, _ = multihash.Sum(node, multihash.SHA2_256, -1)
+	fileCID := cid.NewCidV0(sum)
+	putLegacyBlock(t, dir, fileCID, node)
+	got, err = checkpoint.ReadAll(ctx, store, fileCID.String(), 0)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("expected the chunked legacy file back, got %q (%v)", got, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, leaves[1].String()), []byte("tampered!!!"), 0o600); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+	if _, err := checkpoint.ReadAll(ctx, store, fileCID.String(), 0); !errors.Is(err, checkpoint.ErrHashMismatch) {
+		t.Fatalf("expected a tampered legacy block to fail verification, got %v", err)
+	}
+
+	short := unixfsNode(nil, uint64(len(payload)+1), leaves[:1])
+	sum, _ = multihash.Sum(short, multihash.SHA2_256, -1)
+	shortCID := cid.NewCidV0(sum)
+	putLegacyBlock(t, dir, shortCID, short)
+	if _, err := checkpoint.ReadAll(ctx, store, shortCID.String(), 0); !errors.Is(err, checkpoint.ErrHashMismatch) {
+		t.Fatalf("expected a file shorter than its filesize to be refused, got %v", err)
+	}
+	if _, err := checkpoint.ParseID(cid.NewCidV1(cid.DagCBOR, sum).String()); err == nil {
+		t.Fatal("expected a dag-cbor cid to be refused")
+	}
+}
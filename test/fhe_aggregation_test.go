@@ -1,47 +1,150 @@
 package test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/fhe"
 )
 
+func newThresholdKey(t *testing.T, holders []fhe.KeyShare, threshold int) (*fhe.PublicKey, []fhe.KeyShare) {
+	t.Helper()
+	pk, shares, err := fhe.GenerateThresholdKey(fhe.MinModulusBits, holders, threshold)
+	if err != nil {
+		t.Fatalf("generate threshold key: %v", err)
+	}
+	return pk, shares
+}
+
 func TestFHEAggregateAndThresholdDecrypt(t *testing.T) {
-	updates := []fhe.EncryptedUpdate{
-		{Contributor: "a", Values: []int64{1, 2, 3}},
-		{Contributor: "b", Values: []int64{2, 3, 4}},
+	holders := []fhe.KeyShare{{NodeID: "a", Weight: 1}, {NodeID: "b", Weight: 1}, {NodeID: "c", Weight: 1}}
+	if err := fhe.ValidateShares(holders, 2); err != nil {
+		t.Fatalf("validate shares: %v", err)
 	}
-	agg, err := fhe.AggregateCiphertexts(updates)
+	pk, shares := newThresholdKey(t, holders, 2)
+
+	inputs := [][]float64{{0.25, -1.5, 3}, {0.5, 2.25, -4.125}}
+	updates := make([]fhe.EncryptedUpdate, len(inputs))
+	for i, in := range inputs {
+		u, err := fhe.Encrypt(pk, string(rune('a'+i)), in, fhe.DefaultFixedPointScale)
+		if err != nil {
+			t.Fatalf("encrypt: %v", err)
+		}
+		updates[i] = u
+	}
+	agg, err := fhe.AggregateCiphertexts(pk, updates)
 	if err != nil {
 		t.Fatalf("aggregate: %v", err)
 	}
-	shares := []fhe.KeyShare{{NodeID: "a", Weight: 1}, {NodeID: "b", Weight: 1}, {NodeID: "c", Weight: 1}}
-	if err := fhe.ValidateShares(shares, 2); err != nil {
-		t.Fatalf("validate shares: %v", err)
+	if agg.Count != 2 {
+		t.Fatalf("aggregate count=%d, want 2", agg.Count)
 	}
-	plain, err := fhe.DecryptAggregate(agg, []string{"a", "b"}, fhe.ShareMap(shares), 2)
+
+	want := []float64{0.75, 0.75, -1.125}
+	for _, quorum := range [][]string{{"a", "b"}, {"b", "c"}, {"a", "c"}} {
+		plain, err := fhe.DecryptAggregate(pk, agg, quorum, fhe.ShareMap(shares))
+		if err != nil {
+			t.Fatalf("decrypt with quorum %v: %v", quorum, err)
+		}
+		for i := range want {
+			if math.Abs(plain[i]-want[i]) > 1e-6 {
+				t.Fatalf("quorum %v decrypted %v, want %v", quorum, plain, want)
+			}
+		}
+	}
+	if _, err := fhe.DecryptAggregate(pk, agg, []string{"a"}, fhe.ShareMap(shares)); err == nil {
+		t.Fatal("expected insufficient quorum to fail")
+	}
+}
+
+func TestFHEPartialDecryptionsBelowThresholdFail(t *testing.T) {
+	holders := []fhe.KeyShare{{NodeID: "a", Weight: 2}, {NodeID: "b", Weight: 1}, {NodeID: "c", Weight: 1}}
+	pk, shares := newThresholdKey(t, holders, 3)
+	byNode := fhe.ShareMap(shares)
+
+	u, err := fhe.Encrypt(pk, "x", []float64{1.5}, fhe.DefaultFixedPointScale)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	again, err := fhe.Encrypt(pk, "x", []float64{1.5}, fhe.DefaultFixedPointScale)
 	if err != nil {
-		t.Fatalf("decrypt with quorum: %v", err)
+		t.Fatalf("encrypt: %v", err)
 	}
-	if len(plain) != 3 || plain[0] != 3 || plain[1] != 5 || plain[2] != 7 {
-		t.Fatalf("unexpected decrypted aggregate: %#v", plain)
+	if u.Ciphertexts[0].Cmp(again.Ciphertexts[0]) == 0 {
+		t.Fatal("encryption must be randomized")
 	}
-	if _, err := fhe.DecryptAggregate(agg, []string{"a"}, fhe.ShareMap(shares), 2); err == nil {
-		t.Fatal("expected insufficient quorum to fail")
+
+	partA, err := fhe.PartialDecrypt(pk, byNode["a"], u)
+	if err != nil {
+		t.Fatalf("partial decrypt: %v", err)
+	}
+	if _, err := fhe.CombinePartialDecryptions(pk, u, partA); err == nil {
+		t.Fatal("weight-2 holder alone must not reach a threshold of 3")
+	}
+
+	partC, err := fhe.PartialDecrypt(pk, byNode["c"], u)
+	if err != nil {
+		t.Fatalf("partial decrypt: %v", err)
+	}
+	plain, err := fhe.CombinePartialDecryptions(pk, u, append(partA, partC...))
+	if err != nil {
+		t.Fatalf("combine: %v", err)
+	}
+	if math.Abs(plain[0]-1.5) > 1e-6 {
+		t.Fatalf("decrypted %v, want 1.5", plain)
+	}
+}
+
+func TestFHERejectsForeignKeyCiphertexts(t *testing.T) {
+	holders := []fhe.KeyShare{{NodeID: "a", Weight: 1}, {NodeID: "b", Weight: 1}}
+	pk1, _ := newThresholdKey(t, holders, 2)
+	pk2, _ := newThresholdKey(t, holders, 2)
+
+	u1, err := fhe.Encrypt(pk1, "a", []float64{1}, fhe.DefaultFixedPointScale)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	u2, err := fhe.Encrypt(pk2, "b", []float64{1}, fhe.DefaultFixedPointScale)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if _, err := fhe.AggregateCiphertexts(pk1, []fhe.EncryptedUpdate{u1, u2}); err == nil {
+		t.Fatal("expected ciphertexts under different keys to be rejected")
 	}
 }
 
 func TestFHESerializationRoundTrip(t *testing.T) {
-	update := fhe.EncryptedUpdate{Contributor: "node-x", Values: []int64{9, 8, 7}}
+	pk, _ := newThresholdKey(t, []fhe.KeyShare{{NodeID: "a", Weight: 1}}, 1)
+	update, err := fhe.Encrypt(pk, "node-x", []float64{9, 8, 7}, fhe.DefaultFixedPointScale)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
 	raw, err := fhe.MarshalUpdate(update)
 	if err != nil {
 		t.Fatalf("marshal: %v", err)
 	}
+	if raw[4] != fhe.UpdateVersion1 {
+		t.Fatalf("serialized version=%d, want %d", raw[4], fhe.UpdateVersion1)
+	}
 	decoded, err := fhe.UnmarshalUpdate(raw)
 	if err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
-	if decoded.Contributor != update.Contributor || len(decoded.Values) != len(update.Values) {
+	if decoded.Contributor != update.Contributor || decoded.KeyID != update.KeyID || decoded.Scale != update.Scale || len(decoded.Ciphertexts) != 3 {
 		t.Fatalf("roundtrip mismatch: %#v", decoded)
 	}
+	for i := range update.Ciphertexts {
+		if decoded.Ciphertexts[i].Cmp(update.Ciphertexts[i]) != 0 {
+			t.Fatalf("ciphertext %d changed in roundtrip", i)
+		}
+	}
+
+	raw[4] = 99
+	if _, err := fhe.UnmarshalUpdate(raw); err == nil {
+		t.Fatal("expected unknown version to be rejected")
+	}
+	raw[4] = fhe.UpdateVersion1
+	if _, err := fhe.UnmarshalUpdate(raw[:len(raw)-1]); err == nil {
+		t.Fatal("expected truncated payload to be rejected")
+	}
 }
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+func TestKeystoreSignsWithKeyID(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := manifest.NewKeystore(priv)
+	m := &manifest.Manifest{TaskID: "t1", NodeID: "n1"}
+	if err := keys.Sign(m); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	current := keys.Current()
+	if m.KeyID != current.KeyID || current.KeyID != manifest.KeyIDFor(priv.Public().(ed25519.PublicKey)) {
+		t.Fatalf("expected key_id %s, got %s", current.KeyID, m.KeyID)
+	}
+	if err := manifest.VerifySignature(m, current.PublicKey); err != nil {
+		t.Fatalf("verify with raw key: %v", err)
+	}
+
+	// The key ID is covered by the signature
+	m.KeyID = "0000000000000000"
+	if err := manifest.VerifySignature(m, current.PublicKey); err == nil {
+		t.Fatal("expected a rewritten key_id to fail verification")
+	}
+}
+
+func TestKeystoreRotationKeepsOverlap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	keys, err := manifest.OpenKeystore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	oldKey := keys.Current()
+	before := &manifest.Manifest{TaskID: "t1", NodeID: "n1"}
+	if err := keys.Sign(before); err != nil {
+		t.Fatal(err)
+	}
+
+	newKey, err := keys.Rotate(time.Hour)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	after := &manifest.Manifest{TaskID: "t2", NodeID: "n1"}
+	if err := keys.Sign(after); err != nil {
+		t.Fatal(err)
+	}
+	if newKey.KeyID == oldKey.KeyID || after.KeyID != newKey.KeyID {
+		t.Fatalf("expected new manifests signed by %s, got %s", newKey.KeyID, after.KeyID)
+	}
+
+	published, err := json.Marshal(keys.KeySet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*manifest.Manifest{before, after} {
+		if err := manifest.VerifySignature(m, published); err != nil {
+			t.Fatalf("verify %s with key set: %v", m.TaskID, err)
+		}
+	}
+	ks, err := manifest.ParseKeySet(published)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(2 * time.Hour)
+	if err := ks.Verify(before, later); err == nil {
+		t.Fatal("expected the old key to expire after its overlap window")
+	}
+	if err := ks.Verify(after, later); err != nil {
+		t.Fatalf("current key should stay valid: %v", err)
+	}
+
+	// A node still holding only the old key gets a clear key mismatch
+	if err := manifest.VerifySignature(after, oldKey.PublicKey); err == nil || !strings.Contains(err.Error(), newKey.KeyID) {
+		t.Fatalf("expected a key_id mismatch, got %v", err)
+	}
+
+	reopened, err := manifest.OpenKeystore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.Current().KeyID != newKey.KeyID || len(reopened.KeySet().Keys) != 2 {
+		t.Fatalf("expected rotation to persist, got %+v", reopened.KeySet())
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected keystore mode 0600, got %v %v", info, err)
+	}
+
+	// Closing a key's window retires it: it leaves the key set but stays in
+	// the history, and the keystore still reopens
+	if _, err := reopened.Rotate(0); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if _, err := reopened.Rotate(0); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	retired, err := manifest.OpenKeystore(path)
+	if err != nil {
+		t.Fatalf("reopen with retired keys: %v", err)
+	}
+	history := retired.History()
+	if len(history.Keys) != 4 || history.Keys[1].KeyID != newKey.KeyID {
+		t.Fatalf("expected every key in the history, got %+v", history)
+	}
+	for _, k := range retired.KeySet().Keys {
+		if k.KeyID == newKey.KeyID {
+			t.Fatal("expected the retired key to leave the key set")
+		}
+	}
+	if err := history.Verify(after, time.Now()); err == nil {
+		t.Fatal("expected the retired key's window to stay closed")
+	}
+	if _, err := history.Key(newKey.KeyID, newKey.NotBefore); err != nil {
+		t.Fatalf("expected the history to verify records signed while the key was current: %v", err)
+	}
+}
+
+func TestLoadPrivateKeyFile(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "orchestrator.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := manifest.LoadPrivateKeyFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !loaded.Equal(priv) {
+		t.Fatal("loaded key differs from the written key")
+	}
+}
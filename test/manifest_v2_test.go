@@ -0,0 +1,121 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/manifest"
+)
+
+func TestCanonicalJSONFollowsJCS(t *testing.T) {
+	got, err := manifest.CanonicalJSON(map[string]any{
+		"b":       1,
+		"a":       []any{1e-7, 1e21, 4.50, 0.002, 333333333.33333329, -0.0},
+		"c":       " \x0f\"€",
+		"é":       true,
+		"a\u0000": nil,
+	})
+	if err != nil {
+		t.Fatalf("canonical: %v", err)
+	}
+	want := `{"a":[1e-7,1e+21,4.5,0.002,333333333.3333333,0],"a\u0000":null,"b":1,"c":"` + " " + `\u000f\"€","é":true}`
+	if string(got) != want {
+		t.Fatalf("canonical json\n got %s\nwant %s", got, want)
+	}
+}
+
+// signedV2Manifest returns a v2 manifest signed by a fresh keystore and the
+// published key set
+func signedV2Manifest(t *testing.T, issuedAt time.Time) (*manifest.Manifest, []byte) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := manifest.NewKeystore(priv)
+	m := &manifest.Manifest{
+		SchemaVersion: manifest.SchemaV2,
+		TaskID:        "task-1",
+		NodeID:        "node-1",
+		MaxGradNorm:   1,
+		Delta:         1e-5,
+		IssuedAt:      issuedAt,
+		ExpiresAt:     issuedAt.Add(10 * time.Minute),
+		RoundID:       7,
+		ModelCID:      "bafy-model",
+		Nonce:         "00112233445566778899aabbccddeeff",
+	}
+	if err := keys.Sign(m); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	published, err := json.Marshal(keys.KeySet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, published
+}
+
+func TestVerifyEnforcesExpiryAndSingleUse(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	m, keys := signedV2Manifest(t, now)
+
+	// The signature covers canonical JSON, so a re-encoded manifest still verifies
+	raw, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded manifest.Manifest
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	guard := manifest.NewReplayGuard()
+	if err := manifest.Verify(&decoded, keys, manifest.VerifyOptions{Replay: guard, RequireV2: true}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := manifest.Verify(m, keys, manifest.VerifyOptions{Replay: guard}); !errors.Is(err, manifest.ErrManifestReplayed) {
+		t.Fatalf("expected ErrManifestReplayed, got %v", err)
+	}
+
+	if err := manifest.Verify(m, keys, manifest.VerifyOptions{Now: now.Add(time.Hour)}); err == nil {
+		t.Fatal("expected an expired manifest to be refused")
+	}
+	if err := manifest.Verify(m, keys, manifest.VerifyOptions{Now: now.Add(-time.Hour)}); err == nil {
+		t.Fatal("expected a manifest issued in the future to be refused")
+	}
+
+	tampered := *m
+	tampered.RoundID = 8
+	if err := manifest.VerifySignature(&tampered, keys); err == nil {
+		t.Fatal("expected a changed round_id to break the signature")
+	}
+
+	unsupported := *m
+	unsupported.SchemaVersion = 3
+	if err := manifest.VerifySignature(&unsupported, keys); err == nil {
+		t.Fatal("expected an unknown schema version to be refused")
+	}
+}
+
+func TestVerifyAcceptsV1UnlessRequired(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &manifest.Manifest{TaskID: "legacy", NodeID: "node-1"}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Signature = ed25519.Sign(priv, data)
+
+	if err := manifest.VerifySignature(m, pub); err != nil {
+		t.Fatalf("v1 manifest should still verify: %v", err)
+	}
+	if err := manifest.Verify(m, pub, manifest.VerifyOptions{RequireV2: true}); err == nil {
+		t.Fatal("expected v1 to be refused when v2 is required")
+	}
+}
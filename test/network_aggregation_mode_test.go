@@ -10,6 +10,9 @@ func TestParseAggregationModeAliases(t *testing.T) {
 	if got := network.ParseAggregationMode("tfhe"); got != network.AggregationModeFHEThreshold {
 		t.Fatalf("expected tfhe alias -> %q, got %q", network.AggregationModeFHEThreshold, got)
 	}
+	if got := network.ParseAggregationMode("secagg"); got != network.AggregationModeSecAgg {
+		t.Fatalf("expected secagg alias -> %q, got %q", network.AggregationModeSecAgg, got)
+	}
 	if got := network.ParseAggregationMode("plaintext"); got != network.AggregationModePlaintext {
 		t.Fatalf("expected plaintext mode, got %q", got)
 	}
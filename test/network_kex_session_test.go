@@ -0,0 +1,156 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/network"
+)
+
+func establishKEX(t *testing.T, mode network.KEXMode, binding []byte) (*network.KEXInitiator, network.KEXResponse, *network.KEXSession, *network.KEXSession) {
+	t.Helper()
+	initiator, err := network.NewKEXInitiator(mode)
+	if err != nil {
+		t.Fatalf("NewKEXInitiator: %v", err)
+	}
+	if got, want := len(initiator.PublicKey()), mode.ExpectedPublicKeyBytes(); got != want {
+		t.Fatalf("initiator public key bytes=%d, want %d", got, want)
+	}
+	resp, responder, err := network.RespondKEX(mode, initiator.PublicKey(), binding)
+	if err != nil {
+		t.Fatalf("RespondKEX: %v", err)
+	}
+	session, err := initiator.Complete(resp, binding)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	return initiator, resp, session, responder
+}
+
+func TestHybridKEXSessionRoundTrip(t *testing.T) {
+	binding := []byte("node-a|aggregator")
+	_, resp, client, server := establishKEX(t, network.KEXModeHybridX25519MLKEM768, binding)
+	if len(resp.KEMCiphertext) == 0 {
+		t.Fatal("hybrid mode must carry an ML-KEM ciphertext")
+	}
+	if !bytes.Equal(client.TranscriptHash, server.TranscriptHash) {
+		t.Fatal("transcript hashes diverged")
+	}
+
+	nonce, ct, err := client.Seal([]byte(`{"gradients":[0.1,0.2]}`))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(ct, []byte("gradients")) {
+		t.Fatal("ciphertext leaks plaintext")
+	}
+	pt, err := server.Open(nonce, ct)
+	if err != nil || string(pt) != `{"gradients":[0.1,0.2]}` {
+		t.Fatalf("server Open = %q, %v", pt, err)
+	}
+
+	// Directional keys: a client cannot open its own envelope as if it came from the server.
+	if _, err := client.Open(nonce, ct); err == nil {
+		t.Fatal("expected reflected envelope to be rejected")
+	}
+}
+
+func TestHybridKEXRejectsWrongKeysAndForgery(t *testing.T) {
+	binding := []byte("node-a|aggregator")
+	_, resp, client, server := establishKEX(t, network.KEXModeHybridX25519MLKEM768, binding)
+
+	nonce, ct, err := client.Seal([]byte("secret gradient"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	// An eavesdropper replaying the responder's reply with its own ephemeral keys
+	// derives an unrelated session and can neither read nor forge envelopes.
+	eve, err := network.NewKEXInitiator(network.KEXModeHybridX25519MLKEM768)
+	if err != nil {
+		t.Fatalf("NewKEXInitiator: %v", err)
+	}
+	eveSession, err := eve.Complete(resp, binding)
+	if err == nil {
+		if _, err := eveSession.Open(nonce, ct); err == nil {
+			t.Fatal("peer without the initiator's keys decrypted the envelope")
+		}
+		forgedNonce, forged, _ := eveSession.Seal([]byte("poisoned gradient"))
+		if _, err := server.Open(forgedNonce, forged); err == nil {
+			t.Fatal("server accepted an envelope forged under the wrong keys")
+		}
+	}
+
+	tampered := append([]byte(nil), ct...)
+	tampered[0] ^= 0x01
+	if _, err := server.Open(nonce, tampered); err == nil {
+		t.Fatal("server accepted a tampered envelope")
+	}
+
+	// Sessions are bound to the peer identities supplied as binding.
+	_, _, _, otherServer := establishKEX(t, network.KEXModeHybridX25519MLKEM768, []byte("node-b|aggregator"))
+	if _, err := otherServer.Open(nonce, ct); err == nil {
+		t.Fatal("envelope opened under a different session")
+	}
+}
+
+func TestRespondKEXRejectsTruncatedHybridKey(t *testing.T) {
+	initiator, err := network.NewKEXInitiator(network.KEXModeHybridX25519MLKEM768)
+	if err != nil {
+		t.Fatalf("NewKEXInitiator: %v", err)
+	}
+	if _, _, err := network.RespondKEX(network.KEXModeHybridX25519MLKEM768, initiator.PublicKey()[:32], nil); err == nil {
+		t.Fatal("expected x25519-only key to be rejected in hybrid mode")
+	}
+}
+
+func TestGradientProtocolHybridKEX(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg := network.DefaultConfig(0)
+	cfg.KEXMode = network.KEXModeHybridX25519MLKEM768
+	receiver, err := network.NewHost(ctx, cfg)
+	if err != nil {
+		t.Fatalf("receiver host: %v", err)
+	}
+	defer receiver.Close()
+
+	received := make(chan *network.GradientMessage, 1)
+	network.RegisterGradientHandlerWithKEX(receiver, network.KEXModeHybridX25519MLKEM768, func(msg *network.GradientMessage) *network.GradientAck {
+		received <- msg
+		return nil
+	})
+
+	sender, err := network.NewHost(ctx, cfg)
+	if err != nil {
+		t.Fatalf("sender host: %v", err)
+	}
+	defer sender.Close()
+
+	msg := &network.GradientMessage{NodeID: "pq-node", TaskID: "task-pq", Round: 2, Gradients: []float64{0.5, -0.5}}
+	ack, err := network.SendGradientWithKEX(ctx, sender, receiver.ID(), receiver.Addrs(), msg, network.KEXModeHybridX25519MLKEM768)
+	if err != nil {
+		t.Fatalf("SendGradientWithKEX: %v", err)
+	}
+	if !ack.Accepted {
+		t.Fatalf("expected accepted ack, got reason=%q", ack.Reason)
+	}
+	if ack.NegotiatedKEX != string(network.KEXModeHybridX25519MLKEM768) || ack.KEXPublicKeyLen != 1216 {
+		t.Fatalf("ack did not echo hybrid negotiation: %+v", ack)
+	}
+	if ack.KEXCipherSuite != network.KEXCipherSuite || len(ack.KEXTranscriptHash) != 32 {
+		t.Fatalf("ack missing session parameters: %+v", ack)
+	}
+
+	select {
+	case got := <-received:
+		if got.NodeID != "pq-node" || len(got.Gradients) != 2 {
+			t.Fatalf("unexpected message: %+v", got)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for gradient")
+	}
+}
@@ -0,0 +1,247 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	internal "github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/federation"
+	privacy "github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/privacy"
+)
+
+func TestBudgetLedgerReplaysAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, rec := range []privacy.SpendRecord{
+		{Source: "test", Shard: "s1", Epsilon: 0.25},
+		{Source: "test", Shard: "s2", Epsilon: 0.5},
+		{Source: "test", Tier: "regional-1", Epsilon: 1},
+	} {
+		if _, err := l.Charge(rec); err != nil {
+			t.Fatalf("charge: %v", err)
+		}
+	}
+	_ = l.Close()
+
+	reopened, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	totals := reopened.Totals()
+	if totals.Global != 1.75 || totals.Shards["s2"] != 0.5 || totals.Shards["regional-1"] != 1 {
+		t.Fatalf("unexpected replayed totals: %+v", totals)
+	}
+	rec, err := reopened.Charge(privacy.SpendRecord{Source: "test", Epsilon: 0.1})
+	if err != nil {
+		t.Fatalf("charge after reopen: %v", err)
+	}
+	if rec.Seq != 4 || rec.PrevHash == "" {
+		t.Fatalf("expected chained record 4, got seq=%d prev=%q", rec.Seq, rec.PrevHash)
+	}
+}
+
+func TestBudgetLedgerRefusesTamperedChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Charge(privacy.SpendRecord{Source: "test", Epsilon: 0.5}); err != nil {
+			t.Fatalf("charge: %v", err)
+		}
+	}
+	_ = l.Close()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	edited := strings.Replace(string(raw), `"epsilon":0.5`, `"epsilon":0.05`, 1)
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := privacy.OpenBudgetLedger(path); !errors.Is(err, privacy.ErrLedgerTampered) {
+		t.Fatalf("expected ErrLedgerTampered for edited record, got %v", err)
+	}
+
+	lines := strings.SplitAfter(string(raw), "\n")
+	if err := os.WriteFile(path, []byte(strings.Join(lines[:2], "")), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := privacy.OpenBudgetLedger(path); !errors.Is(err, privacy.ErrLedgerTampered) {
+		t.Fatalf("expected ErrLedgerTampered for truncated log, got %v", err)
+	}
+}
+
+func TestBudgetLedgerDropsTornFinalRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := l.Charge(privacy.SpendRecord{Source: "test", Epsilon: 0.5}); err != nil {
+		t.Fatalf("charge: %v", err)
+	}
+	_ = l.Close()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open for append: %v", err)
+	}
+	_, _ = f.WriteString(`{"seq":2,"source":"te`)
+	_ = f.Close()
+
+	reopened, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("reopen with torn record: %v", err)
+	}
+	defer reopened.Close()
+	if n := len(reopened.Records()); n != 1 {
+		t.Fatalf("expected torn record to be discarded, got %d records", n)
+	}
+	if rec, err := reopened.Charge(privacy.SpendRecord{Source: "test", Epsilon: 0.5}); err != nil || rec.Seq != 2 {
+		t.Fatalf("expected clean append as record 2, got seq=%d err=%v", rec.Seq, err)
+	}
+}
+
+func TestRDPAccountantReplaysLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	acc := internal.NewRDPAccountant(10, 1e-5)
+	if err := acc.AttachLedger(l); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := acc.RecordShardSubsampledGaussianStep("health-1", 0.05, 1.0); err != nil {
+			t.Fatalf("shard step: %v", err)
+		}
+	}
+	acc.RecordStep(0.1)
+	want, wantShard := acc.GetCurrentEpsilon(), acc.GetShardEpsilon("health-1")
+	_ = l.Close()
+
+	reopened, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	restored := internal.NewRDPAccountant(10, 1e-5)
+	if err := restored.AttachLedger(reopened); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := restored.GetCurrentEpsilon(); got != want {
+		t.Fatalf("replayed ε=%v, want %v", got, want)
+	}
+	if got := restored.GetShardEpsilon("health-1"); got != wantShard {
+		t.Fatalf("replayed shard ε=%v, want %v", got, wantShard)
+	}
+	if restored.RemainingBudget() != acc.RemainingBudget() || restored.ShardRemainingBudget("health-1") >= 10 {
+		t.Fatalf("unexpected remaining budget after replay: %v", restored.RemainingBudget())
+	}
+}
+
+func TestPersistentOrchestratorAndTierTrackerReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	o, err := privacy.NewPersistentOrchestrator(2.0, 0.2, 1.2, l)
+	if err != nil {
+		t.Fatalf("orchestrator: %v", err)
+	}
+	res, err := o.Allocate(privacy.AllocationRequest{ShardID: "health-shard", Class: privacy.SensitivityHealthcare, ShardSize: 5000, DriftScore: 0.4})
+	if err != nil {
+		t.Fatalf("allocate: %v", err)
+	}
+	tracker := federation.NewDPTierTracker(100, 1e-7)
+	if err := tracker.AttachLedger(l); err != nil {
+		t.Fatalf("attach tracker: %v", err)
+	}
+	if err := tracker.RecordAggregation("regional-1", 100, 0.1, 1.0); err != nil {
+		t.Fatalf("record aggregation: %v", err)
+	}
+	tierRemaining := tracker.GetTierRemaining("regional-1")
+	_ = l.Close()
+
+	reopened, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	restored, err := privacy.NewPersistentOrchestrator(2.0, 0.2, 1.2, reopened)
+	if err != nil {
+		t.Fatalf("restore orchestrator: %v", err)
+	}
+	if restored.RemainingBudget() != res.RemainingBudget || restored.ShardConsumed("health-shard") != res.AllocatedEps {
+		t.Fatalf("orchestrator replay mismatch: remaining=%v consumed=%v", restored.RemainingBudget(), restored.ShardConsumed("health-shard"))
+	}
+	restoredTracker := federation.NewDPTierTracker(100, 1e-7)
+	if err := restoredTracker.AttachLedger(reopened); err != nil {
+		t.Fatalf("replay tracker: %v", err)
+	}
+	if got := restoredTracker.GetTierRemaining("regional-1"); got != tierRemaining || got >= 100 {
+		t.Fatalf("tier remaining after replay=%v, want %v", got, tierRemaining)
+	}
+}
+
+func TestTierTrackerReplaysPreRDPLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "privacy.ledger")
+	l, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Tier charges as written before the tracker moved to the RDP accountant
+	for _, eps := range []float64{0.4, 0.6} {
+		if _, err := l.Charge(privacy.SpendRecord{Source: "dp_tier_tracker", Tier: "regional-1", Epsilon: eps}); err != nil {
+			t.Fatalf("charge: %v", err)
+		}
+	}
+	if totals := l.Totals(); totals.Shards["regional-1"] != 1 {
+		t.Fatalf("expected legacy tier spend under its shard, got %+v", totals)
+	}
+
+	tracker := federation.NewDPTierTracker(100, 1e-7)
+	if err := tracker.AttachLedger(l); err != nil {
+		t.Fatalf("replay legacy ledger: %v", err)
+	}
+	want := internal.NewRDPAccountant(100, 1e-7)
+	want.RecordShardStep("regional-1", 0.4)
+	want.RecordShardStep("regional-1", 0.6)
+	if got := tracker.GetTierEpsilon("regional-1"); got != want.GetShardEpsilon("regional-1") {
+		t.Fatalf("legacy tier ε=%v, want %v", got, want.GetShardEpsilon("regional-1"))
+	}
+	if n := tracker.GetTierStats("regional-1")["aggregations"].(int64); n != 2 {
+		t.Fatalf("expected 2 replayed aggregations, got %d", n)
+	}
+
+	// New charges append in the current format and replay alongside the old
+	if err := tracker.RecordAggregation("regional-1", 100, 0.1, 1.0); err != nil {
+		t.Fatalf("record aggregation: %v", err)
+	}
+	before := tracker.GetTierEpsilon("regional-1")
+	_ = l.Close()
+	reopened, err := privacy.OpenBudgetLedger(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	restored := federation.NewDPTierTracker(100, 1e-7)
+	if err := restored.AttachLedger(reopened); err != nil {
+		t.Fatalf("replay mixed ledger: %v", err)
+	}
+	if got := restored.GetTierEpsilon("regional-1"); got != before || got <= want.GetShardEpsilon("regional-1") {
+		t.Fatalf("tier ε after replay=%v, want %v", got, before)
+	}
+}
@@ -21,3 +21,26 @@ func TestRDPAccountantShardLedger(t *testing.T) {
 		t.Fatalf("expected public shard epsilon 1/2, got %s", p.RatString())
 	}
 }
+
+func TestRDPAccountantShardCurves(t *testing.T) {
+	acc := internal.NewRDPAccountant(10, 1e-5)
+	for i := 0; i < 50; i++ {
+		if err := acc.RecordShardSubsampledGaussianStep("health-1", 0.01, 1.0); err != nil {
+			t.Fatalf("shard step: %v", err)
+		}
+	}
+	if err := acc.RecordShardSubsampledGaussianStep("public-9", 1, 1.0); err != nil {
+		t.Fatalf("shard step: %v", err)
+	}
+
+	health, public := acc.GetShardEpsilon("health-1"), acc.GetShardEpsilon("public-9")
+	if health <= 0 || health >= public {
+		t.Fatalf("expected sampled shard ε=%.4f below full-batch shard ε=%.4f", health, public)
+	}
+	if global := acc.GetCurrentEpsilon(); global < public {
+		t.Fatalf("global ε=%.4f must cover shard ε=%.4f", global, public)
+	}
+	if acc.GetShardEpsilon("unknown") != 0 {
+		t.Fatal("expected zero epsilon for an unknown shard")
+	}
+}
@@ -122,3 +122,40 @@ func TestRDPAccountant_RecordGaussianStepRDP(t *testing.T) {
 		t.Fatalf("expected positive epsilon after gaussian step, got %s", eps.RatString())
 	}
 }
+
+func TestRDPAccountant_SubsampledGaussianAmplifies(t *testing.T) {
+	full := internal.NewRDPAccountant(100.0, 1e-5)
+	sampled := internal.NewRDPAccountant(100.0, 1e-5)
+	for i := 0; i < 100; i++ {
+		if err := full.RecordGaussianStepRDP(1.0); err != nil {
+			t.Fatalf("gaussian step: %v", err)
+		}
+		if err := sampled.RecordSubsampledGaussianStep(0.01, 1.0); err != nil {
+			t.Fatalf("subsampled step: %v", err)
+		}
+	}
+	fullEps := full.GetCurrentEpsilon()
+	sampledEps, order := sampled.GetCurrentEpsilonWithOrder()
+	if sampledEps <= 0 || sampledEps > 1.5 || sampledEps*10 > fullEps {
+		t.Fatalf("expected strong amplification at q=0.01: sampled ε=%.4f (α=%g), full ε=%.4f", sampledEps, order, fullEps)
+	}
+
+	if err := sampled.RecordSubsampledGaussianStep(1.5, 1.0); err == nil {
+		t.Fatal("expected sampling rate above 1 to be rejected")
+	}
+}
+
+func TestRDPAccountant_BestOrderBeatsLegacyConversion(t *testing.T) {
+	acc := internal.NewRDPAccountant(100.0, 1e-5)
+	if err := acc.RecordGaussianStepRDP(5.0); err != nil {
+		t.Fatalf("gaussian step: %v", err)
+	}
+	legacy := 10.0/(2*25) + math.Log(1/1e-5)/9
+	eps, order := acc.GetCurrentEpsilonWithOrder()
+	if eps >= legacy || order == 0 {
+		t.Fatalf("curve ε=%.4f at α=%g should beat single-order ε=%.4f", eps, order, legacy)
+	}
+	if total, _ := acc.TotalEpsilon.Float64(); math.Abs(total-0.2) > 1e-12 {
+		t.Fatalf("legacy ledger at α=10 should hold 0.2, got %g", total)
+	}
+}
@@ -0,0 +1,278 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal/secagg"
+)
+
+// runSecAggRound drives all five rounds in-process. Clients in dropAfterShare
+// share keys but never submit a masked input.
+func runSecAggRound(t *testing.T, params secagg.Params, gradients map[string][]float64, dropAfterShare map[string]bool) (secagg.Sum, map[string]secagg.MaskedInput) {
+	t.Helper()
+	server, err := secagg.NewServer(params)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	clients := map[string]*secagg.Client{}
+	for id := range gradients {
+		c, err := secagg.NewClient(id, params)
+		if err != nil {
+			t.Fatalf("new client: %v", err)
+		}
+		clients[id] = c
+		if err := server.Advertise(c.Advertise()); err != nil {
+			t.Fatalf("advertise: %v", err)
+		}
+	}
+	roster := server.Roster()
+	for id, c := range clients {
+		shares, err := c.ShareKeys(roster)
+		if err != nil {
+			t.Fatalf("share keys: %v", err)
+		}
+		if err := server.SubmitShares(id, shares); err != nil {
+			t.Fatalf("submit shares: %v", err)
+		}
+	}
+	masked := map[string]secagg.MaskedInput{}
+	for id, c := range clients {
+		if dropAfterShare[id] {
+			continue
+		}
+		in, err := c.MaskInput(gradients[id], server.Inbox(id))
+		if err != nil {
+			t.Fatalf("mask input: %v", err)
+		}
+		masked[id] = in
+		if err := server.SubmitMaskedInput(in); err != nil {
+			t.Fatalf("submit masked input: %v", err)
+		}
+	}
+	survivors := server.Survivors()
+	for _, id := range survivors {
+		conf, err := clients[id].ConfirmSurvivors(survivors)
+		if err != nil {
+			t.Fatalf("confirm survivors: %v", err)
+		}
+		if err := server.SubmitConfirmation(conf); err != nil {
+			t.Fatalf("submit confirmation: %v", err)
+		}
+	}
+	for _, id := range survivors {
+		resp, err := clients[id].Unmask(server.Confirmations())
+		if err != nil {
+			t.Fatalf("unmask: %v", err)
+		}
+		if err := server.SubmitUnmask(resp); err != nil {
+			t.Fatalf("submit unmask: %v", err)
+		}
+	}
+	sum, err := server.Finalize()
+	if err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	return sum, masked
+}
+
+func TestSecAggSumHidesIndividualGradients(t *testing.T) {
+	params := secagg.Params{Threshold: 3, Dim: 3, Scale: 1 << 20, ClipNorm: 1000}
+	gradients := map[string][]float64{
+		"edge-a": {0.5, -1.25, 2},
+		"edge-b": {1.5, 0.25, -3},
+		"edge-c": {-0.75, 1, 0.5},
+		"edge-d": {0.25, 0.5, 0.125},
+	}
+	sum, masked := runSecAggRound(t, params, gradients, nil)
+
+	want := []float64{1.5, 0.5, -0.375}
+	for i := range want {
+		if math.Abs(sum.Vector[i]-want[i]) > 1e-5 {
+			t.Fatalf("sum=%v, want %v", sum.Vector, want)
+		}
+	}
+	if len(sum.Contributors) != 4 || len(sum.Dropped) != 0 {
+		t.Fatalf("unexpected contributors=%v dropped=%v", sum.Contributors, sum.Dropped)
+	}
+
+	// The masked vector must not reveal the fixed-point gradient.
+	in := masked["edge-a"]
+	if int64(in.Vector[0]) == int64(0.5*float64(1<<20)) {
+		t.Fatal("masked input equals the plain fixed-point gradient")
+	}
+}
+
+func TestSecAggRecoversFromDropouts(t *testing.T) {
+	params := secagg.Params{Threshold: 3, Dim: 2, Scale: 1 << 16, ClipNorm: 1000}
+	gradients := map[string][]float64{
+		"a": {1, 2},
+		"b": {3, 4},
+		"c": {5, 6},
+		"d": {100, 100},
+		"e": {7, 8},
+	}
+	sum, _ := runSecAggRound(t, params, gradients, map[string]bool{"d": true})
+	if math.Abs(sum.Vector[0]-16) > 1e-4 || math.Abs(sum.Vector[1]-20) > 1e-4 {
+		t.Fatalf("sum=%v, want [16 20] without dropped client", sum.Vector)
+	}
+	if len(sum.Dropped) != 1 || sum.Dropped[0] != "d" {
+		t.Fatalf("dropped=%v, want [d]", sum.Dropped)
+	}
+}
+
+func TestSecAggRejectsTooFewSurvivors(t *testing.T) {
+	params := secagg.Params{Threshold: 3, Dim: 1, Scale: 1 << 16, ClipNorm: 1000}
+	server, _ := secagg.NewServer(params)
+	clients := make([]*secagg.Client, 3)
+	for i, id := range []string{"a", "b", "c"} {
+		clients[i], _ = secagg.NewClient(id, params)
+		if err := server.Advertise(clients[i].Advertise()); err != nil {
+			t.Fatalf("advertise: %v", err)
+		}
+	}
+	for _, c := range clients {
+		shares, err := c.ShareKeys(server.Roster())
+		if err != nil {
+			t.Fatalf("share keys: %v", err)
+		}
+		if err := server.SubmitShares(c.ID(), shares); err != nil {
+			t.Fatalf("submit shares: %v", err)
+		}
+	}
+	for _, c := range clients[:2] {
+		in, err := c.MaskInput([]float64{1}, server.Inbox(c.ID()))
+		if err != nil {
+			t.Fatalf("mask input: %v", err)
+		}
+		if err := server.SubmitMaskedInput(in); err != nil {
+			t.Fatalf("submit masked input: %v", err)
+		}
+	}
+	// With two survivors out of a threshold of three, a client must refuse to
+	// reveal shares: otherwise the server could unmask a single peer.
+	if _, err := clients[0].ConfirmSurvivors(server.Survivors()); err == nil {
+		t.Fatal("expected unmasking below threshold to be refused")
+	}
+	if _, err := server.Finalize(); err == nil {
+		t.Fatal("expected finalize below threshold to fail")
+	}
+}
+
+func TestSecAggClientRejectsForgedShares(t *testing.T) {
+	params := secagg.Params{Threshold: 2, Dim: 1, Scale: 1 << 16, ClipNorm: 1000}
+	server, _ := secagg.NewServer(params)
+	a, _ := secagg.NewClient("a", params)
+	b, _ := secagg.NewClient("b", params)
+	_ = server.Advertise(a.Advertise())
+	_ = server.Advertise(b.Advertise())
+	for _, c := range []*secagg.Client{a, b} {
+		shares, err := c.ShareKeys(server.Roster())
+		if err != nil {
+			t.Fatalf("share keys: %v", err)
+		}
+		_ = server.SubmitShares(c.ID(), shares)
+	}
+	inbox := server.Inbox("a")
+	for i := range inbox {
+		if inbox[i].From == "b" {
+			inbox[i].Ciphertext[0] ^= 0xff
+		}
+	}
+	if _, err := a.MaskInput([]float64{1}, inbox); err == nil {
+		t.Fatal("expected tampered share bundle to be rejected")
+	}
+}
+
+// TestSecAggRefusesInconsistentSurvivorSets plays a server that tells one
+// client that d dropped and the others that it survived, to collect both of
+// d's secrets.
+func TestSecAggRefusesInconsistentSurvivorSets(t *testing.T) {
+	params := secagg.Params{Threshold: 3, Dim: 1, Scale: 1 << 16, ClipNorm: 1000}
+	server, _ := secagg.NewServer(params)
+	clients := map[string]*secagg.Client{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		clients[id], _ = secagg.NewClient(id, params)
+		if err := server.Advertise(clients[id].Advertise()); err != nil {
+			t.Fatalf("advertise: %v", err)
+		}
+	}
+	for id, c := range clients {
+		shares, err := c.ShareKeys(server.Roster())
+		if err != nil {
+			t.Fatalf("share keys: %v", err)
+		}
+		if err := server.SubmitShares(id, shares); err != nil {
+			t.Fatalf("submit shares: %v", err)
+		}
+	}
+	for id, c := range clients {
+		in, err := c.MaskInput([]float64{1}, server.Inbox(id))
+		if err != nil {
+			t.Fatalf("mask input: %v", err)
+		}
+		if err := server.SubmitMaskedInput(in); err != nil {
+			t.Fatalf("submit masked input: %v", err)
+		}
+	}
+
+	var confs []secagg.SurvivorConfirmation
+	for id, set := range map[string][]string{
+		"a": {"a", "b", "c", "e"}, // told that d dropped
+		"b": {"a", "b", "c", "d", "e"},
+		"c": {"a", "b", "c", "d", "e"},
+		"e": {"a", "b", "c", "d", "e"},
+	} {
+		conf, err := clients[id].ConfirmSurvivors(set)
+		if err != nil {
+			t.Fatalf("confirm survivors: %v", err)
+		}
+		confs = append(confs, conf)
+	}
+	if _, err := clients["a"].Unmask(confs); err == nil {
+		t.Fatal("expected a client to refuse shares when peers confirmed a different survivor set")
+	}
+	if _, err := clients["b"].Unmask(confs); err == nil {
+		t.Fatal("expected a different confirmation to be reported, not skipped")
+	}
+	// Confirmations that are missing, rather than conflicting, only count short
+	if _, err := clients["c"].Unmask(confs[:0]); err == nil {
+		t.Fatal("expected unmasking without confirmations to be refused")
+	}
+}
+
+func TestSecAggRequiresThresholdAboveHalfTheRoster(t *testing.T) {
+	params := secagg.Params{Threshold: 2, Dim: 1, Scale: 1 << 16, ClipNorm: 1000}
+	server, _ := secagg.NewServer(params)
+	var first *secagg.Client
+	for _, id := range []string{"a", "b", "c", "d"} {
+		c, _ := secagg.NewClient(id, params)
+		if first == nil {
+			first = c
+		}
+		_ = server.Advertise(c.Advertise())
+	}
+	if _, err := first.ShareKeys(server.Roster()); err == nil {
+		t.Fatal("expected a threshold of half the roster to be refused")
+	}
+}
+
+func TestSecAggClipsInputsToClipNorm(t *testing.T) {
+	params := secagg.Params{Threshold: 3, Dim: 2, Scale: 1 << 20, ClipNorm: 1}
+	gradients := map[string][]float64{
+		"a": {3, 4}, // norm 5, scaled to {0.6, 0.8}
+		"b": {0, 0.5},
+		"c": {-0.25, 0},
+	}
+	sum, _ := runSecAggRound(t, params, gradients, nil)
+	if math.Abs(sum.Vector[0]-0.35) > 1e-5 || math.Abs(sum.Vector[1]-1.3) > 1e-5 {
+		t.Fatalf("sum=%v, want [0.35 1.3] with a's gradient clipped", sum.Vector)
+	}
+	if sum.ClipNorm != 1 {
+		t.Fatalf("sum reports clip norm %v, want 1", sum.ClipNorm)
+	}
+
+	if _, err := secagg.NewClient("a", secagg.Params{Threshold: 2, Dim: 1, Scale: 1 << 16}); err == nil {
+		t.Fatal("expected params without a clip norm to be refused")
+	}
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	internal "github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
+)
+
+func makeGroth16Batch(tb testing.TB, td *groth16Trapdoor, n int) ([][]byte, [][]byte) {
+	tb.Helper()
+	proofs := make([][]byte, n)
+	inputs := make([][]byte, n)
+	for i := range proofs {
+		elems, raw := encodeInputs(uint64(i), uint64(i*i+1))
+		proofs[i] = td.prove(tb, elems)
+		inputs[i] = raw
+	}
+	return proofs, inputs
+}
+
+func TestBatchVerifyProofs_AllValid(t *testing.T) {
+	td := registerTestCircuit(t, "batch-valid", 2)
+	proofs, inputs := makeGroth16Batch(t, td, 16)
+
+	res, err := internal.BatchVerifyProofs("batch-valid", proofs, inputs)
+	if err != nil {
+		t.Fatalf("batch verify: %v", err)
+	}
+	if !res.AllValid() {
+		t.Fatalf("expected every proof to verify, got %v", res.Valid)
+	}
+	if res.PairingChecks != 1 {
+		t.Fatalf("valid batch should need one pairing check, used %d", res.PairingChecks)
+	}
+}
+
+func TestBatchVerifyProofs_BisectsInvalid(t *testing.T) {
+	td := registerTestCircuit(t, "batch-bisect", 2)
+	proofs, inputs := makeGroth16Batch(t, td, 16)
+	_, inputs[3] = encodeInputs(99, 99)
+	_, inputs[12] = encodeInputs(1, 1)
+
+	res, err := internal.BatchVerifyProofs("batch-bisect", proofs, inputs)
+	if err != nil {
+		t.Fatalf("batch verify: %v", err)
+	}
+	for i, ok := range res.Valid {
+		want := i != 3 && i != 12
+		if ok != want {
+			t.Errorf("proof %d: valid=%v, want %v", i, ok, want)
+		}
+		if res.Errors[i] != nil {
+			t.Errorf("proof %d: unexpected parse error %v", i, res.Errors[i])
+		}
+	}
+	if res.PairingChecks <= 1 || res.PairingChecks >= 2*len(proofs) {
+		t.Fatalf("unexpected pairing check count %d", res.PairingChecks)
+	}
+}
+
+func TestBatchVerifyProofs_MalformedEntries(t *testing.T) {
+	td := registerTestCircuit(t, "batch-malformed", 2)
+	proofs, inputs := makeGroth16Batch(t, td, 4)
+	proofs[1] = make([]byte, 64)
+	_, inputs[2] = encodeInputs(5)
+
+	res, err := internal.BatchVerifyProofs("batch-malformed", proofs, inputs)
+	if err != nil {
+		t.Fatalf("batch verify: %v", err)
+	}
+	if res.Errors[1] == nil || res.Errors[2] == nil {
+		t.Fatalf("expected errors for malformed entries, got %v", res.Errors)
+	}
+	if !res.Valid[0] || res.Valid[1] || res.Valid[2] || !res.Valid[3] {
+		t.Fatalf("unexpected validity %v", res.Valid)
+	}
+
+	if _, err := internal.BatchVerifyProofs("unregistered", proofs, inputs); err == nil {
+		t.Fatal("expected unknown circuit to be rejected")
+	}
+	if _, err := internal.BatchVerifyProofs("batch-malformed", proofs, inputs[:2]); err == nil {
+		t.Fatal("expected length mismatch to be rejected")
+	}
+}
+
+func TestBatchVerifierWithGroth16Batch(t *testing.T) {
+	td := registerTestCircuit(t, "batch-signed", 2)
+	proofs, inputs := makeGroth16Batch(t, td, 3)
+	_, inputs[2] = encodeInputs(7, 7)
+
+	pubKeys := make([]ed25519.PublicKey, 3)
+	messages := make([][]byte, 3)
+	sigs := make([][]byte, 3)
+	for i := range pubKeys {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("keygen: %v", err)
+		}
+		pubKeys[i] = pub
+		messages[i] = []byte("update")
+		sigs[i] = ed25519.Sign(priv, messages[i])
+	}
+	sigs[1] = make([]byte, ed25519.SignatureSize)
+
+	bv := internal.NewBatchVerifier(4)
+	results, err := bv.VerifySignaturesWithGroth16Batch(pubKeys, messages, sigs, "batch-signed", proofs, inputs)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !results[0] || results[1] || results[2] {
+		t.Fatalf("unexpected results %v", results)
+	}
+}
+
+const benchGroth16BatchSize = 64
+
+func BenchmarkGroth16Sequential(b *testing.B) {
+	td := registerTestCircuit(b, "bench-sequential", 2)
+	proofs, inputs := makeGroth16Batch(b, td, benchGroth16BatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range proofs {
+			if ok, err := internal.VerifyProof("bench-sequential", proofs[i], inputs[i]); !ok || err != nil {
+				b.Fatalf("proof %d: %v %v", i, ok, err)
+			}
+		}
+	}
+}
+
+func BenchmarkGroth16Batch(b *testing.B) {
+	td := registerTestCircuit(b, "bench-batch", 2)
+	proofs, inputs := makeGroth16Batch(b, td, benchGroth16BatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		res, err := internal.BatchVerifyProofs("bench-batch", proofs, inputs)
+		if err != nil || !res.AllValid() {
+			b.Fatalf("batch: %v", err)
+		}
+	}
+}
@@ -1,28 +1,155 @@
 package test
 
 import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	internal "github.com/rwilliamspbg-ops/Sovereign-Mohawk-Proto/internal"
 )
 
+// groth16Trapdoor builds a verifying key from known toxic waste so tests can
+// simulate valid proofs for arbitrary public inputs without a prover.
+type groth16Trapdoor struct {
+	alpha, beta, gamma, delta fr.Element
+	k                         []fr.Element
+	vk                        *internal.VerifyingKey
+}
+
+func newGroth16Trapdoor(t testing.TB, nbPublic int) *groth16Trapdoor {
+	t.Helper()
+	td := &groth16Trapdoor{k: make([]fr.Element, nbPublic+1)}
+	for _, e := range []*fr.Element{&td.alpha, &td.beta, &td.gamma, &td.delta} {
+		if _, err := e.SetRandom(); err != nil {
+			t.Fatalf("random scalar: %v", err)
+		}
+	}
+	vk := &internal.VerifyingKey{IC: make([]bn254.G1Affine, nbPublic+1)}
+	vk.Alpha.ScalarMultiplicationBase(scalar(td.alpha))
+	vk.BetaG1.ScalarMultiplicationBase(scalar(td.beta))
+	vk.Beta.ScalarMultiplicationBase(scalar(td.beta))
+	vk.Gamma.ScalarMultiplicationBase(scalar(td.gamma))
+	vk.DeltaG1.ScalarMultiplicationBase(scalar(td.delta))
+	vk.Delta.ScalarMultiplicationBase(scalar(td.delta))
+	for i := range td.k {
+		if _, err := td.k[i].SetRandom(); err != nil {
+			t.Fatalf("random scalar: %v", err)
+		}
+		vk.IC[i].ScalarMultiplicationBase(scalar(td.k[i]))
+	}
+	td.vk = vk
+	return td
+}
+
+// prove picks random A, B and solves for C so that
+// a·b = α·β + γ·(k₀ + Σ xᵢkᵢ) + δ·c.
+func (td *groth16Trapdoor) prove(t testing.TB, inputs []fr.Element) []byte {
+	t.Helper()
+	var a, b, c, tmp, ic fr.Element
+	a.SetRandom()
+	b.SetRandom()
+	ic.Set(&td.k[0])
+	for i, x := range inputs {
+		tmp.Mul(&x, &td.k[i+1])
+		ic.Add(&ic, &tmp)
+	}
+	c.Mul(&a, &b)
+	tmp.Mul(&td.alpha, &td.beta)
+	c.Sub(&c, &tmp)
+	tmp.Mul(&td.gamma, &ic)
+	c.Sub(&c, &tmp)
+	tmp.Inverse(&td.delta)
+	c.Mul(&c, &tmp)
+
+	var pA, pC bn254.G1Affine
+	var pB bn254.G2Affine
+	pA.ScalarMultiplicationBase(scalar(a))
+	pB.ScalarMultiplicationBase(scalar(b))
+	pC.ScalarMultiplicationBase(scalar(c))
+	aB, bB, cB := pA.Bytes(), pB.Bytes(), pC.Bytes()
+	return append(append(aB[:], bB[:]...), cB[:]...)
+}
+
+func scalar(e fr.Element) *big.Int {
+	return e.BigInt(new(big.Int))
+}
+
+func encodeInputs(values ...uint64) ([]fr.Element, []byte) {
+	elems := make([]fr.Element, len(values))
+	var raw []byte
+	for i, v := range values {
+		elems[i].SetUint64(v)
+		b := elems[i].Bytes()
+		raw = append(raw, b[:]...)
+	}
+	return elems, raw
+}
+
+func registerTestCircuit(t testing.TB, id string, nbPublic int) *groth16Trapdoor {
+	t.Helper()
+	td := newGroth16Trapdoor(t, nbPublic)
+	if err := internal.RegisterVerifyingKey(id, td.vk); err != nil {
+		t.Fatalf("register verifying key: %v", err)
+	}
+	t.Cleanup(func() { internal.UnregisterVerifyingKey(id) })
+	return td
+}
+
 func TestVerifyProof_Valid(t *testing.T) {
-	// Use the deterministic genesis proof: A=G1gen, B=G2gen, C=−G1gen.
-	// This satisfies e(−A,B)·e(α,β)·e(IC₀,γ)·e(C,δ)=1 under the genesis VK.
-	proof := internal.GenesisProofBytes()
-	ok, err := internal.VerifyProof(proof, nil)
+	td := registerTestCircuit(t, "test-valid", 2)
+	elems, raw := encodeInputs(42, 7)
+	ok, err := internal.VerifyProof("test-valid", td.prove(t, elems), raw)
 	if err != nil {
-		t.Fatalf("Expected no error for genesis proof, got: %v", err)
+		t.Fatalf("Expected no error for valid proof, got: %v", err)
 	}
 	if !ok {
-		t.Error("Expected true for valid genesis proof")
+		t.Error("Expected true for valid proof")
+	}
+}
+
+func TestVerifyProof_WrongPublicInputs(t *testing.T) {
+	td := registerTestCircuit(t, "test-inputs", 2)
+	elems, _ := encodeInputs(42, 7)
+	proof := td.prove(t, elems)
+
+	_, wrong := encodeInputs(42, 8)
+	ok, err := internal.VerifyProof("test-inputs", proof, wrong)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("proof verified against public inputs it was not generated for")
+	}
+
+	_, short := encodeInputs(42)
+	if ok, err := internal.VerifyProof("test-inputs", proof, short); err == nil || ok {
+		t.Fatal("expected arity mismatch to be rejected")
+	}
+}
+
+func TestVerifyProof_WrongCircuit(t *testing.T) {
+	td := registerTestCircuit(t, "test-circuit-a", 1)
+	registerTestCircuit(t, "test-circuit-b", 1)
+	elems, raw := encodeInputs(1)
+	proof := td.prove(t, elems)
+
+	if ok, _ := internal.VerifyProof("test-circuit-b", proof, raw); ok {
+		t.Fatal("proof verified under another circuit's verifying key")
+	}
+	if _, err := internal.VerifyProof("unregistered", proof, raw); err == nil {
+		t.Fatal("expected unknown circuit to be rejected")
 	}
 }
 
 func TestVerifyProof_TooSmall(t *testing.T) {
+	registerTestCircuit(t, "test-small", 0)
 	// Proof shorter than 128 bytes must be rejected before any curve operations.
 	proof := make([]byte, 64)
-	ok, err := internal.VerifyProof(proof, nil)
+	ok, err := internal.VerifyProof("test-small", proof, nil)
 	if err == nil {
 		t.Fatal("Expected error for undersized proof, got nil")
 	}
@@ -32,9 +159,10 @@ func TestVerifyProof_TooSmall(t *testing.T) {
 }
 
 func TestVerifyProof_InvalidPoint(t *testing.T) {
+	registerTestCircuit(t, "test-point", 0)
 	// 128 bytes of zeros are NOT valid compressed BN254 points (not on the curve).
 	proof := make([]byte, 128)
-	ok, err := internal.VerifyProof(proof, nil)
+	ok, err := internal.VerifyProof("test-point", proof, nil)
 	if err == nil {
 		t.Fatal("Expected error for all-zero (invalid-point) proof, got nil")
 	}
@@ -44,13 +172,51 @@ func TestVerifyProof_InvalidPoint(t *testing.T) {
 }
 
 func TestVerifyProof_WrongProof(t *testing.T) {
-	// Corrupt the genesis proof's C point — pairing check must return false.
-	proof := internal.GenesisProofBytes()
+	td := registerTestCircuit(t, "test-corrupt", 0)
+	proof := td.prove(t, nil)
 	// Flip a bit in the C component (bytes 96–127)
 	proof[96] ^= 0x80
 	// The modified bytes may fail point parsing or pairing; either is acceptable.
-	ok, _ := internal.VerifyProof(proof, nil)
+	ok, _ := internal.VerifyProof("test-corrupt", proof, nil)
 	if ok {
 		t.Error("Expected false (or parse error) for corrupted proof, got true")
 	}
 }
+
+func TestVerifyingKeySerializationRoundTrip(t *testing.T) {
+	td := newGroth16Trapdoor(t, 3)
+	var buf bytes.Buffer
+	if _, err := td.vk.WriteTo(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "roundtrip"+internal.VerifyingKeyExt), buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	loaded, err := internal.LoadVerifyingKeysFromDir(dir)
+	if err != nil || len(loaded) != 1 || loaded[0] != "roundtrip" {
+		t.Fatalf("load dir: %v %v", loaded, err)
+	}
+	t.Cleanup(func() { internal.UnregisterVerifyingKey("roundtrip") })
+
+	vk, err := internal.LookupVerifyingKey("roundtrip")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if vk.NbPublicInputs() != 3 || !vk.Alpha.Equal(&td.vk.Alpha) || !vk.Delta.Equal(&td.vk.Delta) || !vk.IC[2].Equal(&td.vk.IC[2]) {
+		t.Fatal("verifying key changed across serialization")
+	}
+
+	var raw bytes.Buffer
+	if _, err := td.vk.WriteRawTo(&raw); err != nil {
+		t.Fatalf("write raw: %v", err)
+	}
+	var fromRaw internal.VerifyingKey
+	if _, err := fromRaw.ReadFrom(&raw); err != nil {
+		t.Fatalf("read raw: %v", err)
+	}
+	if !fromRaw.Gamma.Equal(&td.vk.Gamma) {
+		t.Fatal("uncompressed verifying key changed across serialization")
+	}
+}
@@ -1,7 +1,14 @@
-#[link(wasm_import_module = "env")]
+#![no_std]
+
+#[panic_handler]
+fn panic(_: &core::panic::PanicInfo) -> ! {
+    core::arch::wasm32::unreachable()
+}
+
+#[link(wasm_import_module = "mohawk")]
 extern "C" {
     fn log(level: i32, ptr: *const u8, len: i32);
-    fn submit_gradients(ptr: *const u8, len: i32) -> i32;
+    fn submit_gradient(ptr: *const u8, len: i32) -> i32;
 }
 
 fn host_log(level: i32, msg: &str) {
@@ -16,9 +23,9 @@ pub extern "C" fn run_task() {
         core::slice::from_raw_parts(grads.as_ptr() as *const u8, core::mem::size_of::<[f32; 4]>())
     };
     unsafe {
-        let rc = submit_gradients(bytes.as_ptr(), bytes.len() as i32);
+        let rc = submit_gradient(bytes.as_ptr(), bytes.len() as i32);
         if rc != 0 {
-            host_log(3, "flower submit_gradients failed");
+            host_log(3, "flower submit_gradient failed");
         }
     }
-}
\ No newline at end of file
+}